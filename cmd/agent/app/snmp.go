@@ -0,0 +1,119 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/profilegen"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+var (
+	snmpWalkFile       string
+	snmpProfileOutput  string
+	snmpDeviceConfig   profilegen.DeviceConfig
+	defaultSnmpPort    uint16 = 161
+	defaultSnmpTimeout        = 10
+	defaultSnmpRetries        = 3
+	defaultSnmpVersion        = "2c"
+)
+
+func init() {
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpWalkFile, "walk-file", "f", "", "Path to the output of `snmpwalk -On` to use instead of walking a device.")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpProfileOutput, "output", "o", "", "Path of the generated profile, it is printed if not set.")
+	snmpGenerateProfileCmd.Flags().Uint16VarP(&snmpDeviceConfig.Port, "port", "p", defaultSnmpPort, "SNMP port of the device.")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.SnmpVersion, "snmp-version", "v", defaultSnmpVersion, "SNMP version: 1, 2c or 3.")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.CommunityString, "community-string", "C", "", "Community string (SNMP v1 and v2c).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.User, "user", "u", "", "User name (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.AuthProtocol, "auth-protocol", "a", "", "Authentication protocol: MD5, SHA, SHA224, SHA256, SHA384 or SHA512 (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.AuthKey, "auth-key", "A", "", "Authentication key (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.PrivProtocol, "priv-protocol", "x", "", "Privacy protocol: DES, AES, AES192, AES192C, AES256 or AES256C (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.PrivKey, "priv-key", "X", "", "Privacy key (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().StringVarP(&snmpDeviceConfig.ContextName, "context", "", "", "Context name (SNMP v3).")
+	snmpGenerateProfileCmd.Flags().IntVarP(&snmpDeviceConfig.Timeout, "timeout", "t", defaultSnmpTimeout, "Timeout of the requests, in seconds.")
+	snmpGenerateProfileCmd.Flags().IntVarP(&snmpDeviceConfig.Retries, "retries", "r", defaultSnmpRetries, "Number of retries of the requests.")
+
+	snmpCmd.AddCommand(snmpGenerateProfileCmd)
+	AgentCmd.AddCommand(snmpCmd)
+}
+
+var snmpCmd = &cobra.Command{
+	Use:   "snmp",
+	Short: "SNMP tools",
+	Long:  ``,
+}
+
+var snmpGenerateProfileCmd = &cobra.Command{
+	Use:   "generate-profile [ip_address]",
+	Short: "Generate a draft SNMP profile from a device walk",
+	Long: `Walk a device, or read the output of ` + "`snmpwalk -On`" + ` with --walk-file, and generate a draft profile
+matching the sysObjectID of the device with the metrics of the known MIB tables found in the walk.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: doGenerateSnmpProfile,
+}
+
+func doGenerateSnmpProfile(cmd *cobra.Command, args []string) error {
+	err := common.SetupConfigWithoutSecrets(confFilePath, "")
+	if err != nil {
+		return fmt.Errorf("unable to set up global agent configuration: %v", err)
+	}
+
+	err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+	if err != nil {
+		fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+		return err
+	}
+
+	var variables []profilegen.Variable
+	switch {
+	case snmpWalkFile != "" && len(args) > 0:
+		return fmt.Errorf("either a device IP address or a walk file must be provided, not both")
+	case snmpWalkFile != "":
+		f, err := os.Open(snmpWalkFile)
+		if err != nil {
+			return fmt.Errorf("unable to open walk file: %v", err)
+		}
+		defer f.Close()
+		variables, err = profilegen.ParseWalk(f)
+		if err != nil {
+			return fmt.Errorf("unable to parse walk file `%s`: %v", snmpWalkFile, err)
+		}
+	case len(args) == 1:
+		snmpDeviceConfig.IPAddress = args[0]
+		fmt.Fprintf(os.Stderr, "Walking %s, it can take a few minutes...\n", snmpDeviceConfig.IPAddress)
+		variables, err = profilegen.WalkDevice(snmpDeviceConfig)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("a device IP address or a walk file must be provided")
+	}
+
+	profile, err := profilegen.Generate(variables)
+	if err != nil {
+		return fmt.Errorf("unable to generate profile: %v", err)
+	}
+	content, err := profile.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if snmpProfileOutput == "" {
+		fmt.Print(string(content))
+		return nil
+	}
+	if err := ioutil.WriteFile(snmpProfileOutput, content, 0644); err != nil {
+		return fmt.Errorf("unable to write profile: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Profile with %d metrics written to %s\n", len(profile.Metrics), snmpProfileOutput)
+	return nil
+}
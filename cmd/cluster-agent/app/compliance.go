@@ -112,6 +112,7 @@ func startCompliance(stopper startstop.Stopper, apiCl *apiserver.APIClient, isLe
 			return rule.Scope.Includes(compliance.KubernetesClusterScope)
 		}),
 		checks.WithKubernetesClient(apiCl.DynamicCl, ""),
+		checks.WithKubernetesInformers(apiCl.ComplianceInformerFactory),
 		checks.WithIsLeader(isLeader),
 	)
 	if err != nil {
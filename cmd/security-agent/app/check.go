@@ -13,12 +13,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/DataDog/datadog-agent/cmd/security-agent/common"
+	"github.com/DataDog/datadog-agent/pkg/compliance"
 	"github.com/DataDog/datadog-agent/pkg/compliance/agent"
 	"github.com/DataDog/datadog-agent/pkg/compliance/checks"
 	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/export"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util"
@@ -40,6 +43,9 @@ var (
 		dumpRegoInput     string
 		dumpReports       string
 		skipRegoEval      bool
+		reportFormat      string
+		reportFile        string
+		failOnSeverity    string
 	}{}
 )
 
@@ -52,6 +58,9 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.dumpRegoInput, "dump-rego-input", "", "", "Path to file where to dump the Rego input JSON")
 	cmd.Flags().StringVarP(&checkArgs.dumpReports, "dump-reports", "", "", "Path to file where to dump reports")
 	cmd.Flags().BoolVarP(&checkArgs.skipRegoEval, "skip-rego-eval", "", false, "Skip rego evaluation")
+	cmd.Flags().StringVarP(&checkArgs.reportFormat, "report-format", "", string(export.JSON), "Format of the findings report (json, sarif)")
+	cmd.Flags().StringVarP(&checkArgs.reportFile, "report-file", "", "", "Path to file where to write the findings report")
+	cmd.Flags().StringVarP(&checkArgs.failOnSeverity, "fail-on-severity", "", "", "Exit with an error if a rule of this severity or above failed (info, low, medium, high, critical)")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -74,10 +83,22 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
-	if checkArgs.skipRegoEval && checkArgs.dumpReports != "" {
+	if checkArgs.skipRegoEval && (checkArgs.dumpReports != "" || checkArgs.reportFile != "" || checkArgs.failOnSeverity != "") {
 		return errors.New("skipping the rego evaluation does not allow the generation of reports")
 	}
 
+	reportFormat, err := export.ParseFormat(checkArgs.reportFormat)
+	if err != nil {
+		return err
+	}
+
+	var failOnSeverity compliance.RuleSeverity
+	if checkArgs.failOnSeverity != "" {
+		if failOnSeverity, err = compliance.ParseRuleSeverity(checkArgs.failOnSeverity); err != nil {
+			return err
+		}
+	}
+
 	// We need to set before calling `SetupConfig`
 	configName := "datadog"
 	if flavor.GetFlavor() == flavor.ClusterAgent {
@@ -160,9 +181,12 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 	options = append(options, checks.WithRegoEvalSkip(checkArgs.skipRegoEval))
 
 	if checkArgs.file != "" {
+		reporter.findings.LoadSuites(checkArgs.file)
 		err = agent.RunChecksFromFile(reporter, checkArgs.file, options...)
 	} else {
 		configDir := config.Datadog.GetString("compliance_config.dir")
+		suiteFiles, _ := filepath.Glob(filepath.Join(configDir, "*.yaml"))
+		reporter.findings.LoadSuites(suiteFiles...)
 		err = agent.RunChecks(reporter, configDir, options...)
 	}
 
@@ -176,6 +200,19 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 		return err
 	}
 
+	if checkArgs.reportFile != "" {
+		if err := reporter.writeFindingsReport(checkArgs.reportFile, reportFormat); err != nil {
+			log.Errorf("Failed to write findings report: %v", err)
+			return err
+		}
+	}
+
+	if failOnSeverity != "" {
+		if count := reporter.findings.FailedAtLeast(failOnSeverity); count > 0 {
+			return fmt.Errorf("%d finding(s) failed with a severity of %s or above", count, failOnSeverity)
+		}
+	}
+
 	return nil
 }
 
@@ -201,6 +238,7 @@ func configureLogger() error {
 type RunCheckReporter struct {
 	reporter        event.Reporter
 	events          map[string][]*event.Event
+	findings        *export.Report
 	dumpReportsPath string
 }
 
@@ -223,6 +261,7 @@ func NewCheckReporter(stopper startstop.Stopper, report bool, dumpReportsPath st
 	}
 
 	r.events = make(map[string][]*event.Event)
+	r.findings = export.NewReport()
 	r.dumpReportsPath = dumpReportsPath
 
 	return r, nil
@@ -230,6 +269,7 @@ func NewCheckReporter(stopper startstop.Stopper, report bool, dumpReportsPath st
 
 func (r *RunCheckReporter) Report(event *event.Event) {
 	r.events[event.AgentRuleID] = append(r.events[event.AgentRuleID], event)
+	r.findings.AddEvent(event)
 
 	eventJSON, err := checks.PrettyPrintJSON(event, "  ")
 	if err != nil {
@@ -260,6 +300,19 @@ func (r *RunCheckReporter) dumpReports() error {
 	return nil
 }
 
+func (r *RunCheckReporter) writeFindingsReport(path string, format export.Format) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := r.findings.Write(f, format); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func init() {
 	complianceCmd.AddCommand(CheckCmd(func() []string {
 		return confPathArray
@@ -35,6 +35,7 @@ import (
 	secconfig "github.com/DataDog/datadog-agent/pkg/security/config"
 	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
 	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/replay"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
@@ -184,6 +185,17 @@ var (
 		outputPath string
 	}{}
 
+	replayPoliciesCmd = &cobra.Command{
+		Use:   "replay",
+		Short: "Replay recorded events against policies and return a report",
+		RunE:  replayPolicies,
+	}
+
+	replayPoliciesArgs = struct {
+		dir        string
+		eventsFile string
+	}{}
+
 	commonPolicyCmd = &cobra.Command{
 		Use:   "policy",
 		Short: "Policy related commands",
@@ -291,6 +303,11 @@ func init() {
 	commonPolicyCmd.AddCommand(commonCheckPoliciesCmd)
 
 	commonPolicyCmd.AddCommand(commonReloadPoliciesCmd)
+
+	replayPoliciesCmd.Flags().StringVar(&replayPoliciesArgs.dir, "policies-dir", coreconfig.DefaultRuntimePoliciesDir, "Path to policies directory")
+	replayPoliciesCmd.Flags().StringVar(&replayPoliciesArgs.eventsFile, "events", "", "Path to the file of recorded events, either a JSON array or one JSON event per line. Use - to read from the standard input")
+	_ = replayPoliciesCmd.MarkFlagRequired("events")
+	commonPolicyCmd.AddCommand(replayPoliciesCmd)
 	runtimeCmd.AddCommand(commonPolicyCmd)
 
 	dumpNetworkNamespaceCmd.Flags().BoolVar(&dumpNetworkNamespaceArgs.snapshotInterfaces, "snapshot-interfaces", true, "snapshot the interfaces of each network namespace during the dump")
@@ -523,15 +540,7 @@ func generateGraphFromActivityDump(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func checkPoliciesInner(dir string) error {
-	cfg := &secconfig.Config{
-		PoliciesDir:         dir,
-		EnableKernelFilters: true,
-		EnableApprovers:     true,
-		EnableDiscarders:    true,
-		PIDCacheSize:        1,
-	}
-
+func newPolicyCheckRuleSet() *rules.RuleSet {
 	// enabled all the rules
 	enabled := map[eval.EventType]bool{"*": true}
 
@@ -543,10 +552,23 @@ func checkPoliciesInner(dir string) error {
 		WithEventTypeEnabled(enabled).
 		WithReservedRuleIDs(sprobe.AllCustomRuleIDs()).
 		WithLegacyFields(model.SECLLegacyFields).
+		WithSequenceScopes(sprobe.SequenceScopes).
 		WithLogger(&seclog.PatternLogger{})
 
 	model := &model.Model{}
-	ruleSet := rules.NewRuleSet(model, model.NewEvent, &opts)
+	return rules.NewRuleSet(model, model.NewEvent, &opts)
+}
+
+func checkPoliciesInner(dir string) error {
+	cfg := &secconfig.Config{
+		PoliciesDir:         dir,
+		EnableKernelFilters: true,
+		EnableApprovers:     true,
+		EnableDiscarders:    true,
+		PIDCacheSize:        1,
+	}
+
+	ruleSet := newPolicyCheckRuleSet()
 
 	provider, err := rules.NewPoliciesDirProvider(cfg.PoliciesDir, false)
 	if err != nil {
@@ -581,6 +603,44 @@ func checkPolicies(cmd *cobra.Command, args []string) error {
 	return checkPoliciesInner(checkPoliciesArgs.dir)
 }
 
+func replayPolicies(cmd *cobra.Command, args []string) error {
+	ruleSet := newPolicyCheckRuleSet()
+
+	provider, err := rules.NewPoliciesDirProvider(replayPoliciesArgs.dir, false)
+	if err != nil {
+		return err
+	}
+
+	// load errors are part of the report, the valid rules are still evaluated
+	loadErrs := ruleSet.LoadPolicies(rules.NewPolicyLoader(provider))
+
+	input := os.Stdin
+	if replayPoliciesArgs.eventsFile != "-" {
+		f, err := os.Open(replayPoliciesArgs.eventsFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	events, err := replay.ReadEvents(input)
+	if err != nil {
+		return err
+	}
+
+	report := replay.NewReplayer(ruleSet).Replay(events, loadErrs, sprobe.GetCapababilities())
+
+	content, _ := json.MarshalIndent(report, "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	if report.Failed() {
+		return errors.New("policies replay failed")
+	}
+
+	return nil
+}
+
 func runRuntimeSelfTest(cmd *cobra.Command, args []string) error {
 	client, err := secagent.NewRuntimeSecurityClient()
 	if err != nil {
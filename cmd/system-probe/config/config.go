@@ -40,6 +40,7 @@ const (
 	TCPQueueLengthTracerModule ModuleName = "tcp_queue_length_tracer"
 	SecurityRuntimeModule      ModuleName = "security_runtime"
 	ProcessModule              ModuleName = "process"
+	TracerouteModule           ModuleName = "traceroute"
 )
 
 func key(pieces ...string) string {
@@ -221,6 +222,10 @@ func load(configPath string) (*Config, error) {
 		log.Info("process_config.enabled detected, enabling system-probe")
 		c.EnabledModules[ProcessModule] = struct{}{}
 	}
+	if cfg.GetBool("traceroute_config.enabled") {
+		log.Info("traceroute_config.enabled detected, enabling system-probe with traceroute module")
+		c.EnabledModules[TracerouteModule] = struct{}{}
+	}
 
 	if len(c.EnabledModules) > 0 {
 		c.Enabled = true
@@ -54,3 +54,19 @@ func TestRuntimeSecurityLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestTracerouteLoad(t *testing.T) {
+	newConfig()
+
+	for _, enabled := range []bool{false, true} {
+		t.Run(strconv.FormatBool(enabled), func(t *testing.T) {
+			os.Setenv("DD_TRACEROUTE_CONFIG_ENABLED", strconv.FormatBool(enabled))
+			defer os.Unsetenv("DD_TRACEROUTE_CONFIG_ENABLED")
+
+			cfg, err := New("")
+			require.NoError(t, err)
+			assert.Equal(t, enabled, cfg.ModuleIsEnabled(TracerouteModule))
+			assert.Equal(t, enabled, cfg.Enabled)
+		})
+	}
+}
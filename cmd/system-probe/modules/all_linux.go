@@ -21,6 +21,7 @@ var All = []module.Factory{
 	OOMKillProbe,
 	SecurityRuntime,
 	Process,
+	Traceroute,
 }
 
 func inactivityEventLog(duration time.Duration) {
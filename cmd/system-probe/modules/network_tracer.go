@@ -12,6 +12,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -28,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/encoding"
 	"github.com/DataDog/datadog-agent/pkg/network/http/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer"
+	"github.com/DataDog/datadog-agent/pkg/network/traceroute"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -81,6 +83,7 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 			w.WriteHeader(500)
 			return
 		}
+		observeTracerouteDestinations(cs.Conns)
 		contentType := req.Header.Get("Accept")
 		marshaler := encoding.GetMarshaler(contentType)
 		writeConnections(w, marshaler, cs)
@@ -220,6 +223,21 @@ func logRequests(client string, count uint64, connectionsCount int, start time.T
 	}
 }
 
+// observeTracerouteDestinations feeds the destinations of the outgoing connections to the traceroute module,
+// they are ignored unless its periodic traceroutes are enabled
+func observeTracerouteDestinations(conns []network.ConnectionStats) {
+	tracker := traceroute.GetDefaultDestinationTracker()
+	if !tracker.IsEnabled() {
+		return
+	}
+	for _, c := range conns {
+		if c.Direction != network.OUTGOING || c.IntraHost {
+			continue
+		}
+		tracker.Observe(net.IP(c.Dest.Bytes()), c.DPort, c.Last.SentBytes+c.Last.RecvBytes)
+	}
+}
+
 func getClientID(req *http.Request) string {
 	var clientID = network.DEBUGCLIENT
 	if rawCID := req.URL.Query().Get("client_id"); rawCID != "" {
@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/api/module"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/utils"
+	"github.com/DataDog/datadog-agent/pkg/network/traceroute"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// onDemandTracerouteTimeout bounds the duration of the traceroutes requested through the API
+const onDemandTracerouteTimeout = time.Minute
+
+// Traceroute is a module tracing the network paths to the top destinations seen by the network tracer
+var Traceroute = module.Factory{
+	Name:             config.TracerouteModule,
+	ConfigNamespaces: []string{"traceroute_config"},
+	Fn: func(cfg *config.Config) (module.Module, error) {
+		trCfg := traceroute.NewConfig()
+		t, err := traceroute.NewTracer(trCfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to start the traceroute module: %w", err)
+		}
+
+		runner := traceroute.NewRunner(trCfg, t, traceroute.GetDefaultDestinationTracker())
+		runner.Start()
+		return &tracerouteModule{tracer: t, runner: runner, config: trCfg}, nil
+	},
+}
+
+var _ module.Module = &tracerouteModule{}
+
+type tracerouteModule struct {
+	tracer    *traceroute.Tracer
+	runner    *traceroute.Runner
+	config    *traceroute.Config
+	lastCheck int64
+	onDemand  uint64
+}
+
+// Register registers endpoints for the module to expose data
+func (t *tracerouteModule) Register(httpMux *module.Router) error {
+	httpMux.HandleFunc("/traceroute/run", utils.WithConcurrencyLimit(t.config.MaxConcurrentTraceroutes, func(w http.ResponseWriter, req *http.Request) {
+		dest, protocol, err := parseTracerouteRequest(req, t.config.Protocol)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error())) //nolint:errcheck
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), onDemandTracerouteTimeout)
+		defer cancel()
+		path, err := t.tracer.Trace(ctx, dest, protocol)
+		if err != nil {
+			log.Errorf("unable to trace the path to %s: %s", dest.IP, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		atomic.AddUint64(&t.onDemand, 1)
+		utils.WriteAsJSON(w, path)
+	})).Methods("GET")
+
+	httpMux.HandleFunc("/traceroute/paths", func(w http.ResponseWriter, req *http.Request) {
+		atomic.StoreInt64(&t.lastCheck, time.Now().Unix())
+		utils.WriteAsJSON(w, t.runner.Flush())
+	}).Methods("GET")
+
+	return nil
+}
+
+// GetStats returns stats for the module
+func (t *tracerouteModule) GetStats() map[string]interface{} {
+	stats := t.runner.GetStats()
+	stats["last_check"] = atomic.LoadInt64(&t.lastCheck)
+	stats["on_demand_traceroutes"] = atomic.LoadUint64(&t.onDemand)
+	return stats
+}
+
+// Close stops the periodic traceroutes
+func (t *tracerouteModule) Close() {
+	t.runner.Stop()
+	t.tracer.Close()
+}
+
+// parseTracerouteRequest reads the destination of an on-demand traceroute from the `host`, `port` and `protocol`
+// query parameters, hostnames are resolved to their first IPv4 address
+func parseTracerouteRequest(req *http.Request, defaultProtocol traceroute.Protocol) (traceroute.Destination, traceroute.Protocol, error) {
+	var dest traceroute.Destination
+	query := req.URL.Query()
+
+	protocol := defaultProtocol
+	if rawProtocol := query.Get("protocol"); rawProtocol != "" {
+		protocol = traceroute.Protocol(strings.ToLower(rawProtocol))
+		if err := protocol.Validate(); err != nil {
+			return dest, protocol, err
+		}
+	}
+	if rawPort := query.Get("port"); rawPort != "" {
+		port, err := strconv.ParseUint(rawPort, 10, 16)
+		if err != nil {
+			return dest, protocol, fmt.Errorf("invalid port `%s`", rawPort)
+		}
+		dest.Port = uint16(port)
+	}
+
+	host := query.Get("host")
+	if host == "" {
+		return dest, protocol, fmt.Errorf("the `host` parameter is required")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		dest.IP = ip.String()
+		return dest, protocol, nil
+	}
+	ips, err := net.DefaultResolver.LookupIP(req.Context(), "ip4", host)
+	if err != nil || len(ips) == 0 {
+		return dest, protocol, fmt.Errorf("unable to resolve `%s`: %v", host, err)
+	}
+	dest.IP = ips[0].String()
+	dest.Hostname = host
+	return dest, protocol, nil
+}
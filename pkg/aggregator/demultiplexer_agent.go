@@ -399,6 +399,9 @@ func (d *AgentDemultiplexer) flushToSerializer(start time.Time, waitForSerialize
 		series,
 		sketches,
 		func(seriesSink metrics.SerieSink, sketchesSink metrics.SketchesSink) {
+			// record the values of the metrics requested by the cluster-agent
+			seriesSink = newReportedMetricsSink(seriesSink)
+
 			// flush DogStatsD pipelines (statsd/time samplers)
 			// ------------------------------------------------
 
@@ -434,6 +437,10 @@ func (d *AgentDemultiplexer) flushToSerializer(start time.Time, waitForSerialize
 				d.aggregator.flushChan <- t
 				<-t.trigger.blockChan
 			}
+
+			if sink, ok := seriesSink.(*reportedMetricsSink); ok {
+				sink.commit()
+			}
 		}, func(serieSource metrics.SerieSource) {
 			sendIterableSeries(d.sharedSerializer, start, serieSource)
 		},
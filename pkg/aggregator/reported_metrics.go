@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+// reportedMetricValueMaxAge is the duration after which the value of a metric context that isn't flushed anymore
+// stops being reported
+const reportedMetricValueMaxAge = 5 * time.Minute
+
+// reportedMetrics holds the last flushed values of the metrics requested by the cluster-agent. They're reported in
+// the cluster checks status of the node, so that the cluster-agent can compute external metrics values when the
+// Datadog API is unavailable.
+var reportedMetrics = struct {
+	sync.Mutex
+	// names is replaced, never modified, so that the flushes can use it without holding the lock
+	names  map[string]struct{}
+	values map[string]types.MetricValue
+}{
+	values: make(map[string]types.MetricValue),
+}
+
+// SetReportedMetrics sets the metrics whose last flushed values are reported to the cluster-agent
+func SetReportedMetrics(names []string) {
+	reportedMetrics.Lock()
+	defer reportedMetrics.Unlock()
+
+	newNames := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		newNames[name] = struct{}{}
+	}
+	reportedMetrics.names = newNames
+
+	for key, value := range reportedMetrics.values {
+		if _, found := newNames[value.Name]; !found {
+			delete(reportedMetrics.values, key)
+		}
+	}
+}
+
+// GetReportedMetricValues returns the last flushed values of the metrics requested by the cluster-agent
+func GetReportedMetricValues() []types.MetricValue {
+	reportedMetrics.Lock()
+	defer reportedMetrics.Unlock()
+
+	minTimestamp := time.Now().Add(-reportedMetricValueMaxAge).Unix()
+	values := make([]types.MetricValue, 0, len(reportedMetrics.values))
+	for key, value := range reportedMetrics.values {
+		if value.Timestamp < minTimestamp {
+			delete(reportedMetrics.values, key)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Name != values[j].Name {
+			return values[i].Name < values[j].Name
+		}
+		return values[i].Host < values[j].Host
+	})
+	return values
+}
+
+// reportedMetricsSink records the values of the requested metrics while appending the series to the flushed sink
+type reportedMetricsSink struct {
+	metrics.SerieSink
+	names  map[string]struct{}
+	mu     sync.Mutex
+	values map[string]types.MetricValue
+}
+
+// newReportedMetricsSink wraps sink to record the values of the requested metrics, it returns sink unchanged when no
+// metric is requested
+func newReportedMetricsSink(sink metrics.SerieSink) metrics.SerieSink {
+	reportedMetrics.Lock()
+	names := reportedMetrics.names
+	reportedMetrics.Unlock()
+
+	if len(names) == 0 {
+		return sink
+	}
+	return &reportedMetricsSink{
+		SerieSink: sink,
+		names:     names,
+		values:    make(map[string]types.MetricValue),
+	}
+}
+
+// Append implements metrics.SerieSink
+func (s *reportedMetricsSink) Append(serie *metrics.Serie) {
+	if _, found := s.names[serie.Name]; found && len(serie.Points) > 0 {
+		point := serie.Points[len(serie.Points)-1]
+		value := types.MetricValue{
+			Name:      serie.Name,
+			Host:      serie.Host,
+			Tags:      make([]string, 0, serie.Tags.Len()),
+			Value:     point.Value,
+			Timestamp: int64(point.Ts),
+		}
+		serie.Tags.ForEach(func(tag string) {
+			value.Tags = append(value.Tags, tag)
+		})
+
+		s.mu.Lock()
+		s.values[serie.Name+"|"+serie.Host+"|"+serie.Tags.Join(",")] = value
+		s.mu.Unlock()
+	}
+
+	s.SerieSink.Append(serie)
+}
+
+// commit stores the values recorded during the flush
+func (s *reportedMetricsSink) commit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reportedMetrics.Lock()
+	defer reportedMetrics.Unlock()
+
+	for key, value := range s.values {
+		if _, found := reportedMetrics.names[value.Name]; found {
+			reportedMetrics.values[key] = value
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/tagset"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportedMetrics(t *testing.T) {
+	defer SetReportedMetrics(nil)
+
+	series := &metrics.Series{}
+	assert.Equal(t, series, newReportedMetricsSink(series), "no metric is requested")
+
+	SetReportedMetrics([]string{"nginx.net.request_per_s", "redis.net.clients"})
+
+	now := time.Now()
+	sink := newReportedMetricsSink(series)
+	sink.Append(&metrics.Serie{
+		Name:   "nginx.net.request_per_s",
+		Host:   "node-1",
+		Tags:   tagset.CompositeTagsFromSlice([]string{"kube_deployment:nginx"}),
+		Points: []metrics.Point{{Ts: float64(now.Unix()), Value: 42}},
+	})
+	sink.Append(&metrics.Serie{
+		Name:   "nginx.net.connections",
+		Host:   "node-1",
+		Tags:   tagset.CompositeTagsFromSlice([]string{"kube_deployment:nginx"}),
+		Points: []metrics.Point{{Ts: float64(now.Unix()), Value: 10}},
+	})
+	sink.Append(&metrics.Serie{
+		Name:   "redis.net.clients",
+		Host:   "node-1",
+		Points: []metrics.Point{{Ts: float64(now.Add(-time.Hour).Unix()), Value: 3}},
+	})
+
+	// The series are flushed whether they're requested or not
+	assert.Len(t, *series, 3)
+	assert.Empty(t, GetReportedMetricValues(), "values are stored once the flush is complete")
+
+	reportedSink, ok := sink.(*reportedMetricsSink)
+	require.True(t, ok)
+	reportedSink.commit()
+
+	// Outdated values are not reported
+	assert.Equal(t, []types.MetricValue{{
+		Name:      "nginx.net.request_per_s",
+		Host:      "node-1",
+		Tags:      []string{"kube_deployment:nginx"},
+		Value:     42,
+		Timestamp: now.Unix(),
+	}}, GetReportedMetricValues())
+
+	// Values of the metrics that aren't requested anymore are dropped
+	SetReportedMetrics([]string{"redis.net.clients"})
+	assert.Empty(t, GetReportedMetricValues())
+}
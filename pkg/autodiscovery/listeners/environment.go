@@ -57,6 +57,7 @@ func (l *EnvironmentListener) createServices() {
 		"eks_fargate":       config.EKSFargate,
 		"cri":               config.Cri,
 		"containerd":        config.Containerd,
+		"podman":            config.Podman,
 		"kube_orchestrator": config.KubeOrchestratorExplorer,
 	}
 
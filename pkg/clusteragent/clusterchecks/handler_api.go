@@ -12,8 +12,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
 )
 
 var errNotReady = errors.New("Startup in progress")
@@ -71,9 +73,15 @@ func (h *Handler) GetConfigs(identifier string) (types.ConfigResponse, error) {
 
 // PostStatus handles status reports from the node agents
 func (h *Handler) PostStatus(identifier, clientIP string, status types.NodeStatus) (types.StatusResponse, error) {
+	// The metric values are only used by the external metrics provider, not kept in the dispatching state
+	nodeMetrics := nodemetrics.GetStore()
+	nodeMetrics.Update(identifier, status.Metrics, time.Now())
+	status.Metrics = nil
+
 	upToDate, err := h.dispatcher.processNodeStatus(identifier, clientIP, status)
 	response := types.StatusResponse{
-		IsUpToDate: upToDate,
+		IsUpToDate:       upToDate,
+		RequestedMetrics: nodeMetrics.RequestedMetrics(),
 	}
 	return response, err
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/api"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
 	"github.com/DataDog/datadog-agent/pkg/util/testutil"
 )
 
@@ -305,3 +306,27 @@ func TestHandlerRun(t *testing.T) {
 		return ac.AssertNumberOfCalls(dummyT, "RemoveScheduler", 2)
 	})
 }
+
+func TestPostStatusNodeMetrics(t *testing.T) {
+	nodeMetrics := nodemetrics.GetStore()
+	nodeMetrics.SetRequestedMetrics([]string{"nginx.net.request_per_s"})
+	defer nodeMetrics.SetRequestedMetrics(nil)
+
+	h := &Handler{dispatcher: newDispatcher()}
+	now := time.Now()
+	response, err := h.PostStatus("node-1", "10.0.0.1", types.NodeStatus{
+		Metrics: []types.MetricValue{{Name: "nginx.net.request_per_s", Host: "node-1", Value: 42, Timestamp: now.Unix()}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"nginx.net.request_per_s"}, response.RequestedMetrics)
+	defer nodeMetrics.Update("node-1", nil, now)
+
+	// The values are stored for the external metrics provider, not in the dispatching state
+	value, _, err := nodeMetrics.Query(nodemetrics.Query{Aggregation: nodemetrics.AggregationSum, Metric: "nginx.net.request_per_s"}, now, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, 42.0, value)
+
+	node, found := h.dispatcher.store.getNodeStore("node-1")
+	assert.True(t, found)
+	assert.Empty(t, node.lastStatus.Metrics)
+}
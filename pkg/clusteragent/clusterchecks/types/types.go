@@ -12,11 +12,24 @@ import (
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
 	LastChange int64 `json:"last_change"`
+	// Metrics holds the last values of the metrics requested by the DCA
+	Metrics []MetricValue `json:"metrics,omitempty"`
 }
 
 // StatusResponse holds the DCA response for a status report
 type StatusResponse struct {
 	IsUpToDate bool `json:"isuptodate"`
+	// RequestedMetrics lists the metrics whose last values the node-agent should report in its next status
+	RequestedMetrics []string `json:"requested_metrics,omitempty"`
+}
+
+// MetricValue holds the last value of a metric context flushed by the node-agent aggregator
+type MetricValue struct {
+	Name      string   `json:"name"`
+	Host      string   `json:"host,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Value     float64  `json:"value"`
+	Timestamp int64    `json:"ts"`
 }
 
 // RebalanceResponse holds the DCA response for a rebalancing request
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -31,6 +32,7 @@ type MetricsRetriever struct {
 	metricsMaxAge int64
 	processor     autoscalers.ProcessorInterface
 	store         *DatadogMetricsInternalStore
+	nodeMetrics   *nodemetrics.Store
 	isLeader      func() bool
 }
 
@@ -41,6 +43,7 @@ func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscale
 		metricsMaxAge: metricsMaxAge,
 		processor:     processor,
 		store:         store,
+		nodeMetrics:   nodemetrics.GetStore(),
 		isLeader:      isLeader,
 	}, nil
 }
@@ -65,6 +68,8 @@ func (mr *MetricsRetriever) Run(stopCh <-chan struct{}) {
 func (mr *MetricsRetriever) retrieveMetricsValues() {
 	// We only update active DatadogMetrics
 	datadogMetrics := mr.store.GetFiltered(func(datadogMetric model.DatadogMetricInternal) bool { return datadogMetric.Active })
+	// Request the node agents to report the metrics of the local queries, for the next refreshes
+	mr.nodeMetrics.SetRequestedMetrics(getLocalQueryMetrics(datadogMetrics))
 	if len(datadogMetrics) == 0 {
 		log.Debugf("No active DatadogMetric, nothing to refresh")
 		return
@@ -81,8 +86,11 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 		log.Errorf("Unable to fetch external metrics: %v", err)
 	}
 
-	// Update store with current results
+	// Query fallbacks for DatadogMetrics that did not get a usable result
 	currentTime := time.Now().UTC()
+	fallbackResults := mr.queryFallbacks(datadogMetrics, results, globalError, currentTime)
+
+	// Update store with current results
 	for _, datadogMetric := range datadogMetrics {
 		datadogMetricFromStore := mr.store.LockRead(datadogMetric.ID, false)
 		if datadogMetricFromStore == nil {
@@ -92,44 +100,145 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 		}
 
 		query := datadogMetric.Query()
-		if queryResult, found := results[query]; found {
+		queryResult, found := results[query]
+		if found {
 			log.Debugf("QueryResult from DD for %q: %v", query, queryResult)
+		}
+		mr.applyQueryResult(datadogMetricFromStore, query, queryResult, found, globalError, currentTime)
+
+		if datadogMetricFromStore.Valid {
+			datadogMetricFromStore.SetPrimarySource()
+		} else if datadogMetricFromStore.HasFallback() {
+			mr.applyFallbacks(datadogMetricFromStore, fallbackResults, currentTime)
+		}
+
+		mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
+	}
+}
+
+// applyQueryResult updates the DatadogMetric value and validity from a query result
+func (mr *MetricsRetriever) applyQueryResult(datadogMetric *model.DatadogMetricInternal, query string, queryResult autoscalers.Point, found, globalError bool, currentTime time.Time) {
+	if found {
+		if queryResult.Valid {
+			datadogMetric.Value = queryResult.Value
 
-			if queryResult.Valid {
-				datadogMetricFromStore.Value = queryResult.Value
-
-				// If we get a valid but old metric, flag it as invalid
-				maxAge := datadogMetric.MaxAge
-				if maxAge == 0 {
-					maxAge = time.Duration(mr.metricsMaxAge) * time.Second
-				}
-
-				if time.Duration(currentTime.Unix()-queryResult.Timestamp)*time.Second <= maxAge {
-					datadogMetricFromStore.Valid = true
-					datadogMetricFromStore.Error = nil
-					datadogMetricFromStore.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
-				} else {
-					datadogMetricFromStore.Valid = false
-					datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
-					datadogMetricFromStore.UpdateTime = currentTime
-				}
+			// If we get a valid but old metric, flag it as invalid
+			if time.Duration(currentTime.Unix()-queryResult.Timestamp)*time.Second <= mr.maxAge(datadogMetric) {
+				datadogMetric.Valid = true
+				datadogMetric.Error = nil
+				datadogMetric.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
 			} else {
-				datadogMetricFromStore.Valid = false
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricBackendErrorMessage, query)
-				datadogMetricFromStore.UpdateTime = currentTime
+				datadogMetric.Valid = false
+				datadogMetric.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
+				datadogMetric.UpdateTime = currentTime
 			}
 		} else {
-			datadogMetricFromStore.Valid = false
-			if globalError {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricGlobalErrorMessage)
-			} else {
-				datadogMetricFromStore.Error = fmt.Errorf(invalidMetricNoDataErrorMessage, query)
+			datadogMetric.Valid = false
+			datadogMetric.Error = fmt.Errorf(invalidMetricBackendErrorMessage, query)
+			datadogMetric.UpdateTime = currentTime
+		}
+	} else {
+		datadogMetric.Valid = false
+		if globalError {
+			datadogMetric.Error = fmt.Errorf(invalidMetricGlobalErrorMessage)
+		} else {
+			datadogMetric.Error = fmt.Errorf(invalidMetricNoDataErrorMessage, query)
+		}
+		datadogMetric.UpdateTime = currentTime
+	}
+}
+
+// maxAge returns the maximum age of the values of a DatadogMetric
+func (mr *MetricsRetriever) maxAge(datadogMetric *model.DatadogMetricInternal) time.Duration {
+	if datadogMetric.MaxAge > 0 {
+		return datadogMetric.MaxAge
+	}
+	return time.Duration(mr.metricsMaxAge) * time.Second
+}
+
+// queryFallbacks queries the fallback queries of DatadogMetrics for which the main query did not return a usable result
+func (mr *MetricsRetriever) queryFallbacks(datadogMetrics []model.DatadogMetricInternal, results map[string]autoscalers.Point, globalError bool, currentTime time.Time) map[string]autoscalers.Point {
+	var queries []string
+	unique := make(map[string]struct{})
+	for _, datadogMetric := range datadogMetrics {
+		fallbackQuery := datadogMetric.FallbackQuery()
+		if fallbackQuery == "" {
+			continue
+		}
+
+		// datadogMetric is a copy, we can safely use it to check the result of the main query
+		query := datadogMetric.Query()
+		queryResult, found := results[query]
+		mr.applyQueryResult(&datadogMetric, query, queryResult, found, globalError, currentTime)
+		if datadogMetric.Valid {
+			continue
+		}
+
+		if _, found := unique[fallbackQuery]; !found {
+			unique[fallbackQuery] = struct{}{}
+			queries = append(queries, fallbackQuery)
+		}
+	}
+
+	if len(queries) == 0 {
+		return nil
+	}
+
+	log.Debugf("Querying %d fallback queries", len(queries))
+	fallbackResults, err := mr.processor.QueryExternalMetric(queries)
+	if err != nil {
+		log.Errorf("Unable to fetch fallback external metrics: %v", err)
+	}
+
+	return fallbackResults
+}
+
+// applyFallbacks tries the fallback sources in order: fallback query, last known good value, then local query
+// computed from the metrics reported by the node agents.
+// If no fallback source is usable, the DatadogMetric keeps the error from the main query.
+func (mr *MetricsRetriever) applyFallbacks(datadogMetric *model.DatadogMetricInternal, fallbackResults map[string]autoscalers.Point, currentTime time.Time) {
+	if fallbackQuery := datadogMetric.FallbackQuery(); fallbackQuery != "" {
+		if queryResult, found := fallbackResults[fallbackQuery]; found {
+			log.Debugf("Fallback QueryResult from DD for %q: %v", fallbackQuery, queryResult)
+
+			fallbackMetric := *datadogMetric
+			mr.applyQueryResult(&fallbackMetric, fallbackQuery, queryResult, true, false, currentTime)
+			if fallbackMetric.Valid {
+				*datadogMetric = fallbackMetric
+				datadogMetric.SetFallbackQuerySource()
+				return
 			}
-			datadogMetricFromStore.UpdateTime = currentTime
 		}
+	}
+
+	if datadogMetric.UseLastKnownGood(currentTime) {
+		log.Debugf("Using last known good value for DatadogMetric: %s", datadogMetric.ID)
+		return
+	}
+
+	if localQuery := datadogMetric.LocalQuery(); localQuery != nil {
+		value, updateTime, err := mr.nodeMetrics.Query(*localQuery, currentTime, mr.maxAge(datadogMetric))
+		if err == nil {
+			log.Debugf("Using local value for DatadogMetric: %s, query %q: %v", datadogMetric.ID, localQuery, value)
+			datadogMetric.SetLocalSource(value, updateTime)
+			return
+		}
+		log.Debugf("Unable to compute local value for DatadogMetric: %s: %v", datadogMetric.ID, err)
+	}
 
-		mr.store.UnlockSet(datadogMetric.ID, *datadogMetricFromStore, metricRetrieverStoreID)
+	datadogMetric.FallbackSource = ""
+}
+
+// getLocalQueryMetrics returns the metrics used by the local queries of DatadogMetrics
+func getLocalQueryMetrics(datadogMetrics []model.DatadogMetricInternal) []string {
+	var names []string
+	for _, datadogMetric := range datadogMetrics {
+		if localQuery := datadogMetric.LocalQuery(); localQuery != nil {
+			names = append(names, localQuery.Metric)
+		}
 	}
+
+	return names
 }
 
 func getUniqueQueries(datadogMetrics []model.DatadogMetricInternal) []string {
@@ -13,8 +13,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/custommetrics"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
 
 	"github.com/stretchr/testify/assert"
@@ -630,3 +632,140 @@ func TestRetrieveMetricsNotActive(t *testing.T) {
 		})
 	}
 }
+
+func TestRetrieveMetricsFallback(t *testing.T) {
+	defaultTestTime := time.Now().Add(time.Duration(-1) * time.Second).UTC().Truncate(time.Second)
+	lastKnownGoodTime := time.Now().Add(time.Duration(-5) * time.Minute).UTC().Truncate(time.Second)
+
+	store := NewDatadogMetricsInternalStore()
+
+	// Main query returns invalid data, fallback query is valid
+	metric0 := model.DatadogMetricInternal{
+		ID:         "metric0",
+		Active:     true,
+		UpdateTime: lastKnownGoodTime,
+		Valid:      true,
+	}
+	metric0.SetQueries("query-metric0")
+	metric0.SetFallbackQuery("fallback-query-metric0")
+	store.Set(metric0.ID, metric0, "utest")
+
+	// Main and fallback queries return no data, last known good value is recent enough
+	metric1 := model.DatadogMetricInternal{
+		ID:                 "metric1",
+		Active:             true,
+		UpdateTime:         lastKnownGoodTime,
+		Valid:              true,
+		Value:              5.0,
+		Fallback:           model.Fallback{LastKnownGoodMaxAge: 10 * time.Minute},
+		LastKnownGoodValue: 5.0,
+		LastKnownGoodTime:  lastKnownGoodTime,
+	}
+	metric1.SetQueries("query-metric1")
+	metric1.SetFallbackQuery("fallback-query-metric1")
+	store.Set(metric1.ID, metric1, "utest")
+
+	// Main query returns no data, last known good value is too old
+	metric2 := model.DatadogMetricInternal{
+		ID:                 "metric2",
+		Active:             true,
+		UpdateTime:         lastKnownGoodTime,
+		Valid:              true,
+		Value:              6.0,
+		Fallback:           model.Fallback{LastKnownGoodMaxAge: time.Minute},
+		LastKnownGoodValue: 6.0,
+		LastKnownGoodTime:  lastKnownGoodTime,
+	}
+	metric2.SetQueries("query-metric2")
+	store.Set(metric2.ID, metric2, "utest")
+
+	// Main query returns no data, last known good value is too old, local query is computed from node agents' values
+	metric3 := model.DatadogMetricInternal{
+		ID:                 "metric3",
+		Active:             true,
+		UpdateTime:         lastKnownGoodTime,
+		Valid:              true,
+		Value:              7.0,
+		Fallback:           model.Fallback{LastKnownGoodMaxAge: time.Minute},
+		LastKnownGoodValue: 7.0,
+		LastKnownGoodTime:  lastKnownGoodTime,
+	}
+	metric3.SetQueries("query-metric3")
+	metric3.SetLocalQuery(nodemetrics.Query{Aggregation: nodemetrics.AggregationAvg, Metric: "metric3", Tags: []string{"app:foo"}})
+	store.Set(metric3.ID, metric3, "utest")
+
+	// Main query returns no data, node agents did not report recent values
+	metric4 := model.DatadogMetricInternal{
+		ID:         "metric4",
+		Active:     true,
+		UpdateTime: lastKnownGoodTime,
+		Valid:      true,
+	}
+	metric4.SetQueries("query-metric4")
+	metric4.SetLocalQuery(nodemetrics.Query{Aggregation: nodemetrics.AggregationAvg, Metric: "metric4"})
+	store.Set(metric4.ID, metric4, "utest")
+
+	nodeMetrics := nodemetrics.NewStore()
+	nodeMetrics.Update("node-1", []types.MetricValue{
+		{Name: "metric3", Tags: []string{"app:foo"}, Value: 30.0, Timestamp: defaultTestTime.Unix()},
+		{Name: "metric4", Value: 40.0, Timestamp: lastKnownGoodTime.Unix()},
+	}, defaultTestTime)
+	nodeMetrics.Update("node-2", []types.MetricValue{
+		{Name: "metric3", Tags: []string{"app:foo"}, Value: 50.0, Timestamp: defaultTestTime.Unix()},
+		{Name: "metric3", Tags: []string{"app:bar"}, Value: 1000.0, Timestamp: defaultTestTime.Unix()},
+	}, defaultTestTime)
+
+	mockedProcessor := mockedProcessor{
+		points: map[string]autoscalers.Point{
+			"query-metric0": {
+				Value:     10.0,
+				Timestamp: defaultTestTime.Unix(),
+				Valid:     false,
+			},
+			"fallback-query-metric0": {
+				Value:     20.0,
+				Timestamp: defaultTestTime.Unix(),
+				Valid:     true,
+			},
+		},
+	}
+	metricsRetriever, err := NewMetricsRetriever(0, 30, &mockedProcessor, getIsLeaderFunction(true), &store)
+	assert.Nil(t, err)
+	metricsRetriever.nodeMetrics = nodeMetrics
+	metricsRetriever.retrieveMetricsValues()
+
+	assert.Equal(t, []string{"metric3", "metric4"}, nodeMetrics.RequestedMetrics())
+
+	datadogMetric := store.Get("metric0")
+	assert.True(t, datadogMetric.Valid)
+	assert.Nil(t, datadogMetric.Error)
+	assert.Equal(t, 20.0, datadogMetric.Value)
+	assert.Equal(t, defaultTestTime, datadogMetric.UpdateTime)
+	assert.Equal(t, model.DatadogMetricSourceFallbackQuery, datadogMetric.FallbackSource)
+
+	datadogMetric = store.Get("metric1")
+	assert.True(t, datadogMetric.Valid)
+	assert.Nil(t, datadogMetric.Error)
+	assert.Equal(t, 5.0, datadogMetric.Value)
+	assert.True(t, datadogMetric.UpdateTime.After(defaultTestTime))
+	assert.Equal(t, lastKnownGoodTime, datadogMetric.LastKnownGoodTime)
+	assert.Equal(t, model.DatadogMetricSourceLastKnownGood, datadogMetric.FallbackSource)
+
+	datadogMetric = store.Get("metric2")
+	assert.False(t, datadogMetric.Valid)
+	assert.Equal(t, fmt.Errorf(invalidMetricNoDataErrorMessage, "query-metric2"), datadogMetric.Error)
+	assert.Equal(t, "", datadogMetric.FallbackSource)
+
+	datadogMetric = store.Get("metric3")
+	assert.True(t, datadogMetric.Valid)
+	assert.Nil(t, datadogMetric.Error)
+	assert.Equal(t, 40.0, datadogMetric.Value)
+	assert.Equal(t, defaultTestTime, datadogMetric.UpdateTime)
+	assert.Equal(t, lastKnownGoodTime, datadogMetric.LastKnownGoodTime)
+	assert.Equal(t, model.DatadogMetricSourceLocal, datadogMetric.FallbackSource)
+
+	datadogMetric = store.Get("metric4")
+	assert.False(t, datadogMetric.Valid)
+	assert.Equal(t, fmt.Errorf(invalidMetricNoDataErrorMessage, "query-metric4"), datadogMetric.Error)
+	assert.Equal(t, "", datadogMetric.FallbackSource)
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 
@@ -42,6 +43,10 @@ type DatadogMetricInternal struct {
 	UpdateTime           time.Time
 	Error                error
 	MaxAge               time.Duration
+	Fallback             Fallback
+	FallbackSource       string
+	LastKnownGoodValue   float64
+	LastKnownGoodTime    time.Time
 }
 
 // NewDatadogMetricInternal returns a `DatadogMetricInternal` object from a `DatadogMetric` CRD Object
@@ -93,6 +98,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 		value = 0
 	}
 	internal.Value = value
+	internal.restoreFallback(datadogMetric)
 
 	return internal
 }
@@ -167,6 +173,7 @@ func (d *DatadogMetricInternal) BuildStatus(currentStatus *datadoghq.DatadogMetr
 		datadoghq.DatadogMetricConditionTypeValid:   nil,
 		datadoghq.DatadogMetricConditionTypeUpdated: nil,
 		datadoghq.DatadogMetricConditionTypeError:   nil,
+		DatadogMetricConditionTypeFallback:          nil,
 	}
 
 	if currentStatus != nil {
@@ -187,9 +194,14 @@ func (d *DatadogMetricInternal) BuildStatus(currentStatus *datadoghq.DatadogMetr
 		errorCondition.Message = d.Error.Error()
 	}
 
+	conditions := []datadoghq.DatadogMetricCondition{activeCondition, validCondition, updatedCondition, errorCondition}
+	if fallbackCondition := d.fallbackCondition(updateTime, existingConditions[DatadogMetricConditionTypeFallback]); fallbackCondition != nil {
+		conditions = append(conditions, *fallbackCondition)
+	}
+
 	newStatus := datadoghq.DatadogMetricStatus{
 		Value:                formatDatadogMetricValue(d.Value),
-		Conditions:           []datadoghq.DatadogMetricCondition{activeCondition, validCondition, updatedCondition, errorCondition},
+		Conditions:           conditions,
 		AutoscalerReferences: d.AutoscalerReferences,
 	}
 
@@ -264,3 +276,14 @@ func (d *DatadogMetricInternal) SetQueries(q string) {
 func (d *DatadogMetricInternal) SetQuery(q string) {
 	d.query = q
 }
+
+// SetFallbackQuery is only used for testing in other packages
+func (d *DatadogMetricInternal) SetFallbackQuery(q string) {
+	d.Fallback.query = q
+	d.Fallback.resolvedQuery = &q
+}
+
+// SetLocalQuery is only used for testing in other packages
+func (d *DatadogMetricInternal) SetLocalQuery(q nodemetrics.Query) {
+	d.Fallback.localQuery = &q
+}
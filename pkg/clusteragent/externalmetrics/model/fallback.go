@@ -0,0 +1,210 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations used to configure the fallback chain of a `DatadogMetric`
+const (
+	// FallbackQueryAnnotation holds a secondary query used when the main query fails
+	FallbackQueryAnnotation = "external-metrics.datadoghq.com/fallback-query"
+	// FallbackLastKnownGoodMaxAgeAnnotation enables serving the last valid value (for at most the given duration) when all queries fail
+	FallbackLastKnownGoodMaxAgeAnnotation = "external-metrics.datadoghq.com/fallback-last-known-good-max-age"
+	// FallbackLocalQueryAnnotation holds a query computed from the metrics reported by the node agents, used when no other source is usable
+	FallbackLocalQueryAnnotation = "external-metrics.datadoghq.com/fallback-local-query"
+)
+
+// Sources that can be reported when the value of a `DatadogMetric` does not come from its main query
+const (
+	DatadogMetricSourceFallbackQuery = "FallbackQuery"
+	DatadogMetricSourceLastKnownGood = "LastKnownGood"
+	DatadogMetricSourceLocal         = "Local"
+)
+
+// DatadogMetricConditionTypeFallback is set to True when the value of the `DatadogMetric` comes from a fallback source
+const DatadogMetricConditionTypeFallback datadoghq.DatadogMetricConditionType = "Fallback"
+
+// Fallback holds the fallback chain configuration of a `DatadogMetric`
+type Fallback struct {
+	query               string
+	resolvedQuery       *string
+	LastKnownGoodMaxAge time.Duration
+	localQuery          *nodemetrics.Query
+}
+
+// HasFallback returns true if at least one fallback source is configured
+func (d *DatadogMetricInternal) HasFallback() bool {
+	return d.Fallback.query != "" || d.Fallback.LastKnownGoodMaxAge > 0 || d.Fallback.localQuery != nil
+}
+
+// FallbackQuery returns the secondary query that should be used to fetch metrics, empty if none
+func (d *DatadogMetricInternal) FallbackQuery() string {
+	if d.Fallback.resolvedQuery != nil {
+		return *d.Fallback.resolvedQuery
+	}
+	return d.Fallback.query
+}
+
+// LocalQuery returns the query computed from the metrics reported by the node agents, nil if none
+func (d *DatadogMetricInternal) LocalQuery() *nodemetrics.Query {
+	return d.Fallback.localQuery
+}
+
+// UpdateFallbackFrom updates the fallback configuration from `DatadogMetric` annotations
+func (d *DatadogMetricInternal) UpdateFallbackFrom(objectMeta metav1.ObjectMeta) {
+	d.Fallback = parseFallback(d.ID, objectMeta.Annotations, d.Fallback)
+	if d.Fallback.LastKnownGoodMaxAge == 0 {
+		d.LastKnownGoodValue = 0
+		d.LastKnownGoodTime = time.Time{}
+	}
+	if !d.HasFallback() {
+		d.FallbackSource = ""
+	}
+}
+
+// SetPrimarySource records a valid value coming from the main query
+func (d *DatadogMetricInternal) SetPrimarySource() {
+	d.FallbackSource = ""
+	d.setLastKnownGood()
+}
+
+// SetFallbackQuerySource records a valid value coming from the fallback query
+func (d *DatadogMetricInternal) SetFallbackQuerySource() {
+	d.FallbackSource = DatadogMetricSourceFallbackQuery
+	d.setLastKnownGood()
+}
+
+// UseLastKnownGood keeps serving the last valid value if it's recent enough.
+// Returns false if the last known good value cannot be used.
+func (d *DatadogMetricInternal) UseLastKnownGood(currentTime time.Time) bool {
+	if d.Fallback.LastKnownGoodMaxAge == 0 || d.LastKnownGoodTime.IsZero() {
+		return false
+	}
+
+	if currentTime.Sub(d.LastKnownGoodTime) > d.Fallback.LastKnownGoodMaxAge {
+		return false
+	}
+
+	d.FallbackSource = DatadogMetricSourceLastKnownGood
+	d.Value = d.LastKnownGoodValue
+	d.Valid = true
+	d.Error = nil
+	d.UpdateTime = currentTime
+	return true
+}
+
+// SetLocalSource records a valid value computed from the metrics reported by the node agents.
+// It's not kept as last known good value as it's only an approximation of the main query.
+func (d *DatadogMetricInternal) SetLocalSource(value float64, updateTime time.Time) {
+	d.FallbackSource = DatadogMetricSourceLocal
+	d.Value = value
+	d.Valid = true
+	d.Error = nil
+	d.UpdateTime = updateTime
+}
+
+// fallbackCondition returns the `Fallback` condition if it should be part of the status
+func (d *DatadogMetricInternal) fallbackCondition(updateTime metav1.Time, prevCondition *datadoghq.DatadogMetricCondition) *datadoghq.DatadogMetricCondition {
+	if !d.HasFallback() && prevCondition == nil {
+		return nil
+	}
+
+	condition := d.newCondition(d.FallbackSource != "", updateTime, DatadogMetricConditionTypeFallback, prevCondition)
+	if d.FallbackSource != "" {
+		condition.Reason = d.FallbackSource
+		condition.Message = fmt.Sprintf("Value is served from %s source", d.FallbackSource)
+	}
+
+	return &condition
+}
+
+// restoreFallback restores the fallback state from the `DatadogMetric` annotations and status
+func (d *DatadogMetricInternal) restoreFallback(datadogMetric datadoghq.DatadogMetric) {
+	d.Fallback = parseFallback(d.ID, datadogMetric.Annotations, Fallback{})
+
+	for _, condition := range datadogMetric.Status.Conditions {
+		if condition.Type == DatadogMetricConditionTypeFallback && condition.Status == corev1.ConditionTrue {
+			d.FallbackSource = condition.Reason
+		}
+	}
+
+	// We cannot know when the last known good value was retrieved if it came from the last known good source,
+	// and values computed locally are not kept as last known good values
+	if d.Valid && d.FallbackSource != DatadogMetricSourceLastKnownGood && d.FallbackSource != DatadogMetricSourceLocal {
+		d.setLastKnownGood()
+	}
+}
+
+func (d *DatadogMetricInternal) setLastKnownGood() {
+	if d.Fallback.LastKnownGoodMaxAge > 0 {
+		d.LastKnownGoodValue = d.Value
+		d.LastKnownGoodTime = d.UpdateTime
+	}
+}
+
+func parseFallback(id string, annotations map[string]string, current Fallback) Fallback {
+	fallback := Fallback{}
+
+	if query := annotations[FallbackQueryAnnotation]; query != "" {
+		fallback.query = query
+		if query == current.query && current.resolvedQuery != nil {
+			fallback.resolvedQuery = current.resolvedQuery
+		} else {
+			resolvedQuery, err := resolveQuery(query)
+			if err != nil {
+				log.Errorf("Unable to resolve fallback query %q for DatadogMetric %s: %v", query, id, err)
+				fallback.query = ""
+			} else if resolvedQuery != "" {
+				fallback.resolvedQuery = &resolvedQuery
+			}
+		}
+	}
+
+	if maxAge := annotations[FallbackLastKnownGoodMaxAgeAnnotation]; maxAge != "" {
+		duration, err := time.ParseDuration(maxAge)
+		if err != nil || duration < 0 {
+			log.Errorf("Invalid value %q for annotation %s on DatadogMetric %s, last known good fallback disabled", maxAge, FallbackLastKnownGoodMaxAgeAnnotation, id)
+		} else {
+			fallback.LastKnownGoodMaxAge = duration
+		}
+	}
+
+	if localQuery := annotations[FallbackLocalQueryAnnotation]; localQuery != "" {
+		query, err := parseLocalQuery(localQuery)
+		if err != nil {
+			log.Errorf("Unable to parse local query %q for DatadogMetric %s: %v", localQuery, id, err)
+		} else {
+			fallback.localQuery = &query
+		}
+	}
+
+	return fallback
+}
+
+// parseLocalQuery parses a local query, resolving its template variables like the other queries
+func parseLocalQuery(localQuery string) (nodemetrics.Query, error) {
+	resolvedQuery, err := resolveQuery(localQuery)
+	if err != nil {
+		return nodemetrics.Query{}, err
+	}
+	if resolvedQuery != "" {
+		localQuery = resolvedQuery
+	}
+	return nodemetrics.ParseQuery(localQuery)
+}
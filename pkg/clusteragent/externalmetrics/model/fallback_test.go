@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/nodemetrics"
+	datadoghq "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDatadogMetricInternal_UpdateFallbackFrom(t *testing.T) {
+	templatedTags = templatedTagsStub
+
+	ddm := DatadogMetricInternal{ID: "default/dd-metric-0"}
+	ddm.UpdateFallbackFrom(metav1.ObjectMeta{})
+	assert.False(t, ddm.HasFallback())
+
+	ddm.UpdateFallbackFrom(metav1.ObjectMeta{
+		Annotations: map[string]string{
+			FallbackQueryAnnotation:               templatedQuery,
+			FallbackLastKnownGoodMaxAgeAnnotation: "10m",
+		},
+	})
+	assert.True(t, ddm.HasFallback())
+	assert.Equal(t, resolvedQuery, ddm.FallbackQuery())
+	assert.Equal(t, 10*time.Minute, ddm.Fallback.LastKnownGoodMaxAge)
+
+	ddm.UpdateFallbackFrom(metav1.ObjectMeta{
+		Annotations: map[string]string{
+			FallbackQueryAnnotation:               invalidTemplatedQuery,
+			FallbackLastKnownGoodMaxAgeAnnotation: "foo",
+		},
+	})
+	assert.False(t, ddm.HasFallback())
+	assert.Equal(t, "", ddm.FallbackQuery())
+
+	ddm.UpdateFallbackFrom(metav1.ObjectMeta{
+		Annotations: map[string]string{
+			FallbackLocalQueryAnnotation: "avg:nginx.net.request_per_s{kube_cluster_name:%%tag_kube_cluster_name%%}",
+		},
+	})
+	assert.True(t, ddm.HasFallback())
+	assert.Equal(t, &nodemetrics.Query{
+		Aggregation: nodemetrics.AggregationAvg,
+		Metric:      "nginx.net.request_per_s",
+		Tags:        []string{"kube_cluster_name:cluster-foo"},
+	}, ddm.LocalQuery())
+
+	ddm.UpdateFallbackFrom(metav1.ObjectMeta{
+		Annotations: map[string]string{
+			FallbackLocalQueryAnnotation: "p95:nginx.net.request_per_s{*}",
+		},
+	})
+	assert.False(t, ddm.HasFallback())
+	assert.Nil(t, ddm.LocalQuery())
+}
+
+func TestDatadogMetricInternal_UseLastKnownGood(t *testing.T) {
+	now := time.Now().UTC()
+	ddm := DatadogMetricInternal{
+		ID:         "default/dd-metric-0",
+		Valid:      true,
+		Value:      42,
+		UpdateTime: now.Add(-5 * time.Minute),
+		Fallback:   Fallback{LastKnownGoodMaxAge: 10 * time.Minute},
+	}
+	ddm.SetPrimarySource()
+
+	// Outdated value from backend
+	ddm.Valid = false
+	ddm.Value = 10
+	assert.True(t, ddm.UseLastKnownGood(now))
+	assert.True(t, ddm.Valid)
+	assert.Nil(t, ddm.Error)
+	assert.Equal(t, 42.0, ddm.Value)
+	assert.Equal(t, DatadogMetricSourceLastKnownGood, ddm.FallbackSource)
+
+	// Last known good value is too old
+	ddm.Valid = false
+	assert.False(t, ddm.UseLastKnownGood(now.Add(6*time.Minute)))
+	assert.False(t, ddm.Valid)
+}
+
+func TestDatadogMetricInternal_BuildStatusFallback(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	ddm := DatadogMetricInternal{
+		ID:             "default/dd-metric-0",
+		Active:         true,
+		Valid:          true,
+		Value:          42,
+		UpdateTime:     now,
+		Fallback:       Fallback{LastKnownGoodMaxAge: 10 * time.Minute},
+		FallbackSource: DatadogMetricSourceLastKnownGood,
+	}
+
+	status := ddm.BuildStatus(nil)
+	assert.Len(t, status.Conditions, 5)
+	fallbackCondition := status.Conditions[4]
+	assert.Equal(t, DatadogMetricConditionTypeFallback, fallbackCondition.Type)
+	assert.Equal(t, corev1.ConditionTrue, fallbackCondition.Status)
+	assert.Equal(t, DatadogMetricSourceLastKnownGood, fallbackCondition.Reason)
+
+	// Restoring from the generated status keeps the source but not the last known good value
+	restored := NewDatadogMetricInternal(ddm.ID, datadoghq.DatadogMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{FallbackLastKnownGoodMaxAgeAnnotation: "10m"},
+		},
+		Status: *status,
+	})
+	assert.Equal(t, DatadogMetricSourceLastKnownGood, restored.FallbackSource)
+	assert.True(t, restored.LastKnownGoodTime.IsZero())
+
+	// No fallback configured, no condition
+	ddm = DatadogMetricInternal{ID: "default/dd-metric-1", UpdateTime: now}
+	assert.Len(t, ddm.BuildStatus(nil).Conditions, 4)
+}
@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nodemetrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+// Aggregations supported by local queries
+const (
+	AggregationAvg = "avg"
+	AggregationSum = "sum"
+	AggregationMin = "min"
+	AggregationMax = "max"
+)
+
+// Query is a query computed from the values reported by the node agents, formatted as
+// `<aggregation>:<metric>{<tag>,<tag>}`, for instance `avg:nginx.net.request_per_s{kube_deployment:nginx}`.
+// The aggregation is applied to the last values of the metric contexts having all the tags.
+type Query struct {
+	Aggregation string
+	Metric      string
+	Tags        []string
+}
+
+// ParseQuery parses a local query
+func ParseQuery(query string) (Query, error) {
+	aggregation, rest, found := cut(strings.TrimSpace(query), ":")
+	if !found {
+		return Query{}, fmt.Errorf("invalid query %q: missing aggregation", query)
+	}
+	switch aggregation {
+	case AggregationAvg, AggregationSum, AggregationMin, AggregationMax:
+	default:
+		return Query{}, fmt.Errorf("invalid query %q: unsupported aggregation %q", query, aggregation)
+	}
+
+	parsed := Query{Aggregation: aggregation, Metric: rest}
+	if metric, scope, found := cut(rest, "{"); found {
+		if !strings.HasSuffix(scope, "}") {
+			return Query{}, fmt.Errorf("invalid query %q: unterminated scope", query)
+		}
+		parsed.Metric = metric
+
+		for _, tag := range strings.Split(strings.TrimSuffix(scope, "}"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+				parsed.Tags = append(parsed.Tags, tag)
+			}
+		}
+	}
+
+	parsed.Metric = strings.TrimSpace(parsed.Metric)
+	if parsed.Metric == "" || strings.ContainsAny(parsed.Metric, " (){}") {
+		return Query{}, fmt.Errorf("invalid query %q: invalid metric name", query)
+	}
+	return parsed, nil
+}
+
+// String returns the query formatted as it's parsed
+func (q Query) String() string {
+	return q.Aggregation + ":" + q.Metric + "{" + strings.Join(q.Tags, ",") + "}"
+}
+
+// matches returns true if the value is a value of the metric with all the tags of the query, the host being matched
+// as a host tag
+func (q Query) matches(value types.MetricValue) bool {
+	if value.Name != q.Metric {
+		return false
+	}
+
+	for _, tag := range q.Tags {
+		if tag == "host:"+value.Host {
+			continue
+		}
+
+		found := false
+		for _, valueTag := range value.Tags {
+			if valueTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (q Query) aggregate(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		switch q.Aggregation {
+		case AggregationMin:
+			if value < result {
+				result = value
+			}
+		case AggregationMax:
+			if value > result {
+				result = value
+			}
+		default:
+			result += value
+		}
+	}
+
+	if q.Aggregation == AggregationAvg {
+		result /= float64(len(values))
+	}
+	return result
+}
+
+// cut slices s around the first instance of sep
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
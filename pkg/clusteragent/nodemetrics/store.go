@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package nodemetrics holds the last values of the metrics aggregated by the node agents, reported to the cluster
+// agent with their cluster checks status. They're used to compute the value of external metrics locally when the
+// Datadog API cannot be queried.
+package nodemetrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+// nodeExpiration is the duration after which the values of a node that stopped reporting are dropped
+const nodeExpiration = 10 * time.Minute
+
+var globalStore = NewStore()
+
+// GetStore returns the store shared by the cluster checks handler and the external metrics provider
+func GetStore() *Store {
+	return globalStore
+}
+
+// Store holds the metrics requested to the node agents and the values they reported
+type Store struct {
+	mu        sync.RWMutex
+	requested []string
+	nodes     map[string]nodeValues
+}
+
+type nodeValues struct {
+	values     []types.MetricValue
+	updateTime time.Time
+}
+
+// NewStore returns a new empty store
+func NewStore() *Store {
+	return &Store{
+		nodes: make(map[string]nodeValues),
+	}
+}
+
+// SetRequestedMetrics sets the metrics the node agents should report the values of
+func (s *Store) SetRequestedMetrics(names []string) {
+	unique := make(map[string]struct{}, len(names))
+	requested := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, found := unique[name]; !found {
+			unique[name] = struct{}{}
+			requested = append(requested, name)
+		}
+	}
+	sort.Strings(requested)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requested = requested
+}
+
+// RequestedMetrics returns the metrics the node agents should report the values of
+func (s *Store) RequestedMetrics() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.requested
+}
+
+// Update replaces the values reported by a node
+func (s *Store) Update(nodeName string, values []types.MetricValue, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, node := range s.nodes {
+		if now.Sub(node.updateTime) > nodeExpiration {
+			delete(s.nodes, name)
+		}
+	}
+
+	if len(values) == 0 {
+		delete(s.nodes, nodeName)
+		return
+	}
+	s.nodes[nodeName] = nodeValues{values: values, updateTime: now}
+}
+
+// Query computes the value of a query from the values reported by the node agents, ignoring the values older than
+// maxAge. It returns the timestamp of the most recent value used.
+func (s *Store) Query(query Query, now time.Time, maxAge time.Duration) (float64, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	minTimestamp := now.Add(-maxAge).Unix()
+	var values []float64
+	var timestamp int64
+	for _, node := range s.nodes {
+		for _, value := range node.values {
+			if value.Timestamp < minTimestamp || !query.matches(value) {
+				continue
+			}
+			values = append(values, value.Value)
+			if value.Timestamp > timestamp {
+				timestamp = value.Timestamp
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return 0, time.Time{}, fmt.Errorf("no recent value of %s reported by the node agents", query.Metric)
+	}
+	return query.aggregate(values), time.Unix(timestamp, 0).UTC(), nil
+}
@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package nodemetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestParseQuery(t *testing.T) {
+	query, err := ParseQuery("avg:nginx.net.request_per_s{kube_deployment:nginx, kube_namespace:default}")
+	require.NoError(t, err)
+	assert.Equal(t, Query{
+		Aggregation: AggregationAvg,
+		Metric:      "nginx.net.request_per_s",
+		Tags:        []string{"kube_deployment:nginx", "kube_namespace:default"},
+	}, query)
+	assert.Equal(t, "avg:nginx.net.request_per_s{kube_deployment:nginx,kube_namespace:default}", query.String())
+
+	query, err = ParseQuery("max:redis.net.clients{*}")
+	require.NoError(t, err)
+	assert.Equal(t, Query{Aggregation: AggregationMax, Metric: "redis.net.clients"}, query)
+
+	query, err = ParseQuery("sum:redis.net.clients")
+	require.NoError(t, err)
+	assert.Equal(t, Query{Aggregation: AggregationSum, Metric: "redis.net.clients"}, query)
+
+	for _, invalid := range []string{
+		"redis.net.clients",
+		"p95:redis.net.clients{*}",
+		"avg:redis.net.clients{*",
+		"avg:{*}",
+		"avg:per_second(redis.net.clients){*}",
+	} {
+		_, err = ParseQuery(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestStoreQuery(t *testing.T) {
+	now := time.Now()
+	store := NewStore()
+
+	store.Update("node-1", []types.MetricValue{
+		{Name: "nginx.net.request_per_s", Host: "node-1", Tags: []string{"kube_deployment:nginx"}, Value: 10, Timestamp: now.Add(-20 * time.Second).Unix()},
+		{Name: "nginx.net.request_per_s", Host: "node-1", Tags: []string{"kube_deployment:other"}, Value: 100, Timestamp: now.Unix()},
+	}, now)
+	store.Update("node-2", []types.MetricValue{
+		{Name: "nginx.net.request_per_s", Host: "node-2", Tags: []string{"kube_deployment:nginx"}, Value: 30, Timestamp: now.Add(-10 * time.Second).Unix()},
+		{Name: "nginx.net.request_per_s", Host: "node-2", Tags: []string{"kube_deployment:nginx", "env:staging"}, Value: 50, Timestamp: now.Add(-time.Hour).Unix()},
+	}, now)
+
+	tests := []struct {
+		query    string
+		expected float64
+	}{
+		{"avg:nginx.net.request_per_s{kube_deployment:nginx}", 20},
+		{"sum:nginx.net.request_per_s{kube_deployment:nginx}", 40},
+		{"min:nginx.net.request_per_s{kube_deployment:nginx}", 10},
+		{"max:nginx.net.request_per_s{*}", 100},
+		{"max:nginx.net.request_per_s{kube_deployment:nginx,host:node-1}", 10},
+	}
+	for _, test := range tests {
+		query, err := ParseQuery(test.query)
+		require.NoError(t, err)
+
+		value, timestamp, err := store.Query(query, now, 5*time.Minute)
+		require.NoError(t, err, test.query)
+		assert.Equal(t, test.expected, value, test.query)
+		assert.False(t, timestamp.After(now), test.query)
+	}
+
+	// The only value of the staging deployment is too old
+	query, err := ParseQuery("avg:nginx.net.request_per_s{env:staging}")
+	require.NoError(t, err)
+	_, _, err = store.Query(query, now, 5*time.Minute)
+	assert.Error(t, err)
+
+	// Reporting no value drops the values of the node
+	store.Update("node-1", nil, now)
+	query, err = ParseQuery("sum:nginx.net.request_per_s{kube_deployment:nginx}")
+	require.NoError(t, err)
+	value, _, err := store.Query(query, now, 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, value)
+
+	// Nodes that stopped reporting expire
+	store.Update("node-3", nil, now.Add(time.Hour))
+	_, _, err = store.Query(query, now, 5*time.Minute)
+	assert.Error(t, err)
+}
+
+func TestStoreRequestedMetrics(t *testing.T) {
+	store := NewStore()
+	assert.Empty(t, store.RequestedMetrics())
+
+	store.SetRequestedMetrics([]string{"redis.net.clients", "nginx.net.request_per_s", "redis.net.clients"})
+	assert.Equal(t, []string{"nginx.net.request_per_s", "redis.net.clients"}, store.RequestedMetrics())
+}
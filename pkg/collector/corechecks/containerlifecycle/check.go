@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
 	ddConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
@@ -81,7 +82,12 @@ func (c *Check) Configure(config, initConfig integration.Data, source string) er
 		c.instance.pollInterval = defaultPollInterval
 	}
 
-	c.processor = newProcessor(sender, c.instance.chunkSize)
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Can't get hostname from the agent: %s", err)
+	}
+
+	c.processor = newProcessor(sender, c.instance.chunkSize, hostname, c.workloadmetaStore)
 
 	return nil
 }
@@ -97,7 +103,7 @@ func (c *Check) Run() error {
 		workloadmeta.NewFilter(
 			[]workloadmeta.Kind{workloadmeta.KindContainer},
 			workloadmeta.SourceRuntime,
-			workloadmeta.EventTypeUnset,
+			workloadmeta.EventTypeAll,
 		),
 	)
 
@@ -107,7 +113,17 @@ func (c *Check) Run() error {
 		workloadmeta.NewFilter(
 			[]workloadmeta.Kind{workloadmeta.KindKubernetesPod},
 			workloadmeta.SourceNodeOrchestrator,
-			workloadmeta.EventTypeUnset,
+			workloadmeta.EventTypeAll,
+		),
+	)
+
+	taskEventsCh := c.workloadmetaStore.Subscribe(
+		checkName+"-task",
+		workloadmeta.NormalPriority,
+		workloadmeta.NewFilter(
+			[]workloadmeta.Kind{workloadmeta.KindECSTask},
+			workloadmeta.SourceNodeOrchestrator,
+			workloadmeta.EventTypeAll,
 		),
 	)
 
@@ -122,6 +138,8 @@ func (c *Check) Run() error {
 			c.processor.processEvents(eventBundle)
 		case eventBundle := <-podEventsCh:
 			c.processor.processEvents(eventBundle)
+		case eventBundle := <-taskEventsCh:
+			c.processor.processEvents(eventBundle)
 		case <-c.stopCh:
 			stopProcessor()
 			return nil
@@ -19,18 +19,61 @@ type event interface {
 	withSource(string)
 	withContainerExitCode(*int32)
 	withContainerExitTimestamp(*int64)
+	withObjectName(string)
+	withNamespace(string)
+	withTimestamp(int64)
+	withExitReason(string)
+	withOOMKilled(bool)
+	withOwners([]owner)
 	toPayloadModel() (model.EventsPayload, error)
 	toEventModel() (*model.Event, error)
+	toLifecycleEvent() (lifecycleEvent, error)
+}
+
+// owner references the object owning a container or a pod
+type owner struct {
+	Kind string `json:"kind"`
+	Name string `json:"name,omitempty"`
+	ID   string `json:"id"`
+}
+
+// lifecycleEvent is the event platform representation of a lifecycle event.
+// Unlike the v1 protobuf payload, it supports all event types and object kinds.
+type lifecycleEvent struct {
+	ObjectKind string  `json:"object_kind"`
+	ObjectID   string  `json:"object_id"`
+	ObjectName string  `json:"object_name,omitempty"`
+	Namespace  string  `json:"namespace,omitempty"`
+	EventType  string  `json:"event_type"`
+	Source     string  `json:"source,omitempty"`
+	Timestamp  int64   `json:"timestamp"`
+	ExitCode   *int32  `json:"exit_code,omitempty"`
+	ExitReason string  `json:"exit_reason,omitempty"`
+	OOMKilled  bool    `json:"oom_killed,omitempty"`
+	Owners     []owner `json:"owners,omitempty"`
+}
+
+// lifecyclePayload is a chunk of lifecycle events sent through the event platform
+type lifecyclePayload struct {
+	Version string           `json:"version"`
+	Host    string           `json:"host"`
+	Events  []lifecycleEvent `json:"events"`
 }
 
 type eventTransformer struct {
 	tpl          model.EventsPayload
 	objectKind   string
 	objectID     string
+	objectName   string
+	namespace    string
 	eventType    string
 	source       string
+	timestamp    int64
 	contExitCode *int32
 	contExitTS   *int64
+	exitReason   string
+	oomKilled    bool
+	owners       []owner
 }
 
 func newEvent() event {
@@ -63,6 +106,63 @@ func (e *eventTransformer) withContainerExitTimestamp(exitTS *int64) {
 	e.contExitTS = exitTS
 }
 
+func (e *eventTransformer) withObjectName(name string) {
+	e.objectName = name
+}
+
+func (e *eventTransformer) withNamespace(namespace string) {
+	e.namespace = namespace
+}
+
+func (e *eventTransformer) withTimestamp(ts int64) {
+	e.timestamp = ts
+}
+
+func (e *eventTransformer) withExitReason(reason string) {
+	e.exitReason = reason
+}
+
+func (e *eventTransformer) withOOMKilled(oomKilled bool) {
+	e.oomKilled = oomKilled
+}
+
+func (e *eventTransformer) withOwners(owners []owner) {
+	e.owners = owners
+}
+
+func (e *eventTransformer) toLifecycleEvent() (lifecycleEvent, error) {
+	switch e.objectKind {
+	case types.ObjectKindContainer, types.ObjectKindPod, types.ObjectKindTask:
+	default:
+		return lifecycleEvent{}, fmt.Errorf("unknown object kind %q", e.objectKind)
+	}
+
+	switch e.eventType {
+	case types.EventNameCreate, types.EventNameStart, types.EventNameExit, types.EventNameDelete:
+	default:
+		return lifecycleEvent{}, fmt.Errorf("unknown event type %s", e.eventType)
+	}
+
+	ts := e.timestamp
+	if ts == 0 && e.contExitTS != nil {
+		ts = *e.contExitTS
+	}
+
+	return lifecycleEvent{
+		ObjectKind: e.objectKind,
+		ObjectID:   e.objectID,
+		ObjectName: e.objectName,
+		Namespace:  e.namespace,
+		EventType:  e.eventType,
+		Source:     e.source,
+		Timestamp:  ts,
+		ExitCode:   e.contExitCode,
+		ExitReason: e.exitReason,
+		OOMKilled:  e.oomKilled,
+		Owners:     e.owners,
+	}, nil
+}
+
 func (e *eventTransformer) toPayloadModel() (model.EventsPayload, error) {
 	payload := e.tpl
 	kind, err := e.kind()
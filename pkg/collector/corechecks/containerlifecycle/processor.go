@@ -7,25 +7,63 @@ package containerlifecycle
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	types "github.com/DataDog/datadog-agent/pkg/containerlifecycle"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
+// Phases of Kubernetes pods reported as lifecycle events
+const (
+	podPhaseRunning   = "Running"
+	podPhaseSucceeded = "Succeeded"
+	podPhaseFailed    = "Failed"
+)
+
+// taskStatus tracks the containers of an ECS task to report when the task starts and exits
+type taskStatus struct {
+	// containers holds the last known state of the containers of the task, including the ones that are gone
+	containers map[string]workloadmeta.ContainerState
+	started    bool
+	exited     bool
+}
+
 type processor struct {
 	sender          aggregator.Sender
+	hostname        string
 	podsQueue       *queue
 	containersQueue *queue
+	lifecycleQueue  *lifecycleQueue
+	store           workloadmeta.Store
+
+	// startTime is used to only report the lifecycle events happening while the check runs
+	startTime       time.Time
+	containers      map[string]workloadmeta.ContainerState
+	containerOwners map[string][]owner
+	pods            map[string]*workloadmeta.KubernetesPod
+	tasks           map[string]*workloadmeta.ECSTask
+	taskStatuses    map[string]*taskStatus
 }
 
-func newProcessor(sender aggregator.Sender, chunkSize int) *processor {
+func newProcessor(sender aggregator.Sender, chunkSize int, hostname string, store workloadmeta.Store) *processor {
 	return &processor{
 		sender:          sender,
+		hostname:        hostname,
 		podsQueue:       newQueue(chunkSize),
 		containersQueue: newQueue(chunkSize),
+		lifecycleQueue:  newLifecycleQueue(chunkSize),
+		store:           store,
+		startTime:       time.Now(),
+		containers:      make(map[string]workloadmeta.ContainerState),
+		containerOwners: make(map[string][]owner),
+		pods:            make(map[string]*workloadmeta.KubernetesPod),
+		tasks:           make(map[string]*workloadmeta.ECSTask),
+		taskStatuses:    make(map[string]*taskStatus),
 	}
 }
 
@@ -34,7 +72,8 @@ func (p *processor) start(ctx context.Context, pollInterval time.Duration) {
 	go p.processQueues(ctx, pollInterval)
 }
 
-// processEvents handles workloadmeta events, supports pods and container unset events.
+// processEvents handles workloadmeta events for containers, pods and ECS tasks.
+// Deletions are sent in the v1 payloads, all lifecycle events are sent through the event platform.
 func (p *processor) processEvents(evBundle workloadmeta.EventBundle) {
 	close(evBundle.Ch)
 
@@ -51,16 +90,45 @@ func (p *processor) processEvents(evBundle workloadmeta.EventBundle) {
 				continue
 			}
 
+			if event.Type == workloadmeta.EventTypeSet {
+				p.processContainerSet(container)
+				continue
+			}
+
 			err := p.processContainer(container, []workloadmeta.Source{workloadmeta.SourceRuntime})
 			if err != nil {
 				log.Debugf("Couldn't process container %q: %v", container.ID, err)
 			}
+			p.processContainerUnset(container)
 		case workloadmeta.KindKubernetesPod:
+			pod, ok := event.Entity.(*workloadmeta.KubernetesPod)
+			if !ok {
+				log.Debugf("Expected workloadmeta.KubernetesPod got %T, skipping", event.Entity)
+				continue
+			}
+
+			if event.Type == workloadmeta.EventTypeSet {
+				p.processPodSet(pod)
+				continue
+			}
+
 			err := p.processPod(event.Entity)
 			if err != nil {
 				log.Debugf("Couldn't process pod %q: %v", event.Entity.GetID().ID, err)
 			}
-		case workloadmeta.KindECSTask: // not supported
+			p.processPodUnset(pod)
+		case workloadmeta.KindECSTask:
+			task, ok := event.Entity.(*workloadmeta.ECSTask)
+			if !ok {
+				log.Debugf("Expected workloadmeta.ECSTask got %T, skipping", event.Entity)
+				continue
+			}
+
+			if event.Type == workloadmeta.EventTypeSet {
+				p.processTaskSet(task)
+			} else {
+				p.processTaskUnset(task)
+			}
 		default:
 			log.Tracef("Cannot handle event for entity %q with kind %q", entityID.ID, entityID.Kind)
 		}
@@ -102,6 +170,330 @@ func (p *processor) processPod(pod workloadmeta.Entity) error {
 	return p.podsQueue.add(event)
 }
 
+// processContainerSet enqueues the create, start and exit events that happened since the previous state of the container
+func (p *processor) processContainerSet(container *workloadmeta.Container) {
+	prev, found := p.containers[container.ID]
+	state := container.State
+	p.containers[container.ID] = state
+
+	type timedEvent struct {
+		ts time.Time
+		ev event
+	}
+	var events []timedEvent
+
+	if !found && p.isRecent(state.CreatedAt) {
+		events = append(events, timedEvent{state.CreatedAt, p.newContainerEvent(container, types.EventNameCreate, state.CreatedAt)})
+	}
+
+	if state.StartedAt.After(prev.StartedAt) && p.isRecent(state.StartedAt) {
+		events = append(events, timedEvent{state.StartedAt, p.newContainerEvent(container, types.EventNameStart, state.StartedAt)})
+	}
+
+	// A container may have exited and restarted between two updates
+	if state.FinishedAt.After(prev.FinishedAt) && p.isRecent(state.FinishedAt) {
+		events = append(events, timedEvent{state.FinishedAt, p.newContainerExitEvent(container)})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ts.Before(events[j].ts)
+	})
+	for _, timed := range events {
+		p.addLifecycleEvent(timed.ev)
+	}
+
+	p.updateTaskContainer(container.ID, state)
+}
+
+// processContainerUnset enqueues the exit event of a container, unless it was already reported
+func (p *processor) processContainerUnset(container *workloadmeta.Container) {
+	prev, found := p.containers[container.ID]
+	delete(p.containers, container.ID)
+
+	if !found || container.State.FinishedAt.After(prev.FinishedAt) {
+		p.addLifecycleEvent(p.newContainerExitEvent(container))
+	}
+
+	// Unset events of exited containers may only hold their exit information
+	state := container.State
+	if found {
+		state.CreatedAt, state.StartedAt = prev.CreatedAt, prev.StartedAt
+	}
+	state.Running = false
+	p.updateTaskContainer(container.ID, state)
+
+	// Owners are kept until the container is gone, its pod or task can be deleted first
+	delete(p.containerOwners, container.ID)
+}
+
+// processPodSet enqueues the creation event of new pods, the start and exit events of pods whose phase changed,
+// and records the owners of their containers
+func (p *processor) processPodSet(pod *workloadmeta.KubernetesPod) {
+	prev, found := p.pods[pod.ID]
+	p.pods[pod.ID] = pod
+
+	podOwner := []owner{{Kind: types.ObjectKindPod, Name: pod.Name, ID: pod.ID}}
+	for _, container := range pod.Containers {
+		p.containerOwners[container.ID] = podOwner
+	}
+
+	isNew := !found && p.isRecent(pod.CreationTimestamp)
+	if isNew {
+		p.addLifecycleEvent(p.newPodEvent(pod, types.EventNameCreate, pod.CreationTimestamp))
+	}
+
+	// The phases of the pods that existed before the check started are only reported once they change
+	if !isNew && (!found || prev.Phase == pod.Phase) {
+		return
+	}
+
+	// Pods don't expose when their phase changed, the time of the update is used
+	switch pod.Phase {
+	case podPhaseRunning:
+		p.addLifecycleEvent(p.newPodEvent(pod, types.EventNameStart, time.Now()))
+	case podPhaseSucceeded, podPhaseFailed:
+		event := p.newPodEvent(pod, types.EventNameExit, time.Now())
+		event.withExitReason(podExitReason(pod.Phase))
+		p.addLifecycleEvent(event)
+	}
+}
+
+// processPodUnset enqueues the deletion event of a pod
+func (p *processor) processPodUnset(pod *workloadmeta.KubernetesPod) {
+	// Unset events only hold the entity ID
+	if known, found := p.pods[pod.ID]; found {
+		pod = known
+	}
+
+	delete(p.pods, pod.ID)
+
+	p.addLifecycleEvent(p.newPodEvent(pod, types.EventNameDelete, time.Now()))
+}
+
+// processTaskSet enqueues the creation event of new ECS tasks and records the owners of their containers.
+// Tasks don't have a creation time, a task is considered new if one of its containers was created while the check runs.
+func (p *processor) processTaskSet(task *workloadmeta.ECSTask) {
+	_, found := p.tasks[task.ID]
+	p.tasks[task.ID] = task
+
+	status, statusFound := p.taskStatuses[task.ID]
+	if !statusFound {
+		status = &taskStatus{containers: make(map[string]workloadmeta.ContainerState)}
+		p.taskStatuses[task.ID] = status
+	}
+
+	taskOwner := []owner{{Kind: types.ObjectKindTask, Name: taskName(task), ID: task.ID}}
+	createdAt := time.Time{}
+	for _, taskContainer := range task.Containers {
+		p.containerOwners[taskContainer.ID] = taskOwner
+
+		if container, err := p.store.GetContainer(taskContainer.ID); err == nil {
+			if createdAt.IsZero() || container.State.CreatedAt.Before(createdAt) {
+				createdAt = container.State.CreatedAt
+			}
+			if _, known := status.containers[taskContainer.ID]; !known {
+				status.containers[taskContainer.ID] = container.State
+			}
+		}
+	}
+
+	if !found && p.isRecent(createdAt) {
+		p.addLifecycleEvent(p.newTaskEvent(task, types.EventNameCreate, createdAt))
+	}
+
+	p.processTaskStatus(task, status)
+}
+
+// processTaskUnset enqueues the deletion event of an ECS task
+func (p *processor) processTaskUnset(task *workloadmeta.ECSTask) {
+	if known, found := p.tasks[task.ID]; found {
+		task = known
+	}
+
+	delete(p.tasks, task.ID)
+	delete(p.taskStatuses, task.ID)
+
+	p.addLifecycleEvent(p.newTaskEvent(task, types.EventNameDelete, time.Now()))
+}
+
+// updateTaskContainer records the state of a container owned by an ECS task and reports the changes of the task
+func (p *processor) updateTaskContainer(containerID string, state workloadmeta.ContainerState) {
+	owners := p.containerOwners[containerID]
+	if len(owners) == 0 || owners[0].Kind != types.ObjectKindTask {
+		return
+	}
+
+	task, found := p.tasks[owners[0].ID]
+	if !found {
+		return
+	}
+
+	status := p.taskStatuses[task.ID]
+	status.containers[containerID] = state
+	p.processTaskStatus(task, status)
+}
+
+// processTaskStatus enqueues the start event of an ECS task when its first container starts,
+// and its exit event once all its containers have exited.
+func (p *processor) processTaskStatus(task *workloadmeta.ECSTask, status *taskStatus) {
+	var startedAt, finishedAt time.Time
+	exited := len(task.Containers) > 0
+	for _, taskContainer := range task.Containers {
+		state, found := status.containers[taskContainer.ID]
+		if !found || state.Running || state.FinishedAt.IsZero() {
+			exited = false
+		}
+		if !found {
+			continue
+		}
+
+		if !state.StartedAt.IsZero() && (startedAt.IsZero() || state.StartedAt.Before(startedAt)) {
+			startedAt = state.StartedAt
+		}
+		if state.FinishedAt.After(finishedAt) {
+			finishedAt = state.FinishedAt
+		}
+	}
+
+	if !status.started && !startedAt.IsZero() {
+		status.started = true
+		if p.isRecent(startedAt) {
+			p.addLifecycleEvent(p.newTaskEvent(task, types.EventNameStart, startedAt))
+		}
+	}
+
+	if !status.exited && exited {
+		status.exited = true
+		if p.isRecent(finishedAt) {
+			event := p.newTaskEvent(task, types.EventNameExit, finishedAt)
+			event.withExitReason(taskExitReason(task, status))
+			p.addLifecycleEvent(event)
+		}
+	}
+}
+
+// isRecent returns whether ts is set and happened after the processor started,
+// to avoid reporting past events again when the agent restarts.
+func (p *processor) isRecent(ts time.Time) bool {
+	return !ts.IsZero() && !ts.Before(p.startTime)
+}
+
+func (p *processor) newContainerEvent(container *workloadmeta.Container, eventType string, ts time.Time) event {
+	event := newEvent()
+	event.withObjectKind(types.ObjectKindContainer)
+	event.withEventType(eventType)
+	event.withObjectID(container.ID)
+	event.withObjectName(container.Name)
+	event.withSource(string(workloadmeta.SourceRuntime))
+	event.withTimestamp(ts.Unix())
+	event.withOwners(p.containerOwners[container.ID])
+
+	return event
+}
+
+func (p *processor) newContainerExitEvent(container *workloadmeta.Container) event {
+	finishedAt := container.State.FinishedAt
+	if finishedAt.IsZero() {
+		finishedAt = time.Now()
+	}
+
+	event := p.newContainerEvent(container, types.EventNameExit, finishedAt)
+	event.withOOMKilled(container.State.OOMKilled)
+	event.withExitReason(exitReason(container.State))
+
+	if container.State.ExitCode != nil {
+		code := int32(*container.State.ExitCode)
+		event.withContainerExitCode(&code)
+	}
+
+	return event
+}
+
+func (p *processor) newPodEvent(pod *workloadmeta.KubernetesPod, eventType string, ts time.Time) event {
+	event := newEvent()
+	event.withObjectKind(types.ObjectKindPod)
+	event.withEventType(eventType)
+	event.withObjectID(pod.ID)
+	event.withObjectName(pod.Name)
+	event.withNamespace(pod.Namespace)
+	event.withSource(string(workloadmeta.SourceNodeOrchestrator))
+	event.withTimestamp(ts.Unix())
+
+	owners := make([]owner, 0, len(pod.Owners))
+	for _, podOwner := range pod.Owners {
+		owners = append(owners, owner{Kind: podOwner.Kind, Name: podOwner.Name, ID: podOwner.ID})
+	}
+	event.withOwners(owners)
+
+	return event
+}
+
+func (p *processor) newTaskEvent(task *workloadmeta.ECSTask, eventType string, ts time.Time) event {
+	event := newEvent()
+	event.withObjectKind(types.ObjectKindTask)
+	event.withEventType(eventType)
+	event.withObjectID(task.ID)
+	event.withObjectName(taskName(task))
+	event.withNamespace(task.ClusterName)
+	event.withSource(string(workloadmeta.SourceNodeOrchestrator))
+	event.withTimestamp(ts.Unix())
+
+	return event
+}
+
+func (p *processor) addLifecycleEvent(ev event) {
+	if err := p.lifecycleQueue.add(ev); err != nil {
+		log.Debugf("Couldn't enqueue lifecycle event: %v", err)
+	}
+}
+
+// exitReason follows the Kubernetes terminated container reasons
+func exitReason(state workloadmeta.ContainerState) string {
+	switch {
+	case state.OOMKilled:
+		return types.ExitReasonOOMKilled
+	case state.ExitCode == nil:
+		return ""
+	case *state.ExitCode == 0:
+		return types.ExitReasonCompleted
+	default:
+		return types.ExitReasonError
+	}
+}
+
+// podExitReason returns the exit reason of a pod in a terminal phase
+func podExitReason(phase string) string {
+	if phase == podPhaseSucceeded {
+		return types.ExitReasonCompleted
+	}
+	return types.ExitReasonError
+}
+
+// taskExitReason returns the most severe exit reason of the containers of an ECS task
+func taskExitReason(task *workloadmeta.ECSTask, status *taskStatus) string {
+	reason := ""
+	for _, taskContainer := range task.Containers {
+		switch containerReason := exitReason(status.containers[taskContainer.ID]); containerReason {
+		case types.ExitReasonOOMKilled:
+			return containerReason
+		case types.ExitReasonError:
+			reason = containerReason
+		case types.ExitReasonCompleted:
+			if reason == "" {
+				reason = containerReason
+			}
+		}
+	}
+	return reason
+}
+
+func taskName(task *workloadmeta.ECSTask) string {
+	if task.Family == "" {
+		return ""
+	}
+	return task.Family + ":" + task.Version
+}
+
 // processQueues consumes the data available in the queues
 func (p *processor) processQueues(ctx context.Context, pollInterval time.Duration) {
 	ticker := time.NewTicker(pollInterval)
@@ -121,6 +513,7 @@ func (p *processor) processQueues(ctx context.Context, pollInterval time.Duratio
 func (p *processor) flush() {
 	p.flushContainers()
 	p.flushPods()
+	p.flushLifecycleEvents()
 }
 
 // flushContainers forwards queued container events to the aggregator
@@ -138,3 +531,20 @@ func (p *processor) flushPods() {
 		p.sender.ContainerLifecycleEvent(msgs)
 	}
 }
+
+// flushLifecycleEvents forwards queued lifecycle events to the event platform
+func (p *processor) flushLifecycleEvents() {
+	for _, events := range p.lifecycleQueue.flush() {
+		raw, err := json.Marshal(lifecyclePayload{
+			Version: types.PayloadV2,
+			Host:    p.hostname,
+			Events:  events,
+		})
+		if err != nil {
+			log.Debugf("Couldn't marshal lifecycle events: %v", err)
+			continue
+		}
+
+		p.sender.EventPlatformEvent(string(raw), epforwarder.EventTypeContainerLifecycle)
+	}
+}
@@ -13,7 +13,10 @@ import (
 	model "github.com/DataDog/agent-payload/v5/contlcycle"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	types "github.com/DataDog/datadog-agent/pkg/containerlifecycle"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -60,6 +63,7 @@ func TestProcessQueues(t *testing.T) {
 			p := &processor{
 				containersQueue: tt.containersQueue,
 				podsQueue:       tt.podsQueue,
+				lifecycleQueue:  newLifecycleQueue(10),
 			}
 
 			sender := mocksender.NewMockSender(check.ID(tt.name))
@@ -76,3 +80,211 @@ func TestProcessQueues(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessLifecycleEvents(t *testing.T) {
+	start := time.Now()
+	before := start.Add(-time.Hour)
+	store := workloadmeta.NewMockStore()
+
+	p := newProcessor(nil, 10, "host", store)
+	p.startTime = start
+
+	bundle := func(events ...workloadmeta.Event) workloadmeta.EventBundle {
+		return workloadmeta.EventBundle{Ch: make(chan struct{}), Events: events}
+	}
+	set := func(entity workloadmeta.Entity) workloadmeta.Event {
+		return workloadmeta.Event{Type: workloadmeta.EventTypeSet, Entity: entity}
+	}
+	unset := func(entity workloadmeta.Entity) workloadmeta.Event {
+		return workloadmeta.Event{Type: workloadmeta.EventTypeUnset, Entity: entity}
+	}
+	container := func(id string, state workloadmeta.ContainerState) *workloadmeta.Container {
+		return &workloadmeta.Container{
+			EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: id},
+			EntityMeta: workloadmeta.EntityMeta{Name: id + "-name"},
+			State:      state,
+		}
+	}
+	pod := &workloadmeta.KubernetesPod{
+		EntityID:          workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesPod, ID: "pod-uid"},
+		EntityMeta:        workloadmeta.EntityMeta{Name: "job-1-abcde", Namespace: "default"},
+		Owners:            []workloadmeta.KubernetesPodOwner{{Kind: "Job", Name: "job-1", ID: "job-uid"}},
+		CreationTimestamp: start.Add(time.Second),
+		Containers:        []workloadmeta.OrchestratorContainer{{ID: "new"}},
+	}
+	oldPod := &workloadmeta.KubernetesPod{
+		EntityID:          workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesPod, ID: "old-pod-uid"},
+		CreationTimestamp: before,
+	}
+
+	exitCode := uint32(137)
+	p.processEvents(bundle(
+		// Pre-existing objects are not reported
+		set(container("old", workloadmeta.ContainerState{Running: true, CreatedAt: before, StartedAt: before})),
+		set(oldPod),
+		set(pod),
+		set(container("new", workloadmeta.ContainerState{Running: true, CreatedAt: start.Add(2 * time.Second), StartedAt: start.Add(3 * time.Second)})),
+	))
+	p.processEvents(bundle(
+		// Restart after an OOM kill
+		set(container("old", workloadmeta.ContainerState{Running: true, CreatedAt: before, StartedAt: start.Add(5 * time.Second), FinishedAt: start.Add(4 * time.Second), ExitCode: &exitCode, OOMKilled: true})),
+		unset(container("new", workloadmeta.ContainerState{FinishedAt: start.Add(6 * time.Second)})),
+		unset(&workloadmeta.KubernetesPod{EntityID: pod.EntityID}),
+	))
+
+	var events []lifecycleEvent
+	for _, chunk := range p.lifecycleQueue.flush() {
+		events = append(events, chunk...)
+	}
+
+	summary := make([]string, 0, len(events))
+	for _, ev := range events {
+		summary = append(summary, ev.ObjectKind+"/"+ev.ObjectID+"/"+ev.EventType)
+	}
+	assert.Equal(t, []string{
+		"pod/pod-uid/create",
+		"container/new/create",
+		"container/new/start",
+		"container/old/exit",
+		"container/old/start",
+		"container/new/exit",
+		"pod/pod-uid/delete",
+	}, summary)
+
+	assert.Equal(t, []owner{{Kind: "Job", Name: "job-1", ID: "job-uid"}}, events[0].Owners)
+	assert.Equal(t, []owner{{Kind: types.ObjectKindPod, Name: "job-1-abcde", ID: "pod-uid"}}, events[1].Owners)
+	assert.Equal(t, start.Add(3*time.Second).Unix(), events[2].Timestamp)
+	assert.True(t, events[3].OOMKilled)
+	assert.Equal(t, types.ExitReasonOOMKilled, events[3].ExitReason)
+	assert.Equal(t, int32(137), *events[3].ExitCode)
+	assert.Equal(t, "default", events[6].Namespace)
+
+	// The deletion of the container is also sent in the v1 payload
+	assert.Len(t, p.containersQueue.flush(), 1)
+	assert.Len(t, p.podsQueue.flush(), 1)
+
+	// ECS tasks are new if one of their containers was created after the start
+	store.SetEntity(container("task-ctn", workloadmeta.ContainerState{Running: true, CreatedAt: start.Add(10 * time.Second)}))
+	task := &workloadmeta.ECSTask{
+		EntityID:    workloadmeta.EntityID{Kind: workloadmeta.KindECSTask, ID: "task-arn"},
+		ClusterName: "cluster",
+		Family:      "web",
+		Version:     "3",
+		Containers:  []workloadmeta.OrchestratorContainer{{ID: "task-ctn"}},
+	}
+	p.processEvents(bundle(set(task), unset(container("task-ctn", workloadmeta.ContainerState{}))))
+
+	events = p.lifecycleQueue.flush()[0]
+	assert.Len(t, events, 2)
+	assert.Equal(t, types.ObjectKindTask, events[0].ObjectKind)
+	assert.Equal(t, "web:3", events[0].ObjectName)
+	assert.Equal(t, []owner{{Kind: types.ObjectKindTask, Name: "web:3", ID: "task-arn"}}, events[1].Owners)
+}
+
+func TestProcessPodAndTaskStatusEvents(t *testing.T) {
+	start := time.Now()
+	before := start.Add(-time.Hour)
+	store := workloadmeta.NewMockStore()
+
+	p := newProcessor(nil, 10, "host", store)
+	p.startTime = start
+
+	bundle := func(events ...workloadmeta.Event) workloadmeta.EventBundle {
+		return workloadmeta.EventBundle{Ch: make(chan struct{}), Events: events}
+	}
+	set := func(entity workloadmeta.Entity) workloadmeta.Event {
+		return workloadmeta.Event{Type: workloadmeta.EventTypeSet, Entity: entity}
+	}
+	unset := func(entity workloadmeta.Entity) workloadmeta.Event {
+		return workloadmeta.Event{Type: workloadmeta.EventTypeUnset, Entity: entity}
+	}
+	pod := func(id, phase string, createdAt time.Time) *workloadmeta.KubernetesPod {
+		return &workloadmeta.KubernetesPod{
+			EntityID:          workloadmeta.EntityID{Kind: workloadmeta.KindKubernetesPod, ID: id},
+			CreationTimestamp: createdAt,
+			Phase:             phase,
+		}
+	}
+	container := func(id string, state workloadmeta.ContainerState) *workloadmeta.Container {
+		return &workloadmeta.Container{
+			EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: id},
+			State:    state,
+		}
+	}
+	summarize := func() []string {
+		var summary []string
+		for _, chunk := range p.lifecycleQueue.flush() {
+			for _, ev := range chunk {
+				summary = append(summary, ev.ObjectKind+"/"+ev.ObjectID+"/"+ev.EventType+"/"+ev.ExitReason)
+			}
+		}
+		return summary
+	}
+
+	// The phase of pre-existing pods is only reported once it changes
+	p.processEvents(bundle(
+		set(pod("old-pod", "Running", before)),
+		set(pod("new-pod", "Pending", start.Add(time.Second))),
+		set(pod("failed-pod", "Running", before)),
+	))
+	p.processEvents(bundle(
+		set(pod("old-pod", "Running", before)),
+		set(pod("new-pod", "Running", start.Add(time.Second))),
+		set(pod("failed-pod", "Failed", before)),
+	))
+	p.processEvents(bundle(
+		set(pod("new-pod", "Succeeded", start.Add(time.Second))),
+		set(pod("new-pod", "Succeeded", start.Add(time.Second))),
+	))
+	assert.Equal(t, []string{
+		"pod/new-pod/create/",
+		"pod/new-pod/start/",
+		"pod/failed-pod/exit/" + types.ExitReasonError,
+		"pod/new-pod/exit/" + types.ExitReasonCompleted,
+	}, summarize())
+
+	// ECS tasks start with their first container and exit with their last one
+	exitCode := uint32(0)
+	errorCode := uint32(1)
+	store.SetEntity(container("ctn-1", workloadmeta.ContainerState{CreatedAt: start.Add(time.Second)}))
+	store.SetEntity(container("ctn-2", workloadmeta.ContainerState{CreatedAt: start.Add(time.Second)}))
+	task := &workloadmeta.ECSTask{
+		EntityID:   workloadmeta.EntityID{Kind: workloadmeta.KindECSTask, ID: "task-arn"},
+		Family:     "web",
+		Version:    "1",
+		Containers: []workloadmeta.OrchestratorContainer{{ID: "ctn-1"}, {ID: "ctn-2"}},
+	}
+	p.processEvents(bundle(
+		set(task),
+		set(container("ctn-1", workloadmeta.ContainerState{Running: true, CreatedAt: start.Add(time.Second), StartedAt: start.Add(3 * time.Second)})),
+		set(container("ctn-2", workloadmeta.ContainerState{Running: true, CreatedAt: start.Add(time.Second), StartedAt: start.Add(2 * time.Second)})),
+	))
+	p.processEvents(bundle(
+		set(container("ctn-2", workloadmeta.ContainerState{CreatedAt: start.Add(time.Second), StartedAt: start.Add(2 * time.Second), FinishedAt: start.Add(4 * time.Second), ExitCode: &errorCode})),
+		unset(container("ctn-1", workloadmeta.ContainerState{FinishedAt: start.Add(5 * time.Second), ExitCode: &exitCode})),
+		set(task),
+		unset(task),
+	))
+
+	var taskEvents []string
+	var exitTimestamp int64
+	for _, chunk := range p.lifecycleQueue.flush() {
+		for _, ev := range chunk {
+			if ev.ObjectKind != types.ObjectKindTask {
+				continue
+			}
+			taskEvents = append(taskEvents, ev.EventType+"/"+ev.ExitReason)
+			if ev.EventType == types.EventNameExit {
+				exitTimestamp = ev.Timestamp
+			}
+		}
+	}
+	assert.Equal(t, []string{
+		"create/",
+		"start/",
+		"exit/" + types.ExitReasonError,
+		"delete/",
+	}, taskEvents)
+	assert.Equal(t, start.Add(5*time.Second).Unix(), exitTimestamp)
+	assert.Empty(t, p.taskStatuses)
+}
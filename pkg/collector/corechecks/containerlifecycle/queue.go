@@ -117,3 +117,49 @@ func (q *queue) isLastPayloadFull() bool {
 
 	return len(lastElem.Events) >= q.chunkSize
 }
+
+// lifecycleQueue holds the events sent through the event platform, in chunks of chunkSize events
+type lifecycleQueue struct {
+	chunkSize int
+	data      [][]lifecycleEvent
+	sync.Mutex
+}
+
+// newLifecycleQueue returns a new *lifecycleQueue.
+func newLifecycleQueue(chunkSize int) *lifecycleQueue {
+	return &lifecycleQueue{
+		chunkSize: chunkSize,
+	}
+}
+
+// add enqueues a new event.
+// add is thread-safe.
+func (q *lifecycleQueue) add(ev event) error {
+	lcEvent, err := ev.toLifecycleEvent()
+	if err != nil {
+		return err
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.data) == 0 || len(q.data[len(q.data)-1]) >= q.chunkSize {
+		q.data = append(q.data, make([]lifecycleEvent, 0, q.chunkSize))
+	}
+
+	q.data[len(q.data)-1] = append(q.data[len(q.data)-1], lcEvent)
+
+	return nil
+}
+
+// flush returns and resets the queue content. Returns nil if the queue is empty.
+// flush is thread-safe.
+func (q *lifecycleQueue) flush() [][]lifecycleEvent {
+	q.Lock()
+	defer q.Unlock()
+
+	data := q.data
+	q.data = nil
+
+	return data
+}
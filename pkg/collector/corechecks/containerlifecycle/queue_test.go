@@ -92,3 +92,22 @@ func TestBatching(t *testing.T) {
 		assert.EqualValues(t, data[i].Events, modelEvents(strconv.FormatInt(2*i, 10), strconv.FormatInt(2*i+1, 10)))
 	}
 }
+
+func TestLifecycleQueueAdd(t *testing.T) {
+	q := newLifecycleQueue(2)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, q.add(fakeContainerEvent("obj"+strconv.Itoa(i))))
+	}
+
+	invalid := newEvent()
+	invalid.withObjectKind("unknown")
+	invalid.withEventType("delete")
+	assert.Error(t, q.add(invalid))
+
+	chunks := q.flush()
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], 2)
+	assert.Equal(t, "obj2", chunks[1][0].ObjectID)
+	assert.Nil(t, q.flush())
+}
@@ -0,0 +1,128 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+// +build cri
+
+package crio
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	dderrors "github.com/DataDog/datadog-agent/pkg/errors"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/v2/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+
+	criTypes "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const (
+	crioCheckName = "crio"
+	cacheValidity = 2 * time.Second
+
+	// CRIOExit is the name of the service check sent for containers exit codes
+	CRIOExit = "crio.exit"
+)
+
+// CRIOCheck grabs CRI-O metrics and events
+type CRIOCheck struct {
+	core.CheckBase
+	instance        *generic.RuntimeEventsConfig
+	processor       generic.Processor
+	client          cri.CRIClient
+	containerFilter *containers.Filter
+	eventsReporter  *generic.RuntimeEventsReporter
+	exitedStatuses  map[string]*criTypes.ContainerStatus
+}
+
+func init() {
+	core.RegisterCheck(crioCheckName, CRIOFactory)
+}
+
+// CRIOFactory is exported for integration testing
+func CRIOFactory() check.Check {
+	return &CRIOCheck{
+		CheckBase: core.NewCheckBase(crioCheckName),
+		instance:  &generic.RuntimeEventsConfig{},
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *CRIOCheck) Configure(config, initConfig integration.Data, source string) error {
+	var err error
+	if err = c.CommonConfigure(config, source); err != nil {
+		return err
+	}
+
+	if err = c.instance.Parse(config); err != nil {
+		return err
+	}
+
+	client, err := cri.GetUtil()
+	if err != nil {
+		return err
+	}
+
+	if client.GetRuntime() != string(workloadmeta.ContainerRuntimeCRIO) {
+		return dderrors.NewDisabled(crioCheckName, "CRI runtime is not CRI-O")
+	}
+	c.client = client
+
+	c.containerFilter, err = containers.GetSharedMetricFilter()
+	if err != nil {
+		log.Warnf("Can't get container include/exclude filter, no filtering will be applied: %v", err)
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Can't get hostname, events will not have it: %v", err)
+	}
+
+	c.eventsReporter = generic.NewRuntimeEventsReporter(crioCheckName, CRIOExit, hostname, c.instance, emittedEvents)
+	c.exitedStatuses = make(map[string]*criTypes.ContainerStatus)
+	c.processor = generic.NewProcessor(
+		metrics.GetProvider(),
+		generic.MetadataContainerAccessor{},
+		generic.RuntimeMetricsAdapter{CheckName: crioCheckName, Runtime: workloadmeta.ContainerRuntimeCRIO},
+		generic.NewRuntimeFilter(workloadmeta.ContainerRuntimeCRIO, c.containerFilter),
+	)
+
+	return nil
+}
+
+// Run executes the check
+func (c *CRIOCheck) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	if err := c.runProcessor(sender); err != nil {
+		_ = c.Warnf("Error collecting metrics: %s", err)
+	}
+
+	if c.eventsReporter.Enabled() {
+		if err := c.runEvents(sender); err != nil {
+			_ = c.Warnf("Error collecting events: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *CRIOCheck) runProcessor(sender aggregator.Sender) error {
+	return c.processor.Run(sender, cacheValidity)
+}
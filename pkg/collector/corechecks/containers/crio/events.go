@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+// +build cri
+
+package crio
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	criTypes "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+const oomKilledReason = "OOMKilled"
+
+var emittedEvents = telemetry.NewCounterWithOpts(
+	crioCheckName,
+	"emitted_events",
+	[]string{"type"},
+	"Number of events emitted by the check.",
+	telemetry.Options{NoDoubleUnderscoreSep: true},
+)
+
+// runEvents derives container events from the CRI containers list and reports them
+func (c *CRIOCheck) runEvents(sender aggregator.Sender) error {
+	criContainers, err := c.client.ListContainers()
+	if err != nil {
+		return err
+	}
+
+	states := make([]generic.ContainerLifecycleState, 0, len(criContainers))
+	exitedStatuses := make(map[string]*criTypes.ContainerStatus)
+	for _, container := range criContainers {
+		name := container.GetMetadata().GetName()
+		image := container.GetImage().GetImage()
+		if c.containerFilter != nil && c.containerFilter.IsExcluded(name, image, container.GetLabels()[kubernetes.CriContainerNamespaceLabel]) {
+			continue
+		}
+
+		state := generic.ContainerLifecycleState{
+			ID:        container.GetId(),
+			Name:      name,
+			ImageName: image,
+			Running:   container.GetState() == criTypes.ContainerState_CONTAINER_RUNNING,
+			CreatedAt: time.Unix(0, container.GetCreatedAt()),
+		}
+
+		// Exit information is only available in the container status.
+		// Exited containers never change, their status is only requested once.
+		if container.GetState() == criTypes.ContainerState_CONTAINER_EXITED {
+			status, found := c.exitedStatuses[state.ID]
+			if !found {
+				status, err = c.client.GetContainerStatus(state.ID)
+				if err != nil {
+					log.Debugf("Unable to get status of container %s: %v", state.ID, err)
+				}
+			}
+
+			if status != nil {
+				exitedStatuses[state.ID] = status
+				fillExitInformation(&state, status)
+			}
+		}
+
+		states = append(states, state)
+	}
+	c.exitedStatuses = exitedStatuses
+
+	c.eventsReporter.Report(sender, states, time.Now())
+
+	return nil
+}
+
+func fillExitInformation(state *generic.ContainerLifecycleState, status *criTypes.ContainerStatus) {
+	exitCode := int64(status.GetExitCode())
+	state.ExitCode = &exitCode
+	state.OOMKilled = status.GetReason() == oomKilledReason
+
+	if status.GetStartedAt() > 0 {
+		state.StartedAt = time.Unix(0, status.GetStartedAt())
+	}
+	if status.GetFinishedAt() > 0 {
+		state.FinishedAt = time.Unix(0, status.GetFinishedAt())
+	}
+}
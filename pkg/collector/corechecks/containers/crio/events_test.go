@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build cri
+// +build cri
+
+package crio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/cri/crimock"
+
+	criTypes "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func fakeCRIContainer(id string, state criTypes.ContainerState) *criTypes.Container {
+	return &criTypes.Container{
+		Id:        id,
+		Metadata:  &criTypes.ContainerMetadata{Name: "name-" + id},
+		Image:     &criTypes.ImageSpec{Image: "docker.io/library/redis:latest"},
+		State:     state,
+		CreatedAt: time.Now().Add(-time.Hour).UnixNano(),
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	mockCri := &crimock.MockCRIClient{}
+	check := &CRIOCheck{
+		client:         mockCri,
+		eventsReporter: generic.NewRuntimeEventsReporter(crioCheckName, CRIOExit, "testhostname", &generic.RuntimeEventsConfig{CollectEvents: true, CollectExitCodes: true}, emittedEvents),
+		exitedStatuses: make(map[string]*criTypes.ContainerStatus),
+	}
+
+	mockSender := mocksender.NewMockSender(check.ID())
+	mockSender.SetupAcceptAll()
+
+	// First run only records the current state
+	mockCri.On("ListContainers").Return([]*criTypes.Container{
+		fakeCRIContainer("1", criTypes.ContainerState_CONTAINER_RUNNING),
+	}, nil).Once()
+	assert.NoError(t, check.runEvents(mockSender))
+	mockSender.AssertNumberOfCalls(t, "Event", 0)
+
+	// Container 1 is OOM killed
+	mockCri.On("ListContainers").Return([]*criTypes.Container{
+		fakeCRIContainer("1", criTypes.ContainerState_CONTAINER_EXITED),
+	}, nil).Twice()
+	mockCri.On("GetContainerStatus", "1").Return(&criTypes.ContainerStatus{
+		Id:         "1",
+		ExitCode:   137,
+		Reason:     "OOMKilled",
+		StartedAt:  time.Now().Add(-time.Hour).UnixNano(),
+		FinishedAt: time.Now().UnixNano(),
+	}, nil).Once()
+	assert.NoError(t, check.runEvents(mockSender))
+
+	mockSender.AssertServiceCheck(t, CRIOExit, metrics.ServiceCheckCritical, "", []string{"exit_code:137"}, "Container name-1 exited with 137")
+	mockSender.AssertCalled(t, "Event", mock.MatchedBy(func(ev metrics.Event) bool {
+		return ev.AlertType == metrics.EventAlertTypeError &&
+			ev.SourceTypeName == crioCheckName &&
+			ev.Title == "docker.io/library/redis:latest 1 die 1 oom on testhostname"
+	}))
+
+	// Status of exited containers is cached
+	assert.NoError(t, check.runEvents(mockSender))
+	mockCri.AssertNumberOfCalls(t, "GetContainerStatus", 1)
+	mockSender.AssertNumberOfCalls(t, "Event", 1)
+}
@@ -12,10 +12,10 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/tagger"
 	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
@@ -100,7 +100,7 @@ func (d *DockerCheck) reportEvents(events []*docker.ContainerEvent, sender aggre
 	bundles := aggregateEvents(events, d.instance.FilteredEventType)
 
 	for _, bundle := range bundles {
-		ev, err := bundle.toDatadogEvent(d.dockerHostname)
+		ev, err := bundle.ToDatadogEvent(d.dockerHostname)
 		if err != nil {
 			log.Warnf("can't submit event: %s", err)
 			continue
@@ -108,7 +108,7 @@ func (d *DockerCheck) reportEvents(events []*docker.ContainerEvent, sender aggre
 
 		sender.Event(ev)
 
-		emittedEvents.Inc(string(bundle.alertType))
+		emittedEvents.Inc(string(bundle.AlertType()))
 	}
 
 	return nil
@@ -116,52 +116,24 @@ func (d *DockerCheck) reportEvents(events []*docker.ContainerEvent, sender aggre
 
 // aggregateEvents converts a bunch of ContainerEvent to bundles aggregated by
 // image name. It also filters out unwanted event types.
-func aggregateEvents(events []*docker.ContainerEvent, filteredActions []string) map[string]*dockerEventBundle {
-	// Pre-aggregate container events by image
-	eventsByImage := make(map[string]*dockerEventBundle)
-	filteredByType := make(map[string]int)
-
+func aggregateEvents(events []*docker.ContainerEvent, filteredActions []string) map[string]*generic.EventBundle {
+	containerEvents := make([]*generic.ContainerEvent, 0, len(events))
 	for _, event := range events {
-		if matchFilter(event.Action, filteredActions) {
-			filteredByType[event.Action] = filteredByType[event.Action] + 1
-			continue
-		}
-
-		bundle, found := eventsByImage[event.ImageName]
-		if found == false {
-			bundle = newDockerEventBundler(event.ImageName)
-			eventsByImage[event.ImageName] = bundle
-		}
-
-		err := bundle.addEvent(event)
-		if err != nil {
-			log.Warnf("Error while bundling events, %s.", err.Error())
-			continue
-		}
-
-		dockerEvents.Inc(event.Action)
-	}
-
-	if len(filteredByType) > 0 {
-		log.Debugf("filtered out the following events: %s", formatStringIntMap(filteredByType))
+		containerEvents = append(containerEvents, &generic.ContainerEvent{
+			ContainerID:   event.ContainerID,
+			ContainerName: event.ContainerName,
+			ImageName:     event.ImageName,
+			Action:        event.Action,
+			Timestamp:     event.Timestamp,
+		})
 	}
 
-	return eventsByImage
-}
-
-func matchFilter(item string, filterList []string) bool {
-	for _, filtered := range filterList {
-		if filtered == item {
-			return true
+	bundles := generic.BundleEvents(dockerCheckName, containerEvents, filteredActions)
+	for _, bundle := range bundles {
+		for action, count := range bundle.CountByAction() {
+			dockerEvents.Add(float64(count), action)
 		}
 	}
-	return false
-}
 
-func formatStringIntMap(input map[string]int) string {
-	var parts []string
-	for k, v := range input {
-		parts = append(parts, fmt.Sprintf("%d %s", v, k))
-	}
-	return strings.Join(parts, " ")
+	return bundles
 }
@@ -118,16 +118,21 @@ func TestReportExitCodes(t *testing.T) {
 	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 5)
 }
 
+type expectedBundle struct {
+	countByAction map[string]int
+	alertType     metrics.EventAlertType
+}
+
 func TestAggregateEvents(t *testing.T) {
 	testCases := []struct {
 		events          []*docker.ContainerEvent
 		filteredActions []string
-		output          map[string]*dockerEventBundle
+		output          map[string]expectedBundle
 	}{
 		{
 			events:          nil,
 			filteredActions: nil,
-			output:          make(map[string]*dockerEventBundle),
+			output:          make(map[string]expectedBundle),
 		},
 		{
 			// One filtered out, and one not filtered
@@ -142,9 +147,8 @@ func TestAggregateEvents(t *testing.T) {
 				},
 			},
 			filteredActions: []string{"top", "exec_create", "exec_start"},
-			output: map[string]*dockerEventBundle{
+			output: map[string]expectedBundle{
 				"test_image": {
-					imageName: "test_image",
 					countByAction: map[string]int{
 						"unfiltered_action": 1,
 					},
@@ -161,7 +165,7 @@ func TestAggregateEvents(t *testing.T) {
 				},
 			},
 			filteredActions: []string{"top", "exec_create", "exec_start"},
-			output:          map[string]*dockerEventBundle{},
+			output:          map[string]expectedBundle{},
 		},
 		{
 			// 2+1 events, to count correctly
@@ -180,9 +184,8 @@ func TestAggregateEvents(t *testing.T) {
 				},
 			},
 			filteredActions: []string{"top", "exec_create", "exec_start"},
-			output: map[string]*dockerEventBundle{
+			output: map[string]expectedBundle{
 				"test_image": {
-					imageName: "test_image",
 					countByAction: map[string]int{
 						"unfiltered_action": 2,
 						"other_action":      1,
@@ -212,9 +215,8 @@ func TestAggregateEvents(t *testing.T) {
 				},
 			},
 			filteredActions: []string{"top", "exec_create", "exec_start"},
-			output: map[string]*dockerEventBundle{
+			output: map[string]expectedBundle{
 				"test_image": {
-					imageName: "test_image",
 					countByAction: map[string]int{
 						"unfiltered_action": 2,
 						"other_action":      1,
@@ -222,7 +224,6 @@ func TestAggregateEvents(t *testing.T) {
 					alertType: metrics.EventAlertTypeInfo,
 				},
 				"other_image": {
-					imageName: "other_image",
 					countByAction: map[string]int{
 						"other_action": 1,
 					},
@@ -234,13 +235,16 @@ func TestAggregateEvents(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run("", func(t *testing.T) {
 			bundles := aggregateEvents(tc.events, tc.filteredActions)
-			for _, b := range bundles {
-				// Strip underlying events to ease testing
-				// countByAction is enough for testing the
-				// filtering and aggregation
-				b.events = nil
+			// countByAction is enough for testing the
+			// filtering and aggregation
+			output := make(map[string]expectedBundle, len(bundles))
+			for imageName, b := range bundles {
+				output[imageName] = expectedBundle{
+					countByAction: b.CountByAction(),
+					alertType:     b.AlertType(),
+				}
 			}
-			assert.EqualValues(t, tc.output, bundles)
+			assert.EqualValues(t, tc.output, output)
 		})
 	}
 }
@@ -6,9 +6,13 @@
 package generic
 
 import (
+	"strings"
+
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
+const genericMetricsPrefix = "container."
+
 // MetricsAdapter provides a way to change metrics and tags before sending them out
 type MetricsAdapter interface {
 	// AdaptTags can be used to change Tagger tags before submitting the metrics
@@ -43,3 +47,23 @@ func (a GenericMetricsAdapter) AdaptTags(tags []string, c *workloadmeta.Containe
 func (a GenericMetricsAdapter) AdaptMetrics(metricName string, value float64) (string, float64) {
 	return metricName, value
 }
+
+// RuntimeMetricsAdapter implements MetricsAdapter for the checks of a single runtime.
+// Adds the `runtime` tag and replaces the `container.` prefix of metrics by the check name.
+type RuntimeMetricsAdapter struct {
+	CheckName string
+	Runtime   workloadmeta.ContainerRuntime
+}
+
+// AdaptTags adds a `runtime` tag for all containers
+func (a RuntimeMetricsAdapter) AdaptTags(tags []string, c *workloadmeta.Container) []string {
+	return append(tags, "runtime:"+string(a.Runtime))
+}
+
+// AdaptMetrics prefixes the generic metrics with the check name
+func (a RuntimeMetricsAdapter) AdaptMetrics(metricName string, value float64) (string, float64) {
+	if strings.HasPrefix(metricName, genericMetricsPrefix) {
+		return a.CheckName + "." + strings.TrimPrefix(metricName, genericMetricsPrefix), value
+	}
+	return metricName, value
+}
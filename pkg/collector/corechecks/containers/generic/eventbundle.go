@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// EventBundle holds a list of ContainerEvent identified as coming from the same image.
+// It holds the conversion logic to Datadog events for submission.
+type EventBundle struct {
+	source        string
+	imageName     string
+	events        []*ContainerEvent
+	maxTimestamp  time.Time
+	countByAction map[string]int
+	alertType     metrics.EventAlertType
+}
+
+// NewEventBundle returns a new EventBundle for events of the given image.
+// `source` is used as source type name and event type.
+func NewEventBundle(source, imageName string) *EventBundle {
+	return &EventBundle{
+		source:        source,
+		imageName:     imageName,
+		events:        []*ContainerEvent{},
+		countByAction: make(map[string]int),
+		alertType:     metrics.EventAlertTypeInfo,
+	}
+}
+
+// AlertType returns the alert type of the bundle
+func (b *EventBundle) AlertType() metrics.EventAlertType {
+	return b.alertType
+}
+
+// CountByAction returns the number of events of the bundle for each action
+func (b *EventBundle) CountByAction() map[string]int {
+	return b.countByAction
+}
+
+func (b *EventBundle) addEvent(event *ContainerEvent) error {
+	if event.ImageName != b.imageName {
+		return fmt.Errorf("mismatching image name: %s != %s", event.ImageName, b.imageName)
+	}
+
+	b.events = append(b.events, event)
+	b.countByAction[event.Action]++
+
+	if event.Timestamp.After(b.maxTimestamp) {
+		b.maxTimestamp = event.Timestamp
+	}
+
+	if event.Action == ContainerActionOOM || event.Action == ContainerActionKill {
+		b.alertType = metrics.EventAlertTypeError
+	}
+
+	return nil
+}
+
+// ToDatadogEvent converts the bundle to a single Datadog event
+func (b *EventBundle) ToDatadogEvent(hostname string) (metrics.Event, error) {
+	if len(b.events) == 0 {
+		return metrics.Event{}, errors.New("no event to export")
+	}
+
+	output := metrics.Event{
+		Title: fmt.Sprintf("%s %s on %s",
+			b.imageName,
+			formatActionCount(b.countByAction),
+			hostname,
+		),
+		Priority:       metrics.EventPriorityNormal,
+		Host:           hostname,
+		SourceTypeName: b.source,
+		EventType:      b.source,
+		AlertType:      b.alertType,
+		Ts:             b.maxTimestamp.Unix(),
+		AggregationKey: fmt.Sprintf("%s:%s", b.source, b.imageName),
+	}
+
+	seenContainers := make(map[string]bool)
+	textLines := []string{"%%% ", output.Title, "```"}
+
+	for _, ev := range b.events {
+		textLines = append(textLines, fmt.Sprintf("%s\t%s", strings.ToUpper(ev.Action), ev.ContainerName))
+		seenContainers[ev.ContainerID] = true // Emulating a set with a map
+	}
+	textLines = append(textLines, "```", " %%%")
+	output.Text = strings.Join(textLines, "\n")
+
+	for cid := range seenContainers {
+		tags, err := tagger.Tag(containers.BuildTaggerEntityName(cid), collectors.HighCardinality)
+		if err != nil {
+			log.Debugf("no tags for %s: %s", cid, err)
+		} else {
+			output.Tags = append(output.Tags, tags...)
+		}
+	}
+
+	return output, nil
+}
+
+// BundleEvents aggregates ContainerEvent by image name, skipping actions listed in filteredActions
+func BundleEvents(source string, events []*ContainerEvent, filteredActions []string) map[string]*EventBundle {
+	eventsByImage := make(map[string]*EventBundle)
+	filteredByType := make(map[string]int)
+
+	for _, event := range events {
+		if isFilteredAction(event.Action, filteredActions) {
+			filteredByType[event.Action]++
+			continue
+		}
+
+		bundle, found := eventsByImage[event.ImageName]
+		if !found {
+			bundle = NewEventBundle(source, event.ImageName)
+			eventsByImage[event.ImageName] = bundle
+		}
+
+		if err := bundle.addEvent(event); err != nil {
+			log.Warnf("Error while bundling events, %s.", err.Error())
+		}
+	}
+
+	if len(filteredByType) > 0 {
+		log.Debugf("filtered out the following events: %s", formatActionCount(filteredByType))
+	}
+
+	return eventsByImage
+}
+
+func isFilteredAction(action string, filteredActions []string) bool {
+	for _, filtered := range filteredActions {
+		if filtered == action {
+			return true
+		}
+	}
+	return false
+}
+
+func formatActionCount(input map[string]int) string {
+	parts := make([]string, 0, len(input))
+	for k, v := range input {
+		parts = append(parts, fmt.Sprintf("%d %s", v, k))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Container actions reported as runtime events, named after their Docker counterparts
+const (
+	ContainerActionCreate  = "create"
+	ContainerActionStart   = "start"
+	ContainerActionDie     = "die"
+	ContainerActionKill    = "kill"
+	ContainerActionOOM     = "oom"
+	ContainerActionDestroy = "destroy"
+)
+
+// ContainerEvent is a runtime-agnostic container event
+type ContainerEvent struct {
+	ContainerID   string
+	ContainerName string
+	ImageName     string
+	Action        string
+	Timestamp     time.Time
+	ExitCode      *int64
+}
+
+// ContainerLifecycleState is a snapshot of a container state as reported by its runtime
+type ContainerLifecycleState struct {
+	ID         string
+	Name       string
+	ImageName  string
+	Running    bool
+	CreatedAt  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+	ExitCode   *int64
+	OOMKilled  bool
+}
+
+// ContainerEventTracker generates ContainerEvent by comparing successive ContainerLifecycleState snapshots.
+// It's meant for runtimes that do not expose an event stream.
+type ContainerEventTracker struct {
+	containers  map[string]ContainerLifecycleState
+	initialized bool
+}
+
+// NewContainerEventTracker returns a new ContainerEventTracker
+func NewContainerEventTracker() *ContainerEventTracker {
+	return &ContainerEventTracker{
+		containers: make(map[string]ContainerLifecycleState),
+	}
+}
+
+// Update stores the current states and returns the events that happened since the previous call.
+// The first call only records the current states, no event is generated.
+func (t *ContainerEventTracker) Update(states []ContainerLifecycleState, now time.Time) []*ContainerEvent {
+	var events []*ContainerEvent
+	current := make(map[string]ContainerLifecycleState, len(states))
+
+	for _, state := range states {
+		current[state.ID] = state
+		if !t.initialized {
+			continue
+		}
+
+		prev, found := t.containers[state.ID]
+		var containerEvents []*ContainerEvent
+		if !found {
+			containerEvents = append(containerEvents, newContainerEvent(state, ContainerActionCreate, timeOrNow(state.CreatedAt, now)))
+			prev = ContainerLifecycleState{ID: state.ID}
+		}
+
+		// Some runtimes only expose the start time once the container exited, it's only used to detect restarts
+		restarted := state.StartedAt.After(prev.StartedAt) && (!found || !prev.StartedAt.IsZero())
+		if (state.Running && !prev.Running) || restarted {
+			containerEvents = append(containerEvents, newContainerEvent(state, ContainerActionStart, timeOrNow(state.StartedAt, now)))
+		}
+
+		// A container may have died and restarted between two updates
+		if (!state.Running && prev.Running) || state.FinishedAt.After(prev.FinishedAt) {
+			finishedAt := timeOrNow(state.FinishedAt, now)
+			if state.OOMKilled {
+				containerEvents = append(containerEvents, newContainerEvent(state, ContainerActionOOM, finishedAt))
+			}
+			containerEvents = append(containerEvents, newContainerEvent(state, ContainerActionDie, finishedAt))
+		}
+
+		sort.SliceStable(containerEvents, func(i, j int) bool {
+			return containerEvents[i].Timestamp.Before(containerEvents[j].Timestamp)
+		})
+		events = append(events, containerEvents...)
+	}
+
+	for id, prev := range t.containers {
+		if _, found := current[id]; !found {
+			events = append(events, newContainerEvent(prev, ContainerActionDestroy, now))
+		}
+	}
+
+	t.containers = current
+	t.initialized = true
+
+	return events
+}
+
+func newContainerEvent(state ContainerLifecycleState, action string, ts time.Time) *ContainerEvent {
+	event := &ContainerEvent{
+		ContainerID:   state.ID,
+		ContainerName: state.Name,
+		ImageName:     state.ImageName,
+		Action:        action,
+		Timestamp:     ts,
+	}
+
+	if action == ContainerActionDie {
+		event.ExitCode = state.ExitCode
+	}
+
+	return event
+}
+
+func timeOrNow(ts, now time.Time) time.Time {
+	if ts.IsZero() {
+		return now
+	}
+	return ts
+}
+
+// ReportExitCodes sends a service check for each `die` event with an exit code.
+// The service check is critical if the exit code is not part of okExitCodes.
+func ReportExitCodes(sender aggregator.Sender, serviceCheckName string, events []*ContainerEvent, okExitCodes map[int64]struct{}) {
+	for _, ev := range events {
+		if ev.Action != ContainerActionDie || ev.ExitCode == nil {
+			continue
+		}
+
+		exitCode := *ev.ExitCode
+		message := fmt.Sprintf("Container %s exited with %d", ev.ContainerName, exitCode)
+		status := metrics.ServiceCheckOK
+		if _, ok := okExitCodes[exitCode]; !ok {
+			status = metrics.ServiceCheckCritical
+		}
+
+		tags, err := tagger.Tag(containers.BuildTaggerEntityName(ev.ContainerID), collectors.HighCardinality)
+		if err != nil {
+			log.Debugf("no tags for %s: %s", ev.ContainerID, err)
+			tags = []string{}
+		}
+
+		tags = append(tags, "exit_code:"+strconv.FormatInt(exitCode, 10))
+		sender.ServiceCheck(serviceCheckName, status, "", tags, message)
+	}
+}
+
+// OkExitCodes returns the set of exit codes considered as successful,
+// defaulting to 0 and 143 (SIGTERM sent when stopping a container).
+func OkExitCodes(configured []int) map[int64]struct{} {
+	if len(configured) == 0 {
+		return map[int64]struct{}{0: {}, 143: {}}
+	}
+
+	okExitCodes := make(map[int64]struct{}, len(configured))
+	for _, code := range configured {
+		okExitCodes[int64(code)] = struct{}{}
+	}
+
+	return okExitCodes
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func eventActions(events []*ContainerEvent) []string {
+	actions := make([]string, 0, len(events))
+	for _, ev := range events {
+		actions = append(actions, ev.ContainerID+":"+ev.Action)
+	}
+	return actions
+}
+
+func TestContainerEventTracker(t *testing.T) {
+	now := time.Now()
+	createdAt := now.Add(-10 * time.Minute)
+	startedAt := now.Add(-9 * time.Minute)
+	exitCode := int64(137)
+
+	tracker := NewContainerEventTracker()
+
+	// First update does not generate events
+	events := tracker.Update([]ContainerLifecycleState{
+		{ID: "running", Running: true, CreatedAt: createdAt, StartedAt: startedAt},
+		{ID: "removed", Running: true, CreatedAt: createdAt, StartedAt: startedAt},
+		{ID: "restarted", Running: true, CreatedAt: createdAt, StartedAt: startedAt},
+	}, now)
+	assert.Empty(t, events)
+
+	events = tracker.Update([]ContainerLifecycleState{
+		{ID: "running", Running: false, CreatedAt: createdAt, StartedAt: startedAt, FinishedAt: now.Add(-2 * time.Minute), ExitCode: &exitCode, OOMKilled: true},
+		{ID: "restarted", Running: true, CreatedAt: createdAt, StartedAt: now.Add(-time.Minute), FinishedAt: now.Add(-2 * time.Minute)},
+		{ID: "new", Running: false, CreatedAt: now.Add(-5 * time.Minute), StartedAt: now.Add(-4 * time.Minute), FinishedAt: now.Add(-3 * time.Minute)},
+	}, now)
+	assert.Equal(t, []string{
+		"running:oom",
+		"running:die",
+		"restarted:die",
+		"restarted:start",
+		"new:create",
+		"new:start",
+		"new:die",
+		"removed:destroy",
+	}, eventActions(events))
+	assert.Equal(t, &exitCode, events[1].ExitCode)
+
+	// No change, no event
+	events = tracker.Update([]ContainerLifecycleState{
+		{ID: "restarted", Running: true, CreatedAt: createdAt, StartedAt: now.Add(-time.Minute), FinishedAt: now.Add(-2 * time.Minute)},
+	}, now)
+	assert.ElementsMatch(t, []string{"running:destroy", "new:destroy"}, eventActions(events))
+}
+
+func TestBundleEvents(t *testing.T) {
+	now := time.Now()
+	events := []*ContainerEvent{
+		{ContainerID: "1", ContainerName: "foo", ImageName: "redis", Action: ContainerActionStart, Timestamp: now.Add(-time.Minute)},
+		{ContainerID: "1", ContainerName: "foo", ImageName: "redis", Action: ContainerActionOOM, Timestamp: now},
+		{ContainerID: "2", ContainerName: "bar", ImageName: "nginx", Action: ContainerActionCreate, Timestamp: now},
+		{ContainerID: "2", ContainerName: "bar", ImageName: "nginx", Action: ContainerActionStart, Timestamp: now},
+	}
+
+	bundles := BundleEvents("podman", events, []string{ContainerActionCreate})
+	assert.Len(t, bundles, 2)
+
+	ev, err := bundles["redis"].ToDatadogEvent("testhostname")
+	assert.NoError(t, err)
+	assert.Equal(t, "redis 1 oom 1 start on testhostname", ev.Title)
+	assert.Equal(t, "podman:redis", ev.AggregationKey)
+	assert.Equal(t, now.Unix(), ev.Ts)
+	assert.Equal(t, "error", string(ev.AlertType))
+
+	ev, err = bundles["nginx"].ToDatadogEvent("testhostname")
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx 1 start on testhostname", ev.Title)
+	assert.Equal(t, "info", string(ev.AlertType))
+}
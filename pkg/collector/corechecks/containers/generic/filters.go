@@ -63,3 +63,14 @@ type RuntimeContainerFilter struct {
 func (f RuntimeContainerFilter) IsExcluded(container *workloadmeta.Container) bool {
 	return container.Runtime != f.Runtime
 }
+
+// NewRuntimeFilter returns a ContainerFilter rejecting the containers not run by the given runtime
+// and the ones excluded by the legacy filter
+func NewRuntimeFilter(runtime workloadmeta.ContainerRuntime, legacyFilter *containers.Filter) ContainerFilter {
+	return ANDContainerFilter{
+		Filters: []ContainerFilter{
+			RuntimeContainerFilter{Runtime: runtime},
+			LegacyContainerFilter{OldFilter: legacyFilter},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// RuntimeEventsConfig holds the configuration of the checks reporting events with a ContainerEventTracker
+type RuntimeEventsConfig struct {
+	CollectEvents      bool     `yaml:"collect_events"`
+	FilteredEventTypes []string `yaml:"filtered_event_types"`
+	CollectExitCodes   bool     `yaml:"collect_exit_codes"`
+	OkExitCodes        []int    `yaml:"ok_exit_codes"`
+}
+
+// Parse parses the config and set default values
+func (c *RuntimeEventsConfig) Parse(data []byte) error {
+	// default values
+	c.CollectEvents = true
+	c.CollectExitCodes = true
+
+	return yaml.Unmarshal(data, c)
+}
+
+// RuntimeEventsReporter reports the events and exit codes of the containers of a runtime
+// that does not expose an event stream, by comparing successive states of its containers.
+type RuntimeEventsReporter struct {
+	source           string
+	exitServiceCheck string
+	hostname         string
+	config           *RuntimeEventsConfig
+	tracker          *ContainerEventTracker
+	okExitCodes      map[int64]struct{}
+	emittedEvents    telemetry.Counter
+}
+
+// NewRuntimeEventsReporter returns a new RuntimeEventsReporter.
+// `source` is used as source type name of the events, emittedEvents counts them by alert type.
+func NewRuntimeEventsReporter(source, exitServiceCheck, hostname string, config *RuntimeEventsConfig, emittedEvents telemetry.Counter) *RuntimeEventsReporter {
+	return &RuntimeEventsReporter{
+		source:           source,
+		exitServiceCheck: exitServiceCheck,
+		hostname:         hostname,
+		config:           config,
+		tracker:          NewContainerEventTracker(),
+		okExitCodes:      OkExitCodes(config.OkExitCodes),
+		emittedEvents:    emittedEvents,
+	}
+}
+
+// Enabled returns true if events or exit codes should be reported
+func (r *RuntimeEventsReporter) Enabled() bool {
+	return r.config.CollectEvents || r.config.CollectExitCodes
+}
+
+// Report generates the events that happened since the previous call and sends them
+func (r *RuntimeEventsReporter) Report(sender aggregator.Sender, states []ContainerLifecycleState, now time.Time) {
+	events := r.tracker.Update(states, now)
+
+	if r.config.CollectExitCodes {
+		ReportExitCodes(sender, r.exitServiceCheck, events, r.okExitCodes)
+	}
+
+	if !r.config.CollectEvents {
+		return
+	}
+
+	for _, bundle := range BundleEvents(r.source, events, r.config.FilteredEventTypes) {
+		ev, err := bundle.ToDatadogEvent(r.hostname)
+		if err != nil {
+			log.Warnf("can't submit event: %s", err)
+			continue
+		}
+
+		sender.Event(ev)
+
+		r.emittedEvents.Inc(string(bundle.AlertType()))
+	}
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build podman
+// +build podman
+
+package podman
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/v2/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/podman"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	podmanCheckName = "podman"
+	cacheValidity   = 2 * time.Second
+
+	// PodmanExit is the name of the service check sent for containers exit codes
+	PodmanExit = "podman.exit"
+)
+
+type podmanClient interface {
+	GetAllContainers() ([]podman.Container, error)
+}
+
+// PodmanCheck grabs Podman metrics and events
+type PodmanCheck struct {
+	core.CheckBase
+	instance        *generic.RuntimeEventsConfig
+	processor       generic.Processor
+	client          podmanClient
+	containerFilter *containers.Filter
+	eventsReporter  *generic.RuntimeEventsReporter
+}
+
+func init() {
+	core.RegisterCheck(podmanCheckName, PodmanFactory)
+}
+
+// PodmanFactory is exported for integration testing
+func PodmanFactory() check.Check {
+	return &PodmanCheck{
+		CheckBase: core.NewCheckBase(podmanCheckName),
+		instance:  &generic.RuntimeEventsConfig{},
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *PodmanCheck) Configure(config, initConfig integration.Data, source string) error {
+	var err error
+	if err = c.CommonConfigure(config, source); err != nil {
+		return err
+	}
+
+	if err = c.instance.Parse(config); err != nil {
+		return err
+	}
+
+	c.containerFilter, err = containers.GetSharedMetricFilter()
+	if err != nil {
+		log.Warnf("Can't get container include/exclude filter, no filtering will be applied: %v", err)
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Can't get hostname, events will not have it: %v", err)
+	}
+
+	c.client = podman.NewDBClient(podman.DefaultDBPath)
+	c.eventsReporter = generic.NewRuntimeEventsReporter(podmanCheckName, PodmanExit, hostname, c.instance, emittedEvents)
+	c.processor = generic.NewProcessor(
+		metrics.GetProvider(),
+		generic.MetadataContainerAccessor{},
+		generic.RuntimeMetricsAdapter{CheckName: podmanCheckName, Runtime: workloadmeta.ContainerRuntimePodman},
+		generic.NewRuntimeFilter(workloadmeta.ContainerRuntimePodman, c.containerFilter),
+	)
+
+	return nil
+}
+
+// Run executes the check
+func (c *PodmanCheck) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	if err := c.runProcessor(sender); err != nil {
+		_ = c.Warnf("Error collecting metrics: %s", err)
+	}
+
+	if c.eventsReporter.Enabled() {
+		if err := c.runEvents(sender); err != nil {
+			_ = c.Warnf("Error collecting events: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *PodmanCheck) runProcessor(sender aggregator.Sender) error {
+	return c.processor.Run(sender, cacheValidity)
+}
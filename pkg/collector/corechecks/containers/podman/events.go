@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build podman
+// +build podman
+
+package podman
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/podman"
+)
+
+var emittedEvents = telemetry.NewCounterWithOpts(
+	podmanCheckName,
+	"emitted_events",
+	[]string{"type"},
+	"Number of events emitted by the check.",
+	telemetry.Options{NoDoubleUnderscoreSep: true},
+)
+
+// runEvents derives container events from the Podman DB state and reports them
+func (c *PodmanCheck) runEvents(sender aggregator.Sender) error {
+	podmanContainers, err := c.client.GetAllContainers()
+	if err != nil {
+		return err
+	}
+
+	states := make([]generic.ContainerLifecycleState, 0, len(podmanContainers))
+	for i := range podmanContainers {
+		container := &podmanContainers[i]
+		if c.containerFilter != nil && c.containerFilter.IsExcluded(container.Config.Name, container.Config.RawImageName, "") {
+			continue
+		}
+
+		states = append(states, toLifecycleState(container))
+	}
+
+	c.eventsReporter.Report(sender, states, time.Now())
+
+	return nil
+}
+
+func toLifecycleState(container *podman.Container) generic.ContainerLifecycleState {
+	state := generic.ContainerLifecycleState{
+		ID:         container.Config.ID,
+		Name:       container.Config.Name,
+		ImageName:  container.Config.RawImageName,
+		CreatedAt:  container.Config.CreatedTime,
+		Running:    container.State.State == podman.ContainerStateRunning,
+		StartedAt:  container.State.StartedTime,
+		FinishedAt: container.State.FinishedTime,
+		OOMKilled:  container.State.OOMKilled,
+	}
+
+	if container.State.State == podman.ContainerStateStopped || container.State.State == podman.ContainerStateExited {
+		exitCode := int64(container.State.ExitCode)
+		state.ExitCode = &exitCode
+	}
+
+	return state
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build podman
+// +build podman
+
+package podman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/containers/generic"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/podman"
+)
+
+type fakePodmanClient struct {
+	containers []podman.Container
+}
+
+func (c *fakePodmanClient) GetAllContainers() ([]podman.Container, error) {
+	return c.containers, nil
+}
+
+func fakeContainer(id string, status podman.ContainerStatus, startedAt, finishedAt time.Time, exitCode int32, oomKilled bool) podman.Container {
+	config := &podman.ContainerConfig{
+		ID:           id,
+		Name:         "name-" + id,
+		RawImageName: "docker.io/library/redis:latest",
+	}
+	config.CreatedTime = startedAt
+
+	return podman.Container{
+		Config: config,
+		State: &podman.ContainerState{
+			State:        status,
+			StartedTime:  startedAt,
+			FinishedTime: finishedAt,
+			ExitCode:     exitCode,
+			OOMKilled:    oomKilled,
+		},
+	}
+}
+
+func TestRunEvents(t *testing.T) {
+	startedAt := time.Now().Add(-time.Minute)
+	finishedAt := time.Now()
+
+	client := &fakePodmanClient{
+		containers: []podman.Container{
+			fakeContainer("1", podman.ContainerStateRunning, startedAt, time.Time{}, 0, false),
+			fakeContainer("2", podman.ContainerStateRunning, startedAt, time.Time{}, 0, false),
+		},
+	}
+
+	check := &PodmanCheck{
+		client:         client,
+		eventsReporter: generic.NewRuntimeEventsReporter(podmanCheckName, PodmanExit, "testhostname", &generic.RuntimeEventsConfig{CollectEvents: true, CollectExitCodes: true}, emittedEvents),
+	}
+
+	mockSender := mocksender.NewMockSender(check.ID())
+	mockSender.SetupAcceptAll()
+
+	// First run only records the current state
+	assert.NoError(t, check.runEvents(mockSender))
+	mockSender.AssertNumberOfCalls(t, "Event", 0)
+	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 0)
+
+	// Container 1 is OOM killed, container 2 is removed
+	client.containers = []podman.Container{
+		fakeContainer("1", podman.ContainerStateExited, startedAt, finishedAt, 137, true),
+	}
+	assert.NoError(t, check.runEvents(mockSender))
+
+	mockSender.AssertServiceCheck(t, PodmanExit, metrics.ServiceCheckCritical, "", []string{"exit_code:137"}, "Container name-1 exited with 137")
+	mockSender.AssertCalled(t, "Event", mock.MatchedBy(func(ev metrics.Event) bool {
+		return ev.AlertType == metrics.EventAlertTypeError &&
+			ev.SourceTypeName == podmanCheckName &&
+			ev.Title == "docker.io/library/redis:latest 1 destroy 1 die 1 oom on testhostname"
+	}))
+}
@@ -0,0 +1,234 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package podresources
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	yaml "gopkg.in/yaml.v2"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	checkName         = "kubelet_pod_resources"
+	metricsPrefix     = "kubernetes.pod_resources."
+	defaultSocketPath = "/var/lib/kubelet/pod-resources/kubelet.sock"
+	defaultTimeout    = 5
+)
+
+func init() {
+	core.RegisterCheck(checkName, Factory)
+}
+
+// podResourcesLister abstracts the kubelet PodResources API for testing
+type podResourcesLister interface {
+	ListPodResources(ctx context.Context) ([]*podresourcesv1.PodResources, error)
+	GetAllocatableResources(ctx context.Context) (*podresourcesv1.AllocatableResourcesResponse, error)
+}
+
+// Config holds the kubelet_pod_resources check configuration
+type Config struct {
+	SocketPath     string `yaml:"pod_resources_socket"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`
+}
+
+// Parse parses the kubelet_pod_resources check config and set default values
+func (c *Config) Parse(data []byte) error {
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return err
+	}
+
+	if c.SocketPath == "" {
+		c.SocketPath = defaultSocketPath
+	}
+
+	if c.TimeoutSeconds <= 0 {
+		c.TimeoutSeconds = defaultTimeout
+	}
+
+	return nil
+}
+
+// Check reports the devices, exclusive CPUs and memory allocated to containers by the kubelet
+type Check struct {
+	core.CheckBase
+	instance            *Config
+	client              podResourcesLister
+	podLister           func(ctx context.Context) ([]*kubelet.Pod, error)
+	allocatableDisabled bool
+}
+
+// Factory is exported for integration testing
+func Factory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(checkName),
+		instance:  &Config{},
+	}
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(config, initConfig integration.Data, source string) error {
+	err := c.CommonConfigure(config, source)
+	if err != nil {
+		return err
+	}
+
+	if c.podLister == nil {
+		c.podLister = func(ctx context.Context) ([]*kubelet.Pod, error) {
+			ku, err := kubelet.GetKubeUtil()
+			if err != nil {
+				return nil, err
+			}
+			return ku.GetLocalPodList(ctx)
+		}
+	}
+
+	return c.instance.Parse(config)
+}
+
+// Run executes the check
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	timeout := time.Duration(c.instance.TimeoutSeconds) * time.Second
+
+	if c.client == nil {
+		client, err := kubelet.NewPodResourcesClient(c.instance.SocketPath, timeout)
+		if err != nil {
+			return err
+		}
+		c.client = client
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	podResources, err := c.client.ListPodResources(ctx)
+	if err != nil {
+		return err
+	}
+
+	containerIDs := c.containerIDs(ctx)
+	for _, pod := range podResources {
+		for _, container := range pod.GetContainers() {
+			tags := containerTags(pod, container, containerIDs)
+			reportAllocated(sender, container.GetDevices(), container.GetCpuIds(), container.GetMemory(), "allocated", tags)
+		}
+	}
+
+	if !c.allocatableDisabled {
+		allocatable, err := c.client.GetAllocatableResources(ctx)
+		if status.Code(err) == codes.Unimplemented {
+			log.Infof("The kubelet doesn't support listing allocatable resources (Kubernetes 1.21+ required), node level metrics are disabled")
+			c.allocatableDisabled = true
+		} else if err != nil {
+			log.Debugf("Unable to list allocatable resources: %v", err)
+		} else {
+			reportAllocated(sender, allocatable.GetDevices(), allocatable.GetCpuIds(), allocatable.GetMemory(), "allocatable", nil)
+		}
+	}
+
+	return nil
+}
+
+// containerIDs returns the container IDs by namespace, pod name and container name
+func (c *Check) containerIDs(ctx context.Context) map[string]string {
+	ids := make(map[string]string)
+
+	pods, err := c.podLister(ctx)
+	if err != nil {
+		log.Debugf("Unable to list pods from the kubelet, containers will only be tagged with their names: %v", err)
+		return ids
+	}
+
+	for _, pod := range pods {
+		for _, container := range pod.Status.GetAllContainers() {
+			if container.ID != "" {
+				ids[containerKey(pod.Metadata.Namespace, pod.Metadata.Name, container.Name)] = container.ID
+			}
+		}
+	}
+
+	return ids
+}
+
+func containerKey(namespace, podName, containerName string) string {
+	return namespace + "/" + podName + "/" + containerName
+}
+
+// containerTags returns the tagger tags of the container, or its names if it's unknown to the kubelet
+func containerTags(pod *podresourcesv1.PodResources, container *podresourcesv1.ContainerResources, containerIDs map[string]string) []string {
+	fallback := []string{
+		"kube_namespace:" + pod.GetNamespace(),
+		"pod_name:" + pod.GetName(),
+		"kube_container_name:" + container.GetName(),
+	}
+
+	id, found := containerIDs[containerKey(pod.GetNamespace(), pod.GetName(), container.GetName())]
+	if !found {
+		return fallback
+	}
+
+	entityID, err := kubelet.KubeContainerIDToTaggerEntityID(id)
+	if err != nil {
+		return fallback
+	}
+
+	tags, err := tagger.Tag(entityID, collectors.OrchestratorCardinality)
+	if err != nil || len(tags) == 0 {
+		log.Debugf("No tags for container %s: %v", id, err)
+		return fallback
+	}
+
+	return tags
+}
+
+// reportAllocated sends the device, CPU and memory gauges for a container or the node
+func reportAllocated(sender aggregator.Sender, devices []*podresourcesv1.ContainerDevices, cpuIDs []int64, memory []*podresourcesv1.ContainerMemory, suffix string, tags []string) {
+	deviceCount := make(map[string]int)
+	for _, device := range devices {
+		deviceCount[device.GetResourceName()] += len(device.GetDeviceIds())
+	}
+	for resourceName, count := range deviceCount {
+		sender.Gauge(metricsPrefix+"devices."+suffix, float64(count), "", append(copyTags(tags), "resource_name:"+resourceName))
+	}
+
+	// Only containers of Guaranteed pods with the static CPU manager policy get exclusive CPUs
+	if len(cpuIDs) > 0 {
+		sender.Gauge(metricsPrefix+"cpus."+suffix, float64(len(cpuIDs)), "", tags)
+	}
+
+	memorySize := make(map[string]uint64)
+	for _, mem := range memory {
+		memorySize[mem.GetMemoryType()] += mem.GetSize_()
+	}
+	for memoryType, size := range memorySize {
+		sender.Gauge(metricsPrefix+"memory."+suffix, float64(size), "", append(copyTags(tags), "memory_type:"+memoryType))
+	}
+}
+
+func copyTags(tags []string) []string {
+	return append(make([]string, 0, len(tags)+1), tags...)
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package podresources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/local"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+)
+
+type fakeLister struct {
+	pods           []*podresourcesv1.PodResources
+	allocatable    *podresourcesv1.AllocatableResourcesResponse
+	allocatableErr error
+}
+
+func (f *fakeLister) ListPodResources(ctx context.Context) ([]*podresourcesv1.PodResources, error) {
+	return f.pods, nil
+}
+
+func (f *fakeLister) GetAllocatableResources(ctx context.Context) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	return f.allocatable, f.allocatableErr
+}
+
+func TestRun(t *testing.T) {
+	fakeTagger := local.NewFakeTagger()
+	fakeTagger.SetTags("container_id://abc", "foo", []string{"kube_deployment:dpdk"}, []string{"pod_name:dpdk-0"}, nil, nil)
+	defaultTagger := tagger.GetDefaultTagger()
+	tagger.SetDefaultTagger(fakeTagger)
+	t.Cleanup(func() { tagger.SetDefaultTagger(defaultTagger) })
+
+	lister := &fakeLister{
+		pods: []*podresourcesv1.PodResources{
+			{
+				Name:      "dpdk-0",
+				Namespace: "net",
+				Containers: []*podresourcesv1.ContainerResources{
+					{
+						Name: "dpdk",
+						Devices: []*podresourcesv1.ContainerDevices{
+							{ResourceName: "intel.com/sriov_netdevice", DeviceIds: []string{"0000:3b:02.0", "0000:3b:02.1"}},
+						},
+						CpuIds: []int64{3, 1, 2, 8},
+						Memory: []*podresourcesv1.ContainerMemory{
+							{MemoryType: "hugepages-1Gi", Size_: 2 << 30},
+						},
+					},
+				},
+			},
+			{
+				Name:       "unknown",
+				Namespace:  "default",
+				Containers: []*podresourcesv1.ContainerResources{{Name: "gpu", Devices: []*podresourcesv1.ContainerDevices{{ResourceName: "nvidia.com/gpu", DeviceIds: []string{"GPU-1"}}}}},
+			},
+		},
+		allocatableErr: status.Error(codes.Unimplemented, "unknown method"),
+	}
+
+	c := Factory().(*Check)
+	c.client = lister
+	c.podLister = func(ctx context.Context) ([]*kubelet.Pod, error) {
+		return []*kubelet.Pod{{
+			Metadata: kubelet.PodMetadata{Name: "dpdk-0", Namespace: "net"},
+			Status: kubelet.Status{
+				AllContainers: []kubelet.ContainerStatus{{Name: "dpdk", ID: "containerd://abc"}},
+			},
+		}}, nil
+	}
+	assert.NoError(t, c.Configure(nil, nil, "test"))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+
+	assert.NoError(t, c.Run())
+	tags := []string{"kube_deployment:dpdk", "pod_name:dpdk-0"}
+	sender.AssertMetric(t, "Gauge", "kubernetes.pod_resources.devices.allocated", 2, "", append(tags, "resource_name:intel.com/sriov_netdevice"))
+	sender.AssertMetric(t, "Gauge", "kubernetes.pod_resources.cpus.allocated", 4, "", tags)
+	sender.AssertMetric(t, "Gauge", "kubernetes.pod_resources.memory.allocated", 2<<30, "", append(tags, "memory_type:hugepages-1Gi"))
+	sender.AssertMetric(t, "Gauge", "kubernetes.pod_resources.devices.allocated", 1, "", []string{"kube_namespace:default", "pod_name:unknown", "kube_container_name:gpu", "resource_name:nvidia.com/gpu"})
+	assert.True(t, c.allocatableDisabled)
+
+	lister.allocatableErr = nil
+	lister.allocatable = &podresourcesv1.AllocatableResourcesResponse{CpuIds: []int64{0, 1, 2, 3}}
+	c.allocatableDisabled = false
+	assert.NoError(t, c.Run())
+	sender.AssertMetric(t, "Gauge", "kubernetes.pod_resources.cpus.allocatable", 4, "", nil)
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// The `cgo` build tag is required for the same reason as the ebpf checks:
+// github.com/DataDog/datadog-agent/pkg/process/net depends on `github.com/DataDog/agent-payload/v5/process`,
+// which requires CGO.
+//go:build cgo && linux
+// +build cgo,linux
+
+package networkpath
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ddConfig "github.com/DataDog/datadog-agent/pkg/config"
+	process_net "github.com/DataDog/datadog-agent/pkg/process/net"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const checkName = "network_path"
+
+// Check forwards the network paths traced periodically by the traceroute module of system-probe
+type Check struct {
+	core.CheckBase
+	hostname string
+}
+
+// CheckFactory is exported for integration testing
+func CheckFactory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(checkName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(checkName, CheckFactory)
+}
+
+// Configure parses the check configuration and init the check
+func (c *Check) Configure(config, initConfig integration.Data, source string) error {
+	process_net.SetSystemProbePath(ddConfig.Datadog.GetString("system_probe_config.sysprobe_socket"))
+
+	if err := c.CommonConfigure(config, source); err != nil {
+		return err
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Can't get hostname from the agent: %s", err)
+	}
+	c.hostname = hostname
+	return nil
+}
+
+// Run sends the paths traced since the previous run
+func (c *Check) Run() error {
+	sysProbeUtil, err := process_net.GetRemoteSystemProbeUtil()
+	if err != nil {
+		return err
+	}
+
+	paths, err := sysProbeUtil.GetTraceroutePaths()
+	if err != nil {
+		return err
+	}
+
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	submitPaths(sender, c.hostname, paths)
+	return nil
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package networkpath
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/network/traceroute"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// payload is a traceroute path sent to the event platform, along with the host it was traced from
+type payload struct {
+	Host string `json:"host"`
+	traceroute.Path
+}
+
+// submitPaths sends the traced paths to the event platform and reports how many were sent
+func submitPaths(sender aggregator.Sender, hostname string, paths []traceroute.Path) {
+	sent := 0
+	for _, path := range paths {
+		raw, err := json.Marshal(payload{Host: hostname, Path: path})
+		if err != nil {
+			log.Errorf("Unable to marshal the path to %s: %s", path.Destination.IP, err)
+			continue
+		}
+		sender.EventPlatformEvent(string(raw), epforwarder.EventTypeNetworkPath)
+		sent++
+	}
+	sender.Count("network_path.paths", float64(sent), "", nil)
+	sender.Commit()
+}
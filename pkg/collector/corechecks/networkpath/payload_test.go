@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package networkpath
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/network/traceroute"
+)
+
+func TestSubmitPaths(t *testing.T) {
+	sender := mocksender.NewMockSender("network_path")
+	sender.SetupAcceptAll()
+
+	paths := []traceroute.Path{
+		{
+			Timestamp:   1655000000000,
+			Protocol:    traceroute.ProtocolTCP,
+			Destination: traceroute.Destination{IP: "10.0.0.1", Port: 443},
+			Hops: []traceroute.Hop{
+				{TTL: 1, IP: "192.168.1.1", RTTMs: 0.5},
+				{TTL: 2},
+				{TTL: 3, IP: "10.0.0.1", RTTMs: 2.5, ASN: 64512, ASOrg: "Example"},
+			},
+			Reached: true,
+		},
+	}
+	submitPaths(sender, "my-host", paths)
+
+	sender.AssertEventPlatformEvent(t, `{"host":"my-host","timestamp":1655000000000,"protocol":"tcp","destination":{"ip":"10.0.0.1","port":443},"hops":[{"ttl":1,"ip":"192.168.1.1","rtt_ms":0.5},{"ttl":2},{"ttl":3,"ip":"10.0.0.1","rtt_ms":2.5,"asn":64512,"as_org":"Example"}],"reached":true}`, epforwarder.EventTypeNetworkPath)
+	sender.AssertMetric(t, "Count", "network_path.paths", 1, "", nil)
+	sender.AssertNumberOfCalls(t, "Commit", 1)
+}
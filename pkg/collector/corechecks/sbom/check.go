@@ -0,0 +1,166 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ddConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	checkName                   = "sbom"
+	defaultRescanIntervalHours  = 24
+	defaultMaxPendingImageScans = 100
+)
+
+func init() {
+	core.RegisterCheck(checkName, CheckFactory)
+}
+
+// Config holds the sbom check configuration
+type Config struct {
+	// RescanIntervalHours is the minimum delay before an image with a given digest is scanned again
+	RescanIntervalHours int `yaml:"rescan_interval_hours"`
+	// MaxPendingImageScans is the maximum number of images waiting to be scanned, new images are dropped when reached
+	MaxPendingImageScans int `yaml:"max_pending_image_scans"`
+}
+
+// Parse parses the sbom check config and set default values
+func (c *Config) Parse(data []byte) error {
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return err
+	}
+
+	if c.RescanIntervalHours <= 0 {
+		c.RescanIntervalHours = defaultRescanIntervalHours
+	}
+
+	if c.MaxPendingImageScans <= 0 {
+		c.MaxPendingImageScans = defaultMaxPendingImageScans
+	}
+
+	return nil
+}
+
+// Check generates SBOMs of the images used by running containers
+type Check struct {
+	core.CheckBase
+	workloadmetaStore workloadmeta.Store
+	instance          *Config
+	processor         *processor
+	stopCh            chan struct{}
+}
+
+// Configure parses the check configuration and initializes the sbom check
+func (c *Check) Configure(config, initConfig integration.Data, source string) error {
+	if !ddConfig.Datadog.GetBool("sbom.enabled") {
+		return errors.New("collection of container image SBOMs is disabled")
+	}
+
+	err := c.CommonConfigure(config, source)
+	if err != nil {
+		return err
+	}
+
+	err = c.instance.Parse(config)
+	if err != nil {
+		return err
+	}
+
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("Can't get hostname from the agent: %s", err)
+	}
+
+	c.processor = newProcessor(
+		sender,
+		hostname,
+		hostRoot(),
+		time.Duration(c.instance.RescanIntervalHours)*time.Hour,
+		c.instance.MaxPendingImageScans,
+	)
+
+	return nil
+}
+
+// Run starts the sbom check
+func (c *Check) Run() error {
+	log.Infof("Starting long-running check %q", c.ID())
+	defer log.Infof("Shutting down long-running check %q", c.ID())
+
+	contEventsCh := c.workloadmetaStore.Subscribe(
+		checkName,
+		workloadmeta.NormalPriority,
+		workloadmeta.NewFilter(
+			[]workloadmeta.Kind{workloadmeta.KindContainer},
+			workloadmeta.SourceRuntime,
+			workloadmeta.EventTypeSet,
+		),
+	)
+	defer c.workloadmetaStore.Unsubscribe(contEventsCh)
+
+	processorCtx, stopProcessor := context.WithCancel(context.Background())
+	c.processor.start(processorCtx)
+
+	for {
+		select {
+		case eventBundle := <-contEventsCh:
+			c.processor.processEvents(eventBundle)
+		case <-c.stopCh:
+			stopProcessor()
+			return nil
+		}
+	}
+}
+
+// Stop stops the sbom check
+func (c *Check) Stop() { close(c.stopCh) }
+
+// Interval returns 0, it makes sbom a long-running check
+func (c *Check) Interval() time.Duration { return 0 }
+
+// CheckFactory registers the sbom check
+func CheckFactory() check.Check {
+	return &Check{
+		CheckBase:         core.NewCheckBase(checkName),
+		workloadmetaStore: workloadmeta.GetGlobalStore(),
+		instance:          &Config{},
+		stopCh:            make(chan struct{}),
+	}
+}
+
+// hostRoot returns the path where the host filesystem is mounted, image layers paths
+// reported by the container runtimes are relative to it.
+func hostRoot() string {
+	if root := ddConfig.Datadog.GetString("sbom.host_root"); root != "" {
+		return root
+	}
+
+	if ddConfig.IsContainerized() {
+		if _, err := os.Stat("/host"); err == nil {
+			return "/host"
+		}
+	}
+
+	return ""
+}
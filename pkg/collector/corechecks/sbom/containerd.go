@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build containerd
+// +build containerd
+
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/containerd/containerd/mount"
+
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	cutil "github.com/DataDog/datadog-agent/pkg/util/containerd"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func init() {
+	imageResolvers[workloadmeta.ContainerRuntimeContainerd] = resolveContainerdImage
+}
+
+var (
+	containerdClientMu sync.Mutex
+	containerdClient   cutil.ContainerdItf
+)
+
+// resolveContainerdImage returns the image of a containerd container, only overlay snapshots are supported
+func resolveContainerdImage(ctx context.Context, container *workloadmeta.Container) (sbom.Image, error) {
+	// The client namespace is switched while looking for the container
+	containerdClientMu.Lock()
+	defer containerdClientMu.Unlock()
+
+	if containerdClient == nil {
+		client, err := cutil.NewContainerdUtil()
+		if err != nil {
+			return sbom.Image{}, err
+		}
+		containerdClient = client
+	}
+
+	namespaces, err := cutil.NamespacesToWatch(ctx, containerdClient)
+	if err != nil {
+		return sbom.Image{}, err
+	}
+
+	for _, namespace := range namespaces {
+		containerdClient.SetCurrentNamespace(namespace)
+
+		ctn, err := containerdClient.ContainerWithContext(ctx, container.ID)
+		if err != nil {
+			continue
+		}
+
+		img, err := containerdClient.Image(ctn)
+		if err != nil {
+			return sbom.Image{}, err
+		}
+
+		mounts, err := containerdClient.Mounts(ctn)
+		if err != nil {
+			return sbom.Image{}, err
+		}
+
+		layers, err := imageLayersFromMounts(mounts)
+		if err != nil {
+			return sbom.Image{}, err
+		}
+
+		return sbom.Image{
+			Name:   img.Name(),
+			Digest: img.Target().Digest.String(),
+			Layers: layers,
+		}, nil
+	}
+
+	return sbom.Image{}, fmt.Errorf("container %q not found in containerd namespaces %v", container.ID, namespaces)
+}
+
+// imageLayersFromMounts returns the read-only layers of an overlay root filesystem, the
+// container writable layer (upperdir) is not part of the image.
+func imageLayersFromMounts(mounts []mount.Mount) ([]string, error) {
+	for _, m := range mounts {
+		if m.Type != "overlay" {
+			continue
+		}
+
+		for _, option := range m.Options {
+			if strings.HasPrefix(option, "lowerdir=") {
+				return strings.Split(strings.TrimPrefix(option, "lowerdir="), ":"), nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported root filesystem mounts %v", mounts)
+}
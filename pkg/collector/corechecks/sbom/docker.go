@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build docker
+// +build docker
+
+package sbom
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/util/docker"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func init() {
+	imageResolvers[workloadmeta.ContainerRuntimeDocker] = resolveDockerImage
+}
+
+// resolveDockerImage returns the image of a Docker container, only the overlay2 storage driver is supported
+func resolveDockerImage(ctx context.Context, container *workloadmeta.Container) (sbom.Image, error) {
+	du, err := docker.GetDockerUtil()
+	if err != nil {
+		return sbom.Image{}, err
+	}
+
+	co, err := du.Inspect(ctx, container.ID, false)
+	if err != nil {
+		return sbom.Image{}, err
+	}
+
+	img, err := du.ImageInspect(ctx, co.Image)
+	if err != nil {
+		return sbom.Image{}, err
+	}
+
+	if img.GraphDriver.Name != "overlay2" {
+		return sbom.Image{}, fmt.Errorf("unsupported storage driver %q", img.GraphDriver.Name)
+	}
+
+	image := sbom.Image{
+		Name:   container.Image.RawName,
+		Digest: img.ID,
+	}
+
+	if upperDir := img.GraphDriver.Data["UpperDir"]; upperDir != "" {
+		image.Layers = append(image.Layers, upperDir)
+	}
+	if lowerDir := img.GraphDriver.Data["LowerDir"]; lowerDir != "" {
+		image.Layers = append(image.Layers, strings.Split(lowerDir, ":")...)
+	}
+
+	return image, nil
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/persistentcache"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const cacheKeyPrefix = "sbom:"
+
+// errUnsupportedRuntime is returned when the layers of an image cannot be located for a runtime
+var errUnsupportedRuntime = errors.New("container runtime not supported")
+
+// imageResolver returns the image of a container with the path of its layers on disk
+type imageResolver func(ctx context.Context, container *workloadmeta.Container) (sbom.Image, error)
+
+// imageResolvers are registered by runtime in files with the corresponding build tags
+var imageResolvers = map[workloadmeta.ContainerRuntime]imageResolver{}
+
+// payload is the event sent to the intake for each scanned image
+type payload struct {
+	Host        string    `json:"host"`
+	ImageName   string    `json:"image_name"`
+	ImageDigest string    `json:"image_digest"`
+	GeneratedAt int64     `json:"generated_at"`
+	SBOM        *sbom.BOM `json:"sbom"`
+}
+
+type processor struct {
+	sender         aggregator.Sender
+	hostname       string
+	hostRoot       string
+	rescanInterval time.Duration
+	resolvers      map[workloadmeta.ContainerRuntime]imageResolver
+	queue          chan *workloadmeta.Container
+
+	// pending holds the images waiting to be scanned, by image name, to avoid resolving them once per container
+	pendingMu sync.Mutex
+	pending   map[string]struct{}
+
+	// scanned holds the time each image digest was last scanned
+	scanned map[string]time.Time
+	now     func() time.Time
+}
+
+func newProcessor(sender aggregator.Sender, hostname, hostRoot string, rescanInterval time.Duration, maxPending int) *processor {
+	return &processor{
+		sender:         sender,
+		hostname:       hostname,
+		hostRoot:       hostRoot,
+		rescanInterval: rescanInterval,
+		resolvers:      imageResolvers,
+		queue:          make(chan *workloadmeta.Container, maxPending),
+		pending:        make(map[string]struct{}),
+		scanned:        make(map[string]time.Time),
+		now:            time.Now,
+	}
+}
+
+// start spawns a go routine scanning queued images one at a time
+func (p *processor) start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case container := <-p.queue:
+				p.pendingMu.Lock()
+				delete(p.pending, container.Image.RawName)
+				p.pendingMu.Unlock()
+
+				if err := p.processContainer(ctx, container); err != nil {
+					log.Debugf("Couldn't generate SBOM for the image of container %q: %v", container.ID, err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// processEvents queues the images of new containers for scanning
+func (p *processor) processEvents(evBundle workloadmeta.EventBundle) {
+	close(evBundle.Ch)
+
+	for _, event := range evBundle.Events {
+		container, ok := event.Entity.(*workloadmeta.Container)
+		if !ok {
+			log.Debugf("Expected workloadmeta.Container got %T, skipping", event.Entity)
+			continue
+		}
+
+		if _, found := p.resolvers[container.Runtime]; !found {
+			log.Tracef("Skipping container %q: %v", container.ID, errUnsupportedRuntime)
+			continue
+		}
+
+		p.pendingMu.Lock()
+		if _, found := p.pending[container.Image.RawName]; found {
+			p.pendingMu.Unlock()
+			continue
+		}
+
+		select {
+		case p.queue <- container:
+			p.pending[container.Image.RawName] = struct{}{}
+		default:
+			log.Debugf("Too many images waiting to be scanned, dropping image %q of container %q", container.Image.RawName, container.ID)
+		}
+		p.pendingMu.Unlock()
+	}
+}
+
+// processContainer generates and sends the SBOM of the container image unless it was recently scanned
+func (p *processor) processContainer(ctx context.Context, container *workloadmeta.Container) error {
+	resolve, found := p.resolvers[container.Runtime]
+	if !found {
+		return errUnsupportedRuntime
+	}
+
+	image, err := resolve(ctx, container)
+	if err != nil {
+		return err
+	}
+
+	if image.Digest == "" {
+		return fmt.Errorf("no digest for image %q", image.Name)
+	}
+
+	now := p.now()
+	if !p.shouldScan(image.Digest, now) {
+		log.Tracef("Image %q with digest %q already scanned", image.Name, image.Digest)
+		return nil
+	}
+
+	for i, layer := range image.Layers {
+		image.Layers[i] = filepath.Join(p.hostRoot, layer)
+	}
+
+	bom, err := sbom.Generate(image, now)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(payload{
+		Host:        p.hostname,
+		ImageName:   image.Name,
+		ImageDigest: image.Digest,
+		GeneratedAt: now.Unix(),
+		SBOM:        bom,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.sender.EventPlatformEvent(string(raw), epforwarder.EventTypeContainerSBOM)
+	p.sender.Commit()
+	log.Debugf("Sent SBOM of image %q with digest %q (%d components)", image.Name, image.Digest, len(bom.Components))
+
+	p.scanned[image.Digest] = now
+	if err := persistentcache.Write(cacheKeyPrefix+image.Digest, now.Format(time.RFC3339)); err != nil {
+		log.Debugf("Unable to persist scan time of image %q: %v", image.Digest, err)
+	}
+
+	return nil
+}
+
+// shouldScan returns whether the image digest was not scanned during the last rescan interval,
+// scan times are persisted to avoid rescanning all images on agent restart.
+func (p *processor) shouldScan(digest string, now time.Time) bool {
+	lastScan, found := p.scanned[digest]
+	if !found {
+		if value, err := persistentcache.Read(cacheKeyPrefix + digest); err == nil && value != "" {
+			if ts, err := time.Parse(time.RFC3339, value); err == nil {
+				lastScan = ts
+				p.scanned[digest] = ts
+			}
+		}
+	}
+
+	return now.Sub(lastScan) >= p.rescanInterval
+}
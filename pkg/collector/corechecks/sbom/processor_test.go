@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/sbom"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func TestProcessContainer(t *testing.T) {
+	mockConfig := config.Mock(t)
+	mockConfig.Set("run_path", t.TempDir())
+
+	hostRoot := t.TempDir()
+	layer := "/var/lib/docker/overlay2/abc/diff"
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, layer, "etc"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(hostRoot, layer, "etc/os-release"), []byte("ID=alpine\nVERSION_ID=3.16.0\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(hostRoot, layer, "lib/apk/db"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(hostRoot, layer, "lib/apk/db/installed"), []byte("P:musl\nV:1.2.3-r0\nA:x86_64\n"), 0644))
+
+	resolved := 0
+	sender := mocksender.NewMockSender("sbom")
+	sender.SetupAcceptAll()
+
+	p := newProcessor(sender, "my-host", hostRoot, time.Hour, 10)
+	p.resolvers = map[workloadmeta.ContainerRuntime]imageResolver{
+		workloadmeta.ContainerRuntimeDocker: func(ctx context.Context, container *workloadmeta.Container) (sbom.Image, error) {
+			resolved++
+			return sbom.Image{Name: "alpine:3.16", Digest: "sha256:1234", Layers: []string{layer}}, nil
+		},
+	}
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	container := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{Kind: workloadmeta.KindContainer, ID: "ctn1"},
+		Image:    workloadmeta.ContainerImage{RawName: "alpine:3.16"},
+		Runtime:  workloadmeta.ContainerRuntimeDocker,
+	}
+
+	require.NoError(t, p.processContainer(context.Background(), container))
+	sender.AssertCalled(t, "EventPlatformEvent", mock.MatchedBy(func(raw string) bool {
+		var sent payload
+		if err := json.Unmarshal([]byte(raw), &sent); err != nil {
+			return false
+		}
+		return sent.Host == "my-host" &&
+			sent.ImageDigest == "sha256:1234" &&
+			len(sent.SBOM.Components) == 2 &&
+			sent.SBOM.Components[1].PURL == "pkg:apk/alpine/musl@1.2.3-r0?arch=x86_64&distro=alpine-3.16.0"
+	}), epforwarder.EventTypeContainerSBOM)
+	sender.AssertNumberOfCalls(t, "EventPlatformEvent", 1)
+
+	// Same digest within the rescan interval, even after a restart
+	p = newProcessor(sender, "my-host", hostRoot, time.Hour, 10)
+	p.resolvers = map[workloadmeta.ContainerRuntime]imageResolver{
+		workloadmeta.ContainerRuntimeDocker: func(ctx context.Context, container *workloadmeta.Container) (sbom.Image, error) {
+			return sbom.Image{Name: "alpine:3.16", Digest: "sha256:1234", Layers: []string{layer}}, nil
+		},
+	}
+	p.now = func() time.Time { return now.Add(30 * time.Minute) }
+	require.NoError(t, p.processContainer(context.Background(), container))
+	sender.AssertNumberOfCalls(t, "EventPlatformEvent", 1)
+
+	p.now = func() time.Time { return now.Add(2 * time.Hour) }
+	require.NoError(t, p.processContainer(context.Background(), container))
+	sender.AssertNumberOfCalls(t, "EventPlatformEvent", 2)
+	assert.Equal(t, 1, resolved)
+
+	container.Runtime = workloadmeta.ContainerRuntimeGarden
+	assert.Equal(t, errUnsupportedRuntime, p.processContainer(context.Background(), container))
+}
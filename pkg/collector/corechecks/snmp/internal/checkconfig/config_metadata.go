@@ -86,6 +86,129 @@ var LegacyMetadataConfig = MetadataConfig{
 	},
 }
 
+// TopologyMetadataConfig contains the metadata config of the LLDP-MIB and CISCO-CDP-MIB tables
+// used to collect the links between the devices when `collect_topology` is enabled
+var TopologyMetadataConfig = MetadataConfig{
+	"lldp_remote": {
+		Fields: map[string]MetadataField{
+			"chassis_id_type": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.4",
+					Name: "lldpRemChassisIdSubtype",
+				},
+			},
+			"chassis_id": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.5",
+					Name: "lldpRemChassisId",
+				},
+			},
+			"interface_id_type": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.6",
+					Name: "lldpRemPortIdSubtype",
+				},
+			},
+			"interface_id": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.7",
+					Name: "lldpRemPortId",
+				},
+			},
+			"interface_desc": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.8",
+					Name: "lldpRemPortDesc",
+				},
+			},
+			"device_name": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.9",
+					Name: "lldpRemSysName",
+				},
+			},
+			"device_desc": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.1.1.10",
+					Name: "lldpRemSysDesc",
+				},
+			},
+		},
+	},
+	"lldp_remote_management": {
+		Fields: map[string]MetadataField{
+			"interface_id_type": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.4.2.1.3",
+					Name: "lldpRemManAddrIfSubtype",
+				},
+			},
+		},
+	},
+	"lldp_local": {
+		Fields: map[string]MetadataField{
+			"interface_id_type": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.3.7.1.2",
+					Name: "lldpLocPortIdSubtype",
+				},
+			},
+			"interface_id": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.3.7.1.3",
+					Name: "lldpLocPortId",
+				},
+			},
+			"interface_desc": {
+				Symbol: SymbolConfig{
+					OID:  "1.0.8802.1.1.2.1.3.7.1.4",
+					Name: "lldpLocPortDesc",
+				},
+			},
+		},
+	},
+	"cdp_remote": {
+		Fields: map[string]MetadataField{
+			"address_type": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.3",
+					Name: "cdpCacheAddressType",
+				},
+			},
+			"address": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.4",
+					Name: "cdpCacheAddress",
+				},
+			},
+			"device_desc": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.5",
+					Name: "cdpCacheVersion",
+				},
+			},
+			"device_name": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.6",
+					Name: "cdpCacheDeviceId",
+				},
+			},
+			"interface_id": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.7",
+					Name: "cdpCacheDevicePort",
+				},
+			},
+			"device_platform": {
+				Symbol: SymbolConfig{
+					OID:  "1.3.6.1.4.1.9.9.23.1.2.1.1.8",
+					Name: "cdpCachePlatform",
+				},
+			},
+		},
+	},
+}
+
 // MetadataConfig holds configs per resource type
 type MetadataConfig map[string]MetadataResourceConfig
 
@@ -126,3 +249,24 @@ func updateMetadataDefinitionWithLegacyFallback(config MetadataConfig) MetadataC
 	}
 	return config
 }
+
+// updateMetadataDefinitionWithDefaults adds the legacy fallback metadata definitions,
+// and the topology metadata definitions if the topology collection is enabled
+func updateMetadataDefinitionWithDefaults(config MetadataConfig, collectTopology bool) MetadataConfig {
+	config = updateMetadataDefinitionWithLegacyFallback(config)
+	if !collectTopology {
+		return config
+	}
+
+	// the profile definitions are shared by the instances, the topology definitions are added to a copy
+	newConfig := make(MetadataConfig, len(config)+len(TopologyMetadataConfig))
+	for resourceName, resourceConfig := range config {
+		newConfig[resourceName] = resourceConfig
+	}
+	for resourceName, resourceConfig := range TopologyMetadataConfig {
+		if _, ok := newConfig[resourceName]; !ok {
+			newConfig[resourceName] = resourceConfig
+		}
+	}
+	return newConfig
+}
@@ -32,6 +32,32 @@ var validMetadataResources = map[string]map[string]bool{
 		"mac_address":  true,
 		"admin_status": true,
 		"oper_status":  true,
+		"speed":        true,
+	},
+	"lldp_remote": {
+		"chassis_id_type":   true,
+		"chassis_id":        true,
+		"interface_id_type": true,
+		"interface_id":      true,
+		"interface_desc":    true,
+		"device_name":       true,
+		"device_desc":       true,
+	},
+	"lldp_remote_management": {
+		"interface_id_type": true,
+	},
+	"lldp_local": {
+		"interface_id_type": true,
+		"interface_id":      true,
+		"interface_desc":    true,
+	},
+	"cdp_remote": {
+		"address_type":    true,
+		"address":         true,
+		"device_desc":     true,
+		"device_name":     true,
+		"interface_id":    true,
+		"device_platform": true,
 	},
 }
 
@@ -136,14 +136,33 @@ func readProfileDefinition(definitionFile string) (*profileDefinition, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshall %q: %v", filePath, err)
 	}
+	err = validateEnrichProfileDefinition(profileDefinition)
+	if err != nil {
+		return nil, err
+	}
+	return profileDefinition, nil
+}
+
+// ValidateProfileDefinition validates a profile definition content the same way profiles are validated
+// when they are loaded by the check
+func ValidateProfileDefinition(content []byte) error {
+	profileDefinition := newProfileDefinition()
+	err := yaml.Unmarshal(content, profileDefinition)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshall profile: %v", err)
+	}
+	return validateEnrichProfileDefinition(profileDefinition)
+}
+
+func validateEnrichProfileDefinition(profileDefinition *profileDefinition) error {
 	normalizeMetrics(profileDefinition.Metrics)
 	errors := validateEnrichMetadata(profileDefinition.Metadata)
 	errors = append(errors, ValidateEnrichMetrics(profileDefinition.Metrics)...)
 	errors = append(errors, ValidateEnrichMetricTags(profileDefinition.MetricTags)...)
 	if len(errors) > 0 {
-		return nil, fmt.Errorf("validation errors: %s", strings.Join(errors, "\n"))
+		return fmt.Errorf("validation errors: %s", strings.Join(errors, "\n"))
 	}
-	return profileDefinition, nil
+	return nil
 }
 
 func resolveProfileDefinitionPath(definitionFile string) string {
@@ -752,3 +752,27 @@ func Test_mergeProfileDefinition(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProfileDefinition(t *testing.T) {
+	validProfile := []byte(`
+sysobjectid: 1.3.6.1.4.1.9.1.1208
+metrics:
+  - MIB: TCP-MIB
+    symbol:
+      OID: 1.3.6.1.2.1.6.9.0
+      name: tcpCurrEstab
+`)
+	assert.NoError(t, ValidateProfileDefinition(validProfile))
+
+	invalidProfile := []byte(`
+metrics:
+  - MIB: TCP-MIB
+    symbol:
+      OID: 1.3.6.1.2.1.6.9.0
+`)
+	err := ValidateProfileDefinition(invalidProfile)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "validation errors: either a table symbol or a scalar symbol must be provided")
+
+	assert.Error(t, ValidateProfileDefinition([]byte("metrics: not_a_list")))
+}
@@ -63,6 +63,25 @@ func (s Store) GetColumnAsString(field string, index string) string {
 	return strVal
 }
 
+// GetColumnAsByteArray get column value as a byte array
+func (s Store) GetColumnAsByteArray(field string, index string) []byte {
+	column, ok := s.columnValues[field]
+	if !ok {
+		return nil
+	}
+	value, ok := column[index]
+	if !ok {
+		return nil
+	}
+	switch val := value.Value.(type) {
+	case []byte:
+		return val
+	case string:
+		return []byte(val)
+	}
+	return nil
+}
+
 // GetColumnAsFloat get column value as float
 func (s Store) GetColumnAsFloat(field string, index string) float64 {
 	column, ok := s.columnValues[field]
@@ -65,6 +65,14 @@ func TestStore_Column(t *testing.T) {
 	assert.Equal(t, float64(0), store.GetColumnAsFloat("interface.admin_status", "1.2.3"))   // missing index
 	assert.Equal(t, float64(0), store.GetColumnAsFloat("interface.invalid_value_type", "3")) // missing index
 
+	// test GetColumnAsByteArray
+	store.AddColumnValue("interface.mac_address", "1", valuestore.ResultValue{Value: []byte{0x82, 0xa5}})
+	assert.Equal(t, []byte{0x82, 0xa5}, store.GetColumnAsByteArray("interface.mac_address", "1"))
+	assert.Equal(t, []byte("ifName1"), store.GetColumnAsByteArray("interface.name", "1"))
+	assert.Nil(t, store.GetColumnAsByteArray("interface.admin_status", "1"))
+	assert.Nil(t, store.GetColumnAsByteArray("interface.does_not_exist", "1"))
+	assert.Nil(t, store.GetColumnAsByteArray("interface.mac_address", "2")) // missing index
+
 	// test GetColumnIndexes
 	assert.ElementsMatch(t, []string{"1", "2"}, store.GetColumnIndexes("interface.name"))
 	assert.ElementsMatch(t, []string{"1", "2", "3"}, store.GetColumnIndexes("interface.admin_status"))
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -32,7 +33,16 @@ func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckCon
 
 	interfaces := buildNetworkInterfacesMetadata(config.DeviceID, metadataStore)
 
-	metadataPayloads := batchPayloads(config.Namespace, config.ResolvedSubnetName, collectTime, metadata.PayloadMetadataBatchSize, device, interfaces)
+	if deviceStatus == metadata.DeviceStatusReachable {
+		updateDeviceMetadataCache(config.Namespace, device, interfaces)
+	}
+
+	var topologyLinks []metadata.TopologyLinkMetadata
+	if config.CollectTopology {
+		topologyLinks = buildNetworkTopologyMetadata(config.DeviceID, metadataStore, interfaces)
+	}
+
+	metadataPayloads := batchPayloads(config.Namespace, config.ResolvedSubnetName, collectTime, metadata.PayloadMetadataBatchSize, device, interfaces, topologyLinks)
 
 	for _, payload := range metadataPayloads {
 		payloadBytes, err := json.Marshal(payload)
@@ -88,6 +98,9 @@ func buildMetadataStore(metadataConfigs checkconfig.MetadataConfig, values *valu
 				}
 			}
 		}
+		if resourceName == "interface" {
+			addInterfaceSpeedFallback(metadataConfig, values, metadataStore)
+		}
 		indexOid := metadata.GetIndexOIDForResource(resourceName)
 		if indexOid != "" {
 			indexes, err := values.GetColumnIndexes(indexOid)
@@ -104,6 +117,21 @@ func buildMetadataStore(metadataConfigs checkconfig.MetadataConfig, values *valu
 	return metadataStore
 }
 
+// addInterfaceSpeedFallback uses the `ifHighSpeed` values collected as metrics when the profile doesn't define
+// the speed of the interfaces in its metadata
+func addInterfaceSpeedFallback(metadataConfig checkconfig.MetadataResourceConfig, values *valuestore.ResultValueStore, metadataStore *metadata.Store) {
+	if _, ok := metadataConfig.Fields["speed"]; ok {
+		return
+	}
+	speedValues, err := values.GetColumnValues(ifHighSpeedOID)
+	if err != nil {
+		return
+	}
+	for fullIndex, value := range speedValues {
+		metadataStore.AddColumnValue("interface.speed", fullIndex, value)
+	}
+}
+
 func buildNetworkDeviceMetadata(deviceID string, idTags []string, config *checkconfig.CheckConfig, store *metadata.Store, tags []string, deviceStatus metadata.DeviceStatus) metadata.DeviceMetadata {
 	var vendor, sysName, sysDescr, sysObjectID, location, serialNumber, version, productName, model, osName, osVersion, osHostname string
 	if store != nil {
@@ -181,6 +209,7 @@ func buildNetworkInterfacesMetadata(deviceID string, store *metadata.Store) []me
 			MacAddress:  store.GetColumnAsString("interface.mac_address", strIndex),
 			AdminStatus: int32(store.GetColumnAsFloat("interface.admin_status", strIndex)),
 			OperStatus:  int32(store.GetColumnAsFloat("interface.oper_status", strIndex)),
+			Speed:       uint64(store.GetColumnAsFloat("interface.speed", strIndex)),
 			IDTags:      ifIDTags,
 		}
 		interfaces = append(interfaces, networkInterface)
@@ -188,7 +217,25 @@ func buildNetworkInterfacesMetadata(deviceID string, store *metadata.Store) []me
 	return interfaces
 }
 
-func batchPayloads(namespace string, subnet string, collectTime time.Time, batchSize int, device metadata.DeviceMetadata, interfaces []metadata.InterfaceMetadata) []metadata.NetworkDevicesMetadata {
+// updateDeviceMetadataCache shares the device and interfaces metadata with the other network devices components
+func updateDeviceMetadataCache(namespace string, device metadata.DeviceMetadata, interfaces []metadata.InterfaceMetadata) {
+	cachedInterfaces := make([]devicemetadata.Interface, 0, len(interfaces))
+	for _, networkInterface := range interfaces {
+		cachedInterfaces = append(cachedInterfaces, devicemetadata.Interface{
+			Index: networkInterface.Index,
+			Name:  networkInterface.Name,
+			Alias: networkInterface.Alias,
+			Speed: networkInterface.Speed,
+		})
+	}
+	devicemetadata.GetDefaultCache().SetDevice(namespace, device.IPAddress, devicemetadata.Device{
+		ID:   device.ID,
+		Name: device.Name,
+		Tags: device.Tags,
+	}, cachedInterfaces)
+}
+
+func batchPayloads(namespace string, subnet string, collectTime time.Time, batchSize int, device metadata.DeviceMetadata, interfaces []metadata.InterfaceMetadata, topologyLinks []metadata.TopologyLinkMetadata) []metadata.NetworkDevicesMetadata {
 	var payloads []metadata.NetworkDevicesMetadata
 	var resourceCount int
 	payload := metadata.NetworkDevicesMetadata{
@@ -215,6 +262,20 @@ func batchPayloads(namespace string, subnet string, collectTime time.Time, batch
 		payload.Interfaces = append(payload.Interfaces, interfaceMetadata)
 	}
 
+	for _, linkMetadata := range topologyLinks {
+		if resourceCount == batchSize {
+			payloads = append(payloads, payload)
+			payload = metadata.NetworkDevicesMetadata{
+				Subnet:           subnet,
+				Namespace:        namespace,
+				CollectTimestamp: collectTime.Unix(),
+			}
+			resourceCount = 0
+		}
+		resourceCount++
+		payload.Links = append(payload.Links, linkMetadata)
+	}
+
 	payloads = append(payloads, payload)
 	return payloads
 }
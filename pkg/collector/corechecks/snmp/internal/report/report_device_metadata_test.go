@@ -23,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/metadata"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/valuestore"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 )
 
 func Test_metricSender_reportNetworkDeviceMetadata_withoutInterfaces(t *testing.T) {
@@ -397,6 +398,80 @@ func Test_metricSender_reportNetworkDeviceMetadata_fallbackOnFieldValue(t *testi
 	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 }
 
+func Test_metricSender_reportNetworkDeviceMetadata_interfaceSpeed(t *testing.T) {
+	var storeWithIfSpeed = &valuestore.ResultValueStore{
+		ScalarValues: valuestore.ScalarResultValuesType{
+			"1.3.6.1.2.1.1.5.0": valuestore.ResultValue{Value: "my-router"},
+		},
+		ColumnValues: valuestore.ColumnResultValuesType{
+			"1.3.6.1.2.1.31.1.1.1.1": {
+				"1": valuestore.ResultValue{Value: "eth0"},
+				"2": valuestore.ResultValue{Value: "eth1"},
+			},
+			"1.3.6.1.2.1.31.1.1.1.15": {
+				"1": valuestore.ResultValue{Value: float64(1000)},
+			},
+		},
+	}
+	sender := mocksender.NewMockSender("testID") // required to initiate aggregator
+	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	ms := &MetricSender{
+		sender: sender,
+	}
+
+	config := &checkconfig.CheckConfig{
+		IPAddress:          "1.2.3.5",
+		DeviceID:           "my-ns:1.2.3.5",
+		ResolvedSubnetName: "127.0.0.0/29",
+		Namespace:          "my-ns",
+		Metadata: checkconfig.MetadataConfig{
+			"device": {
+				Fields: map[string]checkconfig.MetadataField{
+					"name": {
+						Symbol: checkconfig.SymbolConfig{
+							OID:  "1.3.6.1.2.1.1.5.0",
+							Name: "sysName",
+						},
+					},
+				},
+			},
+			"interface": {
+				Fields: map[string]checkconfig.MetadataField{
+					"name": {
+						Symbol: checkconfig.SymbolConfig{
+							OID:  "1.3.6.1.2.1.31.1.1.1.1",
+							Name: "ifName",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	ms.ReportNetworkDeviceMetadata(config, storeWithIfSpeed, []string{"snmp_device:1.2.3.5", "device_vendor:cisco"}, common.MockTimeNow(), metadata.DeviceStatusReachable)
+
+	// the speed collected as a metric is used when the profile doesn't define it
+	interfaces := buildNetworkInterfacesMetadata(config.DeviceID, buildMetadataStore(config.Metadata, storeWithIfSpeed))
+	if assert.Len(t, interfaces, 2) {
+		assert.Equal(t, uint64(1000), interfaces[0].Speed)
+		assert.Equal(t, uint64(0), interfaces[1].Speed)
+	}
+
+	// the metadata is shared with the other network devices components
+	device, ok := devicemetadata.GetDefaultCache().GetDevice("my-ns", "1.2.3.5")
+	assert.True(t, ok)
+	assert.Equal(t, devicemetadata.Device{ID: "my-ns:1.2.3.5", Name: "my-router", Tags: []string{"device_vendor:cisco", "snmp_device:1.2.3.5"}}, device)
+
+	networkInterface, ok := devicemetadata.GetDefaultCache().GetInterface("my-ns", "1.2.3.5", 1)
+	assert.True(t, ok)
+	assert.Equal(t, devicemetadata.Interface{Index: 1, Name: "eth0", Speed: 1000}, networkInterface)
+
+	// the metadata of unreachable devices is kept
+	ms.ReportNetworkDeviceMetadata(config, nil, nil, common.MockTimeNow(), metadata.DeviceStatusUnreachable)
+	_, ok = devicemetadata.GetDefaultCache().GetInterface("my-ns", "1.2.3.5", 1)
+	assert.True(t, ok)
+}
+
 func Test_batchPayloads(t *testing.T) {
 	collectTime := common.MockTimeNow()
 	deviceID := "123"
@@ -406,7 +481,7 @@ func Test_batchPayloads(t *testing.T) {
 	for i := 0; i < 350; i++ {
 		interfaces = append(interfaces, metadata.InterfaceMetadata{DeviceID: deviceID, Index: int32(i)})
 	}
-	payloads := batchPayloads("my-ns", "127.0.0.0/30", collectTime, 100, device, interfaces)
+	payloads := batchPayloads("my-ns", "127.0.0.0/30", collectTime, 100, device, interfaces, nil)
 
 	assert.Equal(t, 4, len(payloads))
 
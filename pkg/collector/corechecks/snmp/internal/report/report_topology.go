@@ -0,0 +1,256 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package report
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/metadata"
+)
+
+// lldpChassisIDSubtypes maps the LLDP-MIB LldpChassisIdSubtype values to id types
+var lldpChassisIDSubtypes = map[int]string{
+	1: "chassis_component",
+	2: "interface_alias",
+	3: "port_component",
+	4: "mac_address",
+	5: "network_address",
+	6: "interface_name",
+	7: "local",
+}
+
+// lldpPortIDSubtypes maps the LLDP-MIB LldpPortIdSubtype values to id types
+var lldpPortIDSubtypes = map[int]string{
+	1: "interface_alias",
+	2: "port_component",
+	3: "mac_address",
+	4: "network_address",
+	5: "interface_name",
+	6: "agent_circuit_id",
+	7: "local",
+}
+
+const (
+	// IANA address family numbers used by LLDP network addresses
+	ianaAddressFamilyIPv4 = 1
+	ianaAddressFamilyIPv6 = 2
+
+	// CISCO-CDP-MIB CiscoNetworkProtocol ip value
+	cdpAddressTypeIP = 1
+)
+
+func buildNetworkTopologyMetadata(deviceID string, store *metadata.Store, interfaces []metadata.InterfaceMetadata) []metadata.TopologyLinkMetadata {
+	if store == nil {
+		// it's expected that the value store is nil if we can't reach the device
+		// in that case, we just return a nil slice.
+		return nil
+	}
+	links := buildLLDPLinks(deviceID, store)
+	links = append(links, buildCDPLinks(deviceID, store, interfaces)...)
+	return links
+}
+
+// buildLLDPLinks builds the links from the LLDP-MIB lldpRemTable, indexed by lldpRemTimeMark, lldpRemLocalPortNum
+// and lldpRemIndex
+func buildLLDPLinks(deviceID string, store *metadata.Store) []metadata.TopologyLinkMetadata {
+	indexes := store.GetColumnIndexes("lldp_remote.chassis_id")
+	if len(indexes) == 0 {
+		log.Debugf("Unable to build LLDP links: no LLDP remote indexes found")
+		return nil
+	}
+	sort.Strings(indexes)
+
+	managementAddresses := buildLLDPManagementAddresses(store)
+
+	var links []metadata.TopologyLinkMetadata
+	for _, strIndex := range indexes {
+		indexElems := strings.Split(strIndex, ".")
+		if len(indexElems) != 3 {
+			log.Debugf("LLDP links: invalid remote index: %s", strIndex)
+			continue
+		}
+		localPortNum, remoteIndex := indexElems[1], indexElems[2]
+
+		chassisIDType := lldpChassisIDSubtypes[int(store.GetColumnAsFloat("lldp_remote.chassis_id_type", strIndex))]
+		portIDType := lldpPortIDSubtypes[int(store.GetColumnAsFloat("lldp_remote.interface_id_type", strIndex))]
+		localPortIDType := lldpPortIDSubtypes[int(store.GetColumnAsFloat("lldp_local.interface_id_type", localPortNum))]
+
+		links = append(links, metadata.TopologyLinkMetadata{
+			ID:         deviceID + ":lldp:" + localPortNum + "." + remoteIndex,
+			SourceType: "lldp",
+			Local: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					DeviceID: deviceID,
+				},
+				Interface: &metadata.TopologyLinkInterface{
+					ID:          formatTopologyID(store, "lldp_local.interface_id", localPortNum, localPortIDType),
+					IDType:      localPortIDType,
+					Description: store.GetColumnAsString("lldp_local.interface_desc", localPortNum),
+				},
+			},
+			Remote: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					ID:          formatTopologyID(store, "lldp_remote.chassis_id", strIndex, chassisIDType),
+					IDType:      chassisIDType,
+					Name:        store.GetColumnAsString("lldp_remote.device_name", strIndex),
+					Description: store.GetColumnAsString("lldp_remote.device_desc", strIndex),
+					IPAddress:   managementAddresses[strIndex],
+				},
+				Interface: &metadata.TopologyLinkInterface{
+					ID:          formatTopologyID(store, "lldp_remote.interface_id", strIndex, portIDType),
+					IDType:      portIDType,
+					Description: store.GetColumnAsString("lldp_remote.interface_desc", strIndex),
+				},
+			},
+		})
+	}
+	return links
+}
+
+// buildLLDPManagementAddresses returns the management addresses of the LLDP remote devices indexed by their
+// lldpRemTable index. The addresses are part of the index of the lldpRemManAddrTable:
+// <lldpRemTimeMark>.<lldpRemLocalPortNum>.<lldpRemIndex>.<lldpRemManAddrSubtype>.<address length>.<address>
+func buildLLDPManagementAddresses(store *metadata.Store) map[string]string {
+	addresses := make(map[string]string)
+
+	indexes := store.GetColumnIndexes("lldp_remote_management.interface_id_type")
+	sort.Strings(indexes)
+	for _, strIndex := range indexes {
+		indexElems := strings.Split(strIndex, ".")
+		if len(indexElems) < 5 {
+			continue
+		}
+		remoteIndex := strings.Join(indexElems[:3], ".")
+		addressFamily, err := strconv.Atoi(indexElems[3])
+		if err != nil {
+			continue
+		}
+
+		var address []byte
+		for _, elem := range indexElems[5:] {
+			b, err := strconv.ParseUint(elem, 10, 8)
+			if err != nil {
+				address = nil
+				break
+			}
+			address = append(address, byte(b))
+		}
+
+		ip := formatIPAddress(addressFamily, address)
+		if ip == "" {
+			continue
+		}
+		// IPv4 addresses are preferred
+		if _, ok := addresses[remoteIndex]; !ok || addressFamily == ianaAddressFamilyIPv4 {
+			addresses[remoteIndex] = ip
+		}
+	}
+	return addresses
+}
+
+// buildCDPLinks builds the links from the CISCO-CDP-MIB cdpCacheTable, indexed by cdpCacheIfIndex and
+// cdpCacheDeviceIndex
+func buildCDPLinks(deviceID string, store *metadata.Store, interfaces []metadata.InterfaceMetadata) []metadata.TopologyLinkMetadata {
+	indexes := store.GetColumnIndexes("cdp_remote.device_name")
+	if len(indexes) == 0 {
+		log.Debugf("Unable to build CDP links: no CDP remote indexes found")
+		return nil
+	}
+	sort.Strings(indexes)
+
+	interfaceNames := make(map[int32]string, len(interfaces))
+	for _, networkInterface := range interfaces {
+		interfaceNames[networkInterface.Index] = networkInterface.Name
+	}
+
+	var links []metadata.TopologyLinkMetadata
+	for _, strIndex := range indexes {
+		indexElems := strings.Split(strIndex, ".")
+		if len(indexElems) != 2 {
+			log.Debugf("CDP links: invalid remote index: %s", strIndex)
+			continue
+		}
+		ifIndex, err := strconv.ParseInt(indexElems[0], 10, 32)
+		if err != nil {
+			log.Debugf("CDP links: invalid interface index: %s", strIndex)
+			continue
+		}
+
+		localInterface := &metadata.TopologyLinkInterface{
+			Index: int32(ifIndex),
+		}
+		if name, ok := interfaceNames[int32(ifIndex)]; ok && name != "" {
+			localInterface.ID = name
+			localInterface.IDType = "interface_name"
+		}
+
+		var remoteIPAddress string
+		if int(store.GetColumnAsFloat("cdp_remote.address_type", strIndex)) == cdpAddressTypeIP {
+			remoteIPAddress = formatIPAddress(ianaAddressFamilyIPv4, store.GetColumnAsByteArray("cdp_remote.address", strIndex))
+		}
+
+		deviceName := store.GetColumnAsString("cdp_remote.device_name", strIndex)
+		links = append(links, metadata.TopologyLinkMetadata{
+			ID:         deviceID + ":cdp:" + strIndex,
+			SourceType: "cdp",
+			Local: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					DeviceID: deviceID,
+				},
+				Interface: localInterface,
+			},
+			Remote: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					ID:          deviceName,
+					IDType:      "device_name",
+					Name:        deviceName,
+					Description: store.GetColumnAsString("cdp_remote.device_desc", strIndex),
+					Platform:    store.GetColumnAsString("cdp_remote.device_platform", strIndex),
+					IPAddress:   remoteIPAddress,
+				},
+				Interface: &metadata.TopologyLinkInterface{
+					ID:     store.GetColumnAsString("cdp_remote.interface_id", strIndex),
+					IDType: "interface_name",
+				},
+			},
+		})
+	}
+	return links
+}
+
+// formatTopologyID formats the LLDP ids according to their type, mac addresses and network addresses are sent as
+// raw bytes
+func formatTopologyID(store *metadata.Store, field string, index string, idType string) string {
+	switch idType {
+	case "mac_address":
+		if value := store.GetColumnAsByteArray(field, index); len(value) == 6 {
+			return formatColonSepBytes(value)
+		}
+	case "network_address":
+		// the first byte of a network address is its IANA address family
+		if value := store.GetColumnAsByteArray(field, index); len(value) > 1 {
+			if ip := formatIPAddress(int(value[0]), value[1:]); ip != "" {
+				return ip
+			}
+		}
+	}
+	return store.GetColumnAsString(field, index)
+}
+
+func formatIPAddress(addressFamily int, address []byte) string {
+	switch {
+	case addressFamily == ianaAddressFamilyIPv4 && len(address) == net.IPv4len:
+		return net.IP(address).String()
+	case addressFamily == ianaAddressFamilyIPv6 && len(address) == net.IPv6len:
+		return net.IP(address).String()
+	}
+	return ""
+}
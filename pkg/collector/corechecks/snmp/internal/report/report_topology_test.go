@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/common"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/metadata"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/valuestore"
+)
+
+func Test_buildNetworkTopologyMetadata(t *testing.T) {
+	var storeWithTopology = &valuestore.ResultValueStore{
+		ColumnValues: valuestore.ColumnResultValuesType{
+			// lldpLocPortTable
+			"1.0.8802.1.1.2.1.3.7.1.2": {
+				"3": valuestore.ResultValue{Value: float64(5)},
+			},
+			"1.0.8802.1.1.2.1.3.7.1.3": {
+				"3": valuestore.ResultValue{Value: []byte("Gi0/3")},
+			},
+			"1.0.8802.1.1.2.1.3.7.1.4": {
+				"3": valuestore.ResultValue{Value: []byte("uplink")},
+			},
+			// lldpRemTable
+			"1.0.8802.1.1.2.1.4.1.1.4": {
+				"0.3.1": valuestore.ResultValue{Value: float64(4)},
+				"0.4.2": valuestore.ResultValue{Value: float64(5)},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.5": {
+				"0.3.1": valuestore.ResultValue{Value: []byte{0x00, 0x1c, 0x73, 0x0a, 0x0b, 0x0c}},
+				"0.4.2": valuestore.ResultValue{Value: []byte{0x01, 10, 0, 0, 2}},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.6": {
+				"0.3.1": valuestore.ResultValue{Value: float64(5)},
+				"0.4.2": valuestore.ResultValue{Value: float64(3)},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.7": {
+				"0.3.1": valuestore.ResultValue{Value: []byte("Ethernet1")},
+				"0.4.2": valuestore.ResultValue{Value: []byte{0x00, 0x1c, 0x73, 0x0d, 0x0e, 0x0f}},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.8": {
+				"0.3.1": valuestore.ResultValue{Value: []byte("to core")},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.9": {
+				"0.3.1": valuestore.ResultValue{Value: []byte("core-switch")},
+				"0.4.2": valuestore.ResultValue{Value: []byte("access-point")},
+			},
+			"1.0.8802.1.1.2.1.4.1.1.10": {
+				"0.3.1": valuestore.ResultValue{Value: []byte("Arista EOS")},
+			},
+			// lldpRemManAddrTable
+			"1.0.8802.1.1.2.1.4.2.1.3": {
+				"0.3.1.2.16.32.1.13.184.0.0.0.0.0.0.0.0.0.0.0.1": valuestore.ResultValue{Value: float64(2)},
+				"0.3.1.1.4.10.0.0.1":                             valuestore.ResultValue{Value: float64(2)},
+			},
+			// cdpCacheTable
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.3": {
+				"2.7": valuestore.ResultValue{Value: float64(1)},
+			},
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.4": {
+				"2.7": valuestore.ResultValue{Value: []byte{10, 0, 0, 3}},
+			},
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.5": {
+				"2.7": valuestore.ResultValue{Value: []byte("Cisco IOS Software")},
+			},
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.6": {
+				"2.7": valuestore.ResultValue{Value: []byte("edge-router")},
+			},
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.7": {
+				"2.7": valuestore.ResultValue{Value: []byte("GigabitEthernet0/1")},
+			},
+			"1.3.6.1.4.1.9.9.23.1.2.1.1.8": {
+				"2.7": valuestore.ResultValue{Value: []byte("cisco ISR4331")},
+			},
+		},
+	}
+	interfaces := []metadata.InterfaceMetadata{
+		{DeviceID: "my-ns:1.2.3.4", Index: 2, Name: "eth1"},
+	}
+
+	store := buildMetadataStore(checkconfig.TopologyMetadataConfig, storeWithTopology)
+	links := buildNetworkTopologyMetadata("my-ns:1.2.3.4", store, interfaces)
+
+	localDevice := &metadata.TopologyLinkDevice{DeviceID: "my-ns:1.2.3.4"}
+	expectedLinks := []metadata.TopologyLinkMetadata{
+		{
+			ID:         "my-ns:1.2.3.4:lldp:3.1",
+			SourceType: "lldp",
+			Local: &metadata.TopologyLinkSide{
+				Device:    localDevice,
+				Interface: &metadata.TopologyLinkInterface{ID: "Gi0/3", IDType: "interface_name", Description: "uplink"},
+			},
+			Remote: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					ID:          "00:1c:73:0a:0b:0c",
+					IDType:      "mac_address",
+					Name:        "core-switch",
+					Description: "Arista EOS",
+					IPAddress:   "10.0.0.1",
+				},
+				Interface: &metadata.TopologyLinkInterface{ID: "Ethernet1", IDType: "interface_name", Description: "to core"},
+			},
+		},
+		{
+			ID:         "my-ns:1.2.3.4:lldp:4.2",
+			SourceType: "lldp",
+			Local: &metadata.TopologyLinkSide{
+				Device:    localDevice,
+				Interface: &metadata.TopologyLinkInterface{},
+			},
+			Remote: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					ID:     "10.0.0.2",
+					IDType: "network_address",
+					Name:   "access-point",
+				},
+				Interface: &metadata.TopologyLinkInterface{ID: "00:1c:73:0d:0e:0f", IDType: "mac_address"},
+			},
+		},
+		{
+			ID:         "my-ns:1.2.3.4:cdp:2.7",
+			SourceType: "cdp",
+			Local: &metadata.TopologyLinkSide{
+				Device:    localDevice,
+				Interface: &metadata.TopologyLinkInterface{Index: 2, ID: "eth1", IDType: "interface_name"},
+			},
+			Remote: &metadata.TopologyLinkSide{
+				Device: &metadata.TopologyLinkDevice{
+					ID:          "edge-router",
+					IDType:      "device_name",
+					Name:        "edge-router",
+					Description: "Cisco IOS Software",
+					Platform:    "cisco ISR4331",
+					IPAddress:   "10.0.0.3",
+				},
+				Interface: &metadata.TopologyLinkInterface{ID: "GigabitEthernet0/1", IDType: "interface_name"},
+			},
+		},
+	}
+	assert.Equal(t, expectedLinks, links)
+
+	// no links are built for unreachable devices
+	assert.Nil(t, buildNetworkTopologyMetadata("my-ns:1.2.3.4", nil, interfaces))
+}
+
+func Test_batchPayloads_withTopologyLinks(t *testing.T) {
+	device := metadata.DeviceMetadata{ID: "123"}
+	interfaces := []metadata.InterfaceMetadata{{DeviceID: "123", Index: 1}}
+	var links []metadata.TopologyLinkMetadata
+	for i := 0; i < 150; i++ {
+		links = append(links, metadata.TopologyLinkMetadata{SourceType: "lldp"})
+	}
+
+	payloads := batchPayloads("my-ns", "127.0.0.0/30", common.MockTimeNow(), 100, device, interfaces, links)
+
+	assert.Equal(t, 2, len(payloads))
+	assert.Len(t, payloads[0].Interfaces, 1)
+	assert.Len(t, payloads[0].Links, 98)
+	assert.Len(t, payloads[1].Links, 52)
+}
@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+// mibSymbol is a scalar or a column known by the generator
+type mibSymbol struct {
+	oid  string
+	name string
+}
+
+// mibTag tags the rows of a table with the value of a column, or with an index when column is empty
+type mibTag struct {
+	tag    string
+	column mibSymbol
+	index  uint
+}
+
+// mibTable is a table known by the generator, a metric is generated for each column present in the walk.
+// The column tags present in the walk are used, indexTag is used when none of them is.
+type mibTable struct {
+	mib      string
+	table    mibSymbol
+	columns  []mibSymbol
+	tags     []mibTag
+	indexTag mibTag
+}
+
+// mibScalars are scalars of the same MIB, a metric is generated for each scalar present in the walk
+type mibScalars struct {
+	mib     string
+	scalars []mibSymbol
+}
+
+const (
+	sysObjectIDOID = "1.3.6.1.2.1.1.2.0"
+	ifTableOID     = "1.3.6.1.2.1.2.2"
+)
+
+// baseProfile is always extended by the generated profiles, genericInterfaceProfile is extended
+// when the device supports IF-MIB as it already contains the interfaces metrics and metadata
+const (
+	baseProfile             = "_base.yaml"
+	genericInterfaceProfile = "_generic-if.yaml"
+)
+
+var knownTables = []mibTable{
+	{
+		mib:   "HOST-RESOURCES-MIB",
+		table: mibSymbol{"1.3.6.1.2.1.25.2.3", "hrStorageTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.2.1.25.2.3.1.4", "hrStorageAllocationUnits"},
+			{"1.3.6.1.2.1.25.2.3.1.5", "hrStorageSize"},
+			{"1.3.6.1.2.1.25.2.3.1.6", "hrStorageUsed"},
+		},
+		tags: []mibTag{
+			{tag: "storage_desc", column: mibSymbol{"1.3.6.1.2.1.25.2.3.1.3", "hrStorageDescr"}},
+		},
+		indexTag: mibTag{tag: "storage_index", index: 1},
+	},
+	{
+		mib:   "HOST-RESOURCES-MIB",
+		table: mibSymbol{"1.3.6.1.2.1.25.3.3", "hrProcessorTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.2.1.25.3.3.1.2", "hrProcessorLoad"},
+		},
+		indexTag: mibTag{tag: "processorid", index: 1},
+	},
+	{
+		mib:   "ENTITY-SENSOR-MIB",
+		table: mibSymbol{"1.3.6.1.2.1.99.1.1", "entPhySensorTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.2.1.99.1.1.1.4", "entPhySensorValue"},
+			{"1.3.6.1.2.1.99.1.1.1.5", "entPhySensorOperStatus"},
+		},
+		tags: []mibTag{
+			{tag: "sensor_type", column: mibSymbol{"1.3.6.1.2.1.99.1.1.1.1", "entPhySensorType"}},
+		},
+		indexTag: mibTag{tag: "sensor_id", index: 1},
+	},
+	{
+		mib:   "CISCO-PROCESS-MIB",
+		table: mibSymbol{"1.3.6.1.4.1.9.9.109.1.1.1", "cpmCPUTotalTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.4.1.9.9.109.1.1.1.1.7", "cpmCPUTotal1minRev"},
+			{"1.3.6.1.4.1.9.9.109.1.1.1.1.8", "cpmCPUTotal5minRev"},
+			{"1.3.6.1.4.1.9.9.109.1.1.1.1.12", "cpmCPUMemoryUsed"},
+			{"1.3.6.1.4.1.9.9.109.1.1.1.1.13", "cpmCPUMemoryFree"},
+		},
+		indexTag: mibTag{tag: "cpu", index: 1},
+	},
+	{
+		mib:   "CISCO-MEMORY-POOL-MIB",
+		table: mibSymbol{"1.3.6.1.4.1.9.9.48.1.1", "ciscoMemoryPoolTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.4.1.9.9.48.1.1.1.5", "ciscoMemoryPoolUsed"},
+			{"1.3.6.1.4.1.9.9.48.1.1.1.6", "ciscoMemoryPoolFree"},
+		},
+		tags: []mibTag{
+			{tag: "mem_pool_name", column: mibSymbol{"1.3.6.1.4.1.9.9.48.1.1.1.2", "ciscoMemoryPoolName"}},
+		},
+		indexTag: mibTag{tag: "mem_pool_index", index: 1},
+	},
+	{
+		mib:   "UCD-SNMP-MIB",
+		table: mibSymbol{"1.3.6.1.4.1.2021.9", "dskTable"},
+		columns: []mibSymbol{
+			{"1.3.6.1.4.1.2021.9.1.6", "dskTotal"},
+			{"1.3.6.1.4.1.2021.9.1.7", "dskAvail"},
+			{"1.3.6.1.4.1.2021.9.1.8", "dskUsed"},
+			{"1.3.6.1.4.1.2021.9.1.9", "dskPercent"},
+		},
+		tags: []mibTag{
+			{tag: "disk_path", column: mibSymbol{"1.3.6.1.4.1.2021.9.1.2", "dskPath"}},
+		},
+		indexTag: mibTag{tag: "disk_index", index: 1},
+	},
+}
+
+var knownScalars = []mibScalars{
+	{
+		mib: "TCP-MIB",
+		scalars: []mibSymbol{
+			{"1.3.6.1.2.1.6.5.0", "tcpActiveOpens"},
+			{"1.3.6.1.2.1.6.6.0", "tcpPassiveOpens"},
+			{"1.3.6.1.2.1.6.7.0", "tcpAttemptFails"},
+			{"1.3.6.1.2.1.6.8.0", "tcpEstabResets"},
+			{"1.3.6.1.2.1.6.9.0", "tcpCurrEstab"},
+			{"1.3.6.1.2.1.6.12.0", "tcpRetransSegs"},
+		},
+	},
+	{
+		mib: "UDP-MIB",
+		scalars: []mibSymbol{
+			{"1.3.6.1.2.1.7.1.0", "udpInDatagrams"},
+			{"1.3.6.1.2.1.7.2.0", "udpNoPorts"},
+			{"1.3.6.1.2.1.7.3.0", "udpInErrors"},
+			{"1.3.6.1.2.1.7.4.0", "udpOutDatagrams"},
+		},
+	},
+	{
+		mib: "UCD-SNMP-MIB",
+		scalars: []mibSymbol{
+			{"1.3.6.1.4.1.2021.4.5.0", "memTotalReal"},
+			{"1.3.6.1.4.1.2021.4.6.0", "memAvailReal"},
+			{"1.3.6.1.4.1.2021.4.11.0", "memTotalFree"},
+			{"1.3.6.1.4.1.2021.11.11.0", "ssCpuIdle"},
+		},
+	},
+}
@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+)
+
+const profileHeader = "# Draft profile generated by `agent snmp generate-profile`.\n# Review the metrics and tags before adding it to the `snmp.d/profiles` folder.\n"
+
+// Profile is a draft profile definition, it uses the same YAML syntax as the profiles loaded by the check
+type Profile struct {
+	Extends     []string `yaml:"extends"`
+	SysObjectID string   `yaml:"sysobjectid"`
+	Metrics     []Metric `yaml:"metrics,omitempty"`
+}
+
+// Metric is a scalar metric when Symbol is set, or a table metric when Table and Symbols are set
+type Metric struct {
+	MIB        string      `yaml:"MIB"`
+	Table      *Symbol     `yaml:"table,omitempty"`
+	Symbol     *Symbol     `yaml:"symbol,omitempty"`
+	Symbols    []Symbol    `yaml:"symbols,omitempty"`
+	MetricTags []MetricTag `yaml:"metric_tags,omitempty"`
+}
+
+// Symbol is an OID and its name
+type Symbol struct {
+	OID  string `yaml:"OID"`
+	Name string `yaml:"name"`
+}
+
+// MetricTag tags the rows of a table with a column value or with an index
+type MetricTag struct {
+	Tag    string  `yaml:"tag"`
+	Index  uint    `yaml:"index,omitempty"`
+	Column *Symbol `yaml:"column,omitempty"`
+}
+
+// Generate generates a draft profile from the variables walked from a device: the profile matches the
+// sysObjectID of the device and contains the metrics of the known MIB tables and scalars found in the walk.
+// The profile is validated the same way the check validates the profiles it loads.
+func Generate(variables []Variable) (*Profile, error) {
+	walk := newWalkIndex(variables)
+
+	sysObjectID, ok := walk.values[sysObjectIDOID]
+	if !ok || sysObjectID.Value == "" {
+		return nil, fmt.Errorf("sysObjectID (%s) not found in the walk", sysObjectIDOID)
+	}
+
+	profile := &Profile{
+		Extends:     []string{baseProfile},
+		SysObjectID: sysObjectID.Value,
+	}
+	if walk.hasColumn(ifTableOID) {
+		profile.Extends = append(profile.Extends, genericInterfaceProfile)
+	}
+
+	for _, table := range knownTables {
+		if metric, ok := buildTableMetric(walk, table); ok {
+			profile.Metrics = append(profile.Metrics, metric)
+		}
+	}
+	for _, mibScalars := range knownScalars {
+		for _, scalar := range mibScalars.scalars {
+			if value, ok := walk.values[scalar.oid]; ok && value.IsNumeric() {
+				profile.Metrics = append(profile.Metrics, Metric{
+					MIB:    mibScalars.mib,
+					Symbol: &Symbol{OID: scalar.oid, Name: scalar.name},
+				})
+			}
+		}
+	}
+
+	content, err := profile.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkconfig.ValidateProfileDefinition(content); err != nil {
+		return nil, fmt.Errorf("generated profile is invalid: %w", err)
+	}
+	return profile, nil
+}
+
+// Marshal returns the YAML definition of the profile
+func (p *Profile) Marshal() ([]byte, error) {
+	content, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	return append([]byte(profileHeader), content...), nil
+}
+
+func buildTableMetric(walk *walkIndex, table mibTable) (Metric, bool) {
+	metric := Metric{
+		MIB:   table.mib,
+		Table: &Symbol{OID: table.table.oid, Name: table.table.name},
+	}
+	for _, column := range table.columns {
+		if walk.hasNumericColumn(column.oid) {
+			metric.Symbols = append(metric.Symbols, Symbol{OID: column.oid, Name: column.name})
+		}
+	}
+	if len(metric.Symbols) == 0 {
+		return metric, false
+	}
+
+	for _, tag := range table.tags {
+		if walk.hasColumn(tag.column.oid) {
+			metric.MetricTags = append(metric.MetricTags, newMetricTag(tag))
+		}
+	}
+	if len(metric.MetricTags) == 0 {
+		metric.MetricTags = append(metric.MetricTags, newMetricTag(table.indexTag))
+	}
+	return metric, true
+}
+
+func newMetricTag(tag mibTag) MetricTag {
+	metricTag := MetricTag{Tag: tag.tag, Index: tag.index}
+	if tag.column.oid != "" {
+		metricTag.Column = &Symbol{OID: tag.column.oid, Name: tag.column.name}
+	}
+	return metricTag
+}
+
+// walkIndex indexes the walked variables by OID
+type walkIndex struct {
+	variables []Variable
+	values    map[string]Variable
+}
+
+func newWalkIndex(variables []Variable) *walkIndex {
+	values := make(map[string]Variable, len(variables))
+	for _, variable := range variables {
+		values[variable.OID] = variable
+	}
+	return &walkIndex{variables: variables, values: values}
+}
+
+// hasColumn returns true if the walk contains a value under the OID
+func (w *walkIndex) hasColumn(oid string) bool {
+	return w.findColumnValue(oid, false)
+}
+
+// hasNumericColumn returns true if the walk contains a numeric value under the OID
+func (w *walkIndex) hasNumericColumn(oid string) bool {
+	return w.findColumnValue(oid, true)
+}
+
+func (w *walkIndex) findColumnValue(oid string, numeric bool) bool {
+	prefix := oid + "."
+	for _, variable := range w.variables {
+		if strings.HasPrefix(variable.OID, prefix) && (!numeric || variable.IsNumeric()) {
+			return true
+		}
+	}
+	return false
+}
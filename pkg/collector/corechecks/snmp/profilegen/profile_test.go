@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	f, err := os.Open("testdata/walk.txt")
+	require.NoError(t, err)
+	defer f.Close()
+	variables, err := ParseWalk(f)
+	require.NoError(t, err)
+
+	profile, err := Generate(variables)
+	require.NoError(t, err)
+	content, err := profile.Marshal()
+	require.NoError(t, err)
+
+	expectedProfile := `# Draft profile generated by ` + "`agent snmp generate-profile`" + `.
+# Review the metrics and tags before adding it to the ` + "`snmp.d/profiles`" + ` folder.
+extends:
+- _base.yaml
+- _generic-if.yaml
+sysobjectid: 1.3.6.1.4.1.9.1.1208
+metrics:
+- MIB: CISCO-PROCESS-MIB
+  table:
+    OID: 1.3.6.1.4.1.9.9.109.1.1.1
+    name: cpmCPUTotalTable
+  symbols:
+  - OID: 1.3.6.1.4.1.9.9.109.1.1.1.1.7
+    name: cpmCPUTotal1minRev
+  - OID: 1.3.6.1.4.1.9.9.109.1.1.1.1.8
+    name: cpmCPUTotal5minRev
+  metric_tags:
+  - tag: cpu
+    index: 1
+- MIB: CISCO-MEMORY-POOL-MIB
+  table:
+    OID: 1.3.6.1.4.1.9.9.48.1.1
+    name: ciscoMemoryPoolTable
+  symbols:
+  - OID: 1.3.6.1.4.1.9.9.48.1.1.1.5
+    name: ciscoMemoryPoolUsed
+  - OID: 1.3.6.1.4.1.9.9.48.1.1.1.6
+    name: ciscoMemoryPoolFree
+  metric_tags:
+  - tag: mem_pool_name
+    column:
+      OID: 1.3.6.1.4.1.9.9.48.1.1.1.2
+      name: ciscoMemoryPoolName
+- MIB: TCP-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.6.5.0
+    name: tcpActiveOpens
+- MIB: TCP-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.6.9.0
+    name: tcpCurrEstab
+- MIB: UDP-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.7.1.0
+    name: udpInDatagrams
+`
+	assert.Equal(t, expectedProfile, string(content))
+}
+
+func TestGenerate_noSysObjectID(t *testing.T) {
+	_, err := Generate([]Variable{{OID: "1.3.6.1.2.1.6.9.0", Type: "Gauge32", Value: "3"}})
+	assert.EqualError(t, err, "sysObjectID (1.3.6.1.2.1.1.2.0) not found in the walk")
+}
+
+func TestGenerate_onlyBase(t *testing.T) {
+	profile, err := Generate([]Variable{{OID: "1.3.6.1.2.1.1.2.0", Type: "OID", Value: "1.3.6.1.4.1.8072.3.2.10"}})
+	require.NoError(t, err)
+	assert.Equal(t, &Profile{Extends: []string{"_base.yaml"}, SysObjectID: "1.3.6.1.4.1.8072.3.2.10"}, profile)
+}
@@ -0,0 +1,228 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
+)
+
+const walkRootOID = "1.3.6.1"
+
+// Variable is a value walked from a device, Type uses the names of the types printed by snmpwalk
+// (INTEGER, STRING, OID, Counter32, ...)
+type Variable struct {
+	OID   string
+	Type  string
+	Value string
+}
+
+// numericTypes are the types of the variables that can be submitted as metrics
+var numericTypes = map[string]bool{
+	"INTEGER":    true,
+	"Integer32":  true,
+	"Unsigned32": true,
+	"Counter32":  true,
+	"Counter64":  true,
+	"Gauge32":    true,
+	"Timeticks":  true,
+}
+
+// IsNumeric returns true if the variable can be submitted as a metric
+func (v Variable) IsNumeric() bool {
+	return numericTypes[v.Type]
+}
+
+var (
+	walkLinePattern           = regexp.MustCompile(`^\.?([0-9]+(?:\.[0-9]+)*) = (?:([A-Za-z0-9-]+): )?(.*)$`)
+	nonNumericWalkLinePattern = regexp.MustCompile(`^(\S+) = `)
+)
+
+// ParseWalk parses the output of `snmpwalk -On`, OIDs must be numeric.
+// Lines that aren't variables are continuations of multi-line string values and are ignored.
+func ParseWalk(r io.Reader) ([]Variable, error) {
+	var variables []Variable
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		matches := walkLinePattern.FindStringSubmatch(line)
+		if matches == nil {
+			if nonNumeric := nonNumericWalkLinePattern.FindStringSubmatch(line); nonNumeric != nil && isSymbolicOID(nonNumeric[1]) {
+				return nil, fmt.Errorf("line %d: OID `%s` is not numeric, the walk must be done with `snmpwalk -On`", lineNumber, nonNumeric[1])
+			}
+			continue
+		}
+		variable := Variable{OID: matches[1], Type: matches[2], Value: matches[3]}
+		switch variable.Type {
+		case "STRING":
+			variable.Value = strings.Trim(variable.Value, `"`)
+		case "OID":
+			variable.Value = strings.TrimPrefix(variable.Value, ".")
+		}
+		variables = append(variables, variable)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading walk: %w", err)
+	}
+	return variables, nil
+}
+
+// DeviceConfig contains the parameters used to walk a device
+type DeviceConfig struct {
+	IPAddress       string
+	Port            uint16
+	SnmpVersion     string
+	CommunityString string
+	User            string
+	AuthProtocol    string
+	AuthKey         string
+	PrivProtocol    string
+	PrivKey         string
+	ContextName     string
+	Timeout         int
+	Retries         int
+}
+
+// WalkDevice walks the whole `1.3.6.1` subtree of a device, using GETBULK requests unless SNMPv1 is used
+func WalkDevice(config DeviceConfig) ([]Variable, error) {
+	params, err := buildSNMPParams(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.Connect(); err != nil {
+		return nil, fmt.Errorf("unable to connect to %s: %w", config.IPAddress, err)
+	}
+	defer params.Conn.Close()
+
+	var pdus []gosnmp.SnmpPDU
+	if params.Version == gosnmp.Version1 {
+		pdus, err = params.WalkAll(walkRootOID)
+	} else {
+		pdus, err = params.BulkWalkAll(walkRootOID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to walk %s: %w", config.IPAddress, err)
+	}
+
+	variables := make([]Variable, 0, len(pdus))
+	for _, pdu := range pdus {
+		if variable, ok := variableFromPDU(pdu); ok {
+			variables = append(variables, variable)
+		}
+	}
+	return variables, nil
+}
+
+func buildSNMPParams(config DeviceConfig) (*gosnmp.GoSNMP, error) {
+	params := &gosnmp.GoSNMP{
+		Target:  config.IPAddress,
+		Port:    config.Port,
+		Timeout: time.Duration(config.Timeout) * time.Second,
+		Retries: config.Retries,
+	}
+
+	switch config.SnmpVersion {
+	case "1", "2", "2c", "":
+		if config.CommunityString == "" {
+			return nil, fmt.Errorf("a community string is required with SNMP version `%s`", config.SnmpVersion)
+		}
+		params.Version = gosnmp.Version2c
+		if config.SnmpVersion == "1" {
+			params.Version = gosnmp.Version1
+		}
+		params.Community = config.CommunityString
+	case "3":
+		if config.User == "" {
+			return nil, fmt.Errorf("a user is required with SNMP version 3")
+		}
+		authProtocol, err := gosnmplib.GetAuthProtocol(config.AuthProtocol)
+		if err != nil {
+			return nil, err
+		}
+		privProtocol, err := gosnmplib.GetPrivProtocol(config.PrivProtocol)
+		if err != nil {
+			return nil, err
+		}
+
+		msgFlags := gosnmp.NoAuthNoPriv
+		if privProtocol != gosnmp.NoPriv {
+			// Auth is needed if privacy is used.
+			msgFlags = gosnmp.AuthPriv
+		} else if authProtocol != gosnmp.NoAuth {
+			msgFlags = gosnmp.AuthNoPriv
+		}
+
+		params.Version = gosnmp.Version3
+		params.MsgFlags = msgFlags
+		params.ContextName = config.ContextName
+		params.SecurityModel = gosnmp.UserSecurityModel
+		params.SecurityParameters = &gosnmp.UsmSecurityParameters{
+			UserName:                 config.User,
+			AuthenticationProtocol:   authProtocol,
+			AuthenticationPassphrase: config.AuthKey,
+			PrivacyProtocol:          privProtocol,
+			PrivacyPassphrase:        config.PrivKey,
+		}
+	default:
+		return nil, fmt.Errorf("invalid SNMP version `%s`, valid versions are: 1, 2c, 3", config.SnmpVersion)
+	}
+	return params, nil
+}
+
+// variableFromPDU converts a PDU to a Variable, the PDUs without value are skipped
+func variableFromPDU(pdu gosnmp.SnmpPDU) (Variable, bool) {
+	variable := Variable{OID: strings.TrimPrefix(pdu.Name, ".")}
+	switch pdu.Type {
+	case gosnmp.Integer:
+		variable.Type = "INTEGER"
+	case gosnmp.Counter32:
+		variable.Type = "Counter32"
+	case gosnmp.Counter64:
+		variable.Type = "Counter64"
+	case gosnmp.Gauge32:
+		variable.Type = "Gauge32"
+	case gosnmp.Uinteger32:
+		variable.Type = "Unsigned32"
+	case gosnmp.TimeTicks:
+		variable.Type = "Timeticks"
+	case gosnmp.OctetString:
+		variable.Type = "STRING"
+		if value, ok := pdu.Value.([]byte); ok {
+			variable.Value = string(value)
+		}
+		return variable, true
+	case gosnmp.ObjectIdentifier:
+		variable.Type = "OID"
+		if value, ok := pdu.Value.(string); ok {
+			variable.Value = strings.TrimPrefix(value, ".")
+		}
+		return variable, true
+	case gosnmp.IPAddress:
+		variable.Type = "IpAddress"
+		variable.Value = fmt.Sprint(pdu.Value)
+		return variable, true
+	default:
+		return variable, false
+	}
+	variable.Value = gosnmp.ToBigInt(pdu.Value).String()
+	return variable, true
+}
+
+// isSymbolicOID returns true for the OIDs printed by snmpwalk without `-On`, like `SNMPv2-MIB::sysDescr.0` or `iso.3.6.1.2.1.1.1.0`
+func isSymbolicOID(oid string) bool {
+	return strings.Contains(oid, "::") || strings.HasPrefix(oid, "iso.")
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package profilegen
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWalk(t *testing.T) {
+	f, err := os.Open("testdata/walk.txt")
+	require.NoError(t, err)
+	defer f.Close()
+
+	variables, err := ParseWalk(f)
+	require.NoError(t, err)
+
+	require.Len(t, variables, 20)
+	assert.Equal(t, Variable{OID: "1.3.6.1.2.1.1.1.0", Type: "STRING", Value: "Cisco IOS Software, C2960 Software (C2960-LANBASEK9-M), Version 12.2(55)SE7"}, variables[0])
+	assert.Equal(t, Variable{OID: "1.3.6.1.2.1.1.2.0", Type: "OID", Value: "1.3.6.1.4.1.9.1.1208"}, variables[1])
+	assert.Equal(t, Variable{OID: "1.3.6.1.2.1.1.3.0", Type: "Timeticks", Value: "(123456) 0:20:34.56"}, variables[2])
+	assert.Equal(t, Variable{OID: "1.3.6.1.2.1.6.9.0", Type: "Gauge32", Value: "3"}, variables[8])
+	assert.Equal(t, Variable{OID: "1.3.6.1.4.1.9.9.109.1.1.1.1.12.1", Value: "No Such Object available on this agent at this OID"}, variables[19])
+	assert.False(t, variables[19].IsNumeric())
+}
+
+func TestParseWalk_symbolicOIDs(t *testing.T) {
+	_, err := ParseWalk(strings.NewReader("SNMPv2-MIB::sysDescr.0 = STRING: Linux\n"))
+	assert.EqualError(t, err, "line 1: OID `SNMPv2-MIB::sysDescr.0` is not numeric, the walk must be done with `snmpwalk -On`")
+
+	_, err = ParseWalk(strings.NewReader("iso.3.6.1.2.1.1.1.0 = STRING: \"Linux\"\n"))
+	assert.EqualError(t, err, "line 1: OID `iso.3.6.1.2.1.1.1.0` is not numeric, the walk must be done with `snmpwalk -On`")
+}
+
+func Test_variableFromPDU(t *testing.T) {
+	data := []struct {
+		pdu              gosnmp.SnmpPDU
+		expectedVariable Variable
+		expectedOk       bool
+	}{
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.9.1.1208"}, Variable{OID: "1.3.6.1.2.1.1.2.0", Type: "OID", Value: "1.3.6.1.4.1.9.1.1208"}, true},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.5.0", Type: gosnmp.OctetString, Value: []byte("access-switch")}, Variable{OID: "1.3.6.1.2.1.1.5.0", Type: "STRING", Value: "access-switch"}, true},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.31.1.1.1.6.1", Type: gosnmp.Counter64, Value: uint64(18446744073709551615)}, Variable{OID: "1.3.6.1.2.1.31.1.1.1.6.1", Type: "Counter64", Value: "18446744073709551615"}, true},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.6.9.0", Type: gosnmp.Gauge32, Value: uint(3)}, Variable{OID: "1.3.6.1.2.1.6.9.0", Type: "Gauge32", Value: "3"}, true},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.1.1", Type: gosnmp.Integer, Value: -1}, Variable{OID: "1.3.6.1.2.1.2.2.1.1.1", Type: "INTEGER", Value: "-1"}, true},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.9.0", Type: gosnmp.NoSuchObject}, Variable{OID: "1.3.6.1.2.1.1.9.0"}, false},
+	}
+	for _, d := range data {
+		variable, ok := variableFromPDU(d.pdu)
+		assert.Equal(t, d.expectedOk, ok)
+		assert.Equal(t, d.expectedVariable, variable)
+	}
+}
+
+func Test_buildSNMPParams(t *testing.T) {
+	params, err := buildSNMPParams(DeviceConfig{IPAddress: "1.2.3.4", Port: 161, SnmpVersion: "1", CommunityString: "public", Timeout: 5, Retries: 2})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.Version1, params.Version)
+	assert.Equal(t, "public", params.Community)
+
+	params, err = buildSNMPParams(DeviceConfig{IPAddress: "1.2.3.4", SnmpVersion: "3", User: "admin", AuthProtocol: "sha", AuthKey: "authkey1", PrivProtocol: "aes", PrivKey: "privkey1"})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.Version3, params.Version)
+	assert.Equal(t, gosnmp.AuthPriv, params.MsgFlags)
+
+	_, err = buildSNMPParams(DeviceConfig{IPAddress: "1.2.3.4", SnmpVersion: "2c"})
+	assert.EqualError(t, err, "a community string is required with SNMP version `2c`")
+	_, err = buildSNMPParams(DeviceConfig{IPAddress: "1.2.3.4", SnmpVersion: "3"})
+	assert.EqualError(t, err, "a user is required with SNMP version 3")
+	_, err = buildSNMPParams(DeviceConfig{IPAddress: "1.2.3.4", SnmpVersion: "4"})
+	assert.EqualError(t, err, "invalid SNMP version `4`, valid versions are: 1, 2c, 3")
+}
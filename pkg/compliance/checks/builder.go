@@ -24,8 +24,13 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	cache "github.com/patrickmn/go-cache"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	k8scache "k8s.io/client-go/tools/cache"
 )
 
 // ErrResourceNotSupported is returned when resource type is not supported by Builder
@@ -131,6 +136,9 @@ func WithAuditClient(cli env.AuditClient) BuilderOption {
 type kubeClient struct {
 	dynamic.Interface
 	clusterID string
+
+	informers dynamicinformer.DynamicSharedInformerFactory
+	stopCh    chan struct{}
 }
 
 func (c *kubeClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
@@ -158,6 +166,51 @@ func (c *kubeClient) ClusterID() (string, error) {
 	return c.clusterID, nil
 }
 
+// ListObjects lists the objects of a resource from the cache of its informer. The informer is started on the first
+// call, the objects are listed from the API server if its cache can't be synced.
+func (c *kubeClient) ListObjects(ctx context.Context, resource schema.GroupVersionResource, namespace string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	if c.informers == nil {
+		return listKubeObjects(ctx, c.Interface, resource, namespace, selector)
+	}
+
+	informer := c.informers.ForResource(resource)
+	sharedInformer := informer.Informer()
+	c.informers.Start(c.stopCh)
+
+	if !k8scache.WaitForCacheSync(ctx.Done(), sharedInformer.HasSynced) {
+		log.Warnf("informer cache of %s not synced, listing the objects from the API server", resource)
+		return listKubeObjects(ctx, c.Interface, resource, namespace, selector)
+	}
+
+	var objects []runtime.Object
+	var err error
+	if len(namespace) > 0 {
+		objects, err = informer.Lister().ByNamespace(namespace).List(selector)
+	} else {
+		objects, err = informer.Lister().List(selector)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*unstructured.Unstructured, 0, len(objects))
+	for _, object := range objects {
+		resource, ok := object.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object type %T", object)
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
+
+func (c *kubeClient) stop() {
+	if c.stopCh != nil {
+		close(c.stopCh)
+		c.stopCh = nil
+	}
+}
+
 // WithKubernetesClient allows specific Kubernetes client
 func WithKubernetesClient(cli dynamic.Interface, clusterID string) BuilderOption {
 	return func(b *builder) error {
@@ -166,6 +219,19 @@ func WithKubernetesClient(cli dynamic.Interface, clusterID string) BuilderOption
 	}
 }
 
+// WithKubernetesInformers allows the collections of Kubernetes objects to be listed from the cache of informers. It has
+// to be set after the Kubernetes client.
+func WithKubernetesInformers(informers dynamicinformer.DynamicSharedInformerFactory) BuilderOption {
+	return func(b *builder) error {
+		if b.kubeClient == nil {
+			return errors.New("kubernetes informers require a kubernetes client")
+		}
+		b.kubeClient.informers = informers
+		b.kubeClient.stopCh = make(chan struct{})
+		return nil
+	}
+}
+
 // WithIsLeader allows check runner to know if its a leader instance or not (DCA)
 func WithIsLeader(isLeader func() bool) BuilderOption {
 	return func(b *builder) error {
@@ -322,6 +388,9 @@ func (b *builder) Close() error {
 			return err
 		}
 	}
+	if b.kubeClient != nil {
+		b.kubeClient.stop()
+	}
 
 	return nil
 }
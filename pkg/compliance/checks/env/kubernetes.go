@@ -5,10 +5,22 @@
 
 package env
 
-import "k8s.io/client-go/dynamic"
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
 
 // KubeClient is the Kubernetes (API server) client interface
 type KubeClient interface {
 	dynamic.Interface
 	ClusterID() (string, error)
 }
+
+// KubeObjectsLister is implemented by the Kubernetes clients able to list objects from a local cache
+type KubeObjectsLister interface {
+	ListObjects(ctx context.Context, resource schema.GroupVersionResource, namespace string, selector labels.Selector) ([]*unstructured.Unstructured, error)
+}
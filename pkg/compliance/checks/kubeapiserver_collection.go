@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func resolveKubeCollection(ctx context.Context, e env.Env, ruleID string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.KubeCollection == nil {
+		return nil, fmt.Errorf("expecting KubeCollection resource in KubeCollection check")
+	}
+
+	collection := res.KubeCollection
+
+	if len(collection.Kind) == 0 {
+		return nil, fmt.Errorf("cannot run KubeCollection check, resource kind is empty")
+	}
+
+	version := collection.Version
+	if len(version) == 0 {
+		version = "v1"
+	}
+
+	resourceSchema := schema.GroupVersionResource{
+		Group:    collection.Group,
+		Resource: collection.Kind,
+		Version:  version,
+	}
+
+	selector, err := labels.Parse(collection.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector '%s': %w", collection.LabelSelector, err)
+	}
+
+	var resources []*unstructured.Unstructured
+	if lister, ok := e.KubeClient().(env.KubeObjectsLister); ok {
+		resources, err = lister.ListObjects(ctx, resourceSchema, collection.Namespace, selector)
+	} else {
+		resources, err = listKubeObjects(ctx, e.KubeClient(), resourceSchema, collection.Namespace, selector)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to list Kube resources:'%v', ns:'%s', err: %v", resourceSchema, collection.Namespace, err)
+	}
+
+	log.Debugf("%s: Got %d resources", ruleID, len(resources))
+
+	instances := make([]resolvedInstance, len(resources))
+	for i, resource := range resources {
+		instances[i] = newKubeResourceInstance(*resource)
+	}
+
+	return newResolvedInstances(instances), nil
+}
+
+// listKubeObjects lists the objects of a resource from the API server
+func listKubeObjects(ctx context.Context, client dynamic.Interface, resource schema.GroupVersionResource, namespace string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
+	var resourceAPI dynamic.ResourceInterface = client.Resource(resource)
+	if len(namespace) > 0 {
+		resourceAPI = client.Resource(resource).Namespace(namespace)
+	}
+
+	list, err := resourceAPI.List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		objects[i] = &list.Items[i]
+	}
+	return objects, nil
+}
@@ -0,0 +1,288 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Namespace",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+}
+
+func newNetworkPolicy(namespace, name string, podSelector map[string]string) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+func newPod(namespace, name, serviceAccount string) *corev1.Pod {
+	return &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Pod",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": name},
+		},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: serviceAccount,
+		},
+	}
+}
+
+func newServiceAccount(namespace, name string, automount bool) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		AutomountServiceAccountToken: &automount,
+	}
+}
+
+type kubeCollectionFixture struct {
+	name          string
+	inputs        []compliance.RegoInput
+	module        string
+	objects       []runtime.Object
+	expectReports map[string]bool
+}
+
+func (f *kubeCollectionFixture) run(t *testing.T) {
+	t.Helper()
+	assert := assert.New(t)
+
+	env := &mocks.Env{}
+	env.On("MaxEventsPerRun").Return(30).Maybe()
+	env.On("ProvidedInput", mock.Anything).Return(nil).Once()
+	env.On("Hostname").Return("hostname_test").Once()
+	env.On("DumpInputPath").Return("").Once()
+	env.On("ShouldSkipRegoEval").Return(false).Once()
+
+	kubeClient := &fakeKubeClient{
+		FakeDynamicClient: fake.NewSimpleDynamicClient(scheme, f.objects...),
+	}
+	env.On("KubeClient").Return(kubeClient)
+
+	defer env.AssertExpectations(t)
+
+	fixture := regoFixture{
+		inputs:   f.inputs,
+		module:   f.module,
+		findings: "data.test.findings",
+	}
+	regoCheck, err := fixture.newRegoCheck()
+	assert.NoError(err)
+
+	reports := regoCheck.check(env)
+	assert.Len(reports, len(f.expectReports))
+	for _, report := range reports {
+		assert.NoError(report.Error)
+
+		passed, found := f.expectReports[report.Resource.ID]
+		assert.True(found, "unexpected report for %s", report.Resource.ID)
+		assert.Equal(passed, report.Passed, "unexpected status for %s", report.Resource.ID)
+	}
+}
+
+func TestKubeCollectionRegoCheck(t *testing.T) {
+	tests := []kubeCollectionFixture{
+		{
+			name: "default deny network policy",
+			inputs: []compliance.RegoInput{
+				{
+					ResourceCommon: compliance.ResourceCommon{
+						KubeCollection: &compliance.KubernetesCollection{
+							Kind: "namespaces",
+						},
+					},
+					TagName: "namespaces",
+				},
+				{
+					ResourceCommon: compliance.ResourceCommon{
+						KubeCollection: &compliance.KubernetesCollection{
+							Kind:  "networkpolicies",
+							Group: "networking.k8s.io",
+						},
+					},
+					TagName: "networkpolicies",
+				},
+			},
+			module: `
+				package test
+
+				import data.datadog as dd
+
+				default_deny(ns) {
+					p := input.networkpolicies[_]
+					p.namespace == ns.name
+					count(object.get(p.resource.Object.spec.podSelector, "matchLabels", {})) == 0
+				}
+
+				findings[f] {
+					ns := input.namespaces[_]
+					default_deny(ns)
+					f := dd.passed_finding("kube_namespace", ns.name, {})
+				}
+
+				findings[f] {
+					ns := input.namespaces[_]
+					not default_deny(ns)
+					f := dd.failing_finding("kube_namespace", ns.name, {})
+				}
+			`,
+			objects: []runtime.Object{
+				newNamespace("secure"),
+				newNamespace("insecure"),
+				newNamespace("partial"),
+				newNetworkPolicy("secure", "default-deny", nil),
+				newNetworkPolicy("partial", "allow-web", map[string]string{"app": "web"}),
+			},
+			expectReports: map[string]bool{
+				"secure":   true,
+				"insecure": false,
+				"partial":  false,
+			},
+		},
+		{
+			name: "pods with their service accounts",
+			inputs: []compliance.RegoInput{
+				{
+					ResourceCommon: compliance.ResourceCommon{
+						KubeCollection: &compliance.KubernetesCollection{
+							Kind: "pods",
+						},
+					},
+					TagName: "pods",
+				},
+				{
+					ResourceCommon: compliance.ResourceCommon{
+						KubeCollection: &compliance.KubernetesCollection{
+							Kind: "serviceaccounts",
+						},
+					},
+					TagName: "serviceaccounts",
+				},
+			},
+			module: `
+				package test
+
+				import data.datadog as dd
+
+				automount(pod) {
+					sa := dd.kube_pod_service_account(pod, input.serviceaccounts)
+					sa.resource.Object.automountServiceAccountToken
+				}
+
+				findings[f] {
+					pod := input.pods[_]
+					not automount(pod)
+					f := dd.passed_finding("kube_pod", pod.name, {})
+				}
+
+				findings[f] {
+					pod := input.pods[_]
+					automount(pod)
+					f := dd.failing_finding("kube_pod", pod.name, {})
+				}
+			`,
+			objects: []runtime.Object{
+				newPod("ns1", "web", "web"),
+				newPod("ns1", "worker", ""),
+				newPod("ns2", "db", "web"),
+				newServiceAccount("ns1", "web", false),
+				newServiceAccount("ns1", "default", true),
+				newServiceAccount("ns2", "web", true),
+			},
+			expectReports: map[string]bool{
+				"web":    true,
+				"worker": false,
+				"db":     false,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.run(t)
+		})
+	}
+}
+
+func TestKubeClientListObjects(t *testing.T) {
+	assert := assert.New(t)
+
+	dynamicClient := fake.NewSimpleDynamicClient(scheme,
+		newPod("ns1", "web", ""),
+		newPod("ns1", "worker", ""),
+		newPod("ns2", "web", ""),
+	)
+
+	b := &builder{}
+	assert.Error(WithKubernetesInformers(dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0))(b))
+	assert.NoError(WithKubernetesClient(dynamicClient, "")(b))
+	assert.NoError(WithKubernetesInformers(dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0))(b))
+	defer b.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+	objects, err := b.kubeClient.ListObjects(ctx, pods, "", labels.Everything())
+	assert.NoError(err)
+	assert.Len(objects, 3)
+
+	objects, err = b.kubeClient.ListObjects(ctx, pods, "ns1", labels.Everything())
+	assert.NoError(err)
+	assert.Len(objects, 2)
+
+	objects, err = b.kubeClient.ListObjects(ctx, pods, "", labels.SelectorFromSet(labels.Set{"app": "web"}))
+	assert.NoError(err)
+	assert.Len(objects, 2)
+}
@@ -0,0 +1,253 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb"
+)
+
+const (
+	packageManagerDpkg = "dpkg"
+	packageManagerRpm  = "rpm"
+
+	dpkgStatusPath  = "/var/lib/dpkg/status"
+	rpmPackagesPath = "/var/lib/rpm/Packages"
+	rpmSqliteDBPath = "/var/lib/rpm/rpmdb.sqlite"
+)
+
+var packageReportedFields = []string{
+	compliance.PackageFieldName,
+	compliance.PackageFieldInstalled,
+	compliance.PackageFieldVersion,
+	compliance.PackageFieldArch,
+	compliance.PackageFieldManager,
+}
+
+// ErrPackageDatabaseNotFound is returned when neither a dpkg nor an rpm database can be found
+var ErrPackageDatabaseNotFound = errors.New("package database not found")
+
+type packageInfo struct {
+	name    string
+	version string
+	arch    string
+}
+
+func resolvePackage(_ context.Context, e env.Env, ruleID string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Package == nil {
+		return nil, fmt.Errorf("%s: expecting package resource in package check", ruleID)
+	}
+
+	pkg := res.Package
+	if err := pkg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ruleID, err)
+	}
+
+	manager, packages, err := loadPackageDatabase(e)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ruleID, err)
+	}
+
+	log.Debugf("%s: looking for package %s in the %s database (%d packages)", ruleID, pkg.Name, manager, len(packages))
+
+	// a missing package is still reported so that rules can check that a package is not installed
+	info, installed := packages[pkg.Name]
+	if !installed {
+		info = &packageInfo{name: pkg.Name}
+	}
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.PackageFieldName:      info.name,
+			compliance.PackageFieldInstalled: installed,
+			compliance.PackageFieldVersion:   info.version,
+			compliance.PackageFieldArch:      info.arch,
+			compliance.PackageFieldManager:   manager,
+		},
+		nil,
+		eval.RegoInputMap{
+			"name":      info.name,
+			"installed": installed,
+			"version":   info.version,
+			"arch":      info.arch,
+			"manager":   manager,
+		},
+	)
+
+	return newResolvedInstance(instance, pkg.Name, "package"), nil
+}
+
+type packageDatabase struct {
+	modTime  time.Time
+	size     int64
+	packages map[string]*packageInfo
+}
+
+// packageDatabases caches the parsed package databases, they are parsed again once modified
+var packageDatabases = struct {
+	sync.Mutex
+	entries map[string]*packageDatabase
+}{
+	entries: make(map[string]*packageDatabase),
+}
+
+// loadPackageDatabase returns the name of the package manager of the host and its installed packages
+func loadPackageDatabase(e env.Env) (string, map[string]*packageInfo, error) {
+	if path := e.NormalizeToHostRoot(dpkgStatusPath); fileExists(path) {
+		packages, err := loadCachedPackageDatabase(path, parsePackageFile(parseDpkgStatus))
+		return packageManagerDpkg, packages, err
+	}
+
+	if path := e.NormalizeToHostRoot(rpmPackagesPath); fileExists(path) {
+		packages, err := loadCachedPackageDatabase(path, parsePackageFile(parseRpmDatabase(rpmdb.ParseBerkeleyDB)))
+		return packageManagerRpm, packages, err
+	}
+
+	// the sqlite backend is used since rpm 4.16
+	if path := e.NormalizeToHostRoot(rpmSqliteDBPath); fileExists(path) {
+		packages, err := loadCachedPackageDatabase(path, parsePackageFile(parseRpmDatabase(rpmdb.ParseSqlite)))
+		return packageManagerRpm, packages, err
+	}
+
+	return "", nil, ErrPackageDatabaseNotFound
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func loadCachedPackageDatabase(path string, load func(string) (map[string]*packageInfo, error)) (map[string]*packageInfo, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	packageDatabases.Lock()
+	defer packageDatabases.Unlock()
+
+	if db, found := packageDatabases.entries[path]; found && db.modTime.Equal(fi.ModTime()) && db.size == fi.Size() {
+		return db.packages, nil
+	}
+
+	packages, err := load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package database %s: %w", path, err)
+	}
+
+	packageDatabases.entries[path] = &packageDatabase{
+		modTime:  fi.ModTime(),
+		size:     fi.Size(),
+		packages: packages,
+	}
+	return packages, nil
+}
+
+// parsePackageFile returns a function parsing the package database stored at the given path
+func parsePackageFile(parse func(io.Reader) (map[string]*packageInfo, error)) func(string) (map[string]*packageInfo, error) {
+	return func(path string) (map[string]*packageInfo, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return parse(f)
+	}
+}
+
+// parseDpkgStatus parses the status file of dpkg, only the installed packages are returned
+func parseDpkgStatus(r io.Reader) (map[string]*packageInfo, error) {
+	packages := make(map[string]*packageInfo)
+
+	var current packageInfo
+	var installed bool
+
+	flush := func() {
+		if installed && current.name != "" {
+			if _, exists := packages[current.name]; !exists {
+				info := current
+				packages[current.name] = &info
+			}
+		}
+		current, installed = packageInfo{}, false
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			flush()
+			continue
+		}
+
+		// continuation lines of multi-line fields
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		parts := strings.SplitN(string(line), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(parts[1])
+
+		switch parts[0] {
+		case "Package":
+			current.name = value
+		case "Version":
+			current.version = value
+		case "Architecture":
+			current.arch = value
+		case "Status":
+			// the status is made of the wanted, error and current states, such as "install ok installed"
+			states := strings.Fields(value)
+			installed = len(states) == 3 && states[2] == "installed"
+		}
+	}
+	flush()
+
+	return packages, scanner.Err()
+}
+
+// parseRpmDatabase returns a function parsing a rpm database with the given parser, the version of the packages is
+// formatted as [epoch:]version-release
+func parseRpmDatabase(parse func([]byte) ([]rpmdb.Package, error)) func(io.Reader) (map[string]*packageInfo, error) {
+	return func(r io.Reader) (map[string]*packageInfo, error) {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		rpmPackages, err := parse(content)
+		if err != nil {
+			return nil, err
+		}
+
+		packages := make(map[string]*packageInfo, len(rpmPackages))
+		for _, pkg := range rpmPackages {
+			if _, exists := packages[pkg.Name]; !exists {
+				packages[pkg.Name] = &packageInfo{name: pkg.Name, version: pkg.FullVersion(), arch: pkg.Arch}
+			}
+		}
+		return packages, nil
+	}
+}
@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb"
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb/testutil"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseDpkgStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := os.Open("./testdata/package/dpkg-status")
+	assert.NoError(err)
+	defer f.Close()
+
+	packages, err := parseDpkgStatus(f)
+	assert.NoError(err)
+	assert.Equal(map[string]*packageInfo{
+		"openssh-server": {name: "openssh-server", version: "1:8.9p1-3ubuntu0.1", arch: "amd64"},
+		"sudo":           {name: "sudo", version: "1.9.9-1ubuntu2.1", arch: "amd64"},
+	}, packages)
+}
+
+func TestParseRpmDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	db := testutil.BerkeleyDB(
+		testutil.Header("openssh-server", "7.4p1", "22.el7_9", "x86_64", 0),
+		testutil.Header("openssl", "1.0.2k", "25.el7_9", "x86_64", 1),
+		testutil.Header("kernel", "3.10.0", "1160.el7", "x86_64", 0),
+		testutil.Header("kernel", "3.10.0", "1160.76.1.el7", "x86_64", 0),
+	)
+
+	packages, err := parseRpmDatabase(rpmdb.ParseBerkeleyDB)(bytes.NewReader(db))
+	assert.NoError(err)
+	assert.Equal(map[string]*packageInfo{
+		"openssh-server": {name: "openssh-server", version: "7.4p1-22.el7_9", arch: "x86_64"},
+		"openssl":        {name: "openssl", version: "1:1.0.2k-25.el7_9", arch: "x86_64"},
+		"kernel":         {name: "kernel", version: "3.10.0-1160.el7", arch: "x86_64"},
+	}, packages)
+
+	_, err = parseRpmDatabase(rpmdb.ParseSqlite)(bytes.NewReader(db))
+	assert.Error(err)
+}
+
+func TestPackageCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		pkg       string
+		condition string
+
+		expectReport *compliance.Report
+	}{
+		{
+			name:      "package installed",
+			pkg:       "sudo",
+			condition: `package.installed && package.version == "1.9.9-1ubuntu2.1"`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "sudo",
+					"package.installed": true,
+					"package.version":   "1.9.9-1ubuntu2.1",
+					"package.arch":      "amd64",
+					"package.manager":   "dpkg",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "sudo",
+					Type: "package",
+				},
+			},
+		},
+		{
+			name:      "package removed",
+			pkg:       "telnet",
+			condition: `!package.installed`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"package.name":      "telnet",
+					"package.installed": false,
+					"package.version":   "",
+					"package.arch":      "",
+					"package.manager":   "dpkg",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "telnet",
+					Type: "package",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", dpkgStatusPath).Return("./testdata/package/dpkg-status")
+
+			resource := compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Package: &compliance.Package{
+						Name: test.pkg,
+					},
+				},
+				Condition: test.condition,
+			}
+
+			packageCheck, err := newResourceCheck(env, "rule-id", resource)
+			assert.NoError(err)
+
+			reports := packageCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
+
+func TestPackageCheckRpm(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	rpmPath := filepath.Join(dir, "Packages")
+	assert.NoError(os.WriteFile(rpmPath, testutil.BerkeleyDB(testutil.Header("sudo", "1.8.23", "10.el7_9.2", "x86_64", 0)), 0644))
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", dpkgStatusPath).Return(filepath.Join(dir, "status"))
+	env.On("NormalizeToHostRoot", rpmPackagesPath).Return(rpmPath)
+
+	manager, packages, err := loadPackageDatabase(env)
+	assert.NoError(err)
+	assert.Equal(packageManagerRpm, manager)
+	assert.Contains(packages, "sudo")
+
+	env = &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(filepath.Join(dir, "missing"))
+
+	_, _, err = loadPackageDatabase(env)
+	assert.Equal(ErrPackageDatabaseNotFound, err)
+}
+
+func TestPackageCheckRpmSqlite(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	rpmPath := filepath.Join(dir, "rpmdb.sqlite")
+	assert.NoError(os.WriteFile(rpmPath, testutil.Sqlite(testutil.Header("sudo", "1.9.5p2", "7.el9", "x86_64", 0)), 0644))
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", dpkgStatusPath).Return(filepath.Join(dir, "status"))
+	env.On("NormalizeToHostRoot", rpmPackagesPath).Return(filepath.Join(dir, "Packages"))
+	env.On("NormalizeToHostRoot", rpmSqliteDBPath).Return(rpmPath)
+
+	manager, packages, err := loadPackageDatabase(env)
+	assert.NoError(err)
+	assert.Equal(packageManagerRpm, manager)
+	assert.Equal(&packageInfo{name: "sudo", version: "1.9.5p2-7.el9", arch: "x86_64"}, packages["sudo"])
+}
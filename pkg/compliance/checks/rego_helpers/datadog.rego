@@ -22,3 +22,9 @@ error_finding(resource_type, resource_id, error_msg) = f {
 		"error": error_msg
 	})
 }
+
+kube_pod_service_account(pod, service_accounts) = sa {
+	sa := service_accounts[_]
+	sa.namespace == pod.namespace
+	sa.name == object.get(pod.resource.Object.spec, "serviceAccountName", "default")
+}
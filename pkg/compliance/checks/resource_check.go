@@ -198,6 +198,10 @@ func resourceKindToResolverAndFields(env env.Env, ruleID string, kind compliance
 		return resolveCommand, commandReportedFields, nil
 	case compliance.KindProcess:
 		return resolveProcess, processReportedFields, nil
+	case compliance.KindPackage:
+		return resolvePackage, packageReportedFields, nil
+	case compliance.KindSysctl:
+		return resolveSysctl, sysctlReportedFields, nil
 	case compliance.KindDocker:
 		if env.DockerClient() == nil {
 			return nil, nil, log.Errorf("%s: docker client not initialized", ruleID)
@@ -208,6 +212,11 @@ func resourceKindToResolverAndFields(env env.Env, ruleID string, kind compliance
 			return nil, nil, log.Errorf("%s: kube client not initialized", ruleID)
 		}
 		return resolveKubeapiserver, kubeResourceReportedFields, nil
+	case compliance.KindKubernetesCollection:
+		if env.KubeClient() == nil {
+			return nil, nil, log.Errorf("%s: kube client not initialized", ruleID)
+		}
+		return resolveKubeCollection, kubeResourceReportedFields, nil
 	case compliance.KindConstants:
 		return resolveConstants, nil, nil
 	default:
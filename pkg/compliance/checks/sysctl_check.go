@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/checks/env"
+	"github.com/DataDog/datadog-agent/pkg/compliance/eval"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const procSysPath = "/proc/sys"
+
+var sysctlReportedFields = []string{
+	compliance.SysctlFieldName,
+	compliance.SysctlFieldValue,
+}
+
+// sysctlPath returns the path of a kernel parameter in /proc/sys, dots are used as separator in the name of the
+// parameter and slashes stand for dots, as done by sysctl(8). Names that would resolve outside of /proc/sys are rejected.
+func sysctlPath(name string) (string, error) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, "/", ".")
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid kernel parameter name %q", name)
+		}
+		parts[i] = part
+	}
+	return filepath.Join(append([]string{procSysPath}, parts...)...), nil
+}
+
+func resolveSysctl(_ context.Context, e env.Env, ruleID string, res compliance.ResourceCommon, rego bool) (resolved, error) {
+	if res.Sysctl == nil {
+		return nil, fmt.Errorf("%s: expecting sysctl resource in sysctl check", ruleID)
+	}
+
+	sysctl := res.Sysctl
+	if err := sysctl.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", ruleID, err)
+	}
+
+	path, err := sysctlPath(sysctl.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ruleID, err)
+	}
+	path = e.NormalizeToHostRoot(path)
+
+	log.Debugf("%s: reading kernel parameter %s from %s", ruleID, sysctl.Name, path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read kernel parameter %s: %w", ruleID, sysctl.Name, err)
+	}
+
+	// multi-valued parameters are separated by tabs, sysctl(8) displays them separated by spaces
+	value := strings.Join(strings.Fields(string(content)), " ")
+
+	instance := eval.NewInstance(
+		eval.VarMap{
+			compliance.SysctlFieldName:  sysctl.Name,
+			compliance.SysctlFieldValue: value,
+		},
+		nil,
+		eval.RegoInputMap{
+			"name":  sysctl.Name,
+			"value": value,
+		},
+	)
+
+	return newResolvedInstance(instance, sysctl.Name, "sysctl"), nil
+}
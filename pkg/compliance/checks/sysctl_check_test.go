@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/compliance/mocks"
+
+	"github.com/stretchr/testify/mock"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSysctlPath(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		expectError bool
+	}{
+		{name: "net.ipv4.ip_forward", path: "/proc/sys/net/ipv4/ip_forward"},
+		{name: "net.ipv4.conf.eth0/100.rp_filter", path: "/proc/sys/net/ipv4/conf/eth0.100/rp_filter"},
+		{name: "kernel.//.//.etc.shadow", expectError: true},
+		{name: "kernel.//", expectError: true},
+		{name: "kernel./", expectError: true},
+		{name: "net..ipv4", expectError: true},
+		{name: ".kernel", expectError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path, err := sysctlPath(test.name)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.path, path)
+			}
+		})
+	}
+}
+
+func TestSysctlCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		sysctl    string
+		condition string
+
+		expectReport *compliance.Report
+	}{
+		{
+			name:      "kernel parameter passed",
+			sysctl:    "kernel.randomize_va_space",
+			condition: `sysctl.value == "2"`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"sysctl.name":  "kernel.randomize_va_space",
+					"sysctl.value": "2",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "kernel.randomize_va_space",
+					Type: "sysctl",
+				},
+			},
+		},
+		{
+			name:      "kernel parameter failed",
+			sysctl:    "net.ipv4.ip_forward",
+			condition: `sysctl.value == "0"`,
+			expectReport: &compliance.Report{
+				Passed: false,
+				Data: event.Data{
+					"sysctl.name":  "net.ipv4.ip_forward",
+					"sysctl.value": "1",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "net.ipv4.ip_forward",
+					Type: "sysctl",
+				},
+			},
+		},
+		{
+			name:      "multi-valued kernel parameter",
+			sysctl:    "net.ipv4.tcp_rmem",
+			condition: `sysctl.value == "4096 87380 6291456"`,
+			expectReport: &compliance.Report{
+				Passed: true,
+				Data: event.Data{
+					"sysctl.name":  "net.ipv4.tcp_rmem",
+					"sysctl.value": "4096 87380 6291456",
+				},
+				Resource: compliance.ReportResource{
+					ID:   "net.ipv4.tcp_rmem",
+					Type: "sysctl",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			env := &mocks.Env{}
+			env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(func(path string) string {
+				return filepath.Join("./testdata/sysctl", path)
+			})
+
+			resource := compliance.Resource{
+				ResourceCommon: compliance.ResourceCommon{
+					Sysctl: &compliance.Sysctl{
+						Name: test.sysctl,
+					},
+				},
+				Condition: test.condition,
+			}
+
+			sysctlCheck, err := newResourceCheck(env, "rule-id", resource)
+			assert.NoError(err)
+
+			reports := sysctlCheck.check(env)
+			assert.Equal(test.expectReport, reports[0])
+		})
+	}
+}
+
+func TestSysctlCheckMissingParameter(t *testing.T) {
+	assert := assert.New(t)
+
+	env := &mocks.Env{}
+	env.On("NormalizeToHostRoot", mock.AnythingOfType("string")).Return(func(path string) string {
+		return filepath.Join("./testdata/sysctl", path)
+	})
+
+	resource := compliance.Resource{
+		ResourceCommon: compliance.ResourceCommon{
+			Sysctl: &compliance.Sysctl{
+				Name: "net.ipv6.conf.all.disable_ipv6",
+			},
+		},
+		Condition: `sysctl.value == "1"`,
+	}
+
+	sysctlCheck, err := newResourceCheck(env, "rule-id", resource)
+	assert.NoError(err)
+
+	reports := sysctlCheck.check(env)
+	assert.Len(reports, 1)
+	assert.Error(reports[0].Error)
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package export builds reports of compliance findings in machine readable formats
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Format defines the format of an exported report
+type Format string
+
+const (
+	// JSON format
+	JSON Format = "json"
+	// SARIF format, version 2.1.0
+	SARIF Format = "sarif"
+)
+
+// ParseFormat parses the name of a report format
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(s); format {
+	case JSON, SARIF:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown report format '%s', expecting one of: %s, %s", s, JSON, SARIF)
+	}
+}
+
+// Rule holds the metadata of a rule that produced findings
+type Rule struct {
+	ID            string                  `json:"id"`
+	FrameworkID   string                  `json:"framework_id,omitempty"`
+	SuiteName     string                  `json:"suite_name,omitempty"`
+	SuiteVersion  string                  `json:"suite_version,omitempty"`
+	Description   string                  `json:"description,omitempty"`
+	Severity      compliance.RuleSeverity `json:"severity"`
+	Remediation   string                  `json:"remediation,omitempty"`
+	findingsCount int
+}
+
+// Finding describes the result of a rule for a resource
+type Finding struct {
+	Rule         *Rule       `json:"rule"`
+	Result       string      `json:"result"`
+	ResourceType string      `json:"resource_type,omitempty"`
+	ResourceID   string      `json:"resource_id,omitempty"`
+	Evidence     interface{} `json:"evidence,omitempty"`
+	Evaluator    string      `json:"evaluator,omitempty"`
+}
+
+// Summary holds the count of findings per result
+type Summary struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	Error  int `json:"error"`
+}
+
+// Report collects the findings of a compliance run
+type Report struct {
+	AgentVersion string     `json:"agent_version,omitempty"`
+	Summary      Summary    `json:"summary"`
+	Findings     []*Finding `json:"findings"`
+
+	rules map[string]*Rule
+	// ruleOrder holds the keys of the rules, in the order of their first finding
+	ruleOrder []string
+}
+
+// NewReport returns a new empty report
+func NewReport() *Report {
+	return &Report{
+		Findings: []*Finding{},
+		rules:    make(map[string]*Rule),
+	}
+}
+
+func ruleKey(frameworkID, ruleID string) string {
+	return frameworkID + "/" + ruleID
+}
+
+// AddSuite registers the metadata of the rules of a compliance suite, before adding the events of these rules
+func (r *Report) AddSuite(suite *compliance.Suite) {
+	for _, rule := range suite.RuleCommons() {
+		r.rules[ruleKey(suite.Meta.Framework, rule.ID)] = &Rule{
+			ID:           rule.ID,
+			FrameworkID:  suite.Meta.Framework,
+			SuiteName:    suite.Meta.Name,
+			SuiteVersion: suite.Meta.Version,
+			Description:  rule.Description,
+			Severity:     rule.Severity.OrDefault(),
+			Remediation:  rule.Remediation,
+		}
+	}
+}
+
+// LoadSuites registers the metadata of the rules of the provided compliance suite files, it has to be called
+// before adding the events of these rules
+func (r *Report) LoadSuites(files ...string) {
+	for _, file := range files {
+		suite, err := compliance.ParseSuite(file)
+		if err != nil {
+			log.Warnf("Failed to load rules metadata from %s: %v", file, err)
+			continue
+		}
+		r.AddSuite(suite)
+	}
+}
+
+// AddEvent adds the finding of a rule event to the report
+func (r *Report) AddEvent(e *event.Event) {
+	if r.AgentVersion == "" {
+		r.AgentVersion = e.AgentVersion
+	}
+
+	key := ruleKey(e.AgentFrameworkID, e.AgentRuleID)
+	rule, found := r.rules[key]
+	if !found {
+		rule = &Rule{
+			ID:          e.AgentRuleID,
+			FrameworkID: e.AgentFrameworkID,
+			Severity:    compliance.DefaultSeverity,
+		}
+		r.rules[key] = rule
+	}
+	if rule.findingsCount == 0 {
+		r.ruleOrder = append(r.ruleOrder, key)
+	}
+	rule.findingsCount++
+
+	switch e.Result {
+	case event.Passed:
+		r.Summary.Passed++
+	case event.Failed:
+		r.Summary.Failed++
+	case event.Error:
+		r.Summary.Error++
+	}
+
+	r.Findings = append(r.Findings, &Finding{
+		Rule:         rule,
+		Result:       e.Result,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		Evidence:     e.Data,
+		Evaluator:    e.Evaluator,
+	})
+}
+
+// FailedAtLeast returns the number of failed findings of a severity greater or equal to the provided one
+func (r *Report) FailedAtLeast(severity compliance.RuleSeverity) int {
+	count := 0
+	for _, finding := range r.Findings {
+		if finding.Result == event.Failed && finding.Rule.Severity.AtLeast(severity) {
+			count++
+		}
+	}
+	return count
+}
+
+// Write writes the report in the provided format
+func (r *Report) Write(w io.Writer, format Format) error {
+	var content interface{} = r
+	if format == SARIF {
+		content = r.toSARIF()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(content)
+}
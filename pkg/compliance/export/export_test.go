@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+func newTestReport() *Report {
+	report := NewReport()
+	report.AddSuite(&compliance.Suite{
+		Meta: compliance.SuiteMeta{
+			Name:      "CIS Docker Generic",
+			Framework: "cis-docker",
+			Version:   "1.2.0",
+		},
+		Rules: []compliance.ConditionFallbackRule{
+			{
+				RuleCommon: compliance.RuleCommon{
+					ID:          "cis-docker-1",
+					Description: "Ensure daemon.json permissions are set to 644",
+					Severity:    compliance.SeverityHigh,
+					Remediation: "chmod 644 /etc/docker/daemon.json",
+				},
+			},
+		},
+		RegoRules: []compliance.RegoRule{
+			{
+				RuleCommon: compliance.RuleCommon{
+					ID:       "cis-docker-2",
+					Severity: compliance.SeverityLow,
+				},
+			},
+		},
+	})
+
+	report.AddEvent(&event.Event{
+		AgentRuleID:      "cis-docker-1",
+		AgentFrameworkID: "cis-docker",
+		AgentVersion:     "7.40.0",
+		Result:           event.Failed,
+		ResourceType:     "docker_daemon",
+		ResourceID:       "host_daemon",
+		Data:             event.Data{"file.permissions": 0600},
+	})
+	report.AddEvent(&event.Event{
+		AgentRuleID:      "cis-docker-2",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Failed,
+		ResourceType:     "docker_container",
+		ResourceID:       "abc",
+	})
+	report.AddEvent(&event.Event{
+		AgentRuleID:      "cis-docker-2",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Passed,
+		ResourceType:     "docker_container",
+		ResourceID:       "def",
+	})
+	report.AddEvent(&event.Event{
+		AgentRuleID:      "unknown-rule",
+		AgentFrameworkID: "cis-docker",
+		Result:           event.Error,
+		Data:             event.Data{"error": "failed to evaluate"},
+	})
+
+	return report
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("sarif")
+	assert.NoError(t, err)
+	assert.Equal(t, SARIF, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestReportJSON(t *testing.T) {
+	report := newTestReport()
+
+	assert.Equal(t, Summary{Passed: 1, Failed: 2, Error: 1}, report.Summary)
+	assert.Equal(t, 2, report.FailedAtLeast(compliance.SeverityLow))
+	assert.Equal(t, 1, report.FailedAtLeast(compliance.SeverityHigh))
+	assert.Equal(t, 0, report.FailedAtLeast(compliance.SeverityCritical))
+
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(&buffer, JSON))
+
+	var content struct {
+		AgentVersion string `json:"agent_version"`
+		Findings     []struct {
+			Rule struct {
+				ID          string `json:"id"`
+				Severity    string `json:"severity"`
+				Remediation string `json:"remediation"`
+			} `json:"rule"`
+			Result     string                 `json:"result"`
+			ResourceID string                 `json:"resource_id"`
+			Evidence   map[string]interface{} `json:"evidence"`
+		} `json:"findings"`
+	}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &content))
+
+	assert.Equal(t, "7.40.0", content.AgentVersion)
+	assert.Len(t, content.Findings, 4)
+
+	finding := content.Findings[0]
+	assert.Equal(t, "cis-docker-1", finding.Rule.ID)
+	assert.Equal(t, "high", finding.Rule.Severity)
+	assert.Equal(t, "chmod 644 /etc/docker/daemon.json", finding.Rule.Remediation)
+	assert.Equal(t, "failed", finding.Result)
+	assert.Equal(t, "host_daemon", finding.ResourceID)
+	assert.Equal(t, float64(0600), finding.Evidence["file.permissions"])
+
+	// rules missing from the loaded suites get the default severity
+	assert.Equal(t, "medium", content.Findings[3].Rule.Severity)
+}
+
+func TestReportSARIF(t *testing.T) {
+	report := newTestReport()
+
+	var buffer bytes.Buffer
+	assert.NoError(t, report.Write(&buffer, SARIF))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	if !assert.Len(t, log.Runs, 1) {
+		return
+	}
+	run := log.Runs[0]
+
+	assert.Equal(t, "7.40.0", run.Tool.Driver.Version)
+	if assert.Len(t, run.Tool.Driver.Rules, 3) {
+		rule := run.Tool.Driver.Rules[0]
+		assert.Equal(t, "cis-docker-1", rule.ID)
+		assert.Equal(t, "error", rule.DefaultConfiguration.Level)
+		assert.Equal(t, "chmod 644 /etc/docker/daemon.json", rule.Help.Text)
+		assert.Equal(t, "note", run.Tool.Driver.Rules[1].DefaultConfiguration.Level)
+	}
+
+	expected := []struct {
+		ruleIndex int
+		kind      string
+		level     string
+	}{
+		{0, "fail", "error"},
+		{1, "fail", "note"},
+		{1, "pass", "none"},
+		{2, "review", "none"},
+	}
+	if assert.Len(t, run.Results, len(expected)) {
+		for i, result := range run.Results {
+			assert.Equal(t, expected[i].ruleIndex, result.RuleIndex)
+			assert.Equal(t, expected[i].kind, result.Kind)
+			assert.Equal(t, expected[i].level, result.Level)
+		}
+	}
+
+	assert.Equal(t, "host_daemon", run.Results[0].Locations[0].LogicalLocations[0].Name)
+	assert.Empty(t, run.Results[3].Locations)
+}
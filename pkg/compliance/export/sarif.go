@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package export
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/compliance"
+	"github.com/DataDog/datadog-agent/pkg/compliance/event"
+)
+
+const (
+	sarifSchema    = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion   = "2.1.0"
+	sarifToolName  = "datadog-security-agent"
+	sarifToolURI   = "https://docs.datadoghq.com/security_platform/cspm/"
+	sarifLevelNone = "none"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     *sarifMessage          `json:"shortDescription,omitempty"`
+	Help                 *sarifMessage          `json:"help,omitempty"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Kind       string                 `json:"kind"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// sarifLevel returns the SARIF level of a failing finding for a rule severity
+func sarifLevel(severity compliance.RuleSeverity) string {
+	switch {
+	case severity.AtLeast(compliance.SeverityHigh):
+		return "error"
+	case severity.AtLeast(compliance.SeverityMedium):
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifKindAndLevel returns the SARIF kind and level of a finding, only failing findings get a level
+func sarifKindAndLevel(finding *Finding) (string, string) {
+	switch finding.Result {
+	case event.Passed:
+		return "pass", sarifLevelNone
+	case event.Failed:
+		return "fail", sarifLevel(finding.Rule.Severity)
+	default:
+		// the rule could not be evaluated, the resource has to be reviewed
+		return "review", sarifLevelNone
+	}
+}
+
+func (r *Report) toSARIF() *sarifLog {
+	driver := sarifDriver{
+		Name:           sarifToolName,
+		Version:        r.AgentVersion,
+		InformationURI: sarifToolURI,
+		Rules:          []sarifRule{},
+	}
+
+	ruleIndexes := make(map[*Rule]int)
+	for _, key := range r.ruleOrder {
+		rule := r.rules[key]
+		ruleIndexes[rule] = len(driver.Rules)
+
+		sr := sarifRule{
+			ID: rule.ID,
+			DefaultConfiguration: sarifRuleConfiguration{
+				Level: sarifLevel(rule.Severity),
+			},
+			Properties: map[string]interface{}{
+				"severity": rule.Severity,
+			},
+		}
+		if rule.Description != "" {
+			sr.ShortDescription = &sarifMessage{Text: rule.Description}
+		}
+		if rule.Remediation != "" {
+			sr.Help = &sarifMessage{Text: rule.Remediation}
+		}
+		if rule.FrameworkID != "" {
+			sr.Properties["tags"] = []string{rule.FrameworkID}
+			sr.Properties["framework_id"] = rule.FrameworkID
+		}
+		if rule.SuiteVersion != "" {
+			sr.Properties["suite_version"] = rule.SuiteVersion
+		}
+		driver.Rules = append(driver.Rules, sr)
+	}
+
+	results := make([]sarifResult, 0, len(r.Findings))
+	for _, finding := range r.Findings {
+		kind, level := sarifKindAndLevel(finding)
+
+		result := sarifResult{
+			RuleID:    finding.Rule.ID,
+			RuleIndex: ruleIndexes[finding.Rule],
+			Kind:      kind,
+			Level:     level,
+			Message: sarifMessage{
+				Text: fmt.Sprintf("%s: %s %s [%s]", finding.Rule.ID, finding.ResourceType, finding.ResourceID, finding.Result),
+			},
+		}
+		if finding.ResourceID != "" {
+			result.Locations = []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name: finding.ResourceID,
+					Kind: finding.ResourceType,
+				}},
+			}}
+		}
+		if finding.Evidence != nil {
+			result.Properties = map[string]interface{}{
+				"evidence": finding.Evidence,
+			}
+		}
+		results = append(results, result)
+	}
+
+	return &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}
+}
@@ -30,6 +30,12 @@ const (
 	KindAudit = ResourceKind("audit")
 	// KindKubernetes is used for a KubernetesResource
 	KindKubernetes = ResourceKind("kubernetes")
+	// KindKubernetesCollection is used for a KubernetesCollection
+	KindKubernetesCollection = ResourceKind("kubernetesCollection")
+	// KindPackage is used for a Package resource
+	KindPackage = ResourceKind("package")
+	// KindSysctl is used for a Sysctl resource
+	KindSysctl = ResourceKind("sysctl")
 	// KindConstants is used for Constants check
 	KindConstants = ResourceKind("constants")
 	// KindCustom is used for a Custom check
@@ -38,15 +44,18 @@ const (
 
 // ResourceCommon describes the base fields of resource types
 type ResourceCommon struct {
-	File          *File               `yaml:"file,omitempty"`
-	Process       *Process            `yaml:"process,omitempty"`
-	Group         *Group              `yaml:"group,omitempty"`
-	Command       *Command            `yaml:"command,omitempty"`
-	Audit         *Audit              `yaml:"audit,omitempty"`
-	Docker        *DockerResource     `yaml:"docker,omitempty"`
-	KubeApiserver *KubernetesResource `yaml:"kubeApiserver,omitempty"`
-	Constants     *ConstantsResource  `yaml:"constants,omitempty"`
-	Custom        *Custom             `yaml:"custom,omitempty"`
+	File           *File                 `yaml:"file,omitempty"`
+	Process        *Process              `yaml:"process,omitempty"`
+	Group          *Group                `yaml:"group,omitempty"`
+	Command        *Command              `yaml:"command,omitempty"`
+	Audit          *Audit                `yaml:"audit,omitempty"`
+	Docker         *DockerResource       `yaml:"docker,omitempty"`
+	KubeApiserver  *KubernetesResource   `yaml:"kubeApiserver,omitempty"`
+	KubeCollection *KubernetesCollection `yaml:"kubeCollection,omitempty"`
+	Package        *Package              `yaml:"package,omitempty"`
+	Sysctl         *Sysctl               `yaml:"sysctl,omitempty"`
+	Constants      *ConstantsResource    `yaml:"constants,omitempty"`
+	Custom         *Custom               `yaml:"custom,omitempty"`
 }
 
 // Resource describes supported resource types observed by a Rule
@@ -69,6 +78,10 @@ func (i *RegoInput) ValidateInputType() (string, error) {
 	case "object", "array":
 		return i.Type, nil
 	case "":
+		// a collection is always exported as an array
+		if i.Kind() == KindKubernetesCollection {
+			return "array", nil
+		}
 		return "object", nil
 	default:
 		return "", fmt.Errorf("invalid input type `%s`", i.Type)
@@ -92,6 +105,12 @@ func (r *ResourceCommon) Kind() ResourceKind {
 		return KindDocker
 	case r.KubeApiserver != nil:
 		return KindKubernetes
+	case r.KubeCollection != nil:
+		return KindKubernetesCollection
+	case r.Package != nil:
+		return KindPackage
+	case r.Sysctl != nil:
+		return KindSysctl
 	case r.Constants != nil:
 		return KindConstants
 	case r.Custom != nil:
@@ -183,6 +202,24 @@ type KubernetesAPIRequest struct {
 	ResourceName string `yaml:"resourceName,omitempty"`
 }
 
+// KubernetesCollection describes all the objects of a kind in Kubernetes (incl. CRDs). When running in the Cluster
+// Agent, the objects are listed from the cache of an informer.
+type KubernetesCollection struct {
+	Kind      string `yaml:"kind"`
+	Version   string `yaml:"version,omitempty"`
+	Group     string `yaml:"group,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// A selector to restrict the list of returned objects by their labels.
+	// Defaults to everything.
+	LabelSelector string `yaml:"labelSelector,omitempty"`
+}
+
+// String returns human-friendly information string about the KubernetesCollection
+func (kc *KubernetesCollection) String() string {
+	return fmt.Sprintf("%s/%s - Kind: %s - Namespace: %s - Selector: %s", kc.Group, kc.Version, kc.Kind, kc.Namespace, kc.LabelSelector)
+}
+
 // Fields & functions available for Group
 const (
 	GroupFieldName  = "group.name"
@@ -195,6 +232,47 @@ type Group struct {
 	Name string `yaml:"name"`
 }
 
+// Fields & functions available for Package
+const (
+	PackageFieldName      = "package.name"
+	PackageFieldInstalled = "package.installed"
+	PackageFieldVersion   = "package.version"
+	PackageFieldArch      = "package.arch"
+	PackageFieldManager   = "package.manager"
+)
+
+// Package describes a package resource, read from the dpkg or rpm database of the host
+type Package struct {
+	Name string `yaml:"name"`
+}
+
+// Validate validates package resource
+func (p *Package) Validate() error {
+	if len(p.Name) == 0 {
+		return errors.New("package resource is missing name")
+	}
+	return nil
+}
+
+// Fields & functions available for Sysctl
+const (
+	SysctlFieldName  = "sysctl.name"
+	SysctlFieldValue = "sysctl.value"
+)
+
+// Sysctl describes a kernel parameter resource, read from /proc/sys
+type Sysctl struct {
+	Name string `yaml:"name"`
+}
+
+// Validate validates sysctl resource
+func (s *Sysctl) Validate() error {
+	if len(s.Name) == 0 {
+		return errors.New("sysctl resource is missing name")
+	}
+	return nil
+}
+
 // BinaryCmd describes a command in form of a name + args
 type BinaryCmd struct {
 	Name string   `yaml:"name"`
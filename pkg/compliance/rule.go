@@ -6,7 +6,10 @@
 // Package compliance defines common interfaces and types for Compliance Agent
 package compliance
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Rule defines an interface for rego and condition-fallback rules
 type Rule interface {
@@ -20,6 +23,8 @@ type RuleCommon struct {
 	Description  string        `yaml:"description,omitempty"`
 	Scope        RuleScopeList `yaml:"scope,omitempty"`
 	HostSelector string        `yaml:"hostSelector,omitempty"`
+	Severity     RuleSeverity  `yaml:"severity,omitempty"`
+	Remediation  string        `yaml:"remediation,omitempty"`
 }
 
 // ConditionFallbackRule defines a rule in a compliance config
@@ -87,3 +92,52 @@ func (l RuleScopeList) Includes(ruleScope RuleScope) bool {
 func CheckName(ruleID string, description string) string {
 	return fmt.Sprintf("%s: %s", ruleID, description)
 }
+
+// RuleSeverity defines the severity of a failing rule
+type RuleSeverity string
+
+const (
+	// SeverityInfo const
+	SeverityInfo RuleSeverity = "info"
+	// SeverityLow const
+	SeverityLow RuleSeverity = "low"
+	// SeverityMedium const
+	SeverityMedium RuleSeverity = "medium"
+	// SeverityHigh const
+	SeverityHigh RuleSeverity = "high"
+	// SeverityCritical const
+	SeverityCritical RuleSeverity = "critical"
+
+	// DefaultSeverity is the severity of the rules that don't specify one
+	DefaultSeverity = SeverityMedium
+)
+
+var severityLevels = map[RuleSeverity]int{
+	SeverityInfo:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// ParseRuleSeverity parses a severity name, case insensitive
+func ParseRuleSeverity(s string) (RuleSeverity, error) {
+	severity := RuleSeverity(strings.ToLower(s))
+	if _, ok := severityLevels[severity]; !ok {
+		return "", fmt.Errorf("unknown severity '%s'", s)
+	}
+	return severity, nil
+}
+
+// OrDefault returns the severity, or the default severity if it is not set
+func (s RuleSeverity) OrDefault() RuleSeverity {
+	if s == "" {
+		return DefaultSeverity
+	}
+	return s
+}
+
+// AtLeast returns true if the severity is greater or equal to the provided one
+func (s RuleSeverity) AtLeast(other RuleSeverity) bool {
+	return severityLevels[s.OrDefault()] >= severityLevels[other.OrDefault()]
+}
@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package compliance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleSeverity(t *testing.T) {
+	severity, err := ParseRuleSeverity("HIGH")
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityHigh, severity)
+
+	_, err = ParseRuleSeverity("urgent")
+	assert.Error(t, err)
+
+	assert.True(t, SeverityCritical.AtLeast(SeverityHigh))
+	assert.True(t, SeverityHigh.AtLeast(SeverityHigh))
+	assert.False(t, SeverityLow.AtLeast(SeverityHigh))
+
+	// rules without severity are considered of medium severity
+	assert.True(t, RuleSeverity("").AtLeast(SeverityMedium))
+	assert.False(t, RuleSeverity("").AtLeast(SeverityHigh))
+}
@@ -7,6 +7,7 @@ package compliance
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/Masterminds/semver"
@@ -40,6 +41,18 @@ type Suite struct {
 	RegoRules []RegoRule              `yaml:"regos,omitempty"`
 }
 
+// RuleCommons returns the common fields of all the rules of the suite
+func (s *Suite) RuleCommons() []*RuleCommon {
+	rules := make([]*RuleCommon, 0, len(s.Rules)+len(s.RegoRules))
+	for i := range s.Rules {
+		rules = append(rules, s.Rules[i].Common())
+	}
+	for i := range s.RegoRules {
+		rules = append(rules, s.RegoRules[i].Common())
+	}
+	return rules
+}
+
 type yamlSuite struct {
 	Meta  SuiteMeta                `yaml:",inline"`
 	Rules []map[string]interface{} `yaml:"rules,omitempty"`
@@ -70,6 +83,16 @@ func (ys *yamlSuite) toSuite() (*Suite, error) {
 		}
 	}
 
+	for _, rule := range s.RuleCommons() {
+		if rule.Severity != "" {
+			severity, err := ParseRuleSeverity(string(rule.Severity))
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+			}
+			rule.Severity = severity
+		}
+	}
+
 	return s, nil
 }
 
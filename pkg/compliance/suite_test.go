@@ -37,6 +37,8 @@ func TestParseSuite(t *testing.T) {
 							ID:           "cis-docker-1",
 							Scope:        RuleScopeList{DockerScope},
 							HostSelector: `"foo" in node.labels`,
+							Severity:     SeverityHigh,
+							Remediation:  "Set the permissions of /etc/docker/daemon.json to 0644",
 						},
 						Resources: []Resource{
 							{
@@ -66,3 +68,24 @@ func TestParseSuite(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSuiteInvalidSeverity(t *testing.T) {
+	_, err := ParseSuite("./testdata/cis-docker-invalid-severity.yaml")
+	assert.EqualError(t, err, "rule cis-docker-1: unknown severity 'urgent'")
+}
+
+func TestParseSuiteUppercaseSeverity(t *testing.T) {
+	suite, err := ParseSuite("./testdata/cis-docker-uppercase-severity.yaml")
+	assert.NoError(t, err)
+
+	rules := suite.RuleCommons()
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, SeverityHigh, rules[0].Severity)
+		assert.True(t, rules[0].Severity.AtLeast(SeverityHigh))
+		assert.False(t, rules[0].Severity.AtLeast(SeverityCritical))
+
+		assert.Equal(t, SeverityLow, rules[1].Severity)
+		assert.True(t, rules[1].Severity.AtLeast(SeverityLow))
+		assert.False(t, rules[1].Severity.AtLeast(SeverityMedium))
+	}
+}
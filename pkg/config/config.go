@@ -642,12 +642,16 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("network_devices.snmp_traps.bind_host", "0.0.0.0")
 	config.BindEnvAndSetDefault("network_devices.snmp_traps.stop_timeout", 5) // in seconds
 	config.SetKnown("network_devices.snmp_traps.users")
+	config.SetKnown("network_devices.snmp_traps.rules")
 
 	// NetFlow
 	config.SetKnown("network_devices.netflow")
 	config.BindEnvAndSetDefault("network_devices.netflow.enabled", "false")
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.netflow.forwarder.")
 
+	// Network paths traced by system-probe
+	bindEnvAndSetLogsConfigKeys(config, "network_path.forwarder.")
+
 	// Kube ApiServer
 	config.BindEnvAndSetDefault("kubernetes_kubeconfig_path", "")
 	config.BindEnvAndSetDefault("kubernetes_apiserver_ca_path", "")
@@ -975,6 +979,12 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("container_lifecycle.enabled", false)
 	config.BindEnv("container_lifecycle.dd_url")
 	config.BindEnv("container_lifecycle.additional_endpoints")
+	bindEnvAndSetLogsConfigKeys(config, "container_lifecycle.events.")
+
+	// Container images SBOM configuration
+	config.BindEnvAndSetDefault("sbom.enabled", false)
+	config.BindEnvAndSetDefault("sbom.host_root", "")
+	bindEnvAndSetLogsConfigKeys(config, "sbom.")
 
 	// Orchestrator Explorer - process agent
 	// DEPRECATED in favor of `orchestrator_explorer.orchestrator_dd_url` setting. If both are set `orchestrator_explorer.orchestrator_dd_url` will take precedence.
@@ -1050,9 +1060,25 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.activity_dump.cgroup_dump_timeout", 30)
 	config.BindEnvAndSetDefault("runtime_security_config.activity_dump.cgroup_wait_list_size", 10)
 	config.BindEnvAndSetDefault("runtime_security_config.activity_dump.cgroup_output_directory", "")
+	config.BindEnvAndSetDefault("runtime_security_config.security_profile.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.security_profile.dir", "")
+	config.BindEnvAndSetDefault("runtime_security_config.security_profile.selector_tags", []string{"image_name", "service"})
+	config.BindEnvAndSetDefault("runtime_security_config.security_profile.learning_period", 60)
+	config.BindEnvAndSetDefault("runtime_security_config.security_profile.stabilization_period", 10)
+	config.BindEnvAndSetDefault("runtime_security_config.fim_hash.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.fim_hash.paths", []string{})
+	config.BindEnvAndSetDefault("runtime_security_config.fim_hash.max_file_size", 10*1024*1024)
+	config.BindEnvAndSetDefault("runtime_security_config.fim_hash.baseline_scan_period", 1440)
 	config.BindEnvAndSetDefault("runtime_security_config.network.enabled", true)
 	config.BindEnvAndSetDefault("runtime_security_config.network.lazy_interface_prefixes", []string{})
 	config.BindEnvAndSetDefault("runtime_security_config.remote_configuration.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.remote_configuration.policy_public_keys", []string{})
+	config.BindEnvAndSetDefault("runtime_security_config.remote_configuration.rollback_period", 10)
+	config.BindEnvAndSetDefault("runtime_security_config.remote_configuration.rollback_max_dropped_events", 1000)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.dry_run", false)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.rate", 10)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.burst", 20)
+	config.BindEnvAndSetDefault("runtime_security_config.enforcement.protected_processes", []string{})
 
 	// Serverless Agent
 	config.BindEnvAndSetDefault("serverless.logs_enabled", true)
@@ -8,6 +8,8 @@ package remote
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,11 +41,18 @@ type Client struct {
 	currentConfigs client.Configs
 
 	lastPollErr error
+	applyStates map[string]applyState
 
 	apmSamplingUpdates chan []client.ConfigAPMSamling
 	cwsDDUpdates       chan []client.ConfigCWSDD
 }
 
+// applyState holds the version of a config applied by the client
+type applyState struct {
+	version uint64
+	err     error
+}
+
 // NewClient creates a new client
 func NewClient(agentName string, products []data.Product) (*Client, error) {
 	client, err := newClient(agentName, products)
@@ -70,6 +79,7 @@ func newClient(agentName string, products []data.Product, dialOpts ...grpc.DialO
 		close:              close,
 		pollInterval:       1 * time.Second,
 		stateClient:        stateClient,
+		applyStates:        make(map[string]applyState),
 		apmSamplingUpdates: make(chan []client.ConfigAPMSamling, 8),
 		cwsDDUpdates:       make(chan []client.ConfigCWSDD, 8),
 	}, nil
@@ -101,10 +111,11 @@ func (c *Client) poll() error {
 	c.m.Lock()
 	defer c.m.Unlock()
 	state := c.stateClient.State()
-	lastPollErr := ""
+	var clientErrs []string
 	if c.lastPollErr != nil {
-		lastPollErr = c.lastPollErr.Error()
+		clientErrs = append(clientErrs, c.lastPollErr.Error())
 	}
+	clientErrs = append(clientErrs, c.applyErrors()...)
 	token, err := security.FetchAuthToken()
 	if err != nil {
 		return errors.Wrap(err, "could not acquire agent auth token")
@@ -125,8 +136,8 @@ func (c *Client) poll() error {
 				RootVersion:    uint64(state.RootVersion),
 				TargetsVersion: uint64(state.TargetsVersion),
 				ConfigStates:   c.configStates(),
-				HasError:       c.lastPollErr != nil,
-				Error:          lastPollErr,
+				HasError:       len(clientErrs) > 0,
+				Error:          strings.Join(clientErrs, ", "),
 			},
 			Products: c.products,
 		},
@@ -171,12 +182,55 @@ func (c *Client) configStates() []*pbgo.ConfigState {
 		configStates = append(configStates, &pbgo.ConfigState{
 			Product: string(data.ProductCWSDD),
 			Id:      config.ID,
-			Version: config.Version,
+			Version: c.appliedVersion(data.ProductCWSDD, config.ID, config.Version),
 		})
 	}
 	return configStates
 }
 
+func applyStateKey(product data.Product, id string) string {
+	return string(product) + "/" + id
+}
+
+// appliedVersion returns the version of a config reported as applied, the received version is returned if no status
+// was reported for this config
+func (c *Client) appliedVersion(product data.Product, id string, version uint64) uint64 {
+	if state, found := c.applyStates[applyStateKey(product, id)]; found {
+		return state.version
+	}
+	return version
+}
+
+// applyErrors returns the errors reported while applying the configs
+func (c *Client) applyErrors() []string {
+	var errs []string
+	for key, state := range c.applyStates {
+		if state.err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", key, state.err))
+		}
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// UpdateApplyStatus reports the version of a config applied by the client, and the error that prevented the latest
+// version from being applied if any. The status is sent with the next poll.
+func (c *Client) UpdateApplyStatus(product data.Product, id string, version uint64, err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.applyStates[applyStateKey(product, id)] = applyState{
+		version: version,
+		err:     err,
+	}
+}
+
+// ClearApplyStatus removes the status reported for a config
+func (c *Client) ClearApplyStatus(product data.Product, id string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	delete(c.applyStates, applyStateKey(product, id))
+}
+
 // APMSamplingUpdates returns a chan to consume apm sampling updates
 func (c *Client) APMSamplingUpdates() <-chan []client.ConfigAPMSamling {
 	return c.apmSamplingUpdates
@@ -13,6 +13,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"net"
 	"os"
 	"testing"
@@ -23,6 +24,7 @@ import (
 	rdata "github.com/DataDog/datadog-agent/pkg/config/remote/data"
 	"github.com/DataDog/datadog-agent/pkg/config/remote/meta"
 	"github.com/DataDog/datadog-agent/pkg/proto/pbgo"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client"
 	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client/products/apmsampling"
 	"github.com/DataDog/datadog-agent/pkg/version"
 	"github.com/stretchr/testify/assert"
@@ -163,6 +165,36 @@ func TestClientValidResponse(t *testing.T) {
 	assert.Equal(t, apmConfig, apmUpdate[0].Config)
 }
 
+func TestClientApplyStatus(t *testing.T) {
+	c := &Client{
+		applyStates: make(map[string]applyState),
+		currentConfigs: client.Configs{
+			CWSDDConfigs: []client.ConfigCWSDD{
+				{ID: "config-id-1", Version: 3},
+				{ID: "config-id-2", Version: 5},
+			},
+		},
+	}
+
+	// the received versions are reported until a status is set
+	states := c.configStates()
+	require.Len(t, states, 2)
+	assert.Equal(t, uint64(3), states[0].Version)
+	assert.Empty(t, c.applyErrors())
+
+	c.UpdateApplyStatus(rdata.ProductCWSDD, "config-id-1", 2, errors.New("rolled back"))
+	c.UpdateApplyStatus(rdata.ProductCWSDD, "config-id-2", 5, nil)
+
+	states = c.configStates()
+	assert.Equal(t, uint64(2), states[0].Version)
+	assert.Equal(t, uint64(5), states[1].Version)
+	assert.Equal(t, []string{"CWS_DD/config-id-1: rolled back"}, c.applyErrors())
+
+	c.ClearApplyStatus(rdata.ProductCWSDD, "config-id-1")
+	assert.Equal(t, uint64(3), c.configStates()[0].Version)
+	assert.Empty(t, c.applyErrors())
+}
+
 func generateKey() keys.Signer {
 	key, _ := keys.GenerateEd25519Key()
 	return key
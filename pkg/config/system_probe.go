@@ -19,6 +19,7 @@ const (
 	spNS  = "system_probe_config"
 	netNS = "network_config"
 	smNS  = "service_monitoring_config"
+	trNS  = "traceroute_config"
 
 	defaultConnsMessageBatchSize = 600
 
@@ -150,6 +151,8 @@ func InitSystemProbeConfig(cfg Config) {
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
+	// list of additional ports of the DNS servers whose traffic is monitored
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_monitoring_ports"), []string{}, "DD_SYSTEM_PROBE_NETWORK_DNS_MONITORING_PORTS")
 
 	// windows config
 	cfg.BindEnvAndSetDefault(join(spNS, "windows.enable_monotonic_count"), false)
@@ -165,6 +168,17 @@ func InitSystemProbeConfig(cfg Config) {
 
 	// service monitoring
 	cfg.BindEnvAndSetDefault(join(smNS, "enabled"), false, "DD_SYSTEM_PROBE_SERVICE_MONITORING_ENABLED")
+
+	// traceroute module
+	cfg.BindEnvAndSetDefault(join(trNS, "enabled"), false)
+	cfg.BindEnvAndSetDefault(join(trNS, "protocol"), "udp")
+	cfg.BindEnvAndSetDefault(join(trNS, "max_ttl"), 30)
+	cfg.BindEnvAndSetDefault(join(trNS, "timeout"), 1*time.Second)
+	cfg.BindEnvAndSetDefault(join(trNS, "interval"), 5*time.Minute)
+	cfg.BindEnvAndSetDefault(join(trNS, "max_destinations"), 10)
+	cfg.BindEnvAndSetDefault(join(trNS, "max_concurrent_traceroutes"), 4)
+	cfg.BindEnvAndSetDefault(join(trNS, "reverse_dns"), true)
+	cfg.BindEnvAndSetDefault(join(trNS, "asn_database_path"), "")
 }
 
 func join(pieces ...string) string {
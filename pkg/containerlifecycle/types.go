@@ -8,10 +8,26 @@ package containerlifecycle
 const (
 	// PayloadV1 represents the payload v1 version
 	PayloadV1 = "v1"
+	// PayloadV2 represents the payload v2 version, sent through the event platform
+	PayloadV2 = "v2"
 	// EventNameDelete represents deletion events
 	EventNameDelete = "delete"
+	// EventNameCreate represents creation events
+	EventNameCreate = "create"
+	// EventNameStart represents container start events
+	EventNameStart = "start"
+	// EventNameExit represents container exit events
+	EventNameExit = "exit"
 	// ObjectKindContainer represents container events
 	ObjectKindContainer = "container"
 	// ObjectKindPod represents pod events
 	ObjectKindPod = "pod"
+	// ObjectKindTask represents ECS task events
+	ObjectKindTask = "task"
+	// ExitReasonOOMKilled is the exit reason of containers killed by the OOM killer
+	ExitReasonOOMKilled = "OOMKilled"
+	// ExitReasonCompleted is the exit reason of containers that exited with code 0
+	ExitReasonCompleted = "Completed"
+	// ExitReasonError is the exit reason of containers that exited with a non-zero code
+	ExitReasonError = "Error"
 )
@@ -36,6 +36,15 @@ const (
 
 	// EventTypeNetworkDevicesNetFlow is the event type for network devices NetFlow data
 	EventTypeNetworkDevicesNetFlow = "network-devices-netflow"
+
+	// EventTypeContainerSBOM is the event type for container images SBOM
+	EventTypeContainerSBOM = "container-sbom"
+
+	// EventTypeContainerLifecycle is the event type for container, pod and ECS task lifecycle events
+	EventTypeContainerLifecycle = "container-lifecycle"
+
+	// EventTypeNetworkPath is the event type for the network paths traced by system-probe
+	EventTypeNetworkPath = "network-path"
 )
 
 var passthroughPipelineDescs = []passthroughPipelineDesc{
@@ -96,6 +105,33 @@ var passthroughPipelineDescs = []passthroughPipelineDesc{
 		defaultBatchMaxContentSize:    20e6,  // max 20Mb uncompressed size per payload
 		defaultBatchMaxSize:           10000, // max 10k events per payload
 	},
+	{
+		eventType:                     EventTypeContainerLifecycle,
+		endpointsConfigPrefix:         "container_lifecycle.events.",
+		hostnameEndpointPrefix:        "contlcycle-intake.",
+		intakeTrackType:               "contlcycle",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
+	{
+		eventType:                     EventTypeContainerSBOM,
+		endpointsConfigPrefix:         "sbom.",
+		hostnameEndpointPrefix:        "sbom-intake.",
+		intakeTrackType:               "sbom",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
+	{
+		eventType:                     EventTypeNetworkPath,
+		endpointsConfigPrefix:         "network_path.forwarder.",
+		hostnameEndpointPrefix:        "netpath-intake.",
+		intakeTrackType:               "netpath",
+		defaultBatchMaxConcurrentSend: pkgconfig.DefaultBatchMaxConcurrentSend,
+		defaultBatchMaxContentSize:    pkgconfig.DefaultBatchMaxContentSize,
+		defaultBatchMaxSize:           pkgconfig.DefaultBatchMaxSize,
+	},
 }
 
 // An EventPlatformForwarder forwards Messages to a destination based on their event type
@@ -15,6 +15,9 @@ const (
 	// DefaultAggregatorBufferSize is the default aggregator buffer size interval
 	DefaultAggregatorBufferSize = 100
 
+	// DefaultGeoIPReloadInterval is the default interval in seconds between the checks for updates of the geo-IP databases
+	DefaultGeoIPReloadInterval = 60
+
 	// DefaultBindHost is the default bind host used for flow listeners
 	DefaultBindHost = "0.0.0.0"
 )
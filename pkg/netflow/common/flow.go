@@ -56,6 +56,21 @@ type Flow struct {
 	Tos uint32 // FLOW KEY
 
 	NextHop []byte // FLOW KEY
+
+	// Geo-IP information of the source/destination addresses
+	SrcGeoIP GeoIP
+	DstGeoIP GeoIP
+
+	// Number of flows rolled up into this flow when it is the "other" flow of the top-N aggregation
+	OtherFlowCount uint64
+}
+
+// GeoIP contains the geo-IP information of an address
+type GeoIP struct {
+	Country string // ISO 3166-1 country code
+	City    string
+	ASN     uint32
+	ASOrg   string
 }
 
 // AggregationHash return a hash used as aggregation key
@@ -7,6 +7,7 @@ package config
 
 import (
 	"fmt"
+	"net"
 
 	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/netflow/common"
@@ -19,6 +20,23 @@ type NetflowConfig struct {
 	AggregatorBufferSize    int              `mapstructure:"aggregator_buffer_size"`
 	AggregatorFlushInterval int              `mapstructure:"aggregator_flush_interval"`
 	LogPayloads             bool             `mapstructure:"log_payloads"`
+	GeoIP                   GeoIPConfig      `mapstructure:"geoip"`
+	ScaleBySamplingRate     bool             `mapstructure:"scale_by_sampling_rate"`
+	Exporters               []ExporterConfig `mapstructure:"exporters"`
+	AggregatorTopN          int              `mapstructure:"aggregator_top_n"`
+}
+
+// ExporterConfig contains configuration for a single flow exporter
+type ExporterConfig struct {
+	IPAddress    string `mapstructure:"ip_address"`
+	SamplingRate uint64 `mapstructure:"sampling_rate"`
+}
+
+// GeoIPConfig contains configuration for the geo-IP enrichment of the flows.
+type GeoIPConfig struct {
+	CityDatabasePath string `mapstructure:"city_database_path"`
+	ASNDatabasePath  string `mapstructure:"asn_database_path"`
+	ReloadInterval   int    `mapstructure:"reload_interval"`
 }
 
 // ListenerConfig contains configuration for a single flow listener
@@ -72,10 +90,31 @@ func ReadConfig() (*NetflowConfig, error) {
 	if mainConfig.AggregatorBufferSize == 0 {
 		mainConfig.AggregatorBufferSize = common.DefaultAggregatorBufferSize
 	}
+	if mainConfig.GeoIP.ReloadInterval == 0 {
+		mainConfig.GeoIP.ReloadInterval = common.DefaultGeoIPReloadInterval
+	}
+	for i := range mainConfig.Exporters {
+		exporterConfig := &mainConfig.Exporters[i]
+
+		ip := net.ParseIP(exporterConfig.IPAddress)
+		if ip == nil {
+			return nil, fmt.Errorf("the provided exporter ip address `%s` is not valid", exporterConfig.IPAddress)
+		}
+		// normalize the address to match the exporter address of the flows
+		exporterConfig.IPAddress = ip.String()
+	}
+	if mainConfig.AggregatorTopN < 0 {
+		return nil, fmt.Errorf("the provided aggregator top N `%d` must be positive", mainConfig.AggregatorTopN)
+	}
 
 	return &mainConfig, nil
 }
 
+// Enabled returns whether the geo-IP enrichment is enabled, it requires at least one database.
+func (c *GeoIPConfig) Enabled() bool {
+	return c.CityDatabasePath != "" || c.ASNDatabasePath != ""
+}
+
 // Addr returns the host:port address to listen on.
 func (c *ListenerConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", c.BindHost, c.Port)
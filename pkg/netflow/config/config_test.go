@@ -26,6 +26,17 @@ network_devices:
     aggregator_buffer_size: 20
     aggregator_flush_interval: 30
     log_payloads: true
+    geoip:
+      city_database_path: /opt/geoip/GeoLite2-City.mmdb
+      asn_database_path: /opt/geoip/GeoLite2-ASN.mmdb
+      reload_interval: 600
+    scale_by_sampling_rate: true
+    exporters:
+      - ip_address: 10.0.0.1
+        sampling_rate: 1000
+      - ip_address: "::ffff:10.0.0.2"
+        sampling_rate: 512
+    aggregator_top_n: 50
     listeners:
       - flow_type: netflow9
         bind_host: 127.0.0.1
@@ -43,6 +54,17 @@ network_devices:
 				AggregatorBufferSize:    20,
 				AggregatorFlushInterval: 30,
 				LogPayloads:             true,
+				GeoIP: GeoIPConfig{
+					CityDatabasePath: "/opt/geoip/GeoLite2-City.mmdb",
+					ASNDatabasePath:  "/opt/geoip/GeoLite2-ASN.mmdb",
+					ReloadInterval:   600,
+				},
+				ScaleBySamplingRate: true,
+				Exporters: []ExporterConfig{
+					{IPAddress: "10.0.0.1", SamplingRate: 1000},
+					{IPAddress: "10.0.0.2", SamplingRate: 512},
+				},
+				AggregatorTopN: 50,
 				Listeners: []ListenerConfig{
 					{
 						FlowType:  common.TypeNetFlow9,
@@ -75,6 +97,9 @@ network_devices:
 				AggregatorBufferSize:    100,
 				AggregatorFlushInterval: 300,
 				LogPayloads:             false,
+				GeoIP: GeoIPConfig{
+					ReloadInterval: 60,
+				},
 				Listeners: []ListenerConfig{
 					{
 						FlowType:  common.TypeNetFlow9,
@@ -97,6 +122,18 @@ network_devices:
 `,
 			expectedError: "the provided flow type `invalidType` is not valid",
 		},
+		{
+			name: "invalid exporter ip address",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    exporters:
+      - ip_address: not-an-ip
+        sampling_rate: 1000
+`,
+			expectedError: "the provided exporter ip address `not-an-ip` is not valid",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package enrichment
+
+import (
+	"net"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/util/geoip"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// cityRecord contains the fields used from the records of the MaxMind City databases
+type cityRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// GeoIPEnricher adds the country, city and ASN of the source and destination addresses of the flows
+// using local MaxMind databases (MMDB)
+type GeoIPEnricher struct {
+	city           *geoip.Database
+	asn            *geoip.Database
+	reloadInterval time.Duration
+	stopChan       chan struct{}
+}
+
+// NewGeoIPEnricher returns a new GeoIPEnricher, empty database paths are ignored
+func NewGeoIPEnricher(cityDatabasePath string, asnDatabasePath string, reloadInterval time.Duration) (*GeoIPEnricher, error) {
+	e := &GeoIPEnricher{
+		reloadInterval: reloadInterval,
+		stopChan:       make(chan struct{}),
+	}
+
+	var err error
+	if cityDatabasePath != "" {
+		if e.city, err = geoip.Open(cityDatabasePath); err != nil {
+			return nil, err
+		}
+	}
+	if asnDatabasePath != "" {
+		if e.asn, err = geoip.Open(asnDatabasePath); err != nil {
+			e.Close()
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Start will start the reload of the databases when their files change
+func (e *GeoIPEnricher) Start() {
+	go geoip.ReloadPeriodically(e.reloadInterval, e.stopChan, e.city, e.asn)
+}
+
+// Stop will stop the reload of the databases and close them
+func (e *GeoIPEnricher) Stop() {
+	close(e.stopChan)
+	e.Close()
+}
+
+// Close closes the databases
+func (e *GeoIPEnricher) Close() {
+	for _, db := range []*geoip.Database{e.city, e.asn} {
+		if db != nil {
+			db.Close()
+		}
+	}
+}
+
+// Enrich adds the geo-IP information of the source and destination addresses to the flow
+func (e *GeoIPEnricher) Enrich(flow *common.Flow) {
+	flow.SrcGeoIP = e.lookup(flow.SrcAddr)
+	flow.DstGeoIP = e.lookup(flow.DstAddr)
+}
+
+func (e *GeoIPEnricher) lookup(ipAddr []byte) common.GeoIP {
+	var geoIP common.GeoIP
+
+	ip := net.IP(ipAddr)
+	if len(ip) == 0 || ip.IsPrivate() || ip.IsLoopback() || ip.IsUnspecified() {
+		return geoIP
+	}
+
+	if e.city != nil {
+		var record cityRecord
+		if err := e.city.Lookup(ip, &record); err != nil {
+			log.Debugf("Error looking up %s in geo-IP database `%s`: %s", ip, e.city.Path(), err)
+		} else {
+			geoIP.Country = record.Country.ISOCode
+			geoIP.City = record.City.Names["en"]
+		}
+	}
+	if e.asn != nil {
+		if record, err := e.asn.LookupASN(ip); err != nil {
+			log.Debugf("Error looking up %s in geo-IP database `%s`: %s", ip, e.asn.Path(), err)
+		} else {
+			geoIP.ASN = record.ASN
+			geoIP.ASOrg = record.ASOrg
+		}
+	}
+	return geoIP
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package enrichment
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/util/geoip/testutil"
+)
+
+func TestGeoIPEnricher(t *testing.T) {
+	dir := t.TempDir()
+	cityPath := filepath.Join(dir, "city.mmdb")
+	asnPath := filepath.Join(dir, "asn.mmdb")
+	testutil.WriteMMDB(t, cityPath, "1.2.0.0/16", testutil.CityRecord("FR", "Paris"))
+	testutil.WriteMMDB(t, asnPath, "1.2.0.0/16", testutil.ASNRecord(16276, "OVH SAS"))
+
+	e, err := NewGeoIPEnricher(cityPath, asnPath, time.Minute)
+	require.NoError(t, err)
+	defer e.Close()
+
+	flow := &common.Flow{
+		SrcAddr: []byte{1, 2, 3, 4},
+		DstAddr: []byte{5, 6, 7, 8},
+	}
+	e.Enrich(flow)
+	assert.Equal(t, common.GeoIP{Country: "FR", City: "Paris", ASN: 16276, ASOrg: "OVH SAS"}, flow.SrcGeoIP)
+	assert.Equal(t, common.GeoIP{}, flow.DstGeoIP)
+
+	// private and IPv6 addresses aren't enriched
+	flow = &common.Flow{
+		SrcAddr: []byte{10, 0, 0, 1},
+		DstAddr: net.ParseIP("2001:db8::1"),
+	}
+	e.Enrich(flow)
+	assert.Equal(t, common.GeoIP{}, flow.SrcGeoIP)
+	assert.Equal(t, common.GeoIP{}, flow.DstGeoIP)
+}
+
+func TestGeoIPEnricherReload(t *testing.T) {
+	cityPath := filepath.Join(t.TempDir(), "city.mmdb")
+	testutil.WriteMMDB(t, cityPath, "1.2.0.0/16", testutil.CityRecord("FR", "Paris"))
+
+	e, err := NewGeoIPEnricher(cityPath, "", 10*time.Millisecond)
+	require.NoError(t, err)
+	e.Start()
+	defer e.Stop()
+
+	flow := &common.Flow{SrcAddr: []byte{1, 2, 3, 4}}
+	e.Enrich(flow)
+	assert.Equal(t, common.GeoIP{Country: "FR", City: "Paris"}, flow.SrcGeoIP)
+
+	testutil.WriteMMDB(t, cityPath, "1.2.0.0/16", testutil.CityRecord("DE", "Berlin"))
+	require.NoError(t, os.Chtimes(cityPath, time.Now(), time.Now().Add(time.Hour)))
+	assert.Eventually(t, func() bool {
+		e.Enrich(flow)
+		return flow.SrcGeoIP == common.GeoIP{Country: "DE", City: "Berlin"}
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewGeoIPEnricherInvalidDatabase(t *testing.T) {
+	_, err := NewGeoIPEnricher(filepath.Join(t.TempDir(), "missing.mmdb"), "", time.Minute)
+	assert.Error(t, err)
+}
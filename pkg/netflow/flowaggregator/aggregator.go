@@ -11,6 +11,8 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/netflow/common"
 	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+	"github.com/DataDog/datadog-agent/pkg/netflow/enrichment"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 )
 
 const flowAggregatorFlushInterval = 10 * time.Second
@@ -26,24 +28,50 @@ type FlowAggregator struct {
 	receivedFlowCount uint64
 	flushedFlowCount  uint64
 	hostname          string
+	deviceMetadata    *devicemetadata.Cache
+	geoIP             *enrichment.GeoIPEnricher
+	samplingScaler    *samplingRateScaler
+	topN              int
+	rolledUpFlowCount uint64
 }
 
 // NewFlowAggregator returns a new FlowAggregator
 func NewFlowAggregator(sender aggregator.Sender, config *config.NetflowConfig, hostname string) *FlowAggregator {
+	var geoIP *enrichment.GeoIPEnricher
+	if config.GeoIP.Enabled() {
+		var err error
+		geoIP, err = enrichment.NewGeoIPEnricher(config.GeoIP.CityDatabasePath, config.GeoIP.ASNDatabasePath, time.Duration(config.GeoIP.ReloadInterval)*time.Second)
+		if err != nil {
+			log.Errorf("Error loading geo-IP databases, flows won't be enriched with geo-IP information: %s", err)
+		}
+	}
+
+	var samplingScaler *samplingRateScaler
+	if config.ScaleBySamplingRate {
+		samplingScaler = newSamplingRateScaler(config.Exporters)
+	}
+
 	return &FlowAggregator{
-		flowIn:        make(chan *common.Flow, config.AggregatorBufferSize),
-		flowAcc:       newFlowAccumulator(time.Duration(config.AggregatorFlushInterval) * time.Second),
-		flushInterval: flowAggregatorFlushInterval,
-		sender:        sender,
-		stopChan:      make(chan struct{}),
-		logPayload:    config.LogPayloads,
-		hostname:      hostname,
+		flowIn:         make(chan *common.Flow, config.AggregatorBufferSize),
+		flowAcc:        newFlowAccumulator(time.Duration(config.AggregatorFlushInterval) * time.Second),
+		flushInterval:  flowAggregatorFlushInterval,
+		sender:         sender,
+		stopChan:       make(chan struct{}),
+		logPayload:     config.LogPayloads,
+		hostname:       hostname,
+		deviceMetadata: devicemetadata.GetDefaultCache(),
+		geoIP:          geoIP,
+		samplingScaler: samplingScaler,
+		topN:           config.AggregatorTopN,
 	}
 }
 
 // Start will start the FlowAggregator worker
 func (agg *FlowAggregator) Start() {
 	log.Info("Flow Aggregator started")
+	if agg.geoIP != nil {
+		agg.geoIP.Start()
+	}
 	go agg.run()
 	agg.flushLoop() // blocking call
 }
@@ -51,6 +79,9 @@ func (agg *FlowAggregator) Start() {
 // Stop will stop running FlowAggregator
 func (agg *FlowAggregator) Stop() {
 	close(agg.stopChan)
+	if agg.geoIP != nil {
+		agg.geoIP.Stop()
+	}
 }
 
 // GetFlowInChan returns flow input chan
@@ -66,6 +97,12 @@ func (agg *FlowAggregator) run() {
 			return
 		case flow := <-agg.flowIn:
 			atomic.AddUint64(&agg.receivedFlowCount, 1)
+			if agg.samplingScaler != nil {
+				agg.samplingScaler.scale(flow)
+			}
+			if agg.geoIP != nil {
+				agg.geoIP.Enrich(flow)
+			}
 			agg.flowAcc.add(flow)
 		}
 	}
@@ -73,7 +110,7 @@ func (agg *FlowAggregator) run() {
 
 func (agg *FlowAggregator) sendFlows(flows []*common.Flow) {
 	for _, flow := range flows {
-		flowPayload := buildPayload(flow, agg.hostname)
+		flowPayload := buildPayload(flow, agg.hostname, agg.deviceMetadata)
 		payloadBytes, err := json.Marshal(flowPayload)
 		if err != nil {
 			log.Errorf("Error marshalling device metadata: %s", err)
@@ -118,11 +155,17 @@ func (agg *FlowAggregator) flush() int {
 	}
 	// TODO: Add flush stats to agent telemetry e.g. aggregator newFlushCountStats()
 
+	flowsToFlush, rolledUpFlowCount := keepTopFlows(flowsToFlush, agg.topN)
+	atomic.AddUint64(&agg.rolledUpFlowCount, uint64(rolledUpFlowCount))
+
 	agg.sendFlows(flowsToFlush)
 
 	atomic.AddUint64(&agg.flushedFlowCount, uint64(len(flowsToFlush)))
 	agg.sender.MonotonicCount("datadog.netflow.aggregator.flows_received", float64(atomic.LoadUint64(&agg.receivedFlowCount)), "", nil)
 	agg.sender.MonotonicCount("datadog.netflow.aggregator.flows_flushed", float64(atomic.LoadUint64(&agg.flushedFlowCount)), "", nil)
+	if agg.topN > 0 {
+		agg.sender.MonotonicCount("datadog.netflow.aggregator.flows_rolled_up", float64(atomic.LoadUint64(&agg.rolledUpFlowCount)), "", nil)
+	}
 
 	return len(flowsToFlush)
 }
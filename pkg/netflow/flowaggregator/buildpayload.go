@@ -1,14 +1,17 @@
 package flowaggregator
 
 import (
+	"math"
+
 	"github.com/DataDog/datadog-agent/pkg/netflow/enrichment"
 
 	"github.com/DataDog/datadog-agent/pkg/netflow/common"
 	"github.com/DataDog/datadog-agent/pkg/netflow/payload"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 )
 
-func buildPayload(aggFlow *common.Flow, hostname string) payload.FlowPayload {
-	return payload.FlowPayload{
+func buildPayload(aggFlow *common.Flow, hostname string, deviceMetadata *devicemetadata.Cache) payload.FlowPayload {
+	flowPayload := payload.FlowPayload{
 		// TODO: Implement Tos
 		FlowType:     string(aggFlow.FlowType),
 		SamplingRate: aggFlow.SamplingRate,
@@ -23,16 +26,18 @@ func buildPayload(aggFlow *common.Flow, hostname string) payload.FlowPayload {
 		EtherType:  enrichment.MapEtherType(aggFlow.EtherType),
 		IPProtocol: enrichment.MapIPProtocol(aggFlow.IPProtocol),
 		Source: payload.Endpoint{
-			IP:   common.IPBytesToString(aggFlow.SrcAddr),
-			Port: aggFlow.SrcPort,
-			Mac:  enrichment.FormatMacAddress(aggFlow.SrcMac),
-			Mask: enrichment.FormatMask(aggFlow.SrcAddr, aggFlow.SrcMask),
+			IP:    common.IPBytesToString(aggFlow.SrcAddr),
+			Port:  aggFlow.SrcPort,
+			Mac:   enrichment.FormatMacAddress(aggFlow.SrcMac),
+			Mask:  enrichment.FormatMask(aggFlow.SrcAddr, aggFlow.SrcMask),
+			GeoIP: buildGeoIP(aggFlow.SrcGeoIP),
 		},
 		Destination: payload.Endpoint{
-			IP:   common.IPBytesToString(aggFlow.DstAddr),
-			Port: aggFlow.DstPort,
-			Mac:  enrichment.FormatMacAddress(aggFlow.DstMac),
-			Mask: enrichment.FormatMask(aggFlow.DstAddr, aggFlow.DstMask),
+			IP:    common.IPBytesToString(aggFlow.DstAddr),
+			Port:  aggFlow.DstPort,
+			Mac:   enrichment.FormatMacAddress(aggFlow.DstMac),
+			Mask:  enrichment.FormatMask(aggFlow.DstAddr, aggFlow.DstMask),
+			GeoIP: buildGeoIP(aggFlow.DstGeoIP),
 		},
 		Ingress: payload.ObservationPoint{
 			Interface: payload.Interface{
@@ -50,5 +55,49 @@ func buildPayload(aggFlow *common.Flow, hostname string) payload.FlowPayload {
 		NextHop: payload.NextHop{
 			IP: common.IPBytesToString(aggFlow.NextHop),
 		},
+		OtherFlowCount: aggFlow.OtherFlowCount,
+	}
+	if deviceMetadata != nil {
+		enrichWithDeviceMetadata(&flowPayload, deviceMetadata)
+	}
+	return flowPayload
+}
+
+// buildGeoIP returns the geo-IP details of an endpoint, or nil if the endpoint wasn't enriched
+func buildGeoIP(geoIP common.GeoIP) *payload.GeoIP {
+	if geoIP == (common.GeoIP{}) {
+		return nil
+	}
+	return &payload.GeoIP{
+		Country: geoIP.Country,
+		City:    geoIP.City,
+		ASN:     geoIP.ASN,
+		ASOrg:   geoIP.ASOrg,
+	}
+}
+
+// enrichWithDeviceMetadata adds the exporter and interfaces metadata collected by the SNMP check
+func enrichWithDeviceMetadata(flowPayload *payload.FlowPayload, deviceMetadata *devicemetadata.Cache) {
+	device, ok := deviceMetadata.GetDevice(flowPayload.Namespace, flowPayload.Exporter.IP)
+	if !ok {
+		return
+	}
+	flowPayload.Exporter.Name = device.Name
+
+	enrichInterface(&flowPayload.Ingress.Interface, flowPayload.Namespace, flowPayload.Exporter.IP, deviceMetadata)
+	enrichInterface(&flowPayload.Egress.Interface, flowPayload.Namespace, flowPayload.Exporter.IP, deviceMetadata)
+}
+
+func enrichInterface(flowInterface *payload.Interface, namespace string, exporterIP string, deviceMetadata *devicemetadata.Cache) {
+	// IF-MIB ifIndex values range from 1 to 2147483647
+	if flowInterface.Index == 0 || flowInterface.Index > math.MaxInt32 {
+		return
+	}
+	networkInterface, ok := deviceMetadata.GetInterface(namespace, exporterIP, int32(flowInterface.Index))
+	if !ok {
+		return
 	}
+	flowInterface.Name = networkInterface.Name
+	flowInterface.Alias = networkInterface.Alias
+	flowInterface.Speed = networkInterface.Speed
 }
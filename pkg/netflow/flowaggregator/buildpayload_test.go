@@ -3,6 +3,7 @@ package flowaggregator
 import (
 	"github.com/DataDog/datadog-agent/pkg/netflow/common"
 	"github.com/DataDog/datadog-agent/pkg/netflow/payload"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -78,8 +79,46 @@ func Test_buildPayload(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			flowPayload := buildPayload(&tt.flow, "my-hostname")
+			flowPayload := buildPayload(&tt.flow, "my-hostname", nil)
 			assert.Equal(t, tt.expectedPayload, flowPayload)
 		})
 	}
 }
+
+func Test_buildPayload_deviceMetadata(t *testing.T) {
+	deviceMetadata := devicemetadata.NewCache(devicemetadata.DefaultTTL)
+	deviceMetadata.SetDevice("my-namespace", "127.0.0.1", devicemetadata.Device{ID: "my-namespace:127.0.0.1", Name: "my-router"}, []devicemetadata.Interface{
+		{Index: 10, Name: "eth0", Alias: "uplink", Speed: 10000},
+	})
+
+	flow := common.Flow{
+		Namespace:       "my-namespace",
+		ExporterAddr:    []byte{127, 0, 0, 1},
+		InputInterface:  10,
+		OutputInterface: 20,
+	}
+	flowPayload := buildPayload(&flow, "my-hostname", deviceMetadata)
+
+	assert.Equal(t, payload.Exporter{IP: "127.0.0.1", Name: "my-router"}, flowPayload.Exporter)
+	assert.Equal(t, payload.Interface{Index: 10, Name: "eth0", Alias: "uplink", Speed: 10000}, flowPayload.Ingress.Interface)
+	// unknown interfaces only carry their index
+	assert.Equal(t, payload.Interface{Index: 20}, flowPayload.Egress.Interface)
+
+	// flows of devices that aren't monitored by the SNMP check aren't enriched
+	flow.Namespace = "other-namespace"
+	flowPayload = buildPayload(&flow, "my-hostname", deviceMetadata)
+	assert.Equal(t, payload.Exporter{IP: "127.0.0.1"}, flowPayload.Exporter)
+	assert.Equal(t, payload.Interface{Index: 10}, flowPayload.Ingress.Interface)
+}
+
+func Test_buildPayload_geoIP(t *testing.T) {
+	flow := common.Flow{
+		SrcAddr:  []byte{1, 2, 3, 4},
+		DstAddr:  []byte{10, 0, 0, 1},
+		SrcGeoIP: common.GeoIP{Country: "FR", City: "Paris", ASN: 16276, ASOrg: "OVH SAS"},
+	}
+	flowPayload := buildPayload(&flow, "my-hostname", nil)
+
+	assert.Equal(t, &payload.GeoIP{Country: "FR", City: "Paris", ASN: 16276, ASOrg: "OVH SAS"}, flowPayload.Source.GeoIP)
+	assert.Nil(t, flowPayload.Destination.GeoIP)
+}
@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+)
+
+// samplingRateScaler scales the bytes and packets of sampled flows by their sampling rate
+type samplingRateScaler struct {
+	// exporterSamplingRates holds the sampling rates configured per exporter ip address,
+	// they take precedence over the sampling rates reported by the exporters
+	exporterSamplingRates map[string]uint64
+}
+
+func newSamplingRateScaler(exporters []config.ExporterConfig) *samplingRateScaler {
+	exporterSamplingRates := make(map[string]uint64, len(exporters))
+	for _, exporter := range exporters {
+		if exporter.SamplingRate > 0 {
+			exporterSamplingRates[exporter.IPAddress] = exporter.SamplingRate
+		}
+	}
+	return &samplingRateScaler{
+		exporterSamplingRates: exporterSamplingRates,
+	}
+}
+
+// scale multiplies the bytes and packets of the flow by its sampling rate, the sampling rate of the flow is then
+// reset to 1 so that the scaled flow is not scaled a second time downstream
+func (s *samplingRateScaler) scale(flow *common.Flow) {
+	if samplingRate, ok := s.exporterSamplingRates[common.IPBytesToString(flow.ExporterAddr)]; ok {
+		flow.SamplingRate = samplingRate
+	}
+	if flow.SamplingRate <= 1 {
+		return
+	}
+	flow.Bytes *= flow.SamplingRate
+	flow.Packets *= flow.SamplingRate
+	flow.SamplingRate = 1
+}
@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+	"github.com/DataDog/datadog-agent/pkg/netflow/config"
+)
+
+func Test_samplingRateScaler_scale(t *testing.T) {
+	scaler := newSamplingRateScaler([]config.ExporterConfig{
+		{IPAddress: "127.0.0.2", SamplingRate: 1000},
+	})
+
+	tests := []struct {
+		name                 string
+		flow                 common.Flow
+		expectedSamplingRate uint64
+		expectedBytes        uint64
+		expectedPackets      uint64
+	}{
+		{
+			name:                 "sampling rate reported by the exporter",
+			flow:                 common.Flow{ExporterAddr: []byte{127, 0, 0, 1}, SamplingRate: 10, Bytes: 100, Packets: 2},
+			expectedSamplingRate: 1,
+			expectedBytes:        1000,
+			expectedPackets:      20,
+		},
+		{
+			name:                 "sampling rate configured for the exporter",
+			flow:                 common.Flow{ExporterAddr: []byte{127, 0, 0, 2}, SamplingRate: 10, Bytes: 100, Packets: 2},
+			expectedSamplingRate: 1,
+			expectedBytes:        100000,
+			expectedPackets:      2000,
+		},
+		{
+			name:            "unsampled flow",
+			flow:            common.Flow{ExporterAddr: []byte{127, 0, 0, 1}, Bytes: 100, Packets: 2},
+			expectedBytes:   100,
+			expectedPackets: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scaler.scale(&tt.flow)
+			assert.Equal(t, tt.expectedSamplingRate, tt.flow.SamplingRate)
+			assert.Equal(t, tt.expectedBytes, tt.flow.Bytes)
+			assert.Equal(t, tt.expectedPackets, tt.flow.Packets)
+		})
+	}
+}
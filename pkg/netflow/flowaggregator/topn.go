@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+type exporterKey struct {
+	namespace    string
+	exporterAddr string
+	flowType     common.FlowType
+}
+
+// keepTopFlows keeps the `topN` flows with the most bytes of each exporter and rolls up the other flows into an
+// "other" flow per exporter, the number of rolled up flows is returned
+func keepTopFlows(flows []*common.Flow, topN int) ([]*common.Flow, int) {
+	if topN <= 0 || len(flows) <= topN {
+		return flows, 0
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool {
+		return flows[i].Bytes > flows[j].Bytes
+	})
+
+	// the flows of each exporter, sorted by bytes
+	exporterFlows := make(map[exporterKey][]*common.Flow)
+	var exporterKeys []exporterKey
+	for _, flow := range flows {
+		key := exporterKey{
+			namespace:    flow.Namespace,
+			exporterAddr: string(flow.ExporterAddr),
+			flowType:     flow.FlowType,
+		}
+
+		if _, ok := exporterFlows[key]; !ok {
+			exporterKeys = append(exporterKeys, key)
+		}
+		exporterFlows[key] = append(exporterFlows[key], flow)
+	}
+
+	topFlows := make([]*common.Flow, 0, len(flows))
+	rolledUpFlowCount := 0
+	for _, key := range exporterKeys {
+		flows := exporterFlows[key]
+		if len(flows) <= topN {
+			topFlows = append(topFlows, flows...)
+			continue
+		}
+
+		topFlows = append(topFlows, flows[:topN]...)
+		topFlows = append(topFlows, rollUpFlows(flows[topN:]))
+		rolledUpFlowCount += len(flows) - topN
+	}
+	return topFlows, rolledUpFlowCount
+}
+
+// rollUpFlows aggregates flows of the same exporter into an "other" flow
+func rollUpFlows(flows []*common.Flow) *common.Flow {
+	otherFlow := &common.Flow{
+		Namespace:      flows[0].Namespace,
+		FlowType:       flows[0].FlowType,
+		SamplingRate:   flows[0].SamplingRate,
+		ExporterAddr:   flows[0].ExporterAddr,
+		StartTimestamp: flows[0].StartTimestamp,
+		EndTimestamp:   flows[0].EndTimestamp,
+	}
+	for _, flow := range flows {
+		otherFlow.Bytes += flow.Bytes
+		otherFlow.Packets += flow.Packets
+		otherFlow.StartTimestamp = common.MinUint64(otherFlow.StartTimestamp, flow.StartTimestamp)
+		otherFlow.EndTimestamp = common.MaxUint64(otherFlow.EndTimestamp, flow.EndTimestamp)
+		otherFlow.OtherFlowCount++
+	}
+	return otherFlow
+}
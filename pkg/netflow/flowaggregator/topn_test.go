@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flowaggregator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/netflow/common"
+)
+
+func Test_keepTopFlows(t *testing.T) {
+	newFlow := func(exporter byte, bytes uint64, start uint64) *common.Flow {
+		return &common.Flow{
+			Namespace:      "my-ns",
+			FlowType:       common.TypeNetFlow9,
+			ExporterAddr:   []byte{127, 0, 0, exporter},
+			SrcAddr:        []byte{10, 0, 0, byte(bytes)},
+			Bytes:          bytes,
+			Packets:        1,
+			StartTimestamp: start,
+			EndTimestamp:   start + 10,
+		}
+	}
+	flows := []*common.Flow{
+		newFlow(1, 10, 100),
+		newFlow(1, 50, 100),
+		newFlow(2, 20, 90),
+		newFlow(1, 30, 80),
+		newFlow(1, 40, 110),
+		newFlow(2, 15, 95),
+		newFlow(2, 5, 85),
+	}
+
+	// nothing is rolled up when the top N mode is disabled or the flows are fewer than N
+	topFlows, rolledUp := keepTopFlows(flows, 0)
+	assert.Len(t, topFlows, 7)
+	assert.Equal(t, 0, rolledUp)
+	topFlows, rolledUp = keepTopFlows(flows, 7)
+	assert.Len(t, topFlows, 7)
+	assert.Equal(t, 0, rolledUp)
+
+	// the top N flows are kept and the other flows are rolled up per exporter
+	topFlows, rolledUp = keepTopFlows(flows, 2)
+	assert.Equal(t, 3, rolledUp)
+	if assert.Len(t, topFlows, 6) {
+		assert.Equal(t, uint64(50), topFlows[0].Bytes)
+		assert.Equal(t, uint64(40), topFlows[1].Bytes)
+		assert.Equal(t, &common.Flow{
+			Namespace:      "my-ns",
+			FlowType:       common.TypeNetFlow9,
+			ExporterAddr:   []byte{127, 0, 0, 1},
+			Bytes:          40,
+			Packets:        2,
+			StartTimestamp: 80,
+			EndTimestamp:   110,
+			OtherFlowCount: 2,
+		}, topFlows[2])
+
+		assert.Equal(t, uint64(20), topFlows[3].Bytes)
+		assert.Equal(t, uint64(15), topFlows[4].Bytes)
+		assert.Equal(t, &common.Flow{
+			Namespace:      "my-ns",
+			FlowType:       common.TypeNetFlow9,
+			ExporterAddr:   []byte{127, 0, 0, 2},
+			Bytes:          5,
+			Packets:        1,
+			StartTimestamp: 85,
+			EndTimestamp:   95,
+			OtherFlowCount: 1,
+		}, topFlows[5])
+	}
+}
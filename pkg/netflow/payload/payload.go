@@ -7,15 +7,25 @@ package payload
 
 // Exporter contains exporter details
 type Exporter struct {
-	IP string `json:"ip"`
+	IP   string `json:"ip"`
+	Name string `json:"name,omitempty"`
+}
+
+// GeoIP contains geo-IP details of an endpoint
+type GeoIP struct {
+	Country string `json:"country,omitempty"`
+	City    string `json:"city,omitempty"`
+	ASN     uint32 `json:"asn,omitempty"`
+	ASOrg   string `json:"as_org,omitempty"`
 }
 
 // Endpoint contains source or destination endpoint details
 type Endpoint struct {
-	IP   string `json:"ip"`
-	Port uint32 `json:"port"`
-	Mac  string `json:"mac"`
-	Mask string `json:"mask"`
+	IP    string `json:"ip"`
+	Port  uint32 `json:"port"`
+	Mac   string `json:"mac"`
+	Mask  string `json:"mask"`
+	GeoIP *GeoIP `json:"geoip,omitempty"`
 }
 
 // NextHop contains next hop details
@@ -26,6 +36,9 @@ type NextHop struct {
 // Interface contains interface details
 type Interface struct {
 	Index uint32 `json:"index"`
+	Name  string `json:"name,omitempty"`
+	Alias string `json:"alias,omitempty"`
+	Speed uint64 `json:"speed,omitempty"` // in Mb/s
 }
 
 // ObservationPoint contains ingress or egress observation point
@@ -53,4 +66,6 @@ type FlowPayload struct {
 	Host         string           `json:"host"`
 	TCPFlags     []string         `json:"tcp_flags,omitempty"`
 	NextHop      NextHop          `json:"next_hop,omitempty"`
+	// OtherFlowCount is the number of flows rolled up into this flow when it is the "other" flow of the top-N aggregation
+	OtherFlowCount uint64 `json:"other_flow_count,omitempty"`
 }
@@ -6,6 +6,8 @@
 package config
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,6 +27,11 @@ const (
 
 	defaultOffsetThreshold = 400
 	maxOffsetThreshold     = 3000
+
+	// DefaultDNSPort is the port of the DNS servers whose traffic is always monitored
+	DefaultDNSPort = 53
+	// maxDNSMonitoringPorts is the maximum number of additional DNS ports, bounded by the size of the socket filters
+	maxDNSMonitoringPorts = 16
 )
 
 // Config stores all flags used by the network eBPF tracer
@@ -65,6 +72,10 @@ type Config struct {
 	// These stats objects get flushed on every client request (default 30s check interval)
 	MaxDNSStats int
 
+	// DNSMonitoringPorts is the list of the ports, on top of DefaultDNSPort, of the DNS servers whose traffic
+	// is monitored, such as NodeLocal DNS or custom stub resolvers
+	DNSMonitoringPorts []uint16
+
 	// EnableHTTPMonitoring specifies whether the tracer should monitor HTTP traffic
 	EnableHTTPMonitoring bool
 
@@ -232,6 +243,12 @@ func New() *Config {
 		c.MaxClosedConnectionsBuffered = int(c.MaxTrackedConnections)
 	}
 
+	dnsPorts, err := DNSMonitoringPorts(cfg)
+	if err != nil {
+		log.Warnf("error parsing %q: %v", join(netNS, "dns_monitoring_ports"), err)
+	}
+	c.DNSMonitoringPorts = dnsPorts
+
 	httpRRKey := join(netNS, "http_replace_rules")
 	rr, err := parseReplaceRules(cfg, httpRRKey)
 	if err != nil {
@@ -273,3 +290,53 @@ func New() *Config {
 
 	return c
 }
+
+// DNSPorts returns the ports of the DNS servers whose traffic is monitored
+func (c *Config) DNSPorts() []uint16 {
+	return append([]uint16{DefaultDNSPort}, c.DNSMonitoringPorts...)
+}
+
+// IsDNSPort returns whether the traffic of a DNS server listening on the given port is monitored
+func (c *Config) IsDNSPort(port uint16) bool {
+	if port == DefaultDNSPort {
+		return true
+	}
+	for _, p := range c.DNSMonitoringPorts {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// DNSMonitoringPorts returns the additional DNS ports set in network_config.dns_monitoring_ports.
+// Invalid and duplicate ports are skipped, as well as the ports above the maximum number of ports, in which
+// case an error listing them is returned along with the valid ports.
+func DNSMonitoringPorts(cfg ddconfig.Config) ([]uint16, error) {
+	var (
+		ports   []uint16
+		skipped []string
+	)
+	seen := map[uint16]struct{}{DefaultDNSPort: {}}
+	for _, s := range cfg.GetStringSlice(join(netNS, "dns_monitoring_ports")) {
+		port, err := strconv.ParseUint(s, 10, 16)
+		if err != nil || port == 0 {
+			skipped = append(skipped, s)
+			continue
+		}
+		if _, ok := seen[uint16(port)]; ok {
+			continue
+		}
+		if len(ports) == maxDNSMonitoringPorts {
+			skipped = append(skipped, s)
+			continue
+		}
+		seen[uint16(port)] = struct{}{}
+		ports = append(ports, uint16(port))
+	}
+
+	if len(skipped) > 0 {
+		return ports, fmt.Errorf("skipped invalid ports or ports above the maximum of %d: %s", maxDNSMonitoringPorts, strings.Join(skipped, ", "))
+	}
+	return ports, nil
+}
@@ -238,6 +238,59 @@ func TestSettingMaxDNSStats(t *testing.T) {
 	})
 }
 
+func TestDNSMonitoringPorts(t *testing.T) {
+	newConfig()
+	defer restoreGlobalConfig()
+
+	t.Run("default", func(t *testing.T) {
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Empty(t, cfg.DNSMonitoringPorts)
+		assert.Equal(t, []uint16{53}, cfg.DNSPorts())
+		assert.True(t, cfg.IsDNSPort(53))
+		assert.False(t, cfg.IsDNSPort(5353))
+	})
+
+	t.Run("via YAML", func(t *testing.T) {
+		newConfig()
+		_, err := sysconfig.New("./testdata/TestDDAgentConfigYamlAndSystemProbeConfig-DNSMonitoringPorts.yaml")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Equal(t, []uint16{5353, 8053}, cfg.DNSMonitoringPorts)
+		assert.Equal(t, []uint16{53, 5353, 8053}, cfg.DNSPorts())
+		assert.True(t, cfg.IsDNSPort(8053))
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		newConfig()
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_DNS_MONITORING_PORTS")
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_DNS_MONITORING_PORTS", "5353 foo 8053")
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Equal(t, []uint16{5353, 8053}, cfg.DNSMonitoringPorts)
+	})
+
+	t.Run("too many ports", func(t *testing.T) {
+		newConfig()
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_DNS_MONITORING_PORTS")
+		var ports []string
+		for i := 0; i < maxDNSMonitoringPorts+4; i++ {
+			ports = append(ports, fmt.Sprint(10000+i))
+		}
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_DNS_MONITORING_PORTS", strings.Join(ports, " "))
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Len(t, cfg.DNSMonitoringPorts, maxDNSMonitoringPorts)
+	})
+}
+
 func TestHTTPReplaceRules(t *testing.T) {
 	expected := []*ReplaceRule{
 		{
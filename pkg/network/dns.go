@@ -11,9 +11,13 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
-// DNSKey generates a key suitable for looking up DNS stats based on a ConnectionStats object
-func DNSKey(c *ConnectionStats) (dns.Key, bool) {
-	if c == nil || c.DPort != 53 {
+// DNSKey generates a key suitable for looking up DNS stats based on a ConnectionStats object.
+// No key is returned when the destination port of the connection isn't one of the provided DNS ports.
+func DNSKey(c *ConnectionStats, dnsPorts map[uint16]struct{}) (dns.Key, bool) {
+	if c == nil {
+		return dns.Key{}, false
+	}
+	if _, ok := dnsPorts[c.DPort]; !ok {
 		return dns.Key{}, false
 	}
 
@@ -9,64 +9,104 @@
 package dns
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/DataDog/datadog-agent/pkg/network/config"
 	"golang.org/x/net/bpf"
 )
 
-const port = 53
-
+// generateBPFFilter returns a filter capturing the packets whose source port is a DNS port, as well as the ones
+// whose destination port is a DNS port when DNS stats are collected. For the ports 53 and 5353, it is equivalent to
+//
+//	(000) ldh      [12]                     -- load Ethertype
+//	(001) jeq      #0x86dd   jt 2   jf 11   -- if IPv6, goto 2, else 11
+//	(002) ldb      [20]                     -- load IPv6 Next Header
+//	(003) jeq      #0x6      jt 5   jf 4    -- IPv6 Next Header: if TCP, goto 5
+//	(004) jeq      #0x11     jt 5   jf 25   -- IPv6 Next Header: if UDP, goto 5, else drop
+//	(005) ldh      [54]                     -- load source port
+//	(006) jeq      #0x35     jt 24  jf 7    -- if 53, capture
+//	(007) jeq      #0x14e9   jt 24  jf 8    -- if 5353, capture
+//	(008) ldh      [56]                     -- load dest port
+//	(009) jeq      #0x35     jt 24  jf 10   -- if 53, capture
+//	(010) jeq      #0x14e9   jt 24  jf 25   -- if 5353, capture, else drop
+//	(011) jeq      #0x800    jt 12  jf 25   -- if IPv4, go next, else drop
+//	(012) ldb      [23]                     -- load IPv4 Protocol
+//	(013) jeq      #0x6      jt 15  jf 14   -- if TCP, goto 15
+//	(014) jeq      #0x11     jt 15  jf 25   -- if UDP, goto 15, else drop
+//	(015) ldh      [20]                     -- load Fragment Offset
+//	(016) jset     #0x1fff   jt 25  jf 17   -- use 0x1fff as mask for fragment offset, if != 0, drop
+//	(017) ldxb     4*([14]&0xf)             -- x = IP header length
+//	(018) ldh      [x + 14]                 -- load source port
+//	(019) jeq      #0x35     jt 24  jf 20   -- if 53, capture
+//	(020) jeq      #0x14e9   jt 24  jf 21   -- if 5353, capture
+//	(021) ldh      [x + 16]                 -- load dest port
+//	(022) jeq      #0x35     jt 24  jf 23   -- if 53, capture
+//	(023) jeq      #0x14e9   jt 24  jf 25   -- if 5353, capture, else drop
+//	(024) ret      #262144                  -- capture
+//	(025) ret      #0                       -- drop
 func generateBPFFilter(c *config.Config) ([]bpf.RawInstruction, error) {
-	allowedDestPort := uint32(math.MaxUint32)
+	var srcPorts []uint32
+	for _, p := range c.DNSPorts() {
+		srcPorts = append(srcPorts, uint32(p))
+	}
+	destPorts := []uint32{math.MaxUint32}
 	if c.CollectDNSStats {
-		allowedDestPort = port
+		destPorts = srcPorts
 	}
 
-	return bpf.Assemble([]bpf.Instruction{
-		//(000) ldh      [12] -- load Ethertype
-		bpf.LoadAbsolute{Size: 2, Off: 12},
-		//(001) jeq      #0x86dd          jt 2	jf 9 -- if IPv6, goto 2, else 9
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x86dd, SkipTrue: 0, SkipFalse: 7},
-		//(002) ldb      [20] -- load IPv6 Next Header
-		bpf.LoadAbsolute{Size: 1, Off: 20},
-		//(003) jeq      #0x6             jt 5	jf 4 -- IPv6 Next Header: if TCP, goto 5
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x6, SkipTrue: 1, SkipFalse: 0},
-		//(004) jeq      #0x11            jt 5	jf 21 -- IPv6 Next Header: if UDP, goto 5, else drop
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x11, SkipTrue: 0, SkipFalse: 16},
-		//(005) ldh      [54] -- load source port
-		bpf.LoadAbsolute{Size: 2, Off: 54},
-		//(006) jeq      #0x35            jt 20	jf 7 -- if 53, capture
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipTrue: 13, SkipFalse: 0},
-		//(007) ldh      [56] -- load dest port
-		bpf.LoadAbsolute{Size: 2, Off: 56},
-		//(008) jeq      #0x35            jt 20	jf 21 -- if allowedDestPort, capture, else drop
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: allowedDestPort, SkipTrue: 11, SkipFalse: 12},
-		//(009) jeq      #0x800           jt 10	jf 21 -- if IPv4, go next, else drop
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x800, SkipTrue: 0, SkipFalse: 11},
-		//(010) ldb      [23] -- load IPv4 Protocol
-		bpf.LoadAbsolute{Size: 1, Off: 23},
-		//(011) jeq      #0x6             jt 13	jf 12 -- if TCP, goto 13
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x6, SkipTrue: 1, SkipFalse: 0},
-		//(012) jeq      #0x11            jt 13	jf 21 -- if UDP, goto 13, else drop
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x11, SkipTrue: 0, SkipFalse: 8},
-		//(013) ldh      [20] -- load Fragment Offset
-		bpf.LoadAbsolute{Size: 2, Off: 20},
-		//(014) jset     #0x1fff          jt 21	jf 15 -- use 0x1fff as mask for fragment offset, if != 0, drop
-		bpf.JumpIf{Cond: bpf.JumpBitsSet, Val: 0x1fff, SkipTrue: 6, SkipFalse: 0},
-		//(015) ldxb     4*([14]&0xf) -- x = IP header length
-		bpf.LoadMemShift{Off: 14},
-		//(016) ldh      [x + 14] -- load source port
-		bpf.LoadIndirect{Size: 2, Off: 14},
-		//(017) jeq      #0x35            jt 20	jf 18 -- if port 53 capture
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: port, SkipTrue: 2, SkipFalse: 0},
-		//(018) ldh      [x + 16] -- load dest port
-		bpf.LoadIndirect{Size: 2, Off: 16},
-		//(019) jeq      #0x35            jt 20	jf 21 -- if port allowedDestPort capture, else drop
-		bpf.JumpIf{Cond: bpf.JumpEqual, Val: allowedDestPort, SkipTrue: 0, SkipFalse: 1},
-		//(020) ret      #262144 -- capture
+	// the IPv6 section spans 7 instructions, the IPv4 one 9, on top of the port comparisons
+	ipv4 := 7 + len(srcPorts) + len(destPorts)
+	capture := ipv4 + 9 + len(srcPorts) + len(destPorts)
+	drop := capture + 1
+	if drop > math.MaxUint8 {
+		return nil, fmt.Errorf("too many dns ports: %d", len(srcPorts))
+	}
+
+	var insns []bpf.Instruction
+	// jumpIf appends a conditional jump to the instructions at the indexes jt and jf
+	jumpIf := func(cond bpf.JumpTest, val uint32, jt, jf int) {
+		pc := len(insns)
+		insns = append(insns, bpf.JumpIf{Cond: cond, Val: val, SkipTrue: uint8(jt - pc - 1), SkipFalse: uint8(jf - pc - 1)})
+	}
+	// jumpIfPort appends the jumps to capture if the loaded port is one of ports, and to jf otherwise
+	jumpIfPort := func(ports []uint32, jf int) {
+		for i, p := range ports {
+			next := len(insns) + 1
+			if i == len(ports)-1 {
+				next = jf
+			}
+			jumpIf(bpf.JumpEqual, p, capture, next)
+		}
+	}
+
+	// IPv6
+	insns = append(insns, bpf.LoadAbsolute{Size: 2, Off: 12})
+	jumpIf(bpf.JumpEqual, 0x86dd, len(insns)+1, ipv4)
+	insns = append(insns, bpf.LoadAbsolute{Size: 1, Off: 20})
+	jumpIf(bpf.JumpEqual, 0x6, len(insns)+2, len(insns)+1)
+	jumpIf(bpf.JumpEqual, 0x11, len(insns)+1, drop)
+	insns = append(insns, bpf.LoadAbsolute{Size: 2, Off: 54})
+	jumpIfPort(srcPorts, len(insns)+len(srcPorts))
+	insns = append(insns, bpf.LoadAbsolute{Size: 2, Off: 56})
+	jumpIfPort(destPorts, drop)
+
+	// IPv4
+	jumpIf(bpf.JumpEqual, 0x800, len(insns)+1, drop)
+	insns = append(insns, bpf.LoadAbsolute{Size: 1, Off: 23})
+	jumpIf(bpf.JumpEqual, 0x6, len(insns)+2, len(insns)+1)
+	jumpIf(bpf.JumpEqual, 0x11, len(insns)+1, drop)
+	insns = append(insns, bpf.LoadAbsolute{Size: 2, Off: 20})
+	jumpIf(bpf.JumpBitsSet, 0x1fff, drop, len(insns)+1)
+	insns = append(insns, bpf.LoadMemShift{Off: 14})
+	insns = append(insns, bpf.LoadIndirect{Size: 2, Off: 14})
+	jumpIfPort(srcPorts, len(insns)+len(srcPorts))
+	insns = append(insns, bpf.LoadIndirect{Size: 2, Off: 16})
+	jumpIfPort(destPorts, drop)
+
+	insns = append(insns,
 		bpf.RetConstant{Val: 262144},
-		//(021) ret      #0 -- drop
 		bpf.RetConstant{Val: 0},
-	})
+	)
+	return bpf.Assemble(insns)
 }
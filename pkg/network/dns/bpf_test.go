@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+// +build linux_bpf
+
+package dns
+
+import (
+	"math"
+	"net"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/bpf"
+)
+
+func TestBPFFilterDefaultPort(t *testing.T) {
+	for _, collectDNSStats := range []bool{false, true} {
+		allowedDestPort := uint32(math.MaxUint32)
+		if collectDNSStats {
+			allowedDestPort = 53
+		}
+
+		// filter used before the DNS ports were configurable
+		expected, err := bpf.Assemble([]bpf.Instruction{
+			bpf.LoadAbsolute{Size: 2, Off: 12},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x86dd, SkipTrue: 0, SkipFalse: 7},
+			bpf.LoadAbsolute{Size: 1, Off: 20},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x6, SkipTrue: 1, SkipFalse: 0},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x11, SkipTrue: 0, SkipFalse: 16},
+			bpf.LoadAbsolute{Size: 2, Off: 54},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 53, SkipTrue: 13, SkipFalse: 0},
+			bpf.LoadAbsolute{Size: 2, Off: 56},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: allowedDestPort, SkipTrue: 11, SkipFalse: 12},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x800, SkipTrue: 0, SkipFalse: 11},
+			bpf.LoadAbsolute{Size: 1, Off: 23},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x6, SkipTrue: 1, SkipFalse: 0},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 0x11, SkipTrue: 0, SkipFalse: 8},
+			bpf.LoadAbsolute{Size: 2, Off: 20},
+			bpf.JumpIf{Cond: bpf.JumpBitsSet, Val: 0x1fff, SkipTrue: 6, SkipFalse: 0},
+			bpf.LoadMemShift{Off: 14},
+			bpf.LoadIndirect{Size: 2, Off: 14},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: 53, SkipTrue: 2, SkipFalse: 0},
+			bpf.LoadIndirect{Size: 2, Off: 16},
+			bpf.JumpIf{Cond: bpf.JumpEqual, Val: allowedDestPort, SkipTrue: 0, SkipFalse: 1},
+			bpf.RetConstant{Val: 262144},
+			bpf.RetConstant{Val: 0},
+		})
+		require.NoError(t, err)
+
+		filter, err := generateBPFFilter(&config.Config{CollectDNSStats: collectDNSStats})
+		require.NoError(t, err)
+		assert.Equal(t, expected, filter)
+	}
+}
+
+func TestBPFFilterMonitoringPorts(t *testing.T) {
+	raw, err := generateBPFFilter(&config.Config{CollectDNSStats: true, DNSMonitoringPorts: []uint16{5353, 8053}})
+	require.NoError(t, err)
+	filter, ok := bpf.Disassemble(raw)
+	require.True(t, ok)
+	vm, err := bpf.NewVM(filter)
+	require.NoError(t, err)
+
+	for _, ipv6 := range []bool{false, true} {
+		for _, tc := range []struct {
+			sport, dport uint16
+			captured     bool
+		}{
+			{sport: 40000, dport: 53, captured: true},
+			{sport: 53, dport: 40000, captured: true},
+			{sport: 40000, dport: 5353, captured: true},
+			{sport: 8053, dport: 40000, captured: true},
+			{sport: 40000, dport: 8080, captured: false},
+			{sport: 8080, dport: 40000, captured: false},
+		} {
+			n, err := vm.Run(udpPacket(t, ipv6, tc.sport, tc.dport))
+			require.NoError(t, err)
+			assert.Equal(t, tc.captured, n > 0, "ipv6: %t, sport: %d, dport: %d", ipv6, tc.sport, tc.dport)
+		}
+	}
+}
+
+func udpPacket(t *testing.T, ipv6 bool, sport, dport uint16) []byte {
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		DstMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 6},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	udp := &layers.UDP{SrcPort: layers.UDPPort(sport), DstPort: layers.UDPPort(dport)}
+
+	var ip gopacket.SerializableLayer
+	if ipv6 {
+		eth.EthernetType = layers.EthernetTypeIPv6
+		ip6 := &layers.IPv6{Version: 6, HopLimit: 64, NextHeader: layers.IPProtocolUDP, SrcIP: net.ParseIP("fd00::1"), DstIP: net.ParseIP("fd00::2")}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ip6))
+		ip = ip6
+	} else {
+		ip4 := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2")}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ip4))
+		ip = ip4
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, udp, gopacket.Payload([]byte{0, 1, 2, 3})))
+	return buf.Bytes()
+}
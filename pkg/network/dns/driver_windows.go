@@ -41,22 +41,22 @@ type dnsDriver struct {
 	iocp        windows.Handle
 }
 
-func newDriver() (*dnsDriver, error) {
+func newDriver(ports []uint16) (*dnsDriver, error) {
 	d := &dnsDriver{}
-	err := d.setupDNSHandle()
+	err := d.setupDNSHandle(ports)
 	if err != nil {
 		return nil, err
 	}
 	return d, nil
 }
 
-func (d *dnsDriver) setupDNSHandle() error {
+func (d *dnsDriver) setupDNSHandle(ports []uint16) error {
 	dh, err := driver.NewHandle(windows.FILE_FLAG_OVERLAPPED, driver.DataHandle)
 	if err != nil {
 		return err
 	}
 
-	filters, err := createDNSFilters()
+	filters, err := createDNSFilters(ports)
 	if err != nil {
 		return err
 	}
@@ -136,28 +136,30 @@ func (d *dnsDriver) GetStatsForHandle() (map[string]int64, error) {
 	return d.h.GetStatsForHandle()
 }
 
-func createDNSFilters() ([]driver.FilterDefinition, error) {
+func createDNSFilters(ports []uint16) ([]driver.FilterDefinition, error) {
 	var filters []driver.FilterDefinition
-	
-	filters = append(filters, driver.FilterDefinition{
-		FilterVersion:  driver.Signature,
-		Size:           driver.FilterDefinitionSize,
-		FilterLayer:    driver.LayerTransport,
-		Af:             windows.AF_INET,
-		RemotePort:     53,
-		InterfaceIndex: uint64(0),
-		Direction:      driver.DirectionOutbound,
-	})
-
-	filters = append(filters, driver.FilterDefinition{
-		FilterVersion:  driver.Signature,
-		Size:           driver.FilterDefinitionSize,
-		FilterLayer:    driver.LayerTransport,
-		Af:             windows.AF_INET,
-		RemotePort:     53,
-		InterfaceIndex: uint64(0),
-		Direction:      driver.DirectionInbound,
-	})
+
+	for _, port := range ports {
+		filters = append(filters, driver.FilterDefinition{
+			FilterVersion:  driver.Signature,
+			Size:           driver.FilterDefinitionSize,
+			FilterLayer:    driver.LayerTransport,
+			Af:             windows.AF_INET,
+			RemotePort:     uint64(port),
+			InterfaceIndex: uint64(0),
+			Direction:      driver.DirectionOutbound,
+		})
+
+		filters = append(filters, driver.FilterDefinition{
+			FilterVersion:  driver.Signature,
+			Size:           driver.FilterDefinitionSize,
+			FilterLayer:    driver.LayerTransport,
+			Af:             windows.AF_INET,
+			RemotePort:     uint64(port),
+			InterfaceIndex: uint64(0),
+			Direction:      driver.DirectionInbound,
+		})
+	}
 
 	return filters, nil
 }
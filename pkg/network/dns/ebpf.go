@@ -9,6 +9,7 @@
 package dns
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode"
@@ -62,7 +63,7 @@ func (e *ebpfProgram) Init() error {
 		})
 	}
 
-	return e.InitWithOptions(e.bytecode, manager.Options{
+	err := e.InitWithOptions(e.bytecode, manager.Options{
 		RLimit: &unix.Rlimit{
 			Cur: math.MaxUint64,
 			Max: math.MaxUint64,
@@ -78,4 +79,25 @@ func (e *ebpfProgram) Init() error {
 		},
 		ConstantEditors: constantEditors,
 	})
+	if err != nil {
+		return err
+	}
+
+	return e.setDNSPorts()
+}
+
+// setDNSPorts stores the additional DNS ports in the map read by the socket filter
+func (e *ebpfProgram) setDNSPorts() error {
+	dnsPorts, _, err := e.GetMap(string(probes.DnsPortsMap))
+	if err != nil {
+		return fmt.Errorf("error retrieving the dns ports map: %w", err)
+	}
+
+	enabled := uint8(1)
+	for _, port := range e.cfg.DNSMonitoringPorts {
+		if err := dnsPorts.Put(port, enabled); err != nil {
+			return fmt.Errorf("error adding dns port %d: %w", port, err)
+		}
+	}
+	return nil
 }
@@ -14,7 +14,7 @@ import (
 
 // NewReverseDNS starts snooping on DNS traffic to allow IP -> domain reverse resolution
 func NewReverseDNS(cfg *config.Config) (ReverseDNS, error) {
-	packetSrc, err := newWindowsPacketSource()
+	packetSrc, err := newWindowsPacketSource(cfg.DNSPorts())
 	if err != nil {
 		return nil, err
 	}
@@ -22,8 +22,8 @@ type windowsPacketSource struct {
 }
 
 // newWindowsPacketSource constructs a new packet source
-func newWindowsPacketSource() (packetSource, error) {
-	di, err := newDriver()
+func newWindowsPacketSource(ports []uint16) (packetSource, error) {
+	di, err := newDriver(ports)
 	if err != nil {
 		return nil, err
 	}
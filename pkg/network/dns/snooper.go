@@ -139,6 +139,7 @@ func (s *socketFilterSnooper) GetStats() map[string]int64 {
 		numStats, droppedStats := s.statKeeper.GetNumStats()
 		stats["num_stats"] = int64(numStats)
 		stats["dropped_stats"] = int64(droppedStats)
+		stats["timeouts"] = s.statKeeper.GetTimeouts()
 	}
 	return stats
 }
@@ -224,6 +225,7 @@ func (s *socketFilterSnooper) logDNSStats() {
 		queries, lastQueries     int64
 		successes, lastSuccesses int64
 		errors, lastErrors       int64
+		timeouts, lastTimeouts   int64
 	)
 	for {
 		select {
@@ -231,10 +233,14 @@ func (s *socketFilterSnooper) logDNSStats() {
 			queries = atomic.LoadInt64(&s.queries)
 			successes = atomic.LoadInt64(&s.successes)
 			errors = atomic.LoadInt64(&s.errors)
-			log.Infof("DNS Stats. Queries :%d, Successes :%d, Errors: %d", queries-lastQueries, successes-lastSuccesses, errors-lastErrors)
+			if s.statKeeper != nil {
+				timeouts = s.statKeeper.GetTimeouts()
+			}
+			log.Infof("DNS Stats. Queries :%d, Successes :%d, Errors: %d, Timeouts: %d", queries-lastQueries, successes-lastSuccesses, errors-lastErrors, timeouts-lastTimeouts)
 			lastQueries = queries
 			lastSuccesses = successes
 			lastErrors = errors
+			lastTimeouts = timeouts
 		case <-s.exit:
 			return
 		}
@@ -391,6 +391,42 @@ func TestDNSOverNonPort53(t *testing.T) {
 	}, 3*time.Second, 10*time.Millisecond, "found DNS data for key %v when it should be missing", key)
 }
 
+func TestDNSOverMonitoringPort(t *testing.T) {
+	cfg := testConfig()
+	cfg.CollectDNSStats = true
+	cfg.CollectLocalDNS = true
+	cfg.DNSTimeout = 1 * time.Second
+	cfg.CollectDNSDomains = true
+	cfg.DNSMonitoringPorts = []uint16{5353}
+	rdns, err := NewReverseDNS(cfg)
+	require.NoError(t, err)
+	reverseDNS := rdns.(*dnsMonitor)
+	defer reverseDNS.Close()
+	statKeeper := reverseDNS.statKeeper
+
+	domains := []string{
+		"nonexistent.com.net",
+	}
+	// Set up a local DNS server to return SERVFAIL
+	closeFn := newTestServerOnPort(t, localhost, "5353", "udp", (&handler{}).ServeDNS)
+	defer closeFn()
+	queryIP, queryPort, reps := sendDNSQueriesOnPort(t, domains, localhost, "5353", "udp")
+	require.NotNil(t, reps[0])
+
+	// port 5353 is monitored, so we should get stats
+	key := getKey(queryIP, queryPort, localhost, syscall.IPPROTO_UDP)
+	var allStats StatsByKeyByNameByType
+	require.Eventually(t, func() bool {
+		allStats = statKeeper.Snapshot()
+		return allStats[key] != nil
+	}, 3*time.Second, 10*time.Millisecond, "missing DNS data for key %v", key)
+
+	stats := allStats[key][ToHostname(domains[0])][TypeA]
+	assert.Equal(t, uint32(1), stats.CountByRcode[uint32(layers.DNSResponseCodeServFail)])
+	assert.True(t, stats.FailureLatencySum >= uint64(1))
+	assert.Equal(t, uint64(0), stats.SuccessLatencySum)
+}
+
 func TestDNSOverUDPTimeoutCount(t *testing.T) {
 	reverseDNS := initDNSTestsWithDomainCollection(t, false)
 	defer reverseDNS.Close()
@@ -409,6 +445,7 @@ func TestDNSOverUDPTimeoutCount(t *testing.T) {
 	}, 3*time.Second, 10*time.Millisecond, "missing DNS data for key %v", key)
 	assert.Equal(t, 0, len(allStats[key][ToHostname(domainQueried)][TypeA].CountByRcode))
 	assert.Equal(t, uint32(1), allStats[key][ToHostname(domainQueried)][TypeA].Timeouts)
+	assert.Equal(t, int64(1), reverseDNS.GetStats()["timeouts"])
 	assert.Equal(t, uint64(0), allStats[key][ToHostname(domainQueried)][TypeA].SuccessLatencySum)
 	assert.Equal(t, uint64(0), allStats[key][ToHostname(domainQueried)][TypeA].FailureLatencySum)
 }
@@ -526,7 +563,11 @@ func TestDNSNestedCNAME(t *testing.T) {
 }
 
 func newTestServer(t *testing.T, ip string, protocol string, handler dns.HandlerFunc) func() {
-	addr := net.JoinHostPort(ip, "53")
+	return newTestServerOnPort(t, ip, "53", protocol, handler)
+}
+
+func newTestServerOnPort(t *testing.T, ip string, port string, protocol string, handler dns.HandlerFunc) func() {
+	addr := net.JoinHostPort(ip, port)
 	srv := &dns.Server{Addr: addr, Net: protocol, Handler: handler}
 
 	initChan := make(chan error, 1)
@@ -55,6 +55,9 @@ type stateValue struct {
 }
 
 type dnsStatKeeper struct {
+	// timeouts is at the beginning of the struct to keep it 64-bit aligned, see https://staticcheck.io/docs/checks#SA1027
+	timeouts int64
+
 	mux sync.Mutex
 	// map a DNS key to a map of domain strings to a map of query types to a map of  DNS stats
 	stats            StatsByKeyByNameByType
@@ -152,6 +155,7 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 	// Note: time.Duration in the agent version of go (1.12.9) does not have the Microseconds method.
 	if latency > uint64(d.expirationPeriod.Microseconds()) {
 		byqtype.Timeouts++
+		atomic.AddInt64(&d.timeouts, 1)
 	} else {
 		byqtype.CountByRcode[uint32(info.rCode)]++
 		if info.pktType == successfulResponse {
@@ -171,6 +175,11 @@ func (d *dnsStatKeeper) GetNumStats() (int32, int32) {
 	return numStats, droppedStats
 }
 
+// GetTimeouts returns the number of queries which timed out since the creation of the stat keeper
+func (d *dnsStatKeeper) GetTimeouts() int64 {
+	return atomic.LoadInt64(&d.timeouts)
+}
+
 func (d *dnsStatKeeper) GetAndResetAllStats() StatsByKeyByNameByType {
 	d.mux.Lock()
 	defer d.mux.Unlock()
@@ -239,6 +248,7 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 				stats.CountByRcode = make(map[uint32]uint32)
 			}
 			stats.Timeouts++
+			atomic.AddInt64(&d.timeouts, 1)
 			bytype[v.qtype] = stats
 			allStats[v.question] = bytype
 			d.stats[k.key] = allStats
@@ -62,6 +62,7 @@ func testLatency(
 	assert.Equal(t, expectedSuccessLatency, stats[key][d][TypeA].SuccessLatencySum)
 	assert.Equal(t, expectedFailureLatency, stats[key][d][TypeA].FailureLatencySum)
 	assert.Equal(t, expectedTimeouts, stats[key][d][TypeA].Timeouts)
+	assert.Equal(t, int64(expectedTimeouts), sk.GetTimeouts())
 }
 
 func TestSuccessLatency(t *testing.T) {
@@ -105,6 +106,7 @@ func TestExpiredStateRemoval(t *testing.T) {
 	require.Contains(t, stats[key][d][TypeA].CountByRcode, uint32(0))
 	assert.Equal(t, uint32(2), stats[key][d][TypeA].CountByRcode[0])
 	assert.Equal(t, uint32(1), stats[key][d][TypeA].Timeouts)
+	assert.Equal(t, int64(1), sk.GetTimeouts())
 }
 
 func BenchmarkStats(b *testing.B) {
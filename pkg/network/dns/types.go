@@ -82,6 +82,7 @@ type Key struct {
 
 // Stats holds statistics corresponding to a particular domain
 type Stats struct {
+	// Timeouts counts the queries without a response within the DNS timeout, which are not counted in CountByRcode
 	Timeouts          uint32
 	SuccessLatencySum uint64
 	FailureLatencySum uint64
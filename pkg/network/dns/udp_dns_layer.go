@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows || linux_bpf
+// +build windows linux_bpf
+
+package dns
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+var _ gopacket.DecodingLayer = &udpWithDNSSupport{}
+
+// Gopacket only decodes the payload of the UDP datagrams from or to port 53 as DNS,
+// udpWithDNSSupport does so for all the monitored DNS ports
+type udpWithDNSSupport struct {
+	layers.UDP
+	dnsPorts map[uint16]struct{}
+}
+
+func newUDPWithDNSSupport(ports []uint16) *udpWithDNSSupport {
+	dnsPorts := make(map[uint16]struct{}, len(ports))
+	for _, p := range ports {
+		dnsPorts[p] = struct{}{}
+	}
+	return &udpWithDNSSupport{dnsPorts: dnsPorts}
+}
+
+func (m *udpWithDNSSupport) NextLayerType() gopacket.LayerType {
+	if m.isDNSPort(uint16(m.SrcPort)) || m.isDNSPort(uint16(m.DstPort)) {
+		return layers.LayerTypeDNS
+	}
+	return m.UDP.NextLayerType()
+}
+
+func (m *udpWithDNSSupport) isDNSPort(port uint16) bool {
+	_, ok := m.dnsPorts[port]
+	return ok
+}
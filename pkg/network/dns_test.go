@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package network
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+func TestDNSKey(t *testing.T) {
+	dnsPorts := map[uint16]struct{}{53: {}, 5353: {}}
+	conn := ConnectionStats{
+		Type:   UDP,
+		Family: AFINET,
+		Source: util.AddressFromString("10.0.0.1"),
+		Dest:   util.AddressFromString("10.0.0.2"),
+		SPort:  41000,
+		DPort:  5353,
+	}
+
+	key, ok := DNSKey(&conn, dnsPorts)
+	assert.True(t, ok)
+	assert.Equal(t, util.AddressFromString("10.0.0.2"), key.ServerIP)
+	assert.Equal(t, util.AddressFromString("10.0.0.1"), key.ClientIP)
+	assert.Equal(t, uint16(41000), key.ClientPort)
+	assert.Equal(t, uint8(syscall.IPPROTO_UDP), key.Protocol)
+
+	// connections to other ports aren't DNS connections
+	conn.DPort = 80
+	_, ok = DNSKey(&conn, dnsPorts)
+	assert.False(t, ok)
+
+	_, ok = DNSKey(nil, dnsPorts)
+	assert.False(t, ok)
+}
@@ -9,6 +9,21 @@ static __always_inline bool dns_stats_enabled() {
     return val == ENABLED;
 }
 
+// This map holds the ports, on top of 53, of the DNS servers whose traffic is captured.
+// It is populated from the user-space with the configured dns_monitoring_ports.
+struct bpf_map_def SEC("maps/dns_ports") dns_ports = {
+    .type = BPF_MAP_TYPE_HASH,
+    .key_size = sizeof(__u16),
+    .value_size = sizeof(__u8),
+    .max_entries = 16,
+    .pinning = 0,
+    .namespace = "",
+};
+
+static __always_inline bool is_dns_port(__u16 port) {
+    return port == 53 || bpf_map_lookup_elem(&dns_ports, &port) != NULL;
+}
+
 // This function is meant to be used as a BPF_PROG_TYPE_SOCKET_FILTER.
 // When attached to a RAW_SOCKET, this code filters out everything but DNS traffic.
 // All structs referenced here are kernel independent as they simply map protocol headers (Ethernet, IP and UDP).
@@ -20,7 +35,7 @@ int socket__dns_filter(struct __sk_buff* skb) {
     if (!read_conn_tuple_skb(skb, &skb_info, &tup)) {
         return 0;
     }
-    if (tup.sport != 53 && (!dns_stats_enabled() || tup.dport != 53)) {
+    if (!is_dns_port(tup.sport) && (!dns_stats_enabled() || !is_dns_port(tup.dport))) {
         return 0;
     }
 
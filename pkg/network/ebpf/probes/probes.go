@@ -139,6 +139,7 @@ const (
 	PidFDBySockMap        BPFMapName = "pid_fd_by_sock"
 	TagsMap               BPFMapName = "conn_tags"
 	TcpSendMsgArgsMap     BPFMapName = "tcp_sendmsg_args"
+	DnsPortsMap           BPFMapName = "dns_ports"
 )
 
 // SectionName returns the SectionName for the given BPF map
@@ -11,6 +11,7 @@ import (
 	model "github.com/DataDog/agent-payload/v5/process"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/network"
+	netconfig "github.com/DataDog/datadog-agent/pkg/network/config"
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
@@ -25,6 +26,7 @@ type dnsFormatter struct {
 	ipc       ipCache
 	domainSet map[string]int
 	seen      map[dns.Key]struct{}
+	dnsPorts  map[uint16]struct{}
 
 	// Configuration flags
 	queryTypeEnabled  bool
@@ -32,18 +34,25 @@ type dnsFormatter struct {
 }
 
 func newDNSFormatter(conns *network.Connections, ipc ipCache) *dnsFormatter {
+	dnsPorts := map[uint16]struct{}{netconfig.DefaultDNSPort: {}}
+	ports, _ := netconfig.DNSMonitoringPorts(config.Datadog)
+	for _, port := range ports {
+		dnsPorts[port] = struct{}{}
+	}
+
 	return &dnsFormatter{
 		conns:             conns,
 		ipc:               ipc,
 		domainSet:         make(map[string]int),
 		seen:              make(map[dns.Key]struct{}),
+		dnsPorts:          dnsPorts,
 		queryTypeEnabled:  config.Datadog.GetBool("network_config.enable_dns_by_querytype"),
 		dnsDomainsEnabled: config.Datadog.GetBool("system_probe_config.collect_dns_domains"),
 	}
 }
 
 func (f *dnsFormatter) FormatConnectionDNS(nc network.ConnectionStats, mc *model.Connection) {
-	key, ok := network.DNSKey(&nc)
+	key, ok := network.DNSKey(&nc, f.dnsPorts)
 	if !ok {
 		return
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatConnectionDNS(t *testing.T) {
@@ -42,10 +43,8 @@ func TestFormatConnectionDNS(t *testing.T) {
 			}: map[dns.Hostname]map[dns.QueryType]dns.Stats{
 				dns.ToHostname("foo.com"): {
 					dns.TypeA: {
-						Timeouts:          0,
-						SuccessLatencySum: 0,
-						FailureLatencySum: 0,
-						CountByRcode:      map[uint32]uint32{0: 1},
+						Timeouts:     0,
+						CountByRcode: map[uint32]uint32{0: 1},
 					},
 				},
 			},
@@ -146,10 +145,8 @@ func TestDNSPIDCollision(t *testing.T) {
 			}: map[dns.Hostname]map[dns.QueryType]dns.Stats{
 				dns.ToHostname("foo.com"): {
 					dns.TypeA: {
-						Timeouts:          0,
-						SuccessLatencySum: 0,
-						FailureLatencySum: 0,
-						CountByRcode:      map[uint32]uint32{0: 1},
+						Timeouts:     0,
+						CountByRcode: map[uint32]uint32{0: 1},
 					},
 				},
 			},
@@ -170,3 +167,70 @@ func TestDNSPIDCollision(t *testing.T) {
 	assert.NotNil(t, out1.DnsStatsByDomain)
 	assert.Nil(t, out2.DnsStatsByDomain)
 }
+
+func TestFormatConnectionDNSMonitoringPorts(t *testing.T) {
+	payload := &network.Connections{
+		BufferedData: network.BufferedData{
+			Conns: []network.ConnectionStats{
+				{
+					Source:    util.AddressFromString("10.1.1.1"),
+					Dest:      util.AddressFromString("169.254.20.10"),
+					SPort:     1000,
+					DPort:     5353,
+					Type:      network.UDP,
+					Family:    network.AFINET,
+					Direction: network.OUTGOING,
+				},
+			},
+		},
+		DNSStats: dns.StatsByKeyByNameByType{
+			dns.Key{
+				ClientIP:   util.AddressFromString("10.1.1.1"),
+				ServerIP:   util.AddressFromString("169.254.20.10"),
+				ClientPort: uint16(1000),
+				Protocol:   syscall.IPPROTO_UDP,
+			}: map[dns.Hostname]map[dns.QueryType]dns.Stats{
+				dns.ToHostname("foo.com"): {
+					dns.TypeA: {
+						Timeouts:          2,
+						CountByRcode:      map[uint32]uint32{0: 3, 2: 1},
+						SuccessLatencySum: 3300,
+						FailureLatencySum: 5000,
+					},
+				},
+			},
+		},
+	}
+
+	config.Datadog.Set("system_probe_config.collect_dns_domains", false)
+	config.Datadog.Set("network_config.enable_dns_by_querytype", false)
+	defer config.Datadog.Set("network_config.dns_monitoring_ports", []string{})
+
+	t.Run("port not monitored", func(t *testing.T) {
+		config.Datadog.Set("network_config.dns_monitoring_ports", []string{})
+
+		out := new(model.Connection)
+		newDNSFormatter(payload, make(ipCache)).FormatConnectionDNS(payload.Conns[0], out)
+		assert.Nil(t, out.DnsCountByRcode)
+		assert.Nil(t, out.DnsStatsByDomain)
+	})
+
+	t.Run("port monitored", func(t *testing.T) {
+		config.Datadog.Set("network_config.dns_monitoring_ports", []string{"5353"})
+
+		out := new(model.Connection)
+		newDNSFormatter(payload, make(ipCache)).FormatConnectionDNS(payload.Conns[0], out)
+		assert.Equal(t, uint32(3), out.DnsSuccessfulResponses)
+		assert.Equal(t, uint32(1), out.DnsFailedResponses)
+		assert.Equal(t, uint32(2), out.DnsTimeouts)
+		assert.Equal(t, uint64(3300), out.DnsSuccessLatencySum)
+		assert.Equal(t, uint64(5000), out.DnsFailureLatencySum)
+
+		require.Len(t, out.DnsStatsByDomain, 1)
+		for _, stats := range out.DnsStatsByDomain {
+			assert.Equal(t, uint64(3300), stats.DnsSuccessLatencySum)
+			assert.Equal(t, uint64(5000), stats.DnsFailureLatencySum)
+			assert.Equal(t, uint32(2), stats.DnsTimeouts)
+		}
+	})
+}
@@ -200,10 +200,8 @@ func TestSerialization(t *testing.T) {
 			}: map[dns.Hostname]map[dns.QueryType]dns.Stats{
 				dns.ToHostname("foo.com"): {
 					dns.TypeA: {
-						Timeouts:          0,
-						SuccessLatencySum: 0,
-						FailureLatencySum: 0,
-						CountByRcode:      map[uint32]uint32{0: 1},
+						Timeouts:     0,
+						CountByRcode: map[uint32]uint32{0: 1},
 					},
 				},
 			},
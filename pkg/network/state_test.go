@@ -1278,7 +1278,7 @@ func TestDNSStatsWithMultipleClients(t *testing.T) {
 	state := newDefaultState()
 
 	getRCodeFrom := func(delta Delta, c ConnectionStats, domain string, qtype dns.QueryType, code int) uint32 {
-		key, _ := DNSKey(&c)
+		key, _ := DNSKey(&c, map[uint16]struct{}{53: {}})
 		stats, ok := delta.DNSStats[key]
 		require.Truef(t, ok, "couldn't find DNSStats for connection: %+v", c)
 
@@ -1323,6 +1323,50 @@ func TestDNSStatsWithMultipleClients(t *testing.T) {
 	assert.EqualValues(t, 3, rcode)
 }
 
+func TestDNSLatenciesWithMultipleClients(t *testing.T) {
+	c := ConnectionStats{
+		Source: util.AddressFromString("10.1.1.1"),
+		Dest:   util.AddressFromString("10.2.2.2"),
+		SPort:  1000,
+		DPort:  53,
+	}
+
+	dKey := dns.Key{ClientIP: c.Source, ClientPort: c.SPort, ServerIP: c.Dest, Protocol: getIPProtocol(c.Type)}
+	d := dns.ToHostname("foo.com")
+
+	getStats := func(latency uint64) dns.StatsByKeyByNameByType {
+		return dns.StatsByKeyByNameByType{
+			dKey: {
+				d: {
+					dns.TypeA: dns.Stats{
+						CountByRcode:      map[uint32]uint32{uint32(DNSResponseCodeNoError): 1},
+						SuccessLatencySum: latency,
+					},
+				},
+			},
+		}
+	}
+
+	client1 := "client1"
+	client2 := "client2"
+	state := newDefaultState()
+	state.RegisterClient(client1)
+	state.RegisterClient(client2)
+
+	c.LastUpdateEpoch = latestEpochTime()
+	first := getStats(1000)
+	delta := state.GetDelta(client1, latestEpochTime(), []ConnectionStats{c}, first, nil)
+	assert.Equal(t, uint64(1000), delta.DNSStats[dKey][d][dns.TypeA].SuccessLatencySum)
+
+	delta = state.GetDelta(client1, latestEpochTime(), []ConnectionStats{c}, getStats(2000), nil)
+	assert.Equal(t, uint64(2000), delta.DNSStats[dKey][d][dns.TypeA].SuccessLatencySum)
+
+	// the latencies of the 2nd client are accumulated without modifying the ones returned to the 1st one
+	delta = state.GetDelta(client2, latestEpochTime(), []ConnectionStats{c}, nil, nil)
+	assert.Equal(t, uint64(3000), delta.DNSStats[dKey][d][dns.TypeA].SuccessLatencySum)
+	assert.Equal(t, uint64(1000), first[dKey][d][dns.TypeA].SuccessLatencySum)
+}
+
 func TestClosedMergingWithAddressColision(t *testing.T) {
 	const client = "foo"
 
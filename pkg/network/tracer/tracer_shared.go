@@ -13,9 +13,9 @@ import (
 )
 
 // shouldSkipConnection returns whether or not the tracer should ignore a given connection:
-//  • Local DNS (*:53) requests if configured (default: true)
+//  • Local DNS (*:53 and the other monitored DNS ports) requests if configured (default: true)
 func (t *Tracer) shouldSkipConnection(conn *network.ConnectionStats) bool {
-	isDNSConnection := conn.DPort == 53 || conn.SPort == 53
+	isDNSConnection := t.config.IsDNSPort(conn.DPort) || t.config.IsDNSPort(conn.SPort)
 	if !t.config.CollectLocalDNS && isDNSConnection && conn.Dest.IsLoopback() {
 		return true
 	} else if network.IsExcludedConnection(t.sourceExcludes, t.destExcludes, conn) {
@@ -782,6 +782,28 @@ func TestSkipConnectionDNS(t *testing.T) {
 		}))
 
 	})
+
+	t.Run("DNS monitoring ports", func(t *testing.T) {
+		tr := &Tracer{config: &config.Config{CollectLocalDNS: false, DNSMonitoringPorts: []uint16{5353}}}
+
+		assert.True(t, tr.shouldSkipConnection(&network.ConnectionStats{
+			Source: util.AddressFromString("10.0.0.1"),
+			Dest:   util.AddressFromString("127.0.0.1"),
+			SPort:  1000, DPort: 53,
+		}))
+
+		assert.True(t, tr.shouldSkipConnection(&network.ConnectionStats{
+			Source: util.AddressFromString("10.0.0.1"),
+			Dest:   util.AddressFromString("127.0.0.1"),
+			SPort:  1000, DPort: 5353,
+		}))
+
+		assert.False(t, tr.shouldSkipConnection(&network.ConnectionStats{
+			Source: util.AddressFromString("10.0.0.1"),
+			Dest:   util.AddressFromString("127.0.0.1"),
+			SPort:  1000, DPort: 8053,
+		}))
+	})
 }
 
 func byAddress(l, r net.Addr) func(c network.ConnectionStats) bool {
@@ -1144,7 +1166,7 @@ func testDNSStats(t *testing.T, domain string, success int, failure int, timeout
 	assert.Equal(t, os.Getpid(), int(conn.Pid))
 	assert.Equal(t, dnsServerAddr.Port, int(conn.DPort))
 
-	dnsKey, ok := network.DNSKey(conn)
+	dnsKey, ok := network.DNSKey(conn, map[uint16]struct{}{53: {}})
 	require.True(t, ok)
 
 	dnsStats, ok := connections.DNSStats[dnsKey]
@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+)
+
+const trNS = "traceroute_config"
+
+// Protocol is the protocol of the probes sent by the traceroutes
+type Protocol string
+
+const (
+	// ProtocolUDP sends UDP datagrams to high ports, the destination answers with an ICMP port unreachable
+	ProtocolUDP Protocol = "udp"
+	// ProtocolTCP sends TCP SYN segments, the destination answers with a SYN-ACK or a RST
+	ProtocolTCP Protocol = "tcp"
+	// ProtocolICMP sends ICMP echo requests, the destination answers with an ICMP echo reply
+	ProtocolICMP Protocol = "icmp"
+)
+
+// Config stores the traceroute settings
+type Config struct {
+	// Protocol is the default protocol of the probes
+	Protocol Protocol
+	// MaxTTL is the maximum number of hops probed
+	MaxTTL int
+	// Timeout is the time to wait for the answer to a probe
+	Timeout time.Duration
+
+	// Interval is the interval of the periodic traceroutes of the top destinations, they are disabled when 0
+	Interval time.Duration
+	// MaxDestinations is the number of top destinations traced at each interval
+	MaxDestinations int
+	// MaxConcurrentTraceroutes is the maximum number of traceroutes running at the same time
+	MaxConcurrentTraceroutes int
+
+	// ReverseDNS enables the resolution of the hostnames of the hops
+	ReverseDNS bool
+	// ASNDatabasePath is the path of a MaxMind ASN database used to add the autonomous system of the hops
+	ASNDatabasePath string
+}
+
+// NewConfig creates a config for the traceroute module
+func NewConfig() *Config {
+	cfg := ddconfig.Datadog
+	ddconfig.InitSystemProbeConfig(cfg)
+
+	return &Config{
+		Protocol: Protocol(strings.ToLower(cfg.GetString(join(trNS, "protocol")))),
+		MaxTTL:   cfg.GetInt(join(trNS, "max_ttl")),
+		Timeout:  cfg.GetDuration(join(trNS, "timeout")),
+
+		Interval:                 cfg.GetDuration(join(trNS, "interval")),
+		MaxDestinations:          cfg.GetInt(join(trNS, "max_destinations")),
+		MaxConcurrentTraceroutes: cfg.GetInt(join(trNS, "max_concurrent_traceroutes")),
+
+		ReverseDNS:      cfg.GetBool(join(trNS, "reverse_dns")),
+		ASNDatabasePath: cfg.GetString(join(trNS, "asn_database_path")),
+	}
+}
+
+// Validate checks the settings of the config
+func (c *Config) Validate() error {
+	if err := c.Protocol.Validate(); err != nil {
+		return err
+	}
+	if c.MaxTTL <= 0 || c.MaxTTL > 255 {
+		return fmt.Errorf("invalid max_ttl %d, it must be between 1 and 255", c.MaxTTL)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("invalid timeout %s, it must be positive", c.Timeout)
+	}
+	if c.MaxConcurrentTraceroutes <= 0 {
+		return fmt.Errorf("invalid max_concurrent_traceroutes %d, it must be positive", c.MaxConcurrentTraceroutes)
+	}
+	return nil
+}
+
+// Validate checks that the protocol is supported
+func (p Protocol) Validate() error {
+	switch p {
+	case ProtocolUDP, ProtocolTCP, ProtocolICMP:
+		return nil
+	}
+	return fmt.Errorf("invalid protocol `%s`, valid protocols are: udp, tcp, icmp", p)
+}
+
+func join(pieces ...string) string {
+	return strings.Join(pieces, ".")
+}
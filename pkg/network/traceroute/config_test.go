@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestNewConfig(t *testing.T) {
+	ddconfig.Datadog = ddconfig.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	ddconfig.InitConfig(ddconfig.Datadog)
+	t.Setenv("DD_TRACEROUTE_CONFIG_PROTOCOL", "TCP")
+
+	config := NewConfig()
+	assert.Equal(t, &Config{
+		Protocol:                 ProtocolTCP,
+		MaxTTL:                   30,
+		Timeout:                  time.Second,
+		Interval:                 5 * time.Minute,
+		MaxDestinations:          10,
+		MaxConcurrentTraceroutes: 4,
+		ReverseDNS:               true,
+	}, config)
+	assert.NoError(t, config.Validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	config := &Config{Protocol: ProtocolUDP, MaxTTL: 30, Timeout: time.Second, MaxConcurrentTraceroutes: 1}
+	assert.NoError(t, config.Validate())
+
+	config.Protocol = "sctp"
+	assert.EqualError(t, config.Validate(), "invalid protocol `sctp`, valid protocols are: udp, tcp, icmp")
+	config.Protocol = ProtocolICMP
+	config.MaxTTL = 256
+	assert.EqualError(t, config.Validate(), "invalid max_ttl 256, it must be between 1 and 255")
+	config.MaxTTL = 30
+	config.Timeout = 0
+	assert.EqualError(t, config.Validate(), "invalid timeout 0s, it must be positive")
+}
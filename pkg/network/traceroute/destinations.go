@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"net"
+	"sort"
+	"sync"
+)
+
+// maxTrackedDestinations bounds the number of destinations tracked between two periodic traceroutes
+const maxTrackedDestinations = 10000
+
+// Destination is the target of a traceroute
+type Destination struct {
+	IP string `json:"ip"`
+	// Port is the destination port of the TCP probes
+	Port uint16 `json:"port,omitempty"`
+	// Hostname is set when the traceroute was requested for a hostname
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// DestinationTracker accumulates the traffic sent to the destinations seen in the connection stats,
+// so that the periodic traceroutes target the top destinations
+type DestinationTracker struct {
+	mu      sync.Mutex
+	enabled bool
+	bytes   map[Destination]uint64
+}
+
+var defaultDestinationTracker = NewDestinationTracker()
+
+// GetDefaultDestinationTracker returns the tracker fed by the network tracer
+func GetDefaultDestinationTracker() *DestinationTracker {
+	return defaultDestinationTracker
+}
+
+// NewDestinationTracker creates a disabled DestinationTracker
+func NewDestinationTracker() *DestinationTracker {
+	return &DestinationTracker{bytes: make(map[Destination]uint64)}
+}
+
+// Enable starts tracking the observed destinations, they are ignored until the tracker is enabled
+func (d *DestinationTracker) Enable() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.enabled = true
+}
+
+// IsEnabled returns true if the tracker is enabled
+func (d *DestinationTracker) IsEnabled() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.enabled
+}
+
+// Observe adds the bytes sent and received with a destination
+func (d *DestinationTracker) Observe(ip net.IP, port uint16, bytes uint64) {
+	if len(ip) == 0 || ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() || ip.IsLinkLocalUnicast() || ip.To4() == nil {
+		return
+	}
+	dest := Destination{IP: ip.String(), Port: port}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.enabled {
+		return
+	}
+	if _, ok := d.bytes[dest]; !ok && len(d.bytes) >= maxTrackedDestinations {
+		return
+	}
+	d.bytes[dest] += bytes
+}
+
+// Top returns the n destinations with the most traffic since the last call and resets the tracker
+func (d *DestinationTracker) Top(n int) []Destination {
+	d.mu.Lock()
+	bytes := d.bytes
+	d.bytes = make(map[Destination]uint64)
+	d.mu.Unlock()
+
+	destinations := make([]Destination, 0, len(bytes))
+	for dest := range bytes {
+		destinations = append(destinations, dest)
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		if bytes[destinations[i]] != bytes[destinations[j]] {
+			return bytes[destinations[i]] > bytes[destinations[j]]
+		}
+		if destinations[i].IP != destinations[j].IP {
+			return destinations[i].IP < destinations[j].IP
+		}
+		return destinations[i].Port < destinations[j].Port
+	})
+	if len(destinations) > n {
+		destinations = destinations[:n]
+	}
+	return destinations
+}
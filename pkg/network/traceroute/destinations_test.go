@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDestinationTracker(t *testing.T) {
+	tracker := NewDestinationTracker()
+	assert.False(t, tracker.IsEnabled())
+
+	// destinations are ignored until the tracker is enabled
+	tracker.Observe(net.ParseIP("10.0.0.1"), 443, 100)
+	assert.Empty(t, tracker.Top(10))
+
+	tracker.Enable()
+	assert.True(t, tracker.IsEnabled())
+	tracker.Observe(net.ParseIP("10.0.0.1"), 443, 100)
+	tracker.Observe(net.ParseIP("10.0.0.2"), 5432, 500)
+	tracker.Observe(net.ParseIP("10.0.0.1"), 443, 1000)
+	tracker.Observe(net.ParseIP("10.0.0.3"), 80, 10)
+	tracker.Observe(net.ParseIP("127.0.0.1"), 8126, 100000)
+	tracker.Observe(net.ParseIP("fe80::1"), 80, 100000)
+	tracker.Observe(net.ParseIP("2001:db8::1"), 80, 100000)
+
+	assert.Equal(t, []Destination{
+		{IP: "10.0.0.1", Port: 443},
+		{IP: "10.0.0.2", Port: 5432},
+	}, tracker.Top(2))
+
+	// the tracker is reset by Top
+	assert.Empty(t, tracker.Top(2))
+}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/geoip"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	reverseDNSTimeout = 500 * time.Millisecond
+	// maxCachedHostnames bounds the cache of the reverse DNS lookups, it's reset when full
+	maxCachedHostnames = 10000
+	// asnDatabaseReloadInterval is the interval between the checks for updates of the ASN database
+	asnDatabaseReloadInterval = time.Minute
+)
+
+// hopEnricher adds the hostname and the autonomous system of the hops
+type hopEnricher struct {
+	reverseDNS bool
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+	asn        *geoip.Database
+	stopChan   chan struct{}
+
+	mu        sync.Mutex
+	hostnames map[string]string
+}
+
+func newHopEnricher(config *Config) (*hopEnricher, error) {
+	e := &hopEnricher{
+		reverseDNS: config.ReverseDNS,
+		lookupAddr: net.DefaultResolver.LookupAddr,
+		hostnames:  make(map[string]string),
+		stopChan:   make(chan struct{}),
+	}
+	if config.ASNDatabasePath != "" {
+		db, err := geoip.Open(config.ASNDatabasePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open ASN database `%s`: %w", config.ASNDatabasePath, err)
+		}
+		e.asn = db
+		go geoip.ReloadPeriodically(asnDatabaseReloadInterval, e.stopChan, e.asn)
+	}
+	return e, nil
+}
+
+func (e *hopEnricher) enrich(ctx context.Context, hops []Hop) {
+	for i := range hops {
+		hop := &hops[i]
+		ip := net.ParseIP(hop.IP)
+		if ip == nil {
+			continue
+		}
+		if e.asn != nil && !ip.IsPrivate() {
+			if record, err := e.asn.LookupASN(ip); err != nil {
+				log.Debugf("Error looking up %s in ASN database: %s", ip, err)
+			} else {
+				hop.ASN = record.ASN
+				hop.ASOrg = record.ASOrg
+			}
+		}
+		if e.reverseDNS {
+			hop.Hostname = e.hostname(ctx, hop.IP)
+		}
+	}
+}
+
+// hostname returns the hostname of an address, the failed lookups are cached as well
+func (e *hopEnricher) hostname(ctx context.Context, addr string) string {
+	e.mu.Lock()
+	hostname, ok := e.hostnames[addr]
+	e.mu.Unlock()
+	if ok {
+		return hostname
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, reverseDNSTimeout)
+	defer cancel()
+	names, err := e.lookupAddr(lookupCtx, addr)
+	if err != nil {
+		log.Tracef("Reverse DNS lookup of %s failed: %s", addr, err)
+		if ctx.Err() != nil {
+			// the traceroute was canceled, the address can still be resolved later
+			return ""
+		}
+	} else if len(names) > 0 {
+		hostname = strings.TrimSuffix(names[0], ".")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.hostnames) >= maxCachedHostnames {
+		e.hostnames = make(map[string]string)
+	}
+	e.hostnames[addr] = hostname
+	return hostname
+}
+
+func (e *hopEnricher) close() {
+	close(e.stopChan)
+	if e.asn != nil {
+		e.asn.Close()
+	}
+}
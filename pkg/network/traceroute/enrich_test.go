@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/geoip/testutil"
+)
+
+func TestHopEnricher_reverseDNS(t *testing.T) {
+	e, err := newHopEnricher(&Config{ReverseDNS: true})
+	require.NoError(t, err)
+	lookups := 0
+	e.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		lookups++
+		if addr == "10.0.0.1" {
+			return []string{"gateway.example.com."}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	hops := []Hop{{TTL: 1, IP: "10.0.0.1"}, {TTL: 2}, {TTL: 3, IP: "10.0.0.3"}}
+	e.enrich(context.Background(), hops)
+	assert.Equal(t, []Hop{{TTL: 1, IP: "10.0.0.1", Hostname: "gateway.example.com"}, {TTL: 2}, {TTL: 3, IP: "10.0.0.3"}}, hops)
+	assert.Equal(t, 2, lookups)
+
+	// the lookups are cached, including the failed ones
+	e.enrich(context.Background(), []Hop{{TTL: 1, IP: "10.0.0.1"}, {TTL: 3, IP: "10.0.0.3"}})
+	assert.Equal(t, 2, lookups)
+}
+
+func TestHopEnricher_asn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	testutil.WriteMMDB(t, path, "1.2.0.0/16", testutil.ASNRecord(16276, "OVH SAS"))
+
+	e, err := newHopEnricher(&Config{ASNDatabasePath: path})
+	require.NoError(t, err)
+	defer e.close()
+
+	hops := []Hop{{TTL: 1, IP: "10.0.0.1"}, {TTL: 2}, {TTL: 3, IP: "1.2.3.4"}}
+	e.enrich(context.Background(), hops)
+	assert.Equal(t, []Hop{{TTL: 1, IP: "10.0.0.1"}, {TTL: 2}, {TTL: 3, IP: "1.2.3.4", ASN: 16276, ASOrg: "OVH SAS"}}, hops)
+}
+
+func TestHopEnricher_invalidASNDatabase(t *testing.T) {
+	_, err := newHopEnricher(&Config{ASNDatabasePath: "/does/not/exist.mmdb"})
+	assert.Error(t, err)
+}
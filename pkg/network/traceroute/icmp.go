@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	ipProtocolICMP = 1
+	ipProtocolTCP  = 6
+	ipProtocolUDP  = 17
+
+	ipv4HeaderLen    = 20
+	icmpHeaderLen    = 8
+	icmpEchoReply    = 0
+	icmpDestUnreach  = 3
+	icmpEchoRequest  = 8
+	icmpTimeExceeded = 11
+)
+
+// icmpResponse is an ICMP message answering to a probe
+type icmpResponse struct {
+	from net.IP
+	// only one of timeExceeded, echoReply and unreachable is set
+	timeExceeded bool
+	echoReply    bool
+	unreachable  bool
+
+	// probe identifies the probe answered, for errors it's read from the original datagram they quote
+	probe probeID
+}
+
+// probeID identifies a probe: by its ports for UDP and TCP, by its echo identifier and sequence for ICMP
+type probeID struct {
+	protocol int
+	dst      string
+	srcPort  uint16
+	dstPort  uint16
+	echoID   uint16
+	echoSeq  uint16
+}
+
+// stripIPv4Header returns the payload of an IPv4 datagram
+func stripIPv4Header(b []byte) ([]byte, error) {
+	if len(b) < ipv4HeaderLen {
+		return nil, errors.New("datagram too short")
+	}
+	headerLen := int(b[0]&0x0f) * 4
+	if headerLen < ipv4HeaderLen || len(b) < headerLen {
+		return nil, errors.New("invalid datagram header length")
+	}
+	return b[headerLen:], nil
+}
+
+// parseICMPResponse parses an ICMPv4 message received from a router or from the destination
+// See: https://tools.ietf.org/html/rfc792
+func parseICMPResponse(b []byte, from net.IP) (icmpResponse, error) {
+	response := icmpResponse{from: from}
+	if len(b) < icmpHeaderLen {
+		return response, errors.New("ICMP message too short")
+	}
+
+	switch b[0] {
+	case icmpEchoReply:
+		response.echoReply = true
+		response.probe = probeID{
+			protocol: ipProtocolICMP,
+			dst:      from.String(),
+			echoID:   binary.BigEndian.Uint16(b[4:6]),
+			echoSeq:  binary.BigEndian.Uint16(b[6:8]),
+		}
+		return response, nil
+	case icmpTimeExceeded:
+		response.timeExceeded = true
+	case icmpDestUnreach:
+		response.unreachable = true
+	default:
+		return response, fmt.Errorf("unexpected ICMP message type %d", b[0])
+	}
+
+	var err error
+	response.probe, err = parseQuotedDatagram(b[icmpHeaderLen:])
+	return response, err
+}
+
+// parseQuotedDatagram parses the IPv4 header and the first 8 bytes of the payload of the datagram quoted in ICMP errors
+func parseQuotedDatagram(b []byte) (probeID, error) {
+	var probe probeID
+	payload, err := stripIPv4Header(b)
+	if err != nil {
+		return probe, fmt.Errorf("invalid quoted datagram: %w", err)
+	}
+	if len(payload) < 8 {
+		return probe, errors.New("quoted datagram too short")
+	}
+	probe.protocol = int(b[9])
+	probe.dst = net.IP(b[16:20]).String()
+
+	switch probe.protocol {
+	case ipProtocolUDP, ipProtocolTCP:
+		probe.srcPort = binary.BigEndian.Uint16(payload[0:2])
+		probe.dstPort = binary.BigEndian.Uint16(payload[2:4])
+	case ipProtocolICMP:
+		probe.echoID = binary.BigEndian.Uint16(payload[4:6])
+		probe.echoSeq = binary.BigEndian.Uint16(payload[6:8])
+	default:
+		return probe, fmt.Errorf("unexpected quoted protocol %d", probe.protocol)
+	}
+	return probe, nil
+}
+
+// marshalEchoRequest builds an ICMP echo request
+func marshalEchoRequest(id uint16, seq uint16, data []byte) []byte {
+	b := make([]byte, icmpHeaderLen+len(data))
+	b[0] = icmpEchoRequest
+	binary.BigEndian.PutUint16(b[4:6], id)
+	binary.BigEndian.PutUint16(b[6:8], seq)
+	copy(b[icmpHeaderLen:], data)
+	binary.BigEndian.PutUint16(b[2:4], checksum(b))
+	return b
+}
+
+// checksum computes the internet checksum
+// See: https://tools.ietf.org/html/rfc1071
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
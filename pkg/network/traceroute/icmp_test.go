@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotedDatagram builds the IPv4 header and the first 8 bytes of the payload of a probe, as quoted in ICMP errors
+func quotedDatagram(protocol byte, dst string, payload []byte) []byte {
+	b := make([]byte, ipv4HeaderLen, ipv4HeaderLen+len(payload))
+	b[0] = 0x45
+	b[9] = protocol
+	copy(b[12:16], net.ParseIP("10.0.0.1").To4())
+	copy(b[16:20], net.ParseIP(dst).To4())
+	return append(b, payload...)
+}
+
+func icmpError(icmpType byte, quoted []byte) []byte {
+	return append([]byte{icmpType, 0, 0, 0, 0, 0, 0, 0}, quoted...)
+}
+
+func ports(src, dst uint16) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint16(b[0:2], src)
+	binary.BigEndian.PutUint16(b[2:4], dst)
+	return b
+}
+
+func TestParseICMPResponse(t *testing.T) {
+	router := net.ParseIP("192.168.1.1")
+	dest := net.ParseIP("8.8.8.8")
+
+	response, err := parseICMPResponse(icmpError(icmpTimeExceeded, quotedDatagram(ipProtocolUDP, "8.8.8.8", ports(45000, 33435))), router)
+	require.NoError(t, err)
+	assert.True(t, response.timeExceeded)
+	assert.Equal(t, probeID{protocol: ipProtocolUDP, dst: "8.8.8.8", srcPort: 45000, dstPort: 33435}, response.probe)
+
+	response, err = parseICMPResponse(icmpError(icmpDestUnreach, quotedDatagram(ipProtocolTCP, "8.8.8.8", ports(51000, 443))), dest)
+	require.NoError(t, err)
+	assert.True(t, response.unreachable)
+	assert.Equal(t, probeID{protocol: ipProtocolTCP, dst: "8.8.8.8", srcPort: 51000, dstPort: 443}, response.probe)
+
+	echoRequest := marshalEchoRequest(0x1234, 3, probePayload)
+	response, err = parseICMPResponse(icmpError(icmpTimeExceeded, quotedDatagram(ipProtocolICMP, "8.8.8.8", echoRequest[:8])), router)
+	require.NoError(t, err)
+	assert.Equal(t, probeID{protocol: ipProtocolICMP, dst: "8.8.8.8", echoID: 0x1234, echoSeq: 3}, response.probe)
+
+	echoReply := marshalEchoRequest(0x1234, 7, probePayload)
+	echoReply[0] = icmpEchoReply
+	response, err = parseICMPResponse(echoReply, dest)
+	require.NoError(t, err)
+	assert.True(t, response.echoReply)
+	assert.Equal(t, probeID{protocol: ipProtocolICMP, dst: "8.8.8.8", echoID: 0x1234, echoSeq: 7}, response.probe)
+}
+
+func TestParseICMPResponse_invalid(t *testing.T) {
+	from := net.ParseIP("192.168.1.1")
+
+	_, err := parseICMPResponse([]byte{icmpTimeExceeded, 0}, from)
+	assert.EqualError(t, err, "ICMP message too short")
+
+	_, err = parseICMPResponse(icmpError(5, nil), from)
+	assert.EqualError(t, err, "unexpected ICMP message type 5")
+
+	_, err = parseICMPResponse(icmpError(icmpTimeExceeded, quotedDatagram(ipProtocolUDP, "8.8.8.8", []byte{0, 1})), from)
+	assert.EqualError(t, err, "quoted datagram too short")
+
+	_, err = parseICMPResponse(icmpError(icmpTimeExceeded, quotedDatagram(47, "8.8.8.8", ports(1, 2))), from)
+	assert.EqualError(t, err, "unexpected quoted protocol 47")
+}
+
+func TestMarshalEchoRequest(t *testing.T) {
+	b := marshalEchoRequest(0x1234, 1, []byte("abc"))
+	assert.Equal(t, []byte{icmpEchoRequest, 0}, b[:2])
+	assert.Equal(t, []byte{0x12, 0x34, 0, 1, 'a', 'b', 'c'}, b[4:])
+	// the checksum of a message including its checksum is 0
+	assert.Equal(t, uint16(0), checksum(b))
+}
@@ -0,0 +1,29 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+// Path is the payload of a traceroute, it contains the hops between the host and a destination
+type Path struct {
+	// Timestamp is the start of the traceroute, in milliseconds
+	Timestamp   int64       `json:"timestamp"`
+	Protocol    Protocol    `json:"protocol"`
+	Destination Destination `json:"destination"`
+	Hops        []Hop       `json:"hops"`
+	// Reached is true when the destination answered to a probe, it is then the last hop
+	Reached bool `json:"reached"`
+}
+
+// Hop is a router, or the destination, answering to the probe sent with a TTL
+type Hop struct {
+	TTL int `json:"ttl"`
+	// IP is empty when no answer was received before the timeout
+	IP string `json:"ip,omitempty"`
+	// RTTMs is the round-trip time of the probe, in milliseconds
+	RTTMs    float64 `json:"rtt_ms,omitempty"`
+	Hostname string  `json:"hostname,omitempty"`
+	ASN      uint32  `json:"asn,omitempty"`
+	ASOrg    string  `json:"as_org,omitempty"`
+}
@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// maxStoredPaths bounds the number of paths kept until they are flushed, the oldest ones are dropped
+const maxStoredPaths = 1000
+
+// pathTracer runs a traceroute to a destination
+type pathTracer interface {
+	Trace(ctx context.Context, dest Destination, protocol Protocol) (*Path, error)
+}
+
+// Runner periodically traces the paths to the top destinations seen in the connection stats
+type Runner struct {
+	config       *Config
+	tracer       pathTracer
+	destinations *DestinationTracker
+
+	mu    sync.Mutex
+	paths []Path
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	runs    uint64
+	traced  uint64
+	errors  uint64
+	dropped uint64
+}
+
+// NewRunner creates a Runner, it must be started to run the periodic traceroutes
+func NewRunner(config *Config, tracer pathTracer, destinations *DestinationTracker) *Runner {
+	return &Runner{
+		config:       config,
+		tracer:       tracer,
+		destinations: destinations,
+	}
+}
+
+// Start starts the periodic traceroutes, nothing is done if they are disabled
+func (r *Runner) Start() {
+	if r.config.Interval <= 0 || r.config.MaxDestinations <= 0 {
+		log.Info("Periodic traceroutes are disabled")
+		return
+	}
+	r.destinations.Enable()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.run(ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic traceroutes and waits for the running ones
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	<-r.done
+}
+
+// run traces the paths to the top destinations seen since the previous run
+func (r *Runner) run(ctx context.Context) {
+	atomic.AddUint64(&r.runs, 1)
+	destinations := r.destinations.Top(r.config.MaxDestinations)
+	log.Debugf("Tracing the paths to %d destinations", len(destinations))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, r.config.MaxConcurrentTraceroutes)
+	for _, dest := range destinations {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(dest Destination) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			path, err := r.tracer.Trace(ctx, dest, r.config.Protocol)
+			if err != nil {
+				log.Debugf("Unable to trace the path to %s: %s", dest.IP, err)
+				atomic.AddUint64(&r.errors, 1)
+				return
+			}
+			atomic.AddUint64(&r.traced, 1)
+			r.addPath(*path)
+		}(dest)
+	}
+	wg.Wait()
+}
+
+func (r *Runner) addPath(path Path) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.paths) >= maxStoredPaths {
+		r.paths = r.paths[1:]
+		atomic.AddUint64(&r.dropped, 1)
+	}
+	r.paths = append(r.paths, path)
+}
+
+// Flush returns the paths traced since the last flush
+func (r *Runner) Flush() []Path {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	paths := r.paths
+	r.paths = nil
+	return paths
+}
+
+// GetStats returns the stats of the periodic traceroutes
+func (r *Runner) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"runs":          atomic.LoadUint64(&r.runs),
+		"paths_traced":  atomic.LoadUint64(&r.traced),
+		"errors":        atomic.LoadUint64(&r.errors),
+		"paths_dropped": atomic.LoadUint64(&r.dropped),
+	}
+}
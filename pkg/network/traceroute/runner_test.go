@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTracer struct {
+	mu     sync.Mutex
+	traced []Destination
+}
+
+func (m *mockTracer) Trace(ctx context.Context, dest Destination, protocol Protocol) (*Path, error) {
+	m.mu.Lock()
+	m.traced = append(m.traced, dest)
+	m.mu.Unlock()
+	if dest.IP == "10.0.0.3" {
+		return nil, errors.New("unreachable")
+	}
+	return &Path{Protocol: protocol, Destination: dest, Hops: []Hop{{TTL: 1, IP: dest.IP}}, Reached: true}, nil
+}
+
+func TestRunner(t *testing.T) {
+	config := &Config{Protocol: ProtocolTCP, Interval: 10 * time.Millisecond, MaxDestinations: 2, MaxConcurrentTraceroutes: 2}
+	tracker := NewDestinationTracker()
+	tracer := &mockTracer{}
+	runner := NewRunner(config, tracer, tracker)
+
+	runner.Start()
+	defer runner.Stop()
+	tracker.Observe(net.ParseIP("10.0.0.1"), 443, 100)
+	tracker.Observe(net.ParseIP("10.0.0.2"), 80, 50)
+	tracker.Observe(net.ParseIP("10.0.0.3"), 80, 200)
+	tracker.Observe(net.ParseIP("10.0.0.4"), 80, 10)
+
+	assert.Eventually(t, func() bool {
+		return runner.GetStats()["errors"].(uint64) == 1 && runner.GetStats()["paths_traced"].(uint64) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	paths := runner.Flush()
+	assert.Equal(t, []Path{
+		{Protocol: ProtocolTCP, Destination: Destination{IP: "10.0.0.1", Port: 443}, Hops: []Hop{{TTL: 1, IP: "10.0.0.1"}}, Reached: true},
+	}, paths)
+	assert.Empty(t, runner.Flush())
+
+	tracer.mu.Lock()
+	traced := append([]Destination{}, tracer.traced...)
+	tracer.mu.Unlock()
+	sort.Slice(traced, func(i, j int) bool { return traced[i].IP < traced[j].IP })
+	assert.Equal(t, []Destination{{IP: "10.0.0.1", Port: 443}, {IP: "10.0.0.3", Port: 80}}, traced)
+}
+
+func TestRunner_disabled(t *testing.T) {
+	tracker := NewDestinationTracker()
+	runner := NewRunner(&Config{MaxDestinations: 2, MaxConcurrentTraceroutes: 2}, &mockTracer{}, tracker)
+	runner.Start()
+	runner.Stop()
+
+	tracker.Observe(net.ParseIP("10.0.0.1"), 443, 100)
+	assert.Empty(t, tracker.Top(2))
+}
+
+func TestRunner_maxStoredPaths(t *testing.T) {
+	runner := NewRunner(&Config{}, &mockTracer{}, NewDestinationTracker())
+	for i := 0; i < maxStoredPaths+5; i++ {
+		runner.addPath(Path{Timestamp: int64(i)})
+	}
+	paths := runner.Flush()
+	assert.Len(t, paths, maxStoredPaths)
+	assert.Equal(t, int64(5), paths[0].Timestamp)
+	assert.Equal(t, uint64(5), runner.GetStats()["paths_dropped"])
+}
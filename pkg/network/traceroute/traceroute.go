@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traceroute
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// udpBasePort is the first destination port of the UDP probes, as used by the traceroute command
+	udpBasePort = 33434
+	// defaultTCPPort is the destination port of the TCP probes when the destination has none
+	defaultTCPPort = 80
+)
+
+// Tracer runs traceroutes, the probes are sent and the answers received with raw sockets
+type Tracer struct {
+	config   *Config
+	enricher *hopEnricher
+}
+
+// NewTracer creates a Tracer
+func NewTracer(config *Config) (*Tracer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	enricher, err := newHopEnricher(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracer{config: config, enricher: enricher}, nil
+}
+
+// Trace runs a traceroute to an IPv4 destination, the hops are enriched with their hostname and autonomous system
+func (t *Tracer) Trace(ctx context.Context, dest Destination, protocol Protocol) (*Path, error) {
+	if err := protocol.Validate(); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(dest.IP).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid destination `%s`, only IPv4 destinations are supported", dest.IP)
+	}
+	if protocol == ProtocolTCP && dest.Port == 0 {
+		dest.Port = defaultTCPPort
+	}
+
+	path := &Path{
+		Timestamp:   time.Now().UnixNano() / int64(time.Millisecond),
+		Protocol:    protocol,
+		Destination: dest,
+	}
+	if err := t.trace(ctx, ip, path); err != nil {
+		return nil, err
+	}
+	t.enricher.enrich(ctx, path.Hops)
+	return path, nil
+}
+
+// Close releases the resources of the Tracer
+func (t *Tracer) Close() {
+	t.enricher.close()
+}
@@ -0,0 +1,284 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package traceroute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// tcpPollInterval is the interval at which the TCP probes are checked while waiting for ICMP answers
+const tcpPollInterval = 20 * time.Millisecond
+
+// probePayload is the payload of the UDP and ICMP probes
+var probePayload = []byte("datadog")
+
+// echoIDCounter makes the echo identifiers of the concurrent ICMP traceroutes different
+var echoIDCounter uint32
+
+// prober sends the probes of a traceroute
+type prober interface {
+	// send sends a probe with a TTL and returns its identifier, used to match the ICMP answers
+	send(ttl int) (probeID, error)
+	// reached returns true when the destination answered the last probe out of ICMP, it's the case of the
+	// TCP probes answered with a SYN-ACK or a RST
+	reached() bool
+	close()
+}
+
+func (t *Tracer) trace(ctx context.Context, ip net.IP, path *Path) error {
+	// the raw ICMP socket receives the answers of the routers and the destination to the probes
+	icmpFd, err := unix.Socket(unix.AF_INET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMP)
+	if err != nil {
+		return fmt.Errorf("unable to open raw ICMP socket: %w", err)
+	}
+	defer unix.Close(icmpFd) //nolint:errcheck
+
+	p, err := newProber(path.Protocol, icmpFd, ip, path.Destination.Port)
+	if err != nil {
+		return err
+	}
+	defer p.close()
+
+	buf := make([]byte, 1500)
+	for ttl := 1; ttl <= t.config.MaxTTL; ttl++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hop, done, err := t.probeHop(ctx, icmpFd, p, ip, ttl, buf)
+		if err != nil {
+			return err
+		}
+		path.Hops = append(path.Hops, hop)
+		if hop.IP == ip.String() {
+			path.Reached = true
+		}
+		if done {
+			break
+		}
+	}
+	return nil
+}
+
+// probeHop sends a probe with a TTL and waits for its answer, done is true when no further hop must be probed
+func (t *Tracer) probeHop(ctx context.Context, icmpFd int, p prober, ip net.IP, ttl int, buf []byte) (hop Hop, done bool, err error) {
+	hop = Hop{TTL: ttl}
+	id, err := p.send(ttl)
+	if err != nil {
+		return hop, false, fmt.Errorf("unable to send probe with TTL %d: %w", ttl, err)
+	}
+	start := time.Now()
+	deadline := start.Add(t.config.Timeout)
+
+	for time.Now().Before(deadline) && ctx.Err() == nil {
+		if p.reached() {
+			hop.IP = ip.String()
+			hop.RTTMs = elapsedMs(start)
+			return hop, true, nil
+		}
+
+		readTimeout := time.Until(deadline)
+		if readTimeout > tcpPollInterval {
+			readTimeout = tcpPollInterval
+		}
+		timeout := unix.NsecToTimeval(readTimeout.Nanoseconds())
+		if err := unix.SetsockoptTimeval(icmpFd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+			return hop, false, err
+		}
+		n, from, err := unix.Recvfrom(icmpFd, buf, 0)
+		if err != nil {
+			if errors.Is(err, unix.EAGAIN) || errors.Is(err, unix.EINTR) {
+				continue
+			}
+			return hop, false, fmt.Errorf("error reading ICMP answers: %w", err)
+		}
+		fromAddr, ok := from.(*unix.SockaddrInet4)
+		if !ok {
+			continue
+		}
+		fromIP := net.IP(fromAddr.Addr[:])
+		// raw IPv4 sockets receive the IP header of the datagrams
+		message, err := stripIPv4Header(buf[:n])
+		if err != nil {
+			continue
+		}
+		response, err := parseICMPResponse(message, fromIP)
+		if err != nil || response.probe != id {
+			// ICMP messages unrelated to this traceroute are received as well
+			continue
+		}
+
+		hop.IP = fromIP.String()
+		hop.RTTMs = elapsedMs(start)
+		// the destination answered, or a router reported it's unreachable
+		return hop, response.echoReply || response.unreachable, nil
+	}
+	return hop, false, nil
+}
+
+func elapsedMs(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
+
+func newProber(protocol Protocol, icmpFd int, ip net.IP, port uint16) (prober, error) {
+	addr := unix.SockaddrInet4{}
+	copy(addr.Addr[:], ip)
+
+	switch protocol {
+	case ProtocolUDP:
+		fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open UDP socket: %w", err)
+		}
+		p := &udpProber{fd: fd, addr: addr}
+		if err := unix.Bind(fd, &unix.SockaddrInet4{}); err != nil {
+			p.close()
+			return nil, fmt.Errorf("unable to bind UDP socket: %w", err)
+		}
+		if p.srcPort, err = localPort(fd); err != nil {
+			p.close()
+			return nil, err
+		}
+		return p, nil
+	case ProtocolICMP:
+		echoID := uint16(os.Getpid()) + uint16(atomic.AddUint32(&echoIDCounter, 1))
+		return &icmpProber{fd: icmpFd, addr: addr, echoID: echoID}, nil
+	case ProtocolTCP:
+		addr.Port = int(port)
+		return &tcpProber{addr: addr, fd: -1}, nil
+	}
+	return nil, protocol.Validate()
+}
+
+func localPort(fd int) (uint16, error) {
+	local, err := unix.Getsockname(fd)
+	if err != nil {
+		return 0, err
+	}
+	localAddr, ok := local.(*unix.SockaddrInet4)
+	if !ok {
+		return 0, errors.New("unexpected local address type")
+	}
+	return uint16(localAddr.Port), nil
+}
+
+// udpProber sends UDP datagrams to a different port for each TTL
+type udpProber struct {
+	fd      int
+	addr    unix.SockaddrInet4
+	srcPort uint16
+}
+
+func (p *udpProber) send(ttl int) (probeID, error) {
+	if err := unix.SetsockoptInt(p.fd, unix.IPPROTO_IP, unix.IP_TTL, ttl); err != nil {
+		return probeID{}, err
+	}
+	addr := p.addr
+	addr.Port = udpBasePort + ttl
+	if err := unix.Sendto(p.fd, probePayload, 0, &addr); err != nil {
+		return probeID{}, err
+	}
+	return probeID{protocol: ipProtocolUDP, dst: net.IP(addr.Addr[:]).String(), srcPort: p.srcPort, dstPort: uint16(addr.Port)}, nil
+}
+
+func (p *udpProber) reached() bool {
+	return false
+}
+
+func (p *udpProber) close() {
+	unix.Close(p.fd) //nolint:errcheck
+}
+
+// icmpProber sends ICMP echo requests with the TTL as sequence number
+type icmpProber struct {
+	fd     int
+	addr   unix.SockaddrInet4
+	echoID uint16
+}
+
+func (p *icmpProber) send(ttl int) (probeID, error) {
+	if err := unix.SetsockoptInt(p.fd, unix.IPPROTO_IP, unix.IP_TTL, ttl); err != nil {
+		return probeID{}, err
+	}
+	if err := unix.Sendto(p.fd, marshalEchoRequest(p.echoID, uint16(ttl), probePayload), 0, &p.addr); err != nil {
+		return probeID{}, err
+	}
+	return probeID{protocol: ipProtocolICMP, dst: net.IP(p.addr.Addr[:]).String(), echoID: p.echoID, echoSeq: uint16(ttl)}, nil
+}
+
+func (p *icmpProber) reached() bool {
+	return false
+}
+
+// close does nothing as the raw ICMP socket is owned by the traceroute
+func (p *icmpProber) close() {}
+
+// tcpProber opens a non-blocking TCP connection for each TTL, the kernel sends the SYN segment
+type tcpProber struct {
+	addr unix.SockaddrInet4
+	fd   int
+	// refused is set when the connection is refused synchronously, which happens with local destinations
+	refused bool
+}
+
+func (p *tcpProber) send(ttl int) (probeID, error) {
+	p.close()
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_STREAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return probeID{}, err
+	}
+	p.fd = fd
+	if err := unix.SetsockoptInt(fd, unix.IPPROTO_IP, unix.IP_TTL, ttl); err != nil {
+		return probeID{}, err
+	}
+	if err := unix.Connect(fd, &p.addr); errors.Is(err, unix.ECONNREFUSED) {
+		p.refused = true
+	} else if err != nil && !errors.Is(err, unix.EINPROGRESS) {
+		return probeID{}, err
+	}
+	srcPort, err := localPort(fd)
+	if err != nil {
+		return probeID{}, err
+	}
+	return probeID{protocol: ipProtocolTCP, dst: net.IP(p.addr.Addr[:]).String(), srcPort: srcPort, dstPort: uint16(p.addr.Port)}, nil
+}
+
+// reached returns true when the connection is established or refused by the destination
+func (p *tcpProber) reached() bool {
+	if p.refused {
+		return true
+	}
+	if p.fd < 0 {
+		return false
+	}
+	fds := []unix.PollFd{{Fd: int32(p.fd), Events: unix.POLLOUT}}
+	if n, err := unix.Poll(fds, 0); err != nil || n == 0 {
+		return false
+	}
+	soErr, err := unix.GetsockoptInt(p.fd, unix.SOL_SOCKET, unix.SO_ERROR)
+	if err != nil {
+		return false
+	}
+	return soErr == 0 || unix.Errno(soErr) == unix.ECONNREFUSED
+}
+
+func (p *tcpProber) close() {
+	if p.fd >= 0 {
+		unix.Close(p.fd) //nolint:errcheck
+		p.fd = -1
+	}
+	p.refused = false
+}
@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !linux
+// +build !linux
+
+package traceroute
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+func (t *Tracer) trace(ctx context.Context, ip net.IP, path *Path) error {
+	return errors.New("traceroute is only supported on linux")
+}
@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package net
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/DataDog/datadog-agent/pkg/network/traceroute"
+)
+
+const (
+	traceroutePathsURL = "http://unix/traceroute/paths"
+)
+
+// GetTraceroutePaths returns the paths traced periodically by the traceroute module since the last call
+func (r *RemoteSysProbeUtil) GetTraceroutePaths() ([]traceroute.Path, error) {
+	req, err := http.NewRequest("GET", traceroutePathsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("traceroute paths request failed: socket %s, url %s, status code: %d", r.path, traceroutePathsURL, resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []traceroute.Path
+	if err := json.Unmarshal(body, &paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
@@ -0,0 +1,413 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb"
+)
+
+// Package types, used as purl types
+const (
+	PackageTypeDeb  = "deb"
+	PackageTypeAPK  = "apk"
+	PackageTypeRPM  = "rpm"
+	PackageTypeNPM  = "npm"
+	PackageTypePyPI = "pypi"
+	PackageTypeGem  = "gem"
+)
+
+const (
+	dpkgStatusFile = "/var/lib/dpkg/status"
+	apkDBFile      = "/lib/apk/db/installed"
+	osReleaseFile  = "/etc/os-release"
+)
+
+// rpmDBFiles maps the rpm database files to their parser, the sqlite database is used since rpm 4.16 and the
+// database moved to /usr/lib/sysimage/rpm on recent distributions
+var rpmDBFiles = []struct {
+	path  string
+	parse func([]byte) ([]rpmdb.Package, error)
+}{
+	{"/var/lib/rpm/Packages", rpmdb.ParseBerkeleyDB},
+	{"/var/lib/rpm/rpmdb.sqlite", rpmdb.ParseSqlite},
+	{"/usr/lib/sysimage/rpm/Packages", rpmdb.ParseBerkeleyDB},
+	{"/usr/lib/sysimage/rpm/rpmdb.sqlite", rpmdb.ParseSqlite},
+}
+
+// lockfileParsers maps lockfile base names to their parser
+var lockfileParsers = map[string]func([]byte) ([]Package, error){
+	"package-lock.json": parseNPMLock,
+	"Pipfile.lock":      parsePipfileLock,
+	"Gemfile.lock":      parseGemfileLock,
+}
+
+// Package is a software package found in an image
+type Package struct {
+	Type    string
+	Name    string
+	Version string
+	Arch    string
+	// Path is the file the package was found in
+	Path string
+}
+
+// Distro identifies the Linux distribution of an image
+type Distro struct {
+	ID        string
+	VersionID string
+}
+
+// PURL returns the package URL of the package, see https://github.com/package-url/purl-spec
+func (p Package) PURL(distro Distro) string {
+	namespace, name := "", p.Name
+	switch p.Type {
+	case PackageTypeDeb, PackageTypeAPK, PackageTypeRPM:
+		namespace = distro.ID
+	case PackageTypeNPM:
+		// Scoped npm packages use their scope as namespace
+		if scope, scopedName, found := cut(p.Name, "/"); found {
+			namespace, name = scope, scopedName
+		}
+	}
+
+	purl := "pkg:" + p.Type + "/"
+	if namespace != "" {
+		purl += escapePURLSegment(namespace) + "/"
+	}
+	purl += escapePURLSegment(name)
+
+	version, epoch := p.Version, ""
+	if p.Type == PackageTypeRPM {
+		// The epoch of rpm packages is a qualifier
+		if e, v, found := cut(version, ":"); found {
+			version, epoch = v, e
+		}
+	}
+	if version != "" {
+		purl += "@" + escapePURLSegment(version)
+	}
+
+	var qualifiers []string
+	if p.Arch != "" {
+		qualifiers = append(qualifiers, "arch="+url.QueryEscape(p.Arch))
+	}
+	if namespace != "" && namespace == distro.ID && distro.VersionID != "" {
+		qualifiers = append(qualifiers, "distro="+url.QueryEscape(distro.ID+"-"+distro.VersionID))
+	}
+	if epoch != "" {
+		qualifiers = append(qualifiers, "epoch="+url.QueryEscape(epoch))
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + strings.Join(qualifiers, "&")
+	}
+
+	return purl
+}
+
+func escapePURLSegment(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
+
+// Analyze returns the distribution and the packages found in the image filesystem
+func Analyze(fs *LayeredFS) (Distro, []Package, error) {
+	var packages []Package
+
+	distro := Distro{}
+	if content, err := fs.ReadFile(osReleaseFile); err == nil {
+		distro = parseOSRelease(content)
+	}
+
+	if content, err := fs.ReadFile(dpkgStatusFile); err == nil {
+		packages = append(packages, parseDpkgStatus(content)...)
+	}
+
+	if content, err := fs.ReadFile(apkDBFile); err == nil {
+		packages = append(packages, parseAPKInstalled(content)...)
+	}
+
+	for _, dbFile := range rpmDBFiles {
+		if content, err := fs.ReadFile(dbFile.path); err == nil {
+			packages = append(packages, parseRPMDatabase(dbFile.path, content, dbFile.parse)...)
+		}
+	}
+
+	err := fs.Walk(isLockfile, func(name string, content []byte) error {
+		pkgs, err := lockfileParsers[path.Base(name)](content)
+		if err != nil {
+			log.Debugf("Unable to parse lockfile %s: %v", name, err)
+			return nil
+		}
+
+		for i := range pkgs {
+			pkgs[i].Path = name
+		}
+		packages = append(packages, pkgs...)
+		return nil
+	})
+
+	return distro, packages, err
+}
+
+func isLockfile(name string) bool {
+	_, found := lockfileParsers[path.Base(name)]
+	return found && !strings.Contains(name, "/node_modules/")
+}
+
+func parseOSRelease(content []byte) Distro {
+	distro := Distro{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			distro.ID = value
+		case "VERSION_ID":
+			distro.VersionID = value
+		}
+	}
+
+	return distro
+}
+
+// parseDpkgStatus returns installed packages from the dpkg status database
+func parseDpkgStatus(content []byte) []Package {
+	var packages []Package
+
+	for _, paragraph := range bytes.Split(content, []byte("\n\n")) {
+		fields := make(map[string]string)
+
+		scanner := bufio.NewScanner(bytes.NewReader(paragraph))
+		scanner.Buffer(make([]byte, 64*1024), len(paragraph)+1)
+		for scanner.Scan() {
+			line := scanner.Text()
+			// Continuation lines of multi-line fields start with a space
+			if line == "" || line[0] == ' ' || line[0] == '\t' {
+				continue
+			}
+			if key, value, found := cut(line, ":"); found {
+				fields[key] = strings.TrimSpace(value)
+			}
+		}
+
+		if fields["Package"] == "" || !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+
+		packages = append(packages, Package{
+			Type:    PackageTypeDeb,
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Arch:    fields["Architecture"],
+			Path:    dpkgStatusFile,
+		})
+	}
+
+	return packages
+}
+
+// parseAPKInstalled returns installed packages from the apk database
+func parseAPKInstalled(content []byte) []Package {
+	var packages []Package
+	current := Package{Type: PackageTypeAPK, Path: apkDBFile}
+
+	flush := func() {
+		if current.Name != "" {
+			packages = append(packages, current)
+		}
+		current = Package{Type: PackageTypeAPK, Path: apkDBFile}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+
+		if len(line) < 2 || line[1] != ':' {
+			continue
+		}
+
+		switch line[0] {
+		case 'P':
+			current.Name = line[2:]
+		case 'V':
+			current.Version = line[2:]
+		case 'A':
+			current.Arch = line[2:]
+		}
+	}
+	flush()
+
+	return packages
+}
+
+// parseRPMDatabase returns installed packages from a rpm database, the version of the packages is formatted as
+// [epoch:]version-release
+func parseRPMDatabase(dbFile string, content []byte, parse func([]byte) ([]rpmdb.Package, error)) []Package {
+	rpmPackages, err := parse(content)
+	if err != nil {
+		log.Debugf("Unable to parse rpm database %s: %v", dbFile, err)
+		return nil
+	}
+
+	packages := make([]Package, 0, len(rpmPackages))
+	for _, pkg := range rpmPackages {
+		// The gpg-pubkey packages hold the keys imported in the rpm database
+		if pkg.Name == "gpg-pubkey" {
+			continue
+		}
+		packages = append(packages, Package{
+			Type:    PackageTypeRPM,
+			Name:    pkg.Name,
+			Version: pkg.FullVersion(),
+			Arch:    pkg.Arch,
+			Path:    dbFile,
+		})
+	}
+
+	return packages
+}
+
+// parseNPMLock returns the dependencies of a package-lock.json file
+func parseNPMLock(content []byte) ([]Package, error) {
+	type npmDependency struct {
+		Version      string                   `json:"version"`
+		Dependencies map[string]npmDependency `json:"dependencies"`
+	}
+	var lock struct {
+		LockfileVersion int                      `json:"lockfileVersion"`
+		Packages        map[string]npmDependency `json:"packages"`
+		Dependencies    map[string]npmDependency `json:"dependencies"`
+	}
+
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	var packages []Package
+
+	// lockfileVersion 2 and later list all packages by their path in node_modules
+	if len(lock.Packages) > 0 {
+		for pkgPath, dep := range lock.Packages {
+			idx := strings.LastIndex(pkgPath, "node_modules/")
+			if idx < 0 || dep.Version == "" {
+				continue
+			}
+			packages = append(packages, Package{
+				Type:    PackageTypeNPM,
+				Name:    pkgPath[idx+len("node_modules/"):],
+				Version: dep.Version,
+			})
+		}
+		return packages, nil
+	}
+
+	var walk func(deps map[string]npmDependency)
+	walk = func(deps map[string]npmDependency) {
+		for name, dep := range deps {
+			packages = append(packages, Package{
+				Type:    PackageTypeNPM,
+				Name:    name,
+				Version: dep.Version,
+			})
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+
+	return packages, nil
+}
+
+// parsePipfileLock returns the default (non-development) dependencies of a Pipfile.lock file
+func parsePipfileLock(content []byte) ([]Package, error) {
+	var lock struct {
+		Default map[string]struct {
+			Version string `json:"version"`
+		} `json:"default"`
+	}
+
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	packages := make([]Package, 0, len(lock.Default))
+	for name, dep := range lock.Default {
+		packages = append(packages, Package{
+			Type:    PackageTypePyPI,
+			Name:    name,
+			Version: strings.TrimPrefix(dep.Version, "=="),
+		})
+	}
+
+	return packages, nil
+}
+
+// parseGemfileLock returns the gems listed in the GEM section of a Gemfile.lock file
+func parseGemfileLock(content []byte) ([]Package, error) {
+	var packages []Package
+	inGemSection, inSpecs := false, false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line != "" && line[0] != ' ' {
+			inGemSection = line == "GEM"
+			inSpecs = false
+			continue
+		}
+		if !inGemSection {
+			continue
+		}
+		if strings.TrimSpace(line) == "specs:" {
+			inSpecs = true
+			continue
+		}
+
+		// Gems are indented by 4 spaces, their dependencies by 6
+		if !inSpecs || !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue
+		}
+
+		name, version, found := cut(strings.TrimSpace(line), " ")
+		if !found {
+			return nil, fmt.Errorf("invalid gem specification %q", line)
+		}
+
+		packages = append(packages, Package{
+			Type:    PackageTypeGem,
+			Name:    name,
+			Version: strings.Trim(version, "()"),
+		})
+	}
+
+	return packages, scanner.Err()
+}
+
+// cut slices s around the first instance of sep
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb"
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb/testutil"
+)
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.31-13+deb11u3
+Description: GNU C Library
+ Contains the standard libraries.
+
+Package: removed-pkg
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.1-2+b3
+`
+
+const apkInstalled = `C:Q1abc=
+P:musl
+V:1.2.3-r0
+A:x86_64
+
+P:busybox
+V:1.35.0-r17
+A:x86_64
+`
+
+const npmLockV1 = `{
+  "lockfileVersion": 1,
+  "dependencies": {
+    "express": {"version": "4.17.1", "dependencies": {"debug": {"version": "2.6.9"}}}
+  }
+}`
+
+const npmLockV2 = `{
+  "lockfileVersion": 2,
+  "packages": {
+    "": {"name": "app", "version": "1.0.0"},
+    "node_modules/@types/node": {"version": "18.0.0"}
+  }
+}`
+
+const pipfileLock = `{"default": {"requests": {"version": "==2.28.1"}}, "develop": {"pytest": {"version": "==7.1.2"}}}`
+
+const gemfileLock = `GEM
+  remote: https://rubygems.org/
+  specs:
+    rack (2.2.4)
+    rails (7.0.3)
+      rack (>= 2.0)
+
+PLATFORMS
+  ruby
+`
+
+func TestParsers(t *testing.T) {
+	assert.Equal(t, []Package{
+		{Type: PackageTypeDeb, Name: "libc6", Version: "2.31-13+deb11u3", Arch: "amd64", Path: dpkgStatusFile},
+		{Type: PackageTypeDeb, Name: "bash", Version: "5.1-2+b3", Arch: "amd64", Path: dpkgStatusFile},
+	}, parseDpkgStatus([]byte(dpkgStatus)))
+
+	assert.Equal(t, []Package{
+		{Type: PackageTypeAPK, Name: "musl", Version: "1.2.3-r0", Arch: "x86_64", Path: apkDBFile},
+		{Type: PackageTypeAPK, Name: "busybox", Version: "1.35.0-r17", Arch: "x86_64", Path: apkDBFile},
+	}, parseAPKInstalled([]byte(apkInstalled)))
+
+	rpmDB := testutil.BerkeleyDB(
+		testutil.Header("bash", "4.2.46", "35.el7_9", "x86_64", 0),
+		testutil.Header("openssl-libs", "1.0.2k", "25.el7_9", "x86_64", 1),
+		testutil.Header("gpg-pubkey", "f4a80eb5", "53a7ff4b", "", 0),
+	)
+	assert.Equal(t, []Package{
+		{Type: PackageTypeRPM, Name: "bash", Version: "4.2.46-35.el7_9", Arch: "x86_64", Path: "/var/lib/rpm/Packages"},
+		{Type: PackageTypeRPM, Name: "openssl-libs", Version: "1:1.0.2k-25.el7_9", Arch: "x86_64", Path: "/var/lib/rpm/Packages"},
+	}, parseRPMDatabase("/var/lib/rpm/Packages", rpmDB, rpmdb.ParseBerkeleyDB))
+	assert.Empty(t, parseRPMDatabase("/var/lib/rpm/rpmdb.sqlite", rpmDB, rpmdb.ParseSqlite))
+
+	pkgs, err := parseNPMLock([]byte(npmLockV1))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []Package{
+		{Type: PackageTypeNPM, Name: "express", Version: "4.17.1"},
+		{Type: PackageTypeNPM, Name: "debug", Version: "2.6.9"},
+	}, pkgs)
+
+	pkgs, err = parseNPMLock([]byte(npmLockV2))
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{{Type: PackageTypeNPM, Name: "@types/node", Version: "18.0.0"}}, pkgs)
+
+	pkgs, err = parsePipfileLock([]byte(pipfileLock))
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{{Type: PackageTypePyPI, Name: "requests", Version: "2.28.1"}}, pkgs)
+
+	pkgs, err = parseGemfileLock([]byte(gemfileLock))
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Type: PackageTypeGem, Name: "rack", Version: "2.2.4"},
+		{Type: PackageTypeGem, Name: "rails", Version: "7.0.3"},
+	}, pkgs)
+
+	_, err = parseNPMLock([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestPURL(t *testing.T) {
+	debian := Distro{ID: "debian", VersionID: "11"}
+
+	assert.Equal(t, "pkg:deb/debian/libc6@2.31-13+deb11u3?arch=amd64&distro=debian-11",
+		Package{Type: PackageTypeDeb, Name: "libc6", Version: "2.31-13+deb11u3", Arch: "amd64"}.PURL(debian))
+	assert.Equal(t, "pkg:npm/%40types/node@18.0.0",
+		Package{Type: PackageTypeNPM, Name: "@types/node", Version: "18.0.0"}.PURL(debian))
+
+	centos := Distro{ID: "centos", VersionID: "7"}
+	assert.Equal(t, "pkg:rpm/centos/bash@4.2.46-35.el7_9?arch=x86_64&distro=centos-7",
+		Package{Type: PackageTypeRPM, Name: "bash", Version: "4.2.46-35.el7_9", Arch: "x86_64"}.PURL(centos))
+	assert.Equal(t, "pkg:rpm/centos/openssl-libs@1.0.2k-25.el7_9?arch=x86_64&distro=centos-7&epoch=1",
+		Package{Type: PackageTypeRPM, Name: "openssl-libs", Version: "1:1.0.2k-25.el7_9", Arch: "x86_64"}.PURL(centos))
+}
+
+func TestGenerate(t *testing.T) {
+	layer := writeLayer(t, map[string]string{
+		osReleaseFile:                           "ID=debian\nVERSION_ID=\"11\"\n",
+		dpkgStatusFile:                          dpkgStatus,
+		"/app/Pipfile.lock":                     pipfileLock,
+		"/app/node_modules/x/package-lock.json": npmLockV2,
+	})
+
+	now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+	bom, err := Generate(Image{Name: "debian:11", Digest: "sha256:1234", Layers: []string{layer}}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "2022-06-01T10:00:00Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "debian:11", bom.Metadata.Component.Name)
+
+	var purls []string
+	for _, component := range bom.Components {
+		purls = append(purls, component.PURL)
+	}
+	assert.Equal(t, []string{
+		"",
+		"pkg:deb/debian/bash@5.1-2+b3?arch=amd64&distro=debian-11",
+		"pkg:deb/debian/libc6@2.31-13+deb11u3?arch=amd64&distro=debian-11",
+		"pkg:pypi/requests@2.28.1",
+	}, purls)
+	assert.Equal(t, ComponentTypeOperatingSystem, bom.Components[0].Type)
+}
+
+func TestGenerateRPM(t *testing.T) {
+	layer := writeLayer(t, map[string]string{
+		osReleaseFile: "ID=\"rocky\"\nVERSION_ID=\"9.0\"\n",
+		"/var/lib/rpm/rpmdb.sqlite": string(testutil.Sqlite(
+			testutil.Header("sudo", "1.9.5p2", "7.el9", "x86_64", 0),
+			testutil.Header("openssl", "3.0.1", "41.el9_0", "x86_64", 1),
+		)),
+	})
+
+	bom, err := Generate(Image{Name: "rockylinux:9", Digest: "sha256:1234", Layers: []string{layer}}, time.Now())
+	require.NoError(t, err)
+
+	var purls []string
+	for _, component := range bom.Components {
+		purls = append(purls, component.PURL)
+	}
+	assert.Equal(t, []string{
+		"",
+		"pkg:rpm/rocky/openssl@3.0.1-41.el9_0?arch=x86_64&distro=rocky-9.0&epoch=1",
+		"pkg:rpm/rocky/sudo@1.9.5p2-7.el9?arch=x86_64&distro=rocky-9.0",
+	}, purls)
+	assert.Equal(t, "1:3.0.1-41.el9_0", bom.Components[1].Version)
+}
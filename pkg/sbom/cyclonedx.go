@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+// Minimal CycloneDX 1.4 JSON model, see https://cyclonedx.org/docs/1.4/json/
+const (
+	cycloneDXFormat      = "CycloneDX"
+	cycloneDXSpecVersion = "1.4"
+)
+
+// CycloneDX component types
+const (
+	ComponentTypeContainer       = "container"
+	ComponentTypeLibrary         = "library"
+	ComponentTypeOperatingSystem = "operating-system"
+)
+
+// BOM is a CycloneDX document
+type BOM struct {
+	BOMFormat   string      `json:"bomFormat"`
+	SpecVersion string      `json:"specVersion"`
+	Version     int         `json:"version"`
+	Metadata    Metadata    `json:"metadata"`
+	Components  []Component `json:"components"`
+}
+
+// Metadata describes the subject of a CycloneDX document
+type Metadata struct {
+	Timestamp string     `json:"timestamp"`
+	Tools     []Tool     `json:"tools,omitempty"`
+	Component *Component `json:"component,omitempty"`
+}
+
+// Tool is the tool that generated a CycloneDX document
+type Tool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Component is a software component
+type Component struct {
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	Version    string     `json:"version,omitempty"`
+	PURL       string     `json:"purl,omitempty"`
+	BOMRef     string     `json:"bom-ref,omitempty"`
+	Properties []Property `json:"properties,omitempty"`
+}
+
+// Property is a name/value pair attached to a component
+type Property struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
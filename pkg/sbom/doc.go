@@ -0,0 +1,14 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+/*
+Package sbom generates Software Bill Of Materials (SBOM) for container images.
+
+The image filesystem is read directly from the image layers stored on disk by
+the container runtime, following the overlay filesystem semantics. Installed
+OS packages (dpkg, apk) and language dependencies declared in lockfiles
+(npm, pipenv, bundler) are reported as components of a CycloneDX document.
+*/
+package sbom
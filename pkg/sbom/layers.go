@@ -0,0 +1,199 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+// ErrNotFound is returned when a file does not exist in the merged layers
+var ErrNotFound = errors.New("file not found in image layers")
+
+// skippedDirs are pseudo filesystems that should never be part of an image but may be
+// present in some layers (mount points)
+var skippedDirs = map[string]struct{}{
+	"/proc": {},
+	"/sys":  {},
+	"/dev":  {},
+}
+
+// LayeredFS gives a read-only, merged view of image layers extracted on disk.
+// Layers are ordered from the topmost to the lowest one, whiteouts and opaque
+// directories are handled like overlayfs does.
+type LayeredFS struct {
+	layers []string
+}
+
+// NewLayeredFS returns a LayeredFS for the given layer directories, topmost first
+func NewLayeredFS(layers []string) *LayeredFS {
+	return &LayeredFS{layers: layers}
+}
+
+// ReadFile returns the content of the file at `name` (absolute path in the image)
+func (l *LayeredFS) ReadFile(name string) ([]byte, error) {
+	name = path.Clean("/" + name)
+
+	for _, layer := range l.layers {
+		removed, opaque := lookupLayer(layer, name)
+		if removed {
+			return nil, ErrNotFound
+		}
+
+		fullPath := filepath.Join(layer, filepath.FromSlash(name))
+		if info, err := os.Lstat(fullPath); err == nil {
+			if !info.Mode().IsRegular() {
+				return nil, ErrNotFound
+			}
+			return ioutil.ReadFile(fullPath)
+		}
+
+		if opaque {
+			return nil, ErrNotFound
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// lookupLayer returns whether `name` or one of its parents has been removed in `layer`
+// and whether one of its parents is an opaque directory hiding the lower layers.
+func lookupLayer(layer, name string) (removed bool, opaque bool) {
+	for current := name; current != "/"; current = path.Dir(current) {
+		parent := filepath.Join(layer, filepath.FromSlash(path.Dir(current)))
+
+		if _, err := os.Lstat(filepath.Join(parent, whiteoutPrefix+path.Base(current))); err == nil {
+			return true, false
+		}
+
+		if info, err := os.Lstat(filepath.Join(parent, path.Base(current))); err == nil && isWhiteout(info) {
+			return true, false
+		}
+
+		if _, err := os.Lstat(filepath.Join(parent, opaqueWhiteout)); err == nil || isOpaqueDir(parent) {
+			opaque = true
+		}
+	}
+
+	return false, opaque
+}
+
+// WalkFunc is called for each regular file matching the filter of Walk.
+// `name` is the absolute path of the file in the image.
+type WalkFunc func(name string, content []byte) error
+
+// Walk calls fn for every regular file of the merged view for which match returns true
+func (l *LayeredFS) Walk(match func(name string) bool, fn WalkFunc) error {
+	seen := make(map[string]struct{})
+	// Paths removed or made opaque by upper layers
+	removed := make(map[string]struct{})
+	opaque := make(map[string]struct{})
+
+	for _, layer := range l.layers {
+		layerRemoved := make(map[string]struct{})
+		layerOpaque := make(map[string]struct{})
+
+		err := filepath.Walk(layer, func(fullPath string, info os.FileInfo, err error) error {
+			if err != nil {
+				// Ignore files we cannot read
+				return nil
+			}
+
+			rel, err := filepath.Rel(layer, fullPath)
+			if err != nil {
+				return nil
+			}
+			name := path.Clean("/" + filepath.ToSlash(rel))
+
+			if isHiddenBy(name, removed, opaque) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if info.IsDir() {
+				if _, found := skippedDirs[name]; found {
+					return filepath.SkipDir
+				}
+				if isOpaqueDir(fullPath) {
+					layerOpaque[name] = struct{}{}
+				}
+				return nil
+			}
+
+			base := path.Base(name)
+			switch {
+			case base == opaqueWhiteout:
+				layerOpaque[path.Dir(name)] = struct{}{}
+				return nil
+			case strings.HasPrefix(base, whiteoutPrefix):
+				layerRemoved[path.Join(path.Dir(name), strings.TrimPrefix(base, whiteoutPrefix))] = struct{}{}
+				return nil
+			case isWhiteout(info):
+				layerRemoved[name] = struct{}{}
+				return nil
+			}
+
+			if !info.Mode().IsRegular() || !match(name) {
+				return nil
+			}
+
+			if _, found := seen[name]; found {
+				return nil
+			}
+			seen[name] = struct{}{}
+
+			content, err := ioutil.ReadFile(fullPath)
+			if err != nil {
+				return nil
+			}
+
+			return fn(name, content)
+		})
+		if err != nil {
+			return err
+		}
+
+		for name := range layerRemoved {
+			removed[name] = struct{}{}
+		}
+		for name := range layerOpaque {
+			opaque[name] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// isHiddenBy returns whether name or one of its parents was removed by an upper layer,
+// or if one of its parents is an opaque directory in an upper layer
+func isHiddenBy(name string, removed, opaque map[string]struct{}) bool {
+	if _, found := removed[name]; found {
+		return true
+	}
+
+	for dir := path.Dir(name); ; dir = path.Dir(dir) {
+		if _, found := removed[dir]; found {
+			return true
+		}
+		if _, found := opaque[dir]; found {
+			return true
+		}
+		if dir == "/" {
+			return false
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !linux
+// +build !linux
+
+package sbom
+
+import "os"
+
+// isWhiteout returns whether the file is an overlayfs whiteout, which only exist on Linux
+func isWhiteout(info os.FileInfo) bool {
+	return false
+}
+
+// isOpaqueDir returns whether the directory is flagged as opaque, which only exist on Linux
+func isOpaqueDir(dir string) bool {
+	return false
+}
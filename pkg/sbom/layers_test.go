@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLayer(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		require.NoError(t, ioutil.WriteFile(fullPath, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLayeredFS(t *testing.T) {
+	lower := writeLayer(t, map[string]string{
+		"/etc/os-release":           "lower",
+		"/etc/removed":              "lower",
+		"/app/package-lock.json":    "lower",
+		"/opaque/package-lock.json": "lower",
+		"/gone/package-lock.json":   "lower",
+		"/proc/package-lock.json":   "lower",
+	})
+	upper := writeLayer(t, map[string]string{
+		"/etc/os-release":          "upper",
+		"/etc/.wh.removed":         "",
+		"/opaque/.wh..wh..opq":     "",
+		"/opaque/Pipfile.lock":     "upper",
+		"/.wh.gone":                "",
+		"/app/lib/Gemfile.lock":    "upper",
+		"/app/other-file.json":     "upper",
+		"/srv/.wh.package-lock.js": "",
+	})
+
+	fs := NewLayeredFS([]string{upper, lower})
+
+	content, err := fs.ReadFile("/etc/os-release")
+	assert.NoError(t, err)
+	assert.Equal(t, "upper", string(content))
+
+	content, err = fs.ReadFile("/app/package-lock.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "lower", string(content))
+
+	for _, name := range []string{"/etc/removed", "/opaque/package-lock.json", "/gone/package-lock.json", "/etc/missing", "/etc"} {
+		_, err = fs.ReadFile(name)
+		assert.Equal(t, ErrNotFound, err, name)
+	}
+
+	var found []string
+	err = fs.Walk(isLockfile, func(name string, content []byte) error {
+		found = append(found, name+":"+string(content))
+		return nil
+	})
+	assert.NoError(t, err)
+	sort.Strings(found)
+	assert.Equal(t, []string{
+		"/app/lib/Gemfile.lock:upper",
+		"/app/package-lock.json:lower",
+		"/opaque/Pipfile.lock:upper",
+	}, found)
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sbom
+
+import (
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+// Image identifies the container image an SBOM is generated for
+type Image struct {
+	Name   string
+	Digest string
+	// Layers are the directories holding the extracted image layers, topmost first
+	Layers []string
+}
+
+// Generate returns the CycloneDX SBOM of the image
+func Generate(image Image, now time.Time) (*BOM, error) {
+	distro, packages, err := Analyze(NewLayeredFS(image.Layers))
+	if err != nil {
+		return nil, err
+	}
+
+	bom := &BOM{
+		BOMFormat:   cycloneDXFormat,
+		SpecVersion: cycloneDXSpecVersion,
+		Version:     1,
+		Metadata: Metadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Tools: []Tool{{
+				Vendor:  "Datadog",
+				Name:    "datadog-agent",
+				Version: version.AgentVersion,
+			}},
+			Component: &Component{
+				Type:    ComponentTypeContainer,
+				Name:    image.Name,
+				Version: image.Digest,
+				BOMRef:  image.Digest,
+			},
+		},
+		Components: make([]Component, 0, len(packages)+1),
+	}
+
+	if distro.ID != "" {
+		bom.Components = append(bom.Components, Component{
+			Type:    ComponentTypeOperatingSystem,
+			Name:    distro.ID,
+			Version: distro.VersionID,
+		})
+	}
+
+	seen := make(map[string]struct{}, len(packages))
+	for _, pkg := range packages {
+		purl := pkg.PURL(distro)
+		if _, found := seen[purl+pkg.Path]; found {
+			continue
+		}
+		seen[purl+pkg.Path] = struct{}{}
+
+		component := Component{
+			Type:    ComponentTypeLibrary,
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    purl,
+		}
+		if pkg.Path != "" {
+			component.Properties = []Property{{Name: "datadog:sbom:path", Value: pkg.Path}}
+		}
+		bom.Components = append(bom.Components, component)
+	}
+
+	sort.SliceStable(bom.Components, func(i, j int) bool {
+		return bom.Components[i].PURL < bom.Components[j].PURL
+	})
+
+	return bom, nil
+}
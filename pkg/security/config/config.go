@@ -118,6 +118,29 @@ type Config struct {
 	// ActivityDumpCgroupOutputDirectory defines the output directory for the cgroup activity dumps and graphs. Leave
 	// this field empty to prevent writing any output to disk.
 	ActivityDumpCgroupOutputDirectory string
+	// SecurityProfileEnabled defines if the security profile manager should be enabled
+	SecurityProfileEnabled bool
+	// SecurityProfileDirectory defines the directory from which the activity dumps used as security profiles are
+	// loaded
+	SecurityProfileDirectory string
+	// SecurityProfileSelectorTags defines the tags used to match a workload with a security profile
+	SecurityProfileSelectorTags []string
+	// SecurityProfileLearningPeriod defines the maximum duration during which a security profile learns the activity
+	// of its workload before anomalies are reported
+	SecurityProfileLearningPeriod time.Duration
+	// SecurityProfileStabilizationPeriod defines the duration without any new activity after which a security profile
+	// is considered stable, even if its learning period isn't over
+	SecurityProfileStabilizationPeriod time.Duration
+	// FIMHashEnabled defines if the content of the monitored files should be hashed once they are closed after being
+	// written
+	FIMHashEnabled bool
+	// FIMHashPaths defines the path globs of the files monitored by the FIM hash capture
+	FIMHashPaths []string
+	// FIMHashMaxFileSize defines the maximum size of the files hashed by the FIM hash capture
+	FIMHashMaxFileSize int64
+	// FIMHashBaselineScanPeriod defines the period between two scans of the monitored files, used to detect the
+	// changes that weren't caught at runtime. Set to 0 to disable baseline scans.
+	FIMHashBaselineScanPeriod time.Duration
 	// RuntimeMonitor defines if the runtime monitor should be enabled
 	RuntimeMonitor bool
 	// NetworkEnabled defines if the network probes should be activated
@@ -136,6 +159,24 @@ type Config struct {
 	EventMonitoring bool
 	// RemoteConfigurationEnabled defines whether to use remote monitoring
 	RemoteConfigurationEnabled bool
+	// RemoteConfigurationPolicyPublicKeys defines the base64 encoded ed25519 public keys trusted to sign the policy
+	// bundles delivered over remote config
+	RemoteConfigurationPolicyPublicKeys []string
+	// RemoteConfigurationRollbackPeriod defines the period after the activation of a new remote config policy version
+	// during which it is rolled back if its rules exceed the rate limiter
+	RemoteConfigurationRollbackPeriod time.Duration
+	// RemoteConfigurationRollbackMaxDroppedEvents defines the number of events dropped by the rate limiter that triggers
+	// the rollback of a new remote config policy version
+	RemoteConfigurationRollbackMaxDroppedEvents int64
+	// EnforcementDryRun defines if the kill actions of the rules should only be reported without being executed
+	EnforcementDryRun bool
+	// EnforcementRate defines the rate at which the kill actions of a rule can be executed
+	EnforcementRate int
+	// EnforcementBurst defines the maximum burst of kill actions that can be executed by a rule
+	EnforcementBurst int
+	// EnforcementProtectedProcesses is the list of executable paths, in addition to the built-in ones, of the host
+	// processes that kill actions should never target
+	EnforcementProtectedProcesses []string
 }
 
 // IsEnabled returns true if any feature is enabled. Has to be applied in config package too
@@ -197,14 +238,31 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		ActivityDumpCgroupDumpTimeout:      time.Duration(aconfig.Datadog.GetInt("runtime_security_config.activity_dump.cgroup_dump_timeout")) * time.Minute,
 		ActivityDumpCgroupWaitListSize:     aconfig.Datadog.GetInt("runtime_security_config.activity_dump.cgroup_wait_list_size"),
 		ActivityDumpCgroupOutputDirectory:  aconfig.Datadog.GetString("runtime_security_config.activity_dump.cgroup_output_directory"),
+		SecurityProfileEnabled:             aconfig.Datadog.GetBool("runtime_security_config.security_profile.enabled"),
+		SecurityProfileDirectory:           aconfig.Datadog.GetString("runtime_security_config.security_profile.dir"),
+		SecurityProfileSelectorTags:        aconfig.Datadog.GetStringSlice("runtime_security_config.security_profile.selector_tags"),
+		SecurityProfileLearningPeriod:      time.Duration(aconfig.Datadog.GetInt("runtime_security_config.security_profile.learning_period")) * time.Minute,
+		SecurityProfileStabilizationPeriod: time.Duration(aconfig.Datadog.GetInt("runtime_security_config.security_profile.stabilization_period")) * time.Minute,
+		FIMHashEnabled:                     aconfig.Datadog.GetBool("runtime_security_config.fim_hash.enabled"),
+		FIMHashPaths:                       aconfig.Datadog.GetStringSlice("runtime_security_config.fim_hash.paths"),
+		FIMHashMaxFileSize:                 aconfig.Datadog.GetInt64("runtime_security_config.fim_hash.max_file_size"),
+		FIMHashBaselineScanPeriod:          time.Duration(aconfig.Datadog.GetInt("runtime_security_config.fim_hash.baseline_scan_period")) * time.Minute,
 		RuntimeMonitor:                     aconfig.Datadog.GetBool("runtime_security_config.runtime_monitor.enabled"),
 		NetworkEnabled:                     aconfig.Datadog.GetBool("runtime_security_config.network.enabled"),
 		NetworkLazyInterfacePrefixes:       aconfig.Datadog.GetStringSlice("runtime_security_config.network.lazy_interface_prefixes"),
 		// runtime compilation
-		RuntimeCompilationEnabled:       aconfig.Datadog.GetBool("runtime_security_config.runtime_compilation.enabled"),
-		RuntimeCompiledConstantsEnabled: aconfig.Datadog.GetBool("runtime_security_config.runtime_compilation.compiled_constants_enabled"),
-		RuntimeCompiledConstantsIsSet:   aconfig.Datadog.IsSet("runtime_security_config.runtime_compilation.compiled_constants_enabled"),
-		RemoteConfigurationEnabled:      aconfig.Datadog.GetBool("runtime_security_config.remote_configuration.enabled"),
+		RuntimeCompilationEnabled:                   aconfig.Datadog.GetBool("runtime_security_config.runtime_compilation.enabled"),
+		RuntimeCompiledConstantsEnabled:             aconfig.Datadog.GetBool("runtime_security_config.runtime_compilation.compiled_constants_enabled"),
+		RuntimeCompiledConstantsIsSet:               aconfig.Datadog.IsSet("runtime_security_config.runtime_compilation.compiled_constants_enabled"),
+		RemoteConfigurationEnabled:                  aconfig.Datadog.GetBool("runtime_security_config.remote_configuration.enabled"),
+		RemoteConfigurationPolicyPublicKeys:         aconfig.Datadog.GetStringSlice("runtime_security_config.remote_configuration.policy_public_keys"),
+		RemoteConfigurationRollbackPeriod:           time.Duration(aconfig.Datadog.GetInt("runtime_security_config.remote_configuration.rollback_period")) * time.Minute,
+		RemoteConfigurationRollbackMaxDroppedEvents: aconfig.Datadog.GetInt64("runtime_security_config.remote_configuration.rollback_max_dropped_events"),
+		// enforcement
+		EnforcementDryRun:             aconfig.Datadog.GetBool("runtime_security_config.enforcement.dry_run"),
+		EnforcementRate:               aconfig.Datadog.GetInt("runtime_security_config.enforcement.rate"),
+		EnforcementBurst:              aconfig.Datadog.GetInt("runtime_security_config.enforcement.burst"),
+		EnforcementProtectedProcesses: aconfig.Datadog.GetStringSlice("runtime_security_config.enforcement.protected_processes"),
 	}
 
 	// if runtime is enabled then we force fim
@@ -212,6 +270,11 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		c.FIMEnabled = true
 	}
 
+	// the hash capture relies on the open events of the FIM
+	if !c.FIMEnabled {
+		c.FIMHashEnabled = false
+	}
+
 	if !c.IsEnabled() {
 		return c, nil
 	}
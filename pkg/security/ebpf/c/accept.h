@@ -0,0 +1,114 @@
+#ifndef _ACCEPT_H_
+#define _ACCEPT_H_
+
+struct accept_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct syscall_t syscall;
+
+    u64 addr[2];
+    u16 family;
+    u16 port;
+    u16 protocol;
+    u16 padding;
+};
+
+int __attribute__((always_inline)) sys_accept() {
+    struct policy_t policy = fetch_policy(EVENT_ACCEPT);
+    if (is_discarded_by_process(policy.mode, EVENT_ACCEPT)) {
+        return 0;
+    }
+
+    /* cache the accept and wait to grab the retval to send it */
+    struct syscall_cache_t syscall = {
+        .type = EVENT_ACCEPT,
+    };
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SYSCALL_KPROBE0(accept) {
+    return sys_accept();
+}
+
+SYSCALL_KPROBE0(accept4) {
+    return sys_accept();
+}
+
+SEC("kretprobe/inet_csk_accept")
+int kretprobe_inet_csk_accept(struct pt_regs *ctx) {
+    struct sock *sk = (struct sock *)PT_REGS_RC(ctx);
+    if (!sk) {
+        return 0;
+    }
+
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_ACCEPT);
+    if (!syscall) {
+        return 0;
+    }
+
+    // Extract the IP and port of the remote peer from the new socket
+    bpf_probe_read(&syscall->accept.family, sizeof(syscall->accept.family), &sk->__sk_common.skc_family);
+    if (syscall->accept.family == AF_INET) {
+        bpf_probe_read(&syscall->accept.addr, sizeof(sk->__sk_common.skc_daddr), &sk->__sk_common.skc_daddr);
+    } else if (syscall->accept.family == AF_INET6) {
+        bpf_probe_read(&syscall->accept.addr, sizeof(u64) * 2, &sk->__sk_common.skc_v6_daddr);
+    }
+    bpf_probe_read(&syscall->accept.port, sizeof(syscall->accept.port), &sk->__sk_common.skc_dport);
+
+    // inet_csk_accept is only used by connection oriented sockets
+    syscall->accept.protocol = IPPROTO_TCP;
+
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_accept_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_ACCEPT);
+    if (!syscall) {
+        return 0;
+    }
+
+    if (IS_UNHANDLED_ERROR(retval)) {
+        return 0;
+    }
+
+    /* pre-fill the event */
+    struct accept_event_t event = {
+        .syscall.retval = retval,
+        .addr[0] = syscall->accept.addr[0],
+        .addr[1] = syscall->accept.addr[1],
+        .family = syscall->accept.family,
+        .port = syscall->accept.port,
+        .protocol = syscall->accept.protocol,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+    send_event(ctx, EVENT_ACCEPT, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(accept) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_accept_ret(ctx, retval);
+}
+
+SYSCALL_KRETPROBE(accept4) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_accept_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_accept")
+int tracepoint_syscalls_sys_exit_accept(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_accept_ret(args, args->ret);
+}
+
+SEC("tracepoint/syscalls/sys_exit_accept4")
+int tracepoint_syscalls_sys_exit_accept4(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_accept_ret(args, args->ret);
+}
+
+#endif /* _ACCEPT_H_ */
@@ -0,0 +1,113 @@
+#ifndef _CONNECT_H_
+#define _CONNECT_H_
+
+struct connect_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct syscall_t syscall;
+
+    u64 addr[2];
+    u16 family;
+    u16 port;
+    u16 protocol;
+    u16 padding;
+};
+
+u16 __attribute__((always_inline)) get_socket_protocol(struct socket *sock) {
+    short type = 0;
+    bpf_probe_read(&type, sizeof(type), &sock->type);
+
+    switch (type) {
+    case SOCK_STREAM:
+        return IPPROTO_TCP;
+    case SOCK_DGRAM:
+        return IPPROTO_UDP;
+    }
+    return 0;
+}
+
+SYSCALL_KPROBE3(connect, int, socket, struct sockaddr*, addr, unsigned int, addr_len) {
+    if (!addr) {
+        return 0;
+    }
+
+    struct policy_t policy = fetch_policy(EVENT_CONNECT);
+    if (is_discarded_by_process(policy.mode, EVENT_CONNECT)) {
+        return 0;
+    }
+
+    /* cache the connect and wait to grab the retval to send it */
+    struct syscall_cache_t syscall = {
+        .type = EVENT_CONNECT,
+    };
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_connect")
+int kprobe_security_socket_connect(struct pt_regs *ctx) {
+    struct socket *sock = (struct socket *)PT_REGS_PARM1(ctx);
+    struct sockaddr *address = (struct sockaddr *)PT_REGS_PARM2(ctx);
+
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_CONNECT);
+    if (!syscall) {
+        return 0;
+    }
+
+    // Extract IP and port from the sockaddr structure
+    bpf_probe_read(&syscall->connect.family, sizeof(syscall->connect.family), &address->sa_family);
+    if (syscall->connect.family == AF_INET) {
+        struct sockaddr_in *addr_in = (struct sockaddr_in *)address;
+        bpf_probe_read(&syscall->connect.port, sizeof(addr_in->sin_port), &addr_in->sin_port);
+        bpf_probe_read(&syscall->connect.addr, sizeof(addr_in->sin_addr.s_addr), &addr_in->sin_addr.s_addr);
+    } else if (syscall->connect.family == AF_INET6) {
+        struct sockaddr_in6 *addr_in6 = (struct sockaddr_in6 *)address;
+        bpf_probe_read(&syscall->connect.port, sizeof(addr_in6->sin6_port), &addr_in6->sin6_port);
+        bpf_probe_read(&syscall->connect.addr, sizeof(u64) * 2, (char *)addr_in6 + offsetof(struct sockaddr_in6, sin6_addr));
+    }
+    syscall->connect.protocol = get_socket_protocol(sock);
+
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_connect_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_CONNECT);
+    if (!syscall) {
+        return 0;
+    }
+
+    // non-blocking sockets return EINPROGRESS, the connection is still being established
+    if (IS_UNHANDLED_ERROR(retval) && retval != -EINPROGRESS) {
+        return 0;
+    }
+
+    /* pre-fill the event */
+    struct connect_event_t event = {
+        .syscall.retval = retval,
+        .addr[0] = syscall->connect.addr[0],
+        .addr[1] = syscall->connect.addr[1],
+        .family = syscall->connect.family,
+        .port = syscall->connect.port,
+        .protocol = syscall->connect.protocol,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+    send_event(ctx, EVENT_CONNECT, event);
+    return 0;
+}
+
+SYSCALL_KRETPROBE(connect) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_connect_ret(ctx, retval);
+}
+
+SEC("tracepoint/syscalls/sys_exit_connect")
+int tracepoint_syscalls_sys_exit_connect(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_connect_ret(args, args->ret);
+}
+
+#endif /* _CONNECT_H_ */
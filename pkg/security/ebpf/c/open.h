@@ -24,6 +24,36 @@ struct bpf_map_def SEC("maps/io_uring_req_pid") io_uring_req_pid = {
     .namespace = "",
 };
 
+struct fim_file_key_t {
+    u64 ino;
+    u32 mount_id;
+    u32 padding;
+};
+
+// files opened for writing, a close event is sent when they are closed
+struct bpf_map_def SEC("maps/fim_written_files") fim_written_files = {
+    .type = BPF_MAP_TYPE_LRU_HASH,
+    .key_size = sizeof(struct fim_file_key_t),
+    .value_size = sizeof(struct file_t),
+    .max_entries = 4096,
+    .pinning = 0,
+    .namespace = "",
+};
+
+struct close_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct file_t file;
+};
+
+__attribute__((always_inline)) u64 is_fim_enabled() {
+    u64 fim_enabled;
+    LOAD_CONSTANT("fim_enabled", fim_enabled);
+    return fim_enabled;
+}
+
 struct open_event_t {
     struct kevent_t event;
     struct process_context_t process;
@@ -344,6 +374,35 @@ int kretprobe_io_openat2(struct pt_regs *ctx) {
     return sys_open_ret_with_pid_tgid(ctx, retval, DR_KPROBE, pid_tgid);
 }
 
+void __attribute__((always_inline)) fim_handle_close(struct pt_regs *ctx, struct file *file, u32 mount_id) {
+    fmode_t mode;
+    bpf_probe_read(&mode, sizeof(mode), &file->f_mode);
+    if (!(mode & FMODE_WRITE)) {
+        return;
+    }
+
+    struct fim_file_key_t key = {
+        .ino = get_dentry_ino(get_file_dentry(file)),
+        .mount_id = mount_id,
+    };
+
+    struct file_t *written_file = bpf_map_lookup_elem(&fim_written_files, &key);
+    if (!written_file) {
+        return;
+    }
+
+    struct close_event_t event = {
+        .file = *written_file,
+    };
+    bpf_map_delete_elem(&fim_written_files, &key);
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+
+    send_event(ctx, EVENT_CLOSE, event);
+}
+
 SEC("kprobe/filp_close")
 int kprobe_filp_close(struct pt_regs *ctx) {
     struct file *file = (struct file *) PT_REGS_PARM1(ctx);
@@ -352,6 +411,10 @@ int kprobe_filp_close(struct pt_regs *ctx) {
         dec_mount_ref(ctx, mount_id);
     }
 
+    if (is_fim_enabled()) {
+        fim_handle_close(ctx, file, mount_id);
+    }
+
     return 0;
 }
 
@@ -393,6 +456,16 @@ int __attribute__((always_inline)) dr_open_callback(void *ctx, int retval) {
     fill_span_context(&event.span);
 
     send_event(ctx, EVENT_OPEN, event);
+
+    // keep track of the files opened for writing so that their content can be hashed once closed
+    if (is_fim_enabled() && (event.flags & (O_WRONLY|O_RDWR))) {
+        struct fim_file_key_t key = {
+            .ino = event.file.path_key.ino,
+            .mount_id = event.file.path_key.mount_id,
+        };
+        bpf_map_update_elem(&fim_written_files, &key, &event.file, BPF_ANY);
+    }
+
     return 0;
 }
 
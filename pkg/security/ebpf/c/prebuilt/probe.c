@@ -64,6 +64,8 @@
 #include "module.h"
 #include "signal.h"
 #include "bind.h"
+#include "connect.h"
+#include "accept.h"
 #include "net_device.h"
 #include "procfs.h"
 #include "offset.h"
@@ -207,6 +207,20 @@ struct syscall_cache_t {
             u16 family;
             u16 port;
         } bind;
+
+        struct {
+            u64 addr[2];
+            u16 family;
+            u16 port;
+            u16 protocol;
+        } connect;
+
+        struct {
+            u64 addr[2];
+            u16 family;
+            u16 port;
+            u16 protocol;
+        } accept;
     };
 };
 
@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probes
+
+import manager "github.com/DataDog/ebpf-manager"
+
+// acceptProbes holds the list of probes used to track accept events
+var acceptProbes = []*manager.Probe{
+	{
+		ProbeIdentificationPair: manager.ProbeIdentificationPair{
+			UID:          SecurityAgentUID,
+			EBPFSection:  "kretprobe/inet_csk_accept",
+			EBPFFuncName: "kretprobe_inet_csk_accept",
+		},
+	},
+}
+
+func getAcceptProbes() []*manager.Probe {
+	for _, name := range []string{"accept", "accept4"} {
+		acceptProbes = append(acceptProbes, ExpandSyscallProbes(&manager.Probe{
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				UID: SecurityAgentUID,
+			},
+			SyscallFuncName: name,
+		}, EntryAndExit)...)
+	}
+	return acceptProbes
+}
@@ -56,6 +56,8 @@ func AllProbes() []*manager.Probe {
 	allProbes = append(allProbes, getNetDeviceProbes()...)
 	allProbes = append(allProbes, GetTCProbes()...)
 	allProbes = append(allProbes, getBindProbes()...)
+	allProbes = append(allProbes, getConnectProbes()...)
+	allProbes = append(allProbes, getAcceptProbes()...)
 
 	allProbes = append(allProbes,
 		// Syscall monitor
@@ -109,6 +111,7 @@ func AllMaps() []*manager.Map {
 		// Open tables
 		{Name: "open_flags_approvers"},
 		{Name: "io_uring_req_pid"},
+		{Name: "fim_written_files"},
 		// Exec tables
 		{Name: "proc_cache"},
 		{Name: "pid_cache"},
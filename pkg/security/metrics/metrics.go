@@ -44,6 +44,19 @@ var (
 	// Tags: rule_id
 	MetricRateLimiterAllow = newRuntimeMetric(".rules.rate_limiter.allow")
 
+	// Rule actions metrics
+
+	// MetricRuleActionKill is the name of the metric used to count the kill actions triggered by the rules
+	// Tags: rule_id, status
+	MetricRuleActionKill = newRuntimeMetric(".rules.action.kill")
+
+	// Remote config metrics
+
+	// MetricRemoteConfigUnsignedPolicyRejected is the name of the metric used to count the unsigned remote config
+	// policies rejected because policy public keys are configured
+	// Tags: -
+	MetricRemoteConfigUnsignedPolicyRejected = newRuntimeMetric(".remote_config.unsigned_policy_rejected")
+
 	// Syscall monitoring metrics
 
 	// MetricSyscalls is the name of the metric used to count each syscall executed on the host
@@ -152,6 +165,25 @@ var (
 	// Tags: -
 	MetricActivityDumpActiveDumps = newRuntimeMetric(".activity_dump.active_dumps")
 
+	// Security profile metrics
+
+	// MetricSecurityProfileProfiles is the name of the metric used to report the count of loaded security profiles
+	// Tags: status
+	MetricSecurityProfileProfiles = newRuntimeMetric(".security_profile.profiles")
+	// MetricSecurityProfileAnomalies is the name of the metric used to count the anomalies detected with the security
+	// profiles
+	// Tags: anomaly_type
+	MetricSecurityProfileAnomalies = newRuntimeMetric(".security_profile.anomalies")
+
+	// FIM hash capture metrics
+
+	// MetricFIMHashChanges is the name of the metric used to count the content changes detected on the monitored files
+	// Tags: source
+	MetricFIMHashChanges = newRuntimeMetric(".fim_hash.changes")
+	// MetricFIMHashSkipped is the name of the metric used to count the monitored files that weren't hashed
+	// Tags: reason
+	MetricFIMHashSkipped = newRuntimeMetric(".fim_hash.skipped")
+
 	// Namespace resolver metrics
 
 	// MetricNamespaceResolverNetNSHandle is the name of the metric used to report the count of netns handles
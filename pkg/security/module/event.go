@@ -15,6 +15,19 @@ type AgentContext struct {
 	PolicyName    string `json:"policy_name,omitempty"`
 	PolicyVersion string `json:"policy_version,omitempty"`
 	Version       string `json:"version,omitempty"`
+
+	RuleActions []RuleActionReport `json:"rule_actions,omitempty"`
+}
+
+// RuleActionReport serializes the outcome of a rule action
+// easyjson:json
+type RuleActionReport struct {
+	Type   string   `json:"type"`
+	Signal string   `json:"signal,omitempty"`
+	Scope  string   `json:"scope,omitempty"`
+	Status string   `json:"status"`
+	Pids   []uint32 `json:"pids,omitempty"`
+	Error  string   `json:"error,omitempty"`
 }
 
 // Signal - Rule event wrapper used to send an event to the backend
@@ -63,6 +63,7 @@ type Module struct {
 	grpcServer       *grpc.Server
 	listener         net.Listener
 	rateLimiter      *RateLimiter
+	processKiller    *ProcessKiller
 	sigupChan        chan os.Signal
 	ctx              context.Context
 	cancelFnc        context.CancelFunc
@@ -70,6 +71,7 @@ type Module struct {
 	policiesVersions []string
 	policyProviders  []rules.PolicyProvider
 	policyLoader     *rules.PolicyLoader
+	rcPolicyProvider *rconfig.RCPolicyProvider
 	selfTester       *selftests.SelfTester
 }
 
@@ -163,14 +165,24 @@ func (m *Module) Start() error {
 
 	// add remote config as config provider if enabled
 	if m.config.RemoteConfigurationEnabled {
-		rcPolicyProvider, err := rconfig.NewRCPolicyProvider("security-agent")
+		rcPolicyProvider, err := rconfig.NewRCPolicyProvider("security-agent", rconfig.RCPolicyProviderOpts{
+			PublicKeys:               m.config.RemoteConfigurationPolicyPublicKeys,
+			RollbackPeriod:           m.config.RemoteConfigurationRollbackPeriod,
+			RollbackMaxDroppedEvents: m.config.RemoteConfigurationRollbackMaxDroppedEvents,
+		})
 		if err != nil {
 			log.Errorf("will be unable to load remote policy: %s", err)
 		} else {
 			policyProviders = append(policyProviders, rcPolicyProvider)
+			m.rcPolicyProvider = rcPolicyProvider
 		}
 	}
 
+	// enable the open events of the files monitored by the FIM hash capture
+	if fimHash := m.probe.GetMonitor().GetFIMHashManager(); fimHash != nil {
+		policyProviders = append(policyProviders, fimHash.GetPolicyProvider())
+	}
+
 	if err := m.LoadPolicies(policyProviders, true); err != nil {
 		log.Errorf("failed to load policies: %s", err)
 	}
@@ -302,6 +314,7 @@ func (m *Module) LoadPolicies(policyProviders []rules.PolicyProvider, sendLoaded
 		WithEventTypeEnabled(m.getEventTypeEnabled()).
 		WithReservedRuleIDs(sprobe.AllCustomRuleIDs()).
 		WithLegacyFields(model.SECLLegacyFields).
+		WithSequenceScopes(sprobe.SequenceScopes).
 		WithStateScopes(map[rules.Scope]rules.VariableProviderFactory{
 			"process": func() rules.VariableProvider {
 				return eval.NewScopedVariables(func(ctx *eval.Context) unsafe.Pointer {
@@ -347,6 +360,11 @@ func (m *Module) LoadPolicies(policyProviders []rules.PolicyProvider, sendLoaded
 	m.policyProviders = policyProviders
 	m.currentRuleSet.Store(ruleSet)
 
+	// roll back the remote config policies that failed to load
+	if m.rcPolicyProvider != nil {
+		m.rcPolicyProvider.OnPoliciesLoaded(loadErrs)
+	}
+
 	// notify listeners
 	if m.rulesLoaded != nil {
 		m.rulesLoaded(ruleSet, loadErrs)
@@ -368,6 +386,7 @@ func (m *Module) LoadPolicies(policyProviders []rules.PolicyProvider, sendLoaded
 
 	m.apiServer.Apply(ruleIDs)
 	m.rateLimiter.Apply(ruleIDs)
+	m.processKiller.Apply(ruleIDs)
 
 	m.displayReport(report)
 
@@ -432,14 +451,22 @@ func (m *Module) HandleEvent(event *sprobe.Event) {
 
 // HandleCustomEvent is called by the probe when an event should be sent to Datadog but doesn't need evaluation
 func (m *Module) HandleCustomEvent(rule *rules.Rule, event *sprobe.CustomEvent) {
-	m.SendEvent(rule, event, func() []string { return nil }, "")
+	m.SendEvent(rule, event, func() []string { return nil }, "", nil)
 }
 
 // RuleMatch is called by the ruleset when a rule matches
 func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
+	// the rules of the FIM hash capture only enable the open events of the monitored files
+	if sprobe.IsFIMHashRule(rule) {
+		return
+	}
+
 	// prepare the event
 	m.probe.OnRuleMatch(rule, event.(*sprobe.Event))
 
+	// execute the kill actions as soon as possible, before the process gets a chance to go further
+	actionReports := m.processKiller.KillAndReport(rule, event.(*sprobe.Event))
+
 	// needs to be resolved here, outside of the callback as using process tree
 	// which can be modified during queuing
 	service := event.(*sprobe.Event).GetProcessServiceTag()
@@ -463,16 +490,20 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 
 	// send if not selftest related events
 	if m.selfTester == nil || !m.selfTester.IsExpectedEvent(rule, event) {
-		m.SendEvent(rule, event, extTagsCb, service)
+		m.SendEvent(rule, event, extTagsCb, service, actionReports)
 	}
 }
 
 // SendEvent sends an event to the backend after checking that the rate limiter allows it for the provided rule
-func (m *Module) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []string, service string) {
+func (m *Module) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []string, service string, actionReports []RuleActionReport) {
 	if m.rateLimiter.Allow(rule.ID) {
-		m.apiServer.SendEvent(rule, event, extTagsCb, service)
+		m.apiServer.SendEvent(rule, event, extTagsCb, service, actionReports)
 	} else {
 		seclog.Tracef("Event on rule %s was dropped due to rate limiting", rule.ID)
+
+		if m.rcPolicyProvider != nil {
+			m.rcPolicyProvider.OnEventDropped(rule)
+		}
 	}
 }
 
@@ -501,6 +532,11 @@ func (m *Module) metricsSender() {
 			if err := m.apiServer.SendStats(); err != nil {
 				log.Debug(err)
 			}
+			if m.rcPolicyProvider != nil {
+				if err := m.rcPolicyProvider.SendStats(m.statsdClient); err != nil {
+					log.Debug(err)
+				}
+			}
 		case <-heartbeatTicker.C:
 			tags := []string{fmt.Sprintf("version:%s", version.AgentVersion)}
 
@@ -601,6 +637,7 @@ func NewModule(cfg *sconfig.Config, opts ...Opts) (module.Module, error) {
 		apiServer:      NewAPIServer(cfg, probe, statsdClient),
 		grpcServer:     grpc.NewServer(),
 		rateLimiter:    NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
+		processKiller:  NewProcessKiller(cfg, probe.GetResolvers().ProcessResolver, statsdClient),
 		sigupChan:      make(chan os.Signal, 1),
 		ctx:            ctx,
 		cancelFnc:      cancelFnc,
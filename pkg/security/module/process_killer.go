@@ -0,0 +1,292 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package module
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"golang.org/x/time/rate"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const ruleActionKill = "kill"
+
+// Kill action statuses
+const (
+	// KillActionStatusPerformed is reported when the signal was sent to all the targeted processes
+	KillActionStatusPerformed = "performed"
+	// KillActionStatusDryRun is reported when the signal would have been sent if the dry-run mode was disabled
+	KillActionStatusDryRun = "dry_run"
+	// KillActionStatusRateLimited is reported when the action was dropped by the rate limiter
+	KillActionStatusRateLimited = "rate_limited"
+	// KillActionStatusProtected is reported when all the targeted processes are protected
+	KillActionStatusProtected = "protected"
+	// KillActionStatusError is reported when the signal couldn't be sent
+	KillActionStatusError = "error"
+)
+
+// defaultProtectedProcesses lists the executables of the host processes that are never targeted by a kill action.
+// The processes are matched on the path of their executable rather than on their name, which can be changed
+// by the process itself.
+var defaultProtectedProcesses = []string{
+	"/opt/datadog-agent/bin/agent/agent",
+	"/opt/datadog-agent/embedded/bin/system-probe",
+	"/opt/datadog-agent/embedded/bin/security-agent",
+	"/opt/datadog-agent/embedded/bin/process-agent",
+	"/opt/datadog-agent/embedded/bin/trace-agent",
+	"/sbin/init",
+	"/lib/systemd/systemd",
+	"/usr/lib/systemd/systemd",
+	"/usr/sbin/sshd",
+	"/usr/bin/containerd",
+	"/usr/bin/containerd-shim",
+	"/usr/bin/containerd-shim-runc-v2",
+	"/usr/bin/dockerd",
+	"/usr/bin/runc",
+	"/usr/sbin/runc",
+	"/usr/bin/crio",
+	"/usr/bin/conmon",
+	"/usr/bin/kubelet",
+	"/usr/local/bin/kubelet",
+}
+
+type killTarget struct {
+	pid         uint32
+	ppid        uint32
+	path        string
+	containerID string
+}
+
+func newKillTarget(process *model.Process) killTarget {
+	return killTarget{
+		pid:         process.Pid,
+		ppid:        process.PPid,
+		path:        process.FileEvent.PathnameStr,
+		containerID: process.ContainerID,
+	}
+}
+
+// ProcessKiller executes the kill actions of the rules. Each rule has its own rate limiter so that a noisy rule can't
+// prevent the kill actions of the other rules.
+type ProcessKiller struct {
+	sync.Mutex
+	dryRun       bool
+	limit        rate.Limit
+	burst        int
+	limiters     map[rules.RuleID]*rate.Limiter
+	protected    map[string]bool
+	selfPid      uint32
+	statsdClient statsd.ClientInterface
+	walkFnc      func(callback func(entry *model.ProcessCacheEntry))
+	resolveFnc   func(pid, tid uint32) *model.ProcessCacheEntry
+	killFnc      func(pid int, sig syscall.Signal) error
+}
+
+// NewProcessKiller returns a new ProcessKiller
+func NewProcessKiller(cfg *sconfig.Config, resolver *sprobe.ProcessResolver, statsdClient statsd.ClientInterface) *ProcessKiller {
+	protected := make(map[string]bool)
+	for _, path := range defaultProtectedProcesses {
+		protected[path] = true
+	}
+	for _, path := range cfg.EnforcementProtectedProcesses {
+		protected[path] = true
+	}
+
+	return &ProcessKiller{
+		dryRun:       cfg.EnforcementDryRun,
+		limit:        rate.Limit(cfg.EnforcementRate),
+		burst:        cfg.EnforcementBurst,
+		limiters:     make(map[rules.RuleID]*rate.Limiter),
+		protected:    protected,
+		selfPid:      uint32(os.Getpid()),
+		statsdClient: statsdClient,
+		walkFnc:      resolver.Walk,
+		resolveFnc:   resolver.Resolve,
+		killFnc:      syscall.Kill,
+	}
+}
+
+// Apply drops the rate limiters of the rules that are no longer loaded
+func (p *ProcessKiller) Apply(ruleIDs []rules.RuleID) {
+	p.Lock()
+	defer p.Unlock()
+
+	limiters := make(map[rules.RuleID]*rate.Limiter)
+	for _, id := range ruleIDs {
+		if limiter, found := p.limiters[id]; found {
+			limiters[id] = limiter
+		}
+	}
+	p.limiters = limiters
+}
+
+// allow returns true if the rate limiter of the rule allows a new kill action
+func (p *ProcessKiller) allow(ruleID rules.RuleID) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	limiter, found := p.limiters[ruleID]
+	if !found {
+		limiter = rate.NewLimiter(p.limit, p.burst)
+		p.limiters[ruleID] = limiter
+	}
+	return limiter.Allow()
+}
+
+// KillAndReport executes the kill actions of the rule against the process of the event and returns their outcome
+func (p *ProcessKiller) KillAndReport(rule *rules.Rule, event *sprobe.Event) []RuleActionReport {
+	var reports []RuleActionReport
+	if event.ProcessContext == nil {
+		return reports
+	}
+
+	for _, action := range rule.Definition.Actions {
+		if action.Kill == nil {
+			continue
+		}
+
+		report := p.kill(rule.ID, action.Kill, &event.ProcessContext.Process)
+		if report.Status == KillActionStatusError {
+			seclog.Errorf("failed to execute kill action of rule `%s`: %s", rule.ID, report.Error)
+		} else {
+			seclog.Debugf("kill action of rule `%s` on %v: %s", rule.ID, report.Pids, report.Status)
+		}
+
+		tags := []string{"rule_id:" + rule.ID, "status:" + report.Status}
+		_ = p.statsdClient.Count(metrics.MetricRuleActionKill, 1, tags, 1.0)
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+func (p *ProcessKiller) kill(ruleID rules.RuleID, kill *rules.KillDefinition, process *model.Process) RuleActionReport {
+	report := RuleActionReport{
+		Type:   ruleActionKill,
+		Signal: kill.GetSignal(),
+		Scope:  kill.GetScope(),
+	}
+
+	sig := syscall.Signal(rules.KillSignals[report.Signal])
+	if sig == 0 {
+		report.Status = KillActionStatusError
+		report.Error = fmt.Sprintf("unknown signal %s", report.Signal)
+		return report
+	}
+
+	targets, err := p.getTargets(report.Scope, process)
+	if err != nil {
+		report.Status = KillActionStatusError
+		report.Error = err.Error()
+		return report
+	}
+
+	selfContainerID := p.selfContainerID()
+	for _, target := range targets {
+		if !p.isProtected(target, selfContainerID) {
+			report.Pids = append(report.Pids, target.pid)
+		}
+	}
+
+	if len(report.Pids) == 0 {
+		report.Status = KillActionStatusProtected
+		return report
+	}
+
+	if !p.allow(ruleID) {
+		report.Status = KillActionStatusRateLimited
+		return report
+	}
+
+	if p.dryRun {
+		report.Status = KillActionStatusDryRun
+		return report
+	}
+
+	var errs []string
+	for _, pid := range report.Pids {
+		// the process may have already exited
+		if err := p.killFnc(int(pid), sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Sprintf("%d: %s", pid, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		report.Status = KillActionStatusError
+		report.Error = strings.Join(errs, ", ")
+		return report
+	}
+
+	report.Status = KillActionStatusPerformed
+	return report
+}
+
+func (p *ProcessKiller) getTargets(scope rules.KillScope, process *model.Process) ([]killTarget, error) {
+	switch scope {
+	case rules.KillScopeProcess:
+		return []killTarget{newKillTarget(process)}, nil
+	case rules.KillScopeContainer:
+		if process.ContainerID == "" {
+			return nil, fmt.Errorf("process %d isn't running in a container", process.Pid)
+		}
+
+		var targets []killTarget
+		p.walkFnc(func(entry *model.ProcessCacheEntry) {
+			if entry.ContainerID != process.ContainerID || !entry.ExitTime.IsZero() {
+				return
+			}
+			targets = append(targets, newKillTarget(&entry.Process))
+		})
+
+		sort.Slice(targets, func(i, j int) bool {
+			return targets[i].pid < targets[j].pid
+		})
+
+		return targets, nil
+	default:
+		return nil, fmt.Errorf("unknown kill scope %s", scope)
+	}
+}
+
+// selfContainerID returns the ID of the container of system-probe, it's empty when system-probe runs on the host
+func (p *ProcessKiller) selfContainerID() string {
+	if entry := p.resolveFnc(p.selfPid, p.selfPid); entry != nil {
+		return entry.ContainerID
+	}
+	return ""
+}
+
+// isProtected returns true if the process should never be killed: init, kernel threads, system-probe itself
+// and the processes of its container, and the host processes whose executable is protected.
+func (p *ProcessKiller) isProtected(target killTarget, selfContainerID string) bool {
+	if target.pid <= 2 || target.ppid == 2 || target.pid == p.selfPid {
+		return true
+	}
+
+	// a container image can ship any executable at the path of a protected one
+	if target.containerID != "" {
+		return target.containerID == selfContainerID
+	}
+
+	return p.protected[target.path]
+}
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package module
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+
+	sconfig "github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func newTestProcessEntry(pid, ppid uint32, path, containerID string) *model.ProcessCacheEntry {
+	entry := &model.ProcessCacheEntry{}
+	entry.Pid = pid
+	entry.PPid = ppid
+	entry.FileEvent.SetPathnameStr(path)
+	entry.Comm = filepath.Base(path)
+	entry.ContainerID = containerID
+	return entry
+}
+
+type testKiller struct {
+	*ProcessKiller
+	killed map[uint32]syscall.Signal
+}
+
+func newTestProcessKiller(cfg *sconfig.Config, entries []*model.ProcessCacheEntry, killErrs map[uint32]error) *testKiller {
+	tk := &testKiller{
+		ProcessKiller: NewProcessKiller(cfg, nil, &statsd.NoOpClient{}),
+		killed:        make(map[uint32]syscall.Signal),
+	}
+
+	tk.walkFnc = func(callback func(entry *model.ProcessCacheEntry)) {
+		for _, entry := range entries {
+			callback(entry)
+		}
+	}
+	tk.resolveFnc = func(pid, tid uint32) *model.ProcessCacheEntry {
+		for _, entry := range entries {
+			if entry.Pid == pid {
+				return entry
+			}
+		}
+		return nil
+	}
+	tk.killFnc = func(pid int, sig syscall.Signal) error {
+		if err := killErrs[uint32(pid)]; err != nil {
+			return err
+		}
+		tk.killed[uint32(pid)] = sig
+		return nil
+	}
+
+	return tk
+}
+
+func TestProcessKillerIsProtected(t *testing.T) {
+	p := NewProcessKiller(&sconfig.Config{
+		EnforcementProtectedProcesses: []string{"/usr/local/bin/my-daemon"},
+	}, nil, &statsd.NoOpClient{})
+
+	tests := []struct {
+		name      string
+		target    killTarget
+		protected bool
+	}{
+		{name: "init", target: killTarget{pid: 1, ppid: 0, path: "/bin/bash"}, protected: true},
+		{name: "kthreadd", target: killTarget{pid: 2, ppid: 0}, protected: true},
+		{name: "kernel-thread", target: killTarget{pid: 123, ppid: 2}, protected: true},
+		{name: "self", target: killTarget{pid: uint32(os.Getpid()), ppid: 1, path: "/bin/bash"}, protected: true},
+		{name: "built-in", target: killTarget{pid: 123, ppid: 1, path: "/opt/datadog-agent/embedded/bin/system-probe"}, protected: true},
+		{name: "container-runtime", target: killTarget{pid: 123, ppid: 1, path: "/usr/bin/containerd-shim"}, protected: true},
+		{name: "configured", target: killTarget{pid: 123, ppid: 1, path: "/usr/local/bin/my-daemon"}, protected: true},
+		{name: "regular", target: killTarget{pid: 123, ppid: 1, path: "/bin/bash"}, protected: false},
+		{name: "same-name", target: killTarget{pid: 123, ppid: 1, path: "/tmp/sshd"}, protected: false},
+		{name: "unresolved-path", target: killTarget{pid: 123, ppid: 1}, protected: false},
+		{name: "in-container", target: killTarget{pid: 123, ppid: 1, path: "/usr/sbin/sshd", containerID: "cid1"}, protected: false},
+		{name: "self-container", target: killTarget{pid: 123, ppid: 1, path: "/bin/bash", containerID: "agent"}, protected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.protected, p.isProtected(test.target, "agent"))
+		})
+	}
+}
+
+func TestProcessKillerKill(t *testing.T) {
+	exited := newTestProcessEntry(1003, 1000, "/bin/sleep", "cid1")
+	exited.ExitTime = time.Now()
+
+	entries := []*model.ProcessCacheEntry{
+		newTestProcessEntry(1002, 1000, "/bin/sh", "cid1"),
+		newTestProcessEntry(1000, 1, "/bin/bash", "cid1"),
+		exited,
+		newTestProcessEntry(1004, 1000, "/usr/sbin/sshd", "cid1"),
+		newTestProcessEntry(2000, 1, "/bin/bash", "cid2"),
+		newTestProcessEntry(3000, 1, "/bin/bash", ""),
+		newTestProcessEntry(uint32(os.Getpid()), 1, "/opt/datadog-agent/embedded/bin/system-probe", "agent"),
+		newTestProcessEntry(4000, 1, "/opt/datadog-agent/bin/agent/agent", "agent"),
+	}
+
+	tests := []struct {
+		name     string
+		cfg      sconfig.Config
+		kill     rules.KillDefinition
+		process  *model.ProcessCacheEntry
+		killErrs map[uint32]error
+		status   string
+		pids     []uint32
+		killed   map[uint32]syscall.Signal
+	}{
+		{
+			name:    "process",
+			kill:    rules.KillDefinition{},
+			process: newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			status:  KillActionStatusPerformed,
+			pids:    []uint32{3000},
+			killed:  map[uint32]syscall.Signal{3000: syscall.SIGKILL},
+		},
+		{
+			name:    "process-signal",
+			kill:    rules.KillDefinition{Signal: "SIGTERM"},
+			process: newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			status:  KillActionStatusPerformed,
+			pids:    []uint32{3000},
+			killed:  map[uint32]syscall.Signal{3000: syscall.SIGTERM},
+		},
+		{
+			name:    "unknown-signal",
+			kill:    rules.KillDefinition{Signal: "SIGFOO"},
+			process: newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			status:  KillActionStatusError,
+			killed:  map[uint32]syscall.Signal{},
+		},
+		{
+			name:    "protected",
+			kill:    rules.KillDefinition{},
+			process: newTestProcessEntry(3000, 1, "/usr/bin/dockerd", ""),
+			status:  KillActionStatusProtected,
+			killed:  map[uint32]syscall.Signal{},
+		},
+		{
+			name:    "renamed",
+			kill:    rules.KillDefinition{},
+			process: newTestProcessEntry(3000, 1, "/tmp/sshd", ""),
+			status:  KillActionStatusPerformed,
+			pids:    []uint32{3000},
+			killed:  map[uint32]syscall.Signal{3000: syscall.SIGKILL},
+		},
+		{
+			name:    "dry-run",
+			cfg:     sconfig.Config{EnforcementDryRun: true},
+			kill:    rules.KillDefinition{},
+			process: newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			status:  KillActionStatusDryRun,
+			pids:    []uint32{3000},
+			killed:  map[uint32]syscall.Signal{},
+		},
+		{
+			name:    "container",
+			kill:    rules.KillDefinition{Scope: rules.KillScopeContainer},
+			process: newTestProcessEntry(1002, 1000, "/bin/sh", "cid1"),
+			status:  KillActionStatusPerformed,
+			pids:    []uint32{1000, 1002, 1004},
+			killed:  map[uint32]syscall.Signal{1000: syscall.SIGKILL, 1002: syscall.SIGKILL, 1004: syscall.SIGKILL},
+		},
+		{
+			name:    "agent-container",
+			kill:    rules.KillDefinition{Scope: rules.KillScopeContainer},
+			process: newTestProcessEntry(4000, 1, "/opt/datadog-agent/bin/agent/agent", "agent"),
+			status:  KillActionStatusProtected,
+			killed:  map[uint32]syscall.Signal{},
+		},
+		{
+			name:    "container-without-container",
+			kill:    rules.KillDefinition{Scope: rules.KillScopeContainer},
+			process: newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			status:  KillActionStatusError,
+			killed:  map[uint32]syscall.Signal{},
+		},
+		{
+			name:     "already-exited",
+			kill:     rules.KillDefinition{Scope: rules.KillScopeContainer},
+			process:  newTestProcessEntry(1002, 1000, "/bin/sh", "cid1"),
+			killErrs: map[uint32]error{1000: syscall.ESRCH},
+			status:   KillActionStatusPerformed,
+			pids:     []uint32{1000, 1002, 1004},
+			killed:   map[uint32]syscall.Signal{1002: syscall.SIGKILL, 1004: syscall.SIGKILL},
+		},
+		{
+			name:     "kill-error",
+			kill:     rules.KillDefinition{},
+			process:  newTestProcessEntry(3000, 1, "/bin/bash", ""),
+			killErrs: map[uint32]error{3000: syscall.EPERM},
+			status:   KillActionStatusError,
+			pids:     []uint32{3000},
+			killed:   map[uint32]syscall.Signal{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			cfg.EnforcementRate = 10
+			cfg.EnforcementBurst = 10
+
+			p := newTestProcessKiller(&cfg, entries, test.killErrs)
+			report := p.kill("test_rule", &test.kill, &test.process.Process)
+
+			assert.Equal(t, test.status, report.Status)
+			assert.Equal(t, test.pids, report.Pids)
+			assert.Equal(t, test.killed, p.killed)
+			if test.status == KillActionStatusError {
+				assert.NotEmpty(t, report.Error)
+			}
+		})
+	}
+}
+
+func TestProcessKillerRateLimiter(t *testing.T) {
+	p := newTestProcessKiller(&sconfig.Config{
+		EnforcementRate:  0,
+		EnforcementBurst: 1,
+	}, nil, nil)
+
+	kill := &rules.KillDefinition{}
+	process := &newTestProcessEntry(3000, 1, "/bin/bash", "").Process
+
+	assert.Equal(t, KillActionStatusPerformed, p.kill("rule_1", kill, process).Status)
+	assert.Equal(t, KillActionStatusRateLimited, p.kill("rule_1", kill, process).Status)
+
+	// the limit of a rule doesn't affect the other rules
+	assert.Equal(t, KillActionStatusPerformed, p.kill("rule_2", kill, process).Status)
+
+	// protected processes don't consume the budget of the rule
+	assert.Equal(t, KillActionStatusProtected, p.kill("rule_3", kill, &newTestProcessEntry(3001, 1, "/usr/sbin/sshd", "").Process).Status)
+	assert.Equal(t, KillActionStatusPerformed, p.kill("rule_3", kill, process).Status)
+
+	// the limiters of the rules that are no longer loaded are dropped
+	p.Apply([]rules.RuleID{"rule_2"})
+	assert.Len(t, p.limiters, 1)
+	assert.Equal(t, KillActionStatusPerformed, p.kill("rule_1", kill, process).Status)
+	assert.Equal(t, KillActionStatusRateLimited, p.kill("rule_2", kill, process).Status)
+}
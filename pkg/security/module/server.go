@@ -384,11 +384,12 @@ func (a *APIServer) RunSelfTest(ctx context.Context, params *api.RunSelfTestPara
 }
 
 // SendEvent forwards events sent by the runtime security module to Datadog
-func (a *APIServer) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []string, service string) {
+func (a *APIServer) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []string, service string, actionReports []RuleActionReport) {
 	agentContext := AgentContext{
 		RuleID:      rule.Definition.ID,
 		RuleVersion: rule.Definition.Version,
 		Version:     version.AgentVersion,
+		RuleActions: actionReports,
 	}
 
 	ruleEvent := &Signal{
@@ -363,6 +363,8 @@ func (ad *ActivityDump) Insert(event *Event) (newEntry bool) {
 	switch event.GetEventType() {
 	case model.FileOpenEventType:
 		return node.InsertFileEvent(&event.Open.File, event, Runtime)
+	case model.DNSEventType:
+		return node.InsertDNSEvent(event, Runtime)
 	}
 	return false
 }
@@ -662,6 +664,7 @@ type ProcessActivityNode struct {
 	GenerationType NodeGenerationType `msg:"generation_type"`
 
 	Files    map[string]*FileActivityNode `msg:"files,omitempty"`
+	DNSNames map[string]*DNSNode          `msg:"dns_names,omitempty"`
 	Children []*ProcessActivityNode       `msg:"children,omitempty"`
 }
 
@@ -679,6 +682,7 @@ func NewProcessActivityNode(entry *model.ProcessCacheEntry, generationType NodeG
 		Process:        entry.Process,
 		GenerationType: generationType,
 		Files:          make(map[string]*FileActivityNode),
+		DNSNames:       make(map[string]*DNSNode),
 	}
 	_ = pan.GetID()
 	pan.retain()
@@ -795,6 +799,11 @@ func (pan *ProcessActivityNode) InsertFileEvent(fileEvent *model.FileEvent, even
 
 	// TODO: look for patterns / merge algo
 
+	// the map is empty when the node was decoded from a dump without any file
+	if pan.Files == nil {
+		pan.Files = make(map[string]*FileActivityNode)
+	}
+
 	child, ok := pan.Files[parent]
 	if ok {
 		return child.InsertFileEvent(fileEvent, event, fileEvent.PathnameStr[nextParentIndex:], generationType)
@@ -811,6 +820,26 @@ func (pan *ProcessActivityNode) InsertFileEvent(fileEvent *model.FileEvent, even
 	return true
 }
 
+// InsertDNSEvent inserts the provided DNS event in the current node. This function returns true if a new entry was
+// added, false if the event was dropped.
+func (pan *ProcessActivityNode) InsertDNSEvent(event *Event, generationType NodeGenerationType) bool {
+	if len(event.DNS.Name) == 0 {
+		return false
+	}
+
+	// the map is empty when the node was decoded from a dump without any DNS request
+	if pan.DNSNames == nil {
+		pan.DNSNames = make(map[string]*DNSNode)
+	}
+
+	if _, ok := pan.DNSNames[event.DNS.Name]; ok {
+		return false
+	}
+
+	pan.DNSNames[event.DNS.Name] = NewDNSNode(event, generationType)
+	return true
+}
+
 // snapshot uses procfs to retrieve information about the current process
 func (pan *ProcessActivityNode) snapshot(ad *ActivityDump) error {
 	// call snapshot for all the children of the current node
@@ -992,6 +1021,11 @@ func (fan *FileActivityNode) InsertFileEvent(fileEvent *model.FileEvent, event *
 
 	// TODO: look for patterns / merge algo
 
+	// the map is empty when the node was decoded from a dump without any child
+	if fan.Children == nil {
+		fan.Children = make(map[string]*FileActivityNode)
+	}
+
 	child, ok := fan.Children[parent]
 	if ok {
 		return child.InsertFileEvent(fileEvent, event, remainingPath[nextParentIndex:], generationType)
@@ -1015,3 +1049,17 @@ func (fan *FileActivityNode) debug(prefix string) {
 		child.debug("\t" + prefix)
 	}
 }
+
+// DNSNode holds the activity of a process for a queried domain name
+type DNSNode struct {
+	GenerationType NodeGenerationType `msg:"generation_type"`
+	FirstSeen      time.Time          `msg:"first_seen,omitempty"`
+}
+
+// NewDNSNode returns a new DNSNode instance
+func NewDNSNode(event *Event, generationType NodeGenerationType) *DNSNode {
+	return &DNSNode{
+		GenerationType: generationType,
+		FirstSeen:      event.ResolveEventTimestamp(),
+	}
+}
@@ -32,6 +32,10 @@ const (
 	AbnormalPathRuleID = "abnormal_path"
 	// SelfTestRuleID is the rule ID for the self_test events
 	SelfTestRuleID = "self_test"
+	// AnomalyDetectionRuleID is the rule ID for the anomaly_detection events
+	AnomalyDetectionRuleID = "anomaly_detection"
+	// FileIntegrityRuleID is the rule ID for the file_integrity events
+	FileIntegrityRuleID = "file_integrity"
 )
 
 // AllCustomRuleIDs returns the list of custom rule IDs
@@ -42,6 +46,8 @@ func AllCustomRuleIDs() []string {
 		NoisyProcessRuleID,
 		AbnormalPathRuleID,
 		SelfTestRuleID,
+		AnomalyDetectionRuleID,
+		FileIntegrityRuleID,
 	}
 }
 
@@ -347,3 +353,56 @@ func NewSelfTestEvent(success []string, fails []string) (*rules.Rule, *CustomEve
 			Fails:     fails,
 		})
 }
+
+// AnomalyDetectionEvent is used to report an activity that isn't part of the security profile of a workload
+// easyjson:json
+type AnomalyDetectionEvent struct {
+	Timestamp   time.Time        `json:"date"`
+	Event       *EventSerializer `json:"triggering_event"`
+	AnomalyType string           `json:"anomaly_type"`
+	Profile     string           `json:"profile"`
+}
+
+// NewAnomalyDetectionEvent returns the rule and a populated custom event for an anomaly_detection event
+func NewAnomalyDetectionEvent(event *Event, anomalyType AnomalyType, profile string) (*rules.Rule, *CustomEvent) {
+	return newRule(&rules.RuleDefinition{
+			ID: AnomalyDetectionRuleID,
+		}), newCustomEvent(model.CustomAnomalyDetectionEventType, AnomalyDetectionEvent{
+			Timestamp:   event.ResolveEventTimestamp(),
+			Event:       NewEventSerializer(event),
+			AnomalyType: string(anomalyType),
+			Profile:     profile,
+		})
+}
+
+// FileIntegrityEvent is used to report the change of the content of a monitored file
+// easyjson:json
+type FileIntegrityEvent struct {
+	Timestamp  time.Time        `json:"date"`
+	Source     string           `json:"source"`
+	Path       string           `json:"path"`
+	Algorithm  string           `json:"algorithm"`
+	HashBefore string           `json:"hash_before,omitempty"`
+	HashAfter  string           `json:"hash_after,omitempty"`
+	SizeBefore int64            `json:"size_before"`
+	SizeAfter  int64            `json:"size_after"`
+	Event      *EventSerializer `json:"triggering_event,omitempty"`
+}
+
+// NewFileIntegrityEvent returns the rule and a populated custom event for a file_integrity event. The triggering event
+// is nil when the change was detected by a baseline scan.
+func NewFileIntegrityEvent(timestamp time.Time, event *EventSerializer, source string, path string, before fimHashEntry, after fimHashEntry) (*rules.Rule, *CustomEvent) {
+	return newRule(&rules.RuleDefinition{
+			ID: FileIntegrityRuleID,
+		}), newCustomEvent(model.CustomFileIntegrityEventType, FileIntegrityEvent{
+			Timestamp:  timestamp,
+			Source:     source,
+			Path:       path,
+			Algorithm:  fimHashAlgorithm,
+			HashBefore: before.hash,
+			HashAfter:  after.hash,
+			SizeBefore: before.size,
+			SizeAfter:  after.size,
+			Event:      event,
+		})
+}
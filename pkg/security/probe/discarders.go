@@ -673,4 +673,6 @@ func init() {
 	allDiscarderHandlers["unload_module"] = processDiscarderWrapper(model.UnloadModuleEventType, nil)
 	allDiscarderHandlers["signal"] = processDiscarderWrapper(model.SignalEventType, nil)
 	allDiscarderHandlers["bind"] = processDiscarderWrapper(model.BindEventType, nil)
+	allDiscarderHandlers["connect"] = processDiscarderWrapper(model.ConnectEventType, nil)
+	allDiscarderHandlers["accept"] = processDiscarderWrapper(model.AcceptEventType, nil)
 }
@@ -30,6 +30,7 @@ func (ev *Event) ResolveFields() {
 	_ = ev.ResolveFileFieldsUser(&ev.ProcessContext.Process.FileEvent.FileFields)
 	// resolve event specific fields
 	switch ev.GetEventType().String() {
+	case "accept":
 	case "bind":
 	case "bpf":
 		_ = ev.ResolveHelpers(&ev.BPF.Program)
@@ -50,6 +51,7 @@ func (ev *Event) ResolveFields() {
 		_ = ev.ResolveFileFilesystem(&ev.Chown.File)
 		_ = ev.ResolveChownUID(&ev.Chown)
 		_ = ev.ResolveChownGID(&ev.Chown)
+	case "connect":
 	case "dns":
 	case "exec":
 		_ = ev.ResolveFileFieldsUser(&ev.Exec.Process.FileEvent.FileFields)
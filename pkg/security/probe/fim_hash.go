@@ -0,0 +1,421 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/pkg/errors"
+
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+const (
+	// FIMHashPolicySource is the source of the policy used to enable the open events of the monitored files
+	FIMHashPolicySource  = "fim-hash"
+	fimHashPolicyName    = "datadog-agent-cws-fim-hash-policy"
+	fimHashPolicyVersion = "1.0.0"
+	fimHashRuleIDPrefix  = "datadog_agent_cws_fim_hash_rule"
+
+	fimHashAlgorithm = "sha256"
+	// fimHashCacheSize is the maximum number of files for which the last hash is kept
+	fimHashCacheSize = 65536
+	// fimHashQueueSize is the maximum number of closed files waiting to be hashed
+	fimHashQueueSize = 1024
+
+	// fimHashRuntimeSource is the source of the changes detected when a file is closed after being written
+	fimHashRuntimeSource = "runtime"
+	// fimHashBaselineSource is the source of the changes detected by a baseline scan
+	fimHashBaselineSource = "baseline"
+
+	fimHashSkippedTooLarge  = "too_large"
+	fimHashSkippedQueueFull = "queue_full"
+	fimHashSkippedReadError = "read_error"
+)
+
+var (
+	errFIMHashFileTooLarge = errors.New("file too large")
+	errFIMHashNotRegular   = errors.New("not a regular file")
+)
+
+// fimHashEntry holds the hash and the size of the content of a file
+type fimHashEntry struct {
+	hash string
+	size int64
+}
+
+// fimHashKey identifies a monitored file, the same path can be monitored in multiple containers
+type fimHashKey struct {
+	containerID string
+	path        string
+}
+
+// fimHashRequest is queued when a monitored file is closed after being written
+type fimHashRequest struct {
+	key        fimHashKey
+	readPath   string
+	timestamp  time.Time
+	serializer *EventSerializer
+}
+
+// FIMHashManager hashes the content of the monitored files once they are closed after being written, and reports the
+// files whose content changed
+type FIMHashManager struct {
+	sync.Mutex
+	probe *Probe
+
+	patterns           []string
+	globs              []*eval.Glob
+	maxFileSize        int64
+	baselineScanPeriod time.Duration
+	hostRoot           string
+
+	cache    *simplelru.LRU
+	requests chan fimHashRequest
+	changes  map[string]*uint64
+	skipped  map[string]*uint64
+	dispatch func(rule *rules.Rule, event *CustomEvent)
+}
+
+// NewFIMHashManager returns a new instance of FIMHashManager
+func NewFIMHashManager(p *Probe) (*FIMHashManager, error) {
+	fhm, err := newFIMHashManager(p.config.FIMHashPaths, p.config.FIMHashMaxFileSize, p.config.FIMHashBaselineScanPeriod, p.DispatchCustomEvent)
+	if err != nil {
+		return nil, err
+	}
+	fhm.probe = p
+	// the baseline scans read the host file system through the root of the init process
+	fhm.hostRoot = utils.RootPath(1)
+	return fhm, nil
+}
+
+func newFIMHashManager(patterns []string, maxFileSize int64, baselineScanPeriod time.Duration, dispatch func(rule *rules.Rule, event *CustomEvent)) (*FIMHashManager, error) {
+	cache, err := simplelru.NewLRU(fimHashCacheSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fhm := &FIMHashManager{
+		patterns:           patterns,
+		maxFileSize:        maxFileSize,
+		baselineScanPeriod: baselineScanPeriod,
+		cache:              cache,
+		requests:           make(chan fimHashRequest, fimHashQueueSize),
+		changes:            make(map[string]*uint64),
+		skipped:            make(map[string]*uint64),
+		dispatch:           dispatch,
+	}
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) || strings.Contains(pattern, `"`) {
+			return nil, fmt.Errorf("invalid FIM hash path `%s`: an absolute path is expected", pattern)
+		}
+
+		glob, err := eval.NewGlob(pattern, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FIM hash path `%s`: %w", pattern, err)
+		}
+		fhm.globs = append(fhm.globs, glob)
+	}
+
+	for _, source := range []string{fimHashRuntimeSource, fimHashBaselineSource} {
+		fhm.changes[source] = new(uint64)
+	}
+	for _, reason := range []string{fimHashSkippedTooLarge, fimHashSkippedQueueFull, fimHashSkippedReadError} {
+		fhm.skipped[reason] = new(uint64)
+	}
+
+	return fhm, nil
+}
+
+// FIMHashPolicyProvider generates the policy that enables the open events of the files monitored by the FIM hash
+// capture
+type FIMHashPolicyProvider struct {
+	patterns []string
+}
+
+var _ rules.PolicyProvider = (*FIMHashPolicyProvider)(nil)
+
+// GetPolicyProvider returns the policy provider of the monitored files
+func (fhm *FIMHashManager) GetPolicyProvider() *FIMHashPolicyProvider {
+	return &FIMHashPolicyProvider{patterns: fhm.patterns}
+}
+
+// LoadPolicies implements the PolicyProvider interface
+func (p *FIMHashPolicyProvider) LoadPolicies() ([]*rules.Policy, *multierror.Error) {
+	policy := &rules.Policy{
+		Name:    fimHashPolicyName,
+		Source:  FIMHashPolicySource,
+		Version: fimHashPolicyVersion,
+	}
+
+	for i, pattern := range p.patterns {
+		policy.AddRule(&rules.RuleDefinition{
+			ID:         fmt.Sprintf("%s_%d", fimHashRuleIDPrefix, i),
+			Expression: fmt.Sprintf(`open.file.path =~ "%s" && open.flags & (O_WRONLY | O_RDWR) > 0`, pattern),
+		})
+	}
+
+	return []*rules.Policy{policy}, nil
+}
+
+// SetOnNewPoliciesReadyCb implements the PolicyProvider interface
+func (p *FIMHashPolicyProvider) SetOnNewPoliciesReadyCb(cb func()) {}
+
+// Start implements the PolicyProvider interface
+func (p *FIMHashPolicyProvider) Start() {}
+
+// Close implements the PolicyProvider interface
+func (p *FIMHashPolicyProvider) Close() error {
+	return nil
+}
+
+// IsFIMHashRule returns true if the provided rule was generated by the FIM hash capture
+func IsFIMHashRule(rule *rules.Rule) bool {
+	return rule.Definition != nil && rule.Definition.Policy != nil && rule.Definition.Policy.Source == FIMHashPolicySource
+}
+
+// matches returns true if the provided path is monitored
+func (fhm *FIMHashManager) matches(path string) bool {
+	for _, glob := range fhm.globs {
+		if glob.Matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Start hashes the monitored files closed after being written, and runs the baseline scans. The first baseline scan
+// only records the current content of the monitored files.
+func (fhm *FIMHashManager) Start(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	fhm.scan(false)
+
+	var scanTick <-chan time.Time
+	if fhm.baselineScanPeriod > 0 {
+		ticker := time.NewTicker(fhm.baselineScanPeriod)
+		defer ticker.Stop()
+		scanTick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-fhm.requests:
+			fhm.handleRequest(req)
+		case <-scanTick:
+			fhm.scan(true)
+		}
+	}
+}
+
+// HandleCloseEvent queues the file of the provided close event for hashing if it is monitored
+func (fhm *FIMHashManager) HandleCloseEvent(event *Event) {
+	path := event.ResolveFilePath(&event.Close.File)
+	if event.GetPathResolutionError() != nil || !fhm.matches(path) {
+		return
+	}
+
+	req := fimHashRequest{
+		key: fimHashKey{
+			containerID: event.ResolveContainerID(&event.ContainerContext),
+			path:        path,
+		},
+		timestamp:  event.ResolveEventTimestamp(),
+		serializer: NewEventSerializer(event),
+	}
+	req.readPath = fhm.readPath(req.key.containerID, event.ProcessContext.Pid, path)
+
+	select {
+	case fhm.requests <- req:
+	default:
+		atomic.AddUint64(fhm.skipped[fimHashSkippedQueueFull], 1)
+	}
+}
+
+// readPath returns the path through which a file closed by the provided process is read. The files of a container are
+// read through the root of the process that wrote them, the files of the host through the root used by the baseline
+// scans so that both read the same file when system-probe runs in a container.
+func (fhm *FIMHashManager) readPath(containerID string, pid uint32, path string) string {
+	if len(containerID) > 0 {
+		return filepath.Join(utils.RootPath(int32(pid)), path)
+	}
+	return filepath.Join(fhm.hostRoot, path)
+}
+
+func (fhm *FIMHashManager) handleRequest(req fimHashRequest) {
+	if before, after, changed := fhm.update(req.key, req.readPath); changed {
+		atomic.AddUint64(fhm.changes[fimHashRuntimeSource], 1)
+		fhm.dispatch(NewFileIntegrityEvent(req.timestamp, req.serializer, fimHashRuntimeSource, req.key.path, before, after))
+	}
+}
+
+// update hashes the provided file and returns its previous and current hashes, and true if they differ
+func (fhm *FIMHashManager) update(key fimHashKey, readPath string) (fimHashEntry, fimHashEntry, bool) {
+	after, err := hashFile(readPath, fhm.maxFileSize)
+	if err != nil {
+		if errors.Is(err, errFIMHashFileTooLarge) {
+			atomic.AddUint64(fhm.skipped[fimHashSkippedTooLarge], 1)
+		} else {
+			atomic.AddUint64(fhm.skipped[fimHashSkippedReadError], 1)
+		}
+		seclog.Debugf("couldn't hash %s: %v", readPath, err)
+		return fimHashEntry{}, fimHashEntry{}, false
+	}
+
+	fhm.Lock()
+	defer fhm.Unlock()
+
+	var before fimHashEntry
+	if entry, found := fhm.cache.Get(key); found {
+		before = entry.(fimHashEntry)
+	}
+	fhm.cache.Add(key, after)
+
+	return before, after, before != after
+}
+
+// globPrefix returns the longest directory of the provided pattern that doesn't contain any wildcard
+func globPrefix(pattern string) string {
+	elements := strings.Split(pattern, "/")
+	for i, element := range elements {
+		if strings.Contains(element, "*") {
+			if prefix := strings.Join(elements[:i], "/"); len(prefix) > 0 {
+				return prefix
+			}
+			return "/"
+		}
+	}
+	return pattern
+}
+
+// scan walks the monitored files of the host and hashes them. When report is set, the changes since the previous scan
+// are dispatched.
+func (fhm *FIMHashManager) scan(report bool) {
+	seen := make(map[string]bool)
+
+	for i, glob := range fhm.globs {
+		root := filepath.Join(fhm.hostRoot, globPrefix(fhm.patterns[i]))
+
+		err := filepath.WalkDir(root, func(walkPath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// unreadable directories are skipped
+				return nil
+			}
+
+			path := walkPath
+			if len(fhm.hostRoot) > 0 {
+				path = "/" + strings.TrimPrefix(strings.TrimPrefix(walkPath, fhm.hostRoot), "/")
+			}
+
+			if entry.IsDir() {
+				if walkPath != root && !glob.Contains(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !entry.Type().IsRegular() || seen[path] || !glob.Matches(path) {
+				return nil
+			}
+			seen[path] = true
+
+			before, after, changed := fhm.update(fimHashKey{path: path}, walkPath)
+			if changed && report {
+				atomic.AddUint64(fhm.changes[fimHashBaselineSource], 1)
+				fhm.dispatch(NewFileIntegrityEvent(time.Now(), nil, fimHashBaselineSource, path, before, after))
+			}
+			return nil
+		})
+		if err != nil {
+			seclog.Debugf("couldn't scan %s: %v", root, err)
+		}
+	}
+}
+
+// hashFile returns the SHA-256 hash and the size of the content of the provided file
+func hashFile(path string, maxFileSize int64) (fimHashEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fimHashEntry{}, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return fimHashEntry{}, err
+	}
+
+	if !fi.Mode().IsRegular() {
+		return fimHashEntry{}, errFIMHashNotRegular
+	}
+
+	if maxFileSize > 0 && fi.Size() > maxFileSize {
+		return fimHashEntry{}, errFIMHashFileTooLarge
+	}
+
+	var reader io.Reader = f
+	if maxFileSize > 0 {
+		// the file might grow while it is being read
+		reader = io.LimitReader(f, maxFileSize)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(h, reader)
+	if err != nil {
+		return fimHashEntry{}, err
+	}
+
+	return fimHashEntry{
+		hash: hex.EncodeToString(h.Sum(nil)),
+		size: size,
+	}, nil
+}
+
+// SendStats sends the FIM hash capture stats
+func (fhm *FIMHashManager) SendStats() error {
+	for source, count := range fhm.changes {
+		if value := atomic.SwapUint64(count, 0); value > 0 {
+			tags := []string{fmt.Sprintf("source:%s", source)}
+			if err := fhm.probe.statsdClient.Count(metrics.MetricFIMHashChanges, int64(value), tags, 1.0); err != nil {
+				return errors.Wrapf(err, "couldn't send %s metric", metrics.MetricFIMHashChanges)
+			}
+		}
+	}
+
+	for reason, count := range fhm.skipped {
+		if value := atomic.SwapUint64(count, 0); value > 0 {
+			tags := []string{fmt.Sprintf("reason:%s", reason)}
+			if err := fhm.probe.statsdClient.Count(metrics.MetricFIMHashSkipped, int64(value), tags, 1.0); err != nil {
+				return errors.Wrapf(err, "couldn't send %s metric", metrics.MetricFIMHashSkipped)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+func TestGlobPrefix(t *testing.T) {
+	assert.Equal(t, "/etc", globPrefix("/etc/*.conf"))
+	assert.Equal(t, "/etc/ssh", globPrefix("/etc/ssh/**"))
+	assert.Equal(t, "/etc/passwd", globPrefix("/etc/passwd"))
+	assert.Equal(t, "/", globPrefix("/*/passwd"))
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test")
+	assert.NoError(t, os.WriteFile(path, []byte("hello"), 0600))
+
+	entry, err := hashFile(path, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", entry.hash)
+	assert.Equal(t, int64(5), entry.size)
+
+	_, err = hashFile(path, 4)
+	assert.ErrorIs(t, err, errFIMHashFileTooLarge)
+
+	_, err = hashFile(filepath.Dir(path), 0)
+	assert.Error(t, err)
+}
+
+func TestFIMHashManagerInvalidPaths(t *testing.T) {
+	for _, pattern := range []string{"etc/passwd", `/etc/"passwd`, "/etc/**/passwd"} {
+		_, err := newFIMHashManager([]string{pattern}, 0, 0, nil)
+		assert.Error(t, err, pattern)
+	}
+}
+
+func TestFIMHashManagerPolicy(t *testing.T) {
+	fhm, err := newFIMHashManager([]string{"/etc/*.conf", "/etc/ssh/**"}, 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policies, errs := fhm.GetPolicyProvider().LoadPolicies()
+	assert.Nil(t, errs)
+	if assert.Len(t, policies, 1) && assert.Len(t, policies[0].Rules, 2) {
+		assert.Equal(t, FIMHashPolicySource, policies[0].Source)
+		assert.Equal(t, `open.file.path =~ "/etc/ssh/**" && open.flags & (O_WRONLY | O_RDWR) > 0`, policies[0].Rules[1].Expression)
+	}
+
+	assert.True(t, fhm.matches("/etc/resolv.conf"))
+	assert.True(t, fhm.matches("/etc/ssh/sshd_config"))
+	assert.False(t, fhm.matches("/etc/passwd"))
+}
+
+func TestFIMHashManagerScan(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "conf.d"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.conf"), []byte("a"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), []byte("a"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "other.conf"), []byte("a"), 0600))
+
+	var events []FileIntegrityEvent
+	dispatch := func(rule *rules.Rule, event *CustomEvent) {
+		assert.Equal(t, FileIntegrityRuleID, rule.ID)
+		events = append(events, event.marshaler.(FileIntegrityEvent))
+	}
+
+	fhm, err := newFIMHashManager([]string{filepath.Join(dir, "*.conf")}, 0, time.Hour, dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first scan only records the content of the monitored files
+	fhm.scan(false)
+	assert.Empty(t, events)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.conf"), []byte("ab"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), []byte("ab"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "conf.d", "other.conf"), []byte("ab"), 0600))
+
+	fhm.scan(true)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, fimHashBaselineSource, events[0].Source)
+		assert.Equal(t, filepath.Join(dir, "app.conf"), events[0].Path)
+		assert.Equal(t, int64(1), events[0].SizeBefore)
+		assert.Equal(t, int64(2), events[0].SizeAfter)
+		assert.NotEqual(t, events[0].HashBefore, events[0].HashAfter)
+		assert.Nil(t, events[0].Event)
+	}
+
+	// unchanged files aren't reported
+	fhm.scan(true)
+	assert.Len(t, events, 1)
+
+	// a change detected at runtime is reported once
+	fhm.handleRequest(fimHashRequest{
+		key:      fimHashKey{path: filepath.Join(dir, "app.conf")},
+		readPath: filepath.Join(dir, "app.conf"),
+	})
+	assert.Len(t, events, 1)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "app.conf"), []byte("abc"), 0600))
+	fhm.handleRequest(fimHashRequest{
+		key:      fimHashKey{path: filepath.Join(dir, "app.conf")},
+		readPath: filepath.Join(dir, "app.conf"),
+	})
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, fimHashRuntimeSource, events[1].Source)
+		assert.Equal(t, int64(3), events[1].SizeAfter)
+	}
+}
+
+func TestFIMHashManagerReadPath(t *testing.T) {
+	hostRoot := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(hostRoot, "etc"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(hostRoot, "etc", "app.conf"), []byte("a"), 0600))
+
+	var events []FileIntegrityEvent
+	dispatch := func(rule *rules.Rule, event *CustomEvent) {
+		events = append(events, event.marshaler.(FileIntegrityEvent))
+	}
+
+	fhm, err := newFIMHashManager([]string{"/etc/*.conf"}, 0, time.Hour, dispatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fhm.hostRoot = hostRoot
+
+	assert.Equal(t, filepath.Join(hostRoot, "etc", "app.conf"), fhm.readPath("", 1234, "/etc/app.conf"))
+	assert.Equal(t, filepath.Join(utils.RootPath(1234), "etc", "app.conf"), fhm.readPath("cid", 1234, "/etc/app.conf"))
+
+	// the files of the host are read through the same root by the scans and at runtime
+	fhm.scan(false)
+	fhm.handleRequest(fimHashRequest{
+		key:      fimHashKey{path: "/etc/app.conf"},
+		readPath: fhm.readPath("", 1234, "/etc/app.conf"),
+	})
+	assert.Empty(t, events)
+}
@@ -735,6 +735,21 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 			log.Errorf("failed to decode bind event: %s (offset %d, len %d)", err, offset, len(data))
 			return
 		}
+	case model.ConnectEventType:
+		if _, err = event.Connect.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode connect event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.AcceptEventType:
+		if _, err = event.Accept.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode accept event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.FileCloseEventType:
+		if _, err = event.Close.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode close event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
 	default:
 		log.Errorf("unsupported event type %d", eventType)
 		return
@@ -750,6 +765,14 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 		return
 	}
 
+	// close events are only used by the FIM hash capture, they aren't evaluated against the rules
+	if eventType == model.FileCloseEventType {
+		if fimHash := p.monitor.GetFIMHashManager(); fimHash != nil {
+			fimHash.HandleCloseEvent(event)
+		}
+		return
+	}
+
 	if eventType == model.ExitEventType {
 		defer p.resolvers.ProcessResolver.DeleteEntry(event.ProcessCacheEntry.Pid, event.ResolveEventTimestamp())
 	}
@@ -1317,6 +1340,14 @@ func NewProbe(config *config.Config, statsdClient statsd.ClientInterface) (*Prob
 		)
 	}
 
+	// constants FIM hash capture
+	if p.config.FIMHashEnabled {
+		p.managerOptions.ConstantEditors = append(p.managerOptions.ConstantEditors, manager.ConstantEditor{
+			Name:  "fim_enabled",
+			Value: uint64(1),
+		})
+	}
+
 	// constants syscall monitor
 	if p.config.SyscallMonitor {
 		p.managerOptions.ConstantEditors = append(p.managerOptions.ConstantEditors, manager.ConstantEditor{
@@ -33,6 +33,8 @@ type Monitor struct {
 	syscallMonitor      *SyscallMonitor
 	reordererMonitor    *ReordererMonitor
 	activityDumpManager *ActivityDumpManager
+	securityProfiles    *SecurityProfileManager
+	fimHash             *FIMHashManager
 	runtimeMonitor      *RuntimeMonitor
 	discarderMonitor    *DiscarderMonitor
 }
@@ -68,6 +70,20 @@ func NewMonitor(p *Probe) (*Monitor, error) {
 		}
 	}
 
+	if p.config.SecurityProfileEnabled {
+		m.securityProfiles, err = NewSecurityProfileManager(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't create the security profile manager")
+		}
+	}
+
+	if p.config.FIMHashEnabled {
+		m.fimHash, err = NewFIMHashManager(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't create the FIM hash manager")
+		}
+	}
+
 	// create a new syscall monitor if requested
 	if p.config.SyscallMonitor {
 		m.syscallMonitor, err = NewSyscallMonitor(p.manager)
@@ -93,12 +109,20 @@ func (m *Monitor) GetPerfBufferMonitor() *PerfBufferMonitor {
 	return m.perfBufferMonitor
 }
 
+// GetFIMHashManager returns the FIM hash manager, nil if the FIM hash capture is disabled
+func (m *Monitor) GetFIMHashManager() *FIMHashManager {
+	return m.fimHash
+}
+
 // Start triggers the goroutine of all the underlying controllers and monitors of the Monitor
 func (m *Monitor) Start(ctx context.Context, wg *sync.WaitGroup) error {
 	delta := 2
 	if m.activityDumpManager != nil {
 		delta++
 	}
+	if m.fimHash != nil {
+		delta++
+	}
 	wg.Add(delta)
 
 	go m.loadController.Start(ctx, wg)
@@ -107,6 +131,10 @@ func (m *Monitor) Start(ctx context.Context, wg *sync.WaitGroup) error {
 	if m.activityDumpManager != nil {
 		go m.activityDumpManager.Start(ctx, wg)
 	}
+
+	if m.fimHash != nil {
+		go m.fimHash.Start(ctx, wg)
+	}
 	return nil
 }
 
@@ -151,6 +179,18 @@ func (m *Monitor) SendStats() error {
 		}
 	}
 
+	if m.securityProfiles != nil {
+		if err := m.securityProfiles.SendStats(); err != nil {
+			return errors.Wrap(err, "failed to send security profile manager stats")
+		}
+	}
+
+	if m.fimHash != nil {
+		if err := m.fimHash.SendStats(); err != nil {
+			return errors.Wrap(err, "failed to send FIM hash manager stats")
+		}
+	}
+
 	if m.probe.config.RuntimeMonitor {
 		if err := m.runtimeMonitor.SendStats(); err != nil {
 			return errors.Wrap(err, "failed to send runtime monitor stats")
@@ -195,6 +235,9 @@ func (m *Monitor) ProcessEvent(event *Event, size uint64, CPU int, perfMap *mana
 		if m.activityDumpManager != nil {
 			m.activityDumpManager.ProcessEvent(event)
 		}
+		if m.securityProfiles != nil {
+			m.securityProfiles.ProcessEvent(event)
+		}
 	}
 }
 
@@ -0,0 +1,434 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/golang-lru/simplelru"
+	"github.com/pkg/errors"
+	"github.com/tinylib/msgp/msgp"
+
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+)
+
+const (
+	// securityProfileCacheSize is the maximum number of containers for which the matching profile is cached
+	securityProfileCacheSize = 1024
+	// maxStableProfileAnomalies is the maximum number of anomalies inserted in a stable profile so that they are
+	// reported only once, the next anomalies are reported without being inserted
+	maxStableProfileAnomalies = 1000
+)
+
+// AnomalyType is used to describe the kind of activity that is missing from a security profile
+type AnomalyType string
+
+var (
+	// ProcessAnomaly is reported when a process lineage isn't part of the security profile
+	ProcessAnomaly AnomalyType = "process"
+	// FileAnomaly is reported when a file path isn't part of the security profile
+	FileAnomaly AnomalyType = "file"
+	// DNSAnomaly is reported when a queried domain name isn't part of the security profile
+	DNSAnomaly AnomalyType = "dns"
+
+	allAnomalyTypes = []AnomalyType{ProcessAnomaly, FileAnomaly, DNSAnomaly}
+)
+
+// SecurityProfileStatus is used to describe if a security profile is still learning the activity of its workload
+type SecurityProfileStatus string
+
+var (
+	// Learning is the status of a security profile that silently adds the new activity of its workload
+	Learning SecurityProfileStatus = "learning"
+	// Stable is the status of a security profile that reports the new activity of its workload as anomalies
+	Stable SecurityProfileStatus = "stable"
+)
+
+// SecurityProfile holds the baseline activity of a workload
+type SecurityProfile struct {
+	sync.Mutex
+
+	Name                string
+	ProcessActivityTree []*ProcessActivityNode
+	Status              SecurityProfileStatus
+
+	learningStart   time.Time
+	lastNewActivity time.Time
+	// insertedAnomalies is the number of anomalies inserted since the profile is stable
+	insertedAnomalies int
+}
+
+// NewSecurityProfile returns a new SecurityProfile built from the provided activity dump
+func NewSecurityProfile(name string, dump *ActivityDump) *SecurityProfile {
+	return &SecurityProfile{
+		Name:                name,
+		ProcessActivityTree: dump.ProcessActivityTree,
+		Status:              Learning,
+	}
+}
+
+// updateStatus stabilizes the profile once its learning period is over, or once no new activity was added for the
+// stabilization period
+func (sp *SecurityProfile) updateStatus(now time.Time, learningPeriod time.Duration, stabilizationPeriod time.Duration) {
+	if sp.Status == Stable {
+		return
+	}
+
+	// the learning period starts with the first event of the workload
+	if sp.learningStart.IsZero() {
+		sp.learningStart = now
+		sp.lastNewActivity = now
+	}
+
+	if now.Sub(sp.learningStart) >= learningPeriod || now.Sub(sp.lastNewActivity) >= stabilizationPeriod {
+		sp.Status = Stable
+	}
+}
+
+// newSecurityProfileNode returns a new process node, the arguments and environment variables of the process aren't
+// retained as they are not used to match the activity of the workload
+func newSecurityProfileNode(entry *model.ProcessCacheEntry) *ProcessActivityNode {
+	node := &ProcessActivityNode{
+		Process:        entry.Process,
+		GenerationType: Runtime,
+		Files:          make(map[string]*FileActivityNode),
+		DNSNames:       make(map[string]*DNSNode),
+	}
+	node.Process.ArgsEntry = nil
+	node.Process.EnvsEntry = nil
+	_ = node.GetID()
+	return node
+}
+
+// findOrCreateProcessNode looks up the lineage of the provided entry in the profile, the missing nodes are created
+// when `create` is set. This function returns true if at least one node was missing, the returned node is then nil
+// unless it was created.
+func (sp *SecurityProfile) findOrCreateProcessNode(entry *model.ProcessCacheEntry, create bool) (*ProcessActivityNode, bool) {
+	// the lineage of the entry stops at the boundary of its container
+	var lineage []*model.ProcessCacheEntry
+	for ancestor := entry; ancestor != nil && ancestor.ContainerID == entry.ContainerID; ancestor = ancestor.GetNextAncestorNoFork() {
+		lineage = append(lineage, ancestor)
+	}
+
+	var node *ProcessActivityNode
+	var created bool
+	siblings := &sp.ProcessActivityTree
+
+	for i := len(lineage) - 1; i >= 0; i-- {
+		node = nil
+		for _, sibling := range *siblings {
+			if sibling.Matches(lineage[i], false, nil) {
+				node = sibling
+				break
+			}
+		}
+
+		if node == nil {
+			if !create {
+				return nil, true
+			}
+			node = newSecurityProfileNode(lineage[i])
+			*siblings = append(*siblings, node)
+			created = true
+		}
+		siblings = &node.Children
+	}
+
+	return node, created
+}
+
+// hasFile returns true if the provided path is part of the files of the process node
+func (pan *ProcessActivityNode) hasFile(path string) bool {
+	parent, nextParentIndex := extractFirstParent(path)
+	if nextParentIndex == 0 {
+		return false
+	}
+
+	node, ok := pan.Files[parent]
+	for ok {
+		path = path[nextParentIndex:]
+		if parent, nextParentIndex = extractFirstParent(path); nextParentIndex == 0 {
+			return true
+		}
+		node, ok = node.Children[parent]
+	}
+	return false
+}
+
+// findAnomaly looks up the activity of the provided event in the profile without inserting it. This function returns
+// the type of anomaly to report and true if the activity isn't part of the profile.
+func (sp *SecurityProfile) findAnomaly(event *Event, entry *model.ProcessCacheEntry) (AnomalyType, bool) {
+	node, missing := sp.findOrCreateProcessNode(entry, false)
+	if missing {
+		return ProcessAnomaly, true
+	}
+	if node == nil {
+		return "", false
+	}
+
+	switch event.GetEventType() {
+	case model.FileOpenEventType:
+		if path := event.ResolveFilePath(&event.Open.File); len(path) > 0 && !node.hasFile(path) {
+			return FileAnomaly, true
+		}
+	case model.DNSEventType:
+		if _, found := node.DNSNames[event.DNS.Name]; len(event.DNS.Name) > 0 && !found {
+			return DNSAnomaly, true
+		}
+	}
+	return "", false
+}
+
+// Insert inserts the activity of the provided event in the profile. This function returns the type of anomaly to
+// report and true if the activity wasn't part of a stable profile. Once a stable profile holds
+// maxStableProfileAnomalies anomalies, the new ones are reported without being inserted.
+func (sp *SecurityProfile) Insert(event *Event, entry *model.ProcessCacheEntry, now time.Time, learningPeriod time.Duration, stabilizationPeriod time.Duration) (AnomalyType, bool) {
+	sp.Lock()
+	defer sp.Unlock()
+
+	sp.updateStatus(now, learningPeriod, stabilizationPeriod)
+
+	if sp.Status == Stable && sp.insertedAnomalies >= maxStableProfileAnomalies {
+		return sp.findAnomaly(event, entry)
+	}
+
+	node, created := sp.findOrCreateProcessNode(entry, true)
+	if node == nil {
+		return "", false
+	}
+
+	var anomalyType AnomalyType
+	if created {
+		anomalyType = ProcessAnomaly
+	}
+
+	// the activity of the event is added even if its process is new, only one anomaly is reported
+	switch event.GetEventType() {
+	case model.FileOpenEventType:
+		if node.InsertFileEvent(&event.Open.File, event, Runtime) && len(anomalyType) == 0 {
+			anomalyType = FileAnomaly
+		}
+	case model.DNSEventType:
+		if node.InsertDNSEvent(event, Runtime) && len(anomalyType) == 0 {
+			anomalyType = DNSAnomaly
+		}
+	}
+
+	if len(anomalyType) == 0 {
+		return "", false
+	}
+
+	if sp.Status == Learning {
+		sp.lastNewActivity = now
+		return "", false
+	}
+
+	sp.insertedAnomalies++
+	return anomalyType, true
+}
+
+// GetStatus returns the status of the profile
+func (sp *SecurityProfile) GetStatus() SecurityProfileStatus {
+	sp.Lock()
+	defer sp.Unlock()
+	return sp.Status
+}
+
+// SecurityProfileManager is used to match the activity of the workloads with their security profiles
+type SecurityProfileManager struct {
+	sync.Mutex
+	probe *Probe
+
+	selectorTags        []string
+	learningPeriod      time.Duration
+	stabilizationPeriod time.Duration
+
+	profiles  map[string]*SecurityProfile
+	cache     *simplelru.LRU
+	anomalies map[AnomalyType]*uint64
+}
+
+// NewSecurityProfileManager returns a new instance of SecurityProfileManager
+func NewSecurityProfileManager(p *Probe) (*SecurityProfileManager, error) {
+	cache, err := simplelru.NewLRU(securityProfileCacheSize, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	spm := &SecurityProfileManager{
+		probe:               p,
+		selectorTags:        p.config.SecurityProfileSelectorTags,
+		learningPeriod:      p.config.SecurityProfileLearningPeriod,
+		stabilizationPeriod: p.config.SecurityProfileStabilizationPeriod,
+		profiles:            make(map[string]*SecurityProfile),
+		cache:               cache,
+		anomalies:           make(map[AnomalyType]*uint64),
+	}
+
+	for _, anomalyType := range allAnomalyTypes {
+		count := uint64(0)
+		spm.anomalies[anomalyType] = &count
+	}
+
+	if err = spm.LoadProfiles(p.config.SecurityProfileDirectory); err != nil {
+		return nil, err
+	}
+	return spm, nil
+}
+
+// getSelectorKey returns the key of the profile matching the provided tags, or an empty string if none of the selector
+// tags was found
+func getSelectorKey(selectorTags []string, tags []string) string {
+	var selector []string
+	for _, name := range selectorTags {
+		if value := utils.GetTagValue(name, tags); len(value) > 0 {
+			selector = append(selector, name+":"+value)
+		}
+	}
+	return strings.Join(selector, ",")
+}
+
+// LoadProfiles loads the activity dumps of the provided directory as security profiles
+func (spm *SecurityProfileManager) LoadProfiles(dir string) error {
+	if len(dir) == 0 {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*."+string(MSGP)))
+	if err != nil {
+		return err
+	}
+
+	spm.Lock()
+	defer spm.Unlock()
+
+	for _, file := range files {
+		dump, err := loadActivityDump(file)
+		if err != nil {
+			return err
+		}
+
+		key := getSelectorKey(spm.selectorTags, dump.Tags)
+		if len(key) == 0 {
+			seclog.Warnf("ignoring security profile %s: none of the selector tags %v were found", file, spm.selectorTags)
+			continue
+		}
+
+		if _, exists := spm.profiles[key]; exists {
+			seclog.Warnf("ignoring security profile %s: a profile was already loaded for [%s]", file, key)
+			continue
+		}
+
+		spm.profiles[key] = NewSecurityProfile(key, dump)
+		seclog.Infof("security profile for [%s] loaded from %s", key, file)
+	}
+
+	spm.cache.Purge()
+	return nil
+}
+
+func loadActivityDump(file string) (*ActivityDump, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open activity dump file: %w", err)
+	}
+	defer f.Close()
+
+	var dump ActivityDump
+	if err = dump.DecodeMsg(msgp.NewReader(f)); err != nil {
+		return nil, fmt.Errorf("couldn't parse activity dump file %s: %w", file, err)
+	}
+	return &dump, nil
+}
+
+// getProfile returns the security profile of the provided container, if any
+func (spm *SecurityProfileManager) getProfile(containerID string) *SecurityProfile {
+	spm.Lock()
+	defer spm.Unlock()
+
+	if profile, found := spm.cache.Get(containerID); found {
+		return profile.(*SecurityProfile)
+	}
+
+	tags, err := spm.probe.resolvers.TagsResolver.ResolveWithErr(containerID)
+	if err != nil || len(tags) == 0 {
+		// the tags of the container might not be available yet, retry with the next event
+		return nil
+	}
+
+	profile := spm.profiles[getSelectorKey(spm.selectorTags, tags)]
+	spm.cache.Add(containerID, profile)
+	return profile
+}
+
+// ProcessEvent matches the activity of the provided event with the security profile of its workload
+func (spm *SecurityProfileManager) ProcessEvent(event *Event) {
+	switch event.GetEventType() {
+	case model.ExecEventType, model.FileOpenEventType, model.DNSEventType:
+	default:
+		return
+	}
+
+	entry := event.ResolveProcessCacheEntry()
+	if entry == nil || len(entry.ContainerID) == 0 {
+		return
+	}
+
+	profile := spm.getProfile(entry.ContainerID)
+	if profile == nil {
+		return
+	}
+
+	anomalyType, found := profile.Insert(event, entry, time.Now(), spm.learningPeriod, spm.stabilizationPeriod)
+	if !found {
+		return
+	}
+
+	atomic.AddUint64(spm.anomalies[anomalyType], 1)
+	spm.probe.DispatchCustomEvent(
+		NewAnomalyDetectionEvent(event, anomalyType, profile.Name),
+	)
+}
+
+// SendStats sends the security profile manager stats
+func (spm *SecurityProfileManager) SendStats() error {
+	spm.Lock()
+	counts := make(map[SecurityProfileStatus]float64)
+	for _, profile := range spm.profiles {
+		counts[profile.GetStatus()]++
+	}
+	spm.Unlock()
+
+	for _, status := range []SecurityProfileStatus{Learning, Stable} {
+		tags := []string{fmt.Sprintf("status:%s", status)}
+		if err := spm.probe.statsdClient.Gauge(metrics.MetricSecurityProfileProfiles, counts[status], tags, 1.0); err != nil {
+			return errors.Wrapf(err, "couldn't send %s metric", metrics.MetricSecurityProfileProfiles)
+		}
+	}
+
+	for anomalyType, count := range spm.anomalies {
+		tags := []string{fmt.Sprintf("anomaly_type:%s", anomalyType)}
+		if value := atomic.SwapUint64(count, 0); value > 0 {
+			if err := spm.probe.statsdClient.Count(metrics.MetricSecurityProfileAnomalies, int64(value), tags, 1.0); err != nil {
+				return errors.Wrapf(err, "couldn't send %s metric", metrics.MetricSecurityProfileAnomalies)
+			}
+		}
+	}
+
+	return nil
+}
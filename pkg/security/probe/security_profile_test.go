@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+)
+
+func newSecurityProfileTestEntry(pid uint32, path string, ancestor *model.ProcessCacheEntry) *model.ProcessCacheEntry {
+	entry := &model.ProcessCacheEntry{}
+	entry.Pid = pid
+	entry.Tid = pid
+	entry.Comm = path
+	entry.ContainerID = "0123456789"
+	entry.FileEvent.PathnameStr = path
+	entry.Ancestor = ancestor
+	return entry
+}
+
+func newSecurityProfileTestEvent(eventType model.EventType, value string, now time.Time) *Event {
+	event := &Event{}
+	event.Type = uint32(eventType)
+	event.Timestamp = now
+
+	switch eventType {
+	case model.FileOpenEventType:
+		event.Open.File.PathnameStr = value
+	case model.DNSEventType:
+		event.DNS.Name = value
+	}
+	return event
+}
+
+func TestGetSelectorKey(t *testing.T) {
+	selectorTags := []string{"image_name", "service"}
+
+	assert.Equal(t, "image_name:nginx,service:web", getSelectorKey(selectorTags, []string{"service:web", "env:prod", "image_name:nginx"}))
+	assert.Equal(t, "image_name:nginx", getSelectorKey(selectorTags, []string{"image_name:nginx"}))
+	assert.Equal(t, "", getSelectorKey(selectorTags, []string{"env:prod"}))
+}
+
+func TestSecurityProfileInsert(t *testing.T) {
+	now := time.Now()
+	learningPeriod, stabilizationPeriod := time.Hour, 10*time.Minute
+
+	shell := newSecurityProfileTestEntry(1, "/bin/sh", nil)
+	nginx := newSecurityProfileTestEntry(2, "/usr/sbin/nginx", shell)
+
+	profile := NewSecurityProfile("image_name:nginx", &ActivityDump{})
+
+	// the activity is learned silently
+	_, found := profile.Insert(newSecurityProfileTestEvent(model.ExecEventType, "", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+	_, found = profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/nginx/nginx.conf", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+	assert.Equal(t, Learning, profile.GetStatus())
+
+	// the profile is stabilized once no new activity was added for the stabilization period
+	now = now.Add(stabilizationPeriod)
+	_, found = profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/nginx/nginx.conf", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+	assert.Equal(t, Stable, profile.GetStatus())
+
+	anomalyType, found := profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/shadow", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.True(t, found)
+	assert.Equal(t, FileAnomaly, anomalyType)
+
+	// an anomaly is reported only once
+	_, found = profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/shadow", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+
+	anomalyType, found = profile.Insert(newSecurityProfileTestEvent(model.DNSEventType, "example.com", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.True(t, found)
+	assert.Equal(t, DNSAnomaly, anomalyType)
+
+	curl := newSecurityProfileTestEntry(3, "/usr/bin/curl", nginx)
+	anomalyType, found = profile.Insert(newSecurityProfileTestEvent(model.ExecEventType, "", now), curl, now, learningPeriod, stabilizationPeriod)
+	assert.True(t, found)
+	assert.Equal(t, ProcessAnomaly, anomalyType)
+
+	// once the profile holds too many anomalies, the new ones are reported without being inserted
+	profile.insertedAnomalies = maxStableProfileAnomalies
+	for i := 0; i < 2; i++ {
+		anomalyType, found = profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/passwd", now), nginx, now, learningPeriod, stabilizationPeriod)
+		assert.True(t, found)
+		assert.Equal(t, FileAnomaly, anomalyType)
+
+		wget := newSecurityProfileTestEntry(4, "/usr/bin/wget", nginx)
+		anomalyType, found = profile.Insert(newSecurityProfileTestEvent(model.ExecEventType, "", now), wget, now, learningPeriod, stabilizationPeriod)
+		assert.True(t, found)
+		assert.Equal(t, ProcessAnomaly, anomalyType)
+	}
+	assert.Equal(t, maxStableProfileAnomalies, profile.insertedAnomalies)
+
+	_, found = profile.Insert(newSecurityProfileTestEvent(model.FileOpenEventType, "/etc/shadow", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+	_, found = profile.Insert(newSecurityProfileTestEvent(model.DNSEventType, "example.com", now), nginx, now, learningPeriod, stabilizationPeriod)
+	assert.False(t, found)
+}
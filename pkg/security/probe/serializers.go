@@ -334,6 +334,20 @@ type BindEventSerializer struct {
 	Addr *IPPortFamilySerializer `json:"addr" jsonschema_description:"Bound address (if any)"`
 }
 
+// ConnectEventSerializer serializes a connect event to JSON
+// easyjson:json
+type ConnectEventSerializer struct {
+	Addr     *IPPortFamilySerializer `json:"addr" jsonschema_description:"Connection address"`
+	Protocol string                  `json:"protocol" jsonschema_description:"Transport protocol of the socket"`
+}
+
+// AcceptEventSerializer serializes an accept event to JSON
+// easyjson:json
+type AcceptEventSerializer struct {
+	Addr     *IPPortFamilySerializer `json:"addr" jsonschema_description:"Address of the remote peer"`
+	Protocol string                  `json:"protocol" jsonschema_description:"Transport protocol of the socket"`
+}
+
 // EventSerializer serializes an event to JSON
 // easyjson:json
 type EventSerializer struct {
@@ -350,6 +364,8 @@ type EventSerializer struct {
 	*DNSEventSerializer         `json:"dns,omitempty"`
 	*NetworkContextSerializer   `json:"network,omitempty"`
 	*BindEventSerializer        `json:"bind,omitempty"`
+	*ConnectEventSerializer     `json:"connect,omitempty"`
+	*AcceptEventSerializer      `json:"accept,omitempty"`
 	*UserContextSerializer      `json:"usr,omitempty"`
 	*ProcessContextSerializer   `json:"process,omitempty"`
 	*DDContextSerializer        `json:"dd,omitempty"`
@@ -734,6 +750,20 @@ func newBindEventSerializer(e *Event) *BindEventSerializer {
 	return bes
 }
 
+func newConnectEventSerializer(e *Event) *ConnectEventSerializer {
+	return &ConnectEventSerializer{
+		Addr:     newIPPortFamilySerializer(&e.Connect.Addr, model.AddressFamily(e.Connect.AddrFamily).String()),
+		Protocol: model.L4Protocol(e.Connect.Protocol).String(),
+	}
+}
+
+func newAcceptEventSerializer(e *Event) *AcceptEventSerializer {
+	return &AcceptEventSerializer{
+		Addr:     newIPPortFamilySerializer(&e.Accept.Addr, model.AddressFamily(e.Accept.AddrFamily).String()),
+		Protocol: model.L4Protocol(e.Accept.Protocol).String(),
+	}
+}
+
 func serializeSyscallRetval(retval int64) string {
 	switch {
 	case retval < 0:
@@ -817,6 +847,10 @@ func NewEventSerializer(event *Event) *EventSerializer {
 		s.FileSerializer.Flags = model.OpenFlags(event.Open.Flags).StringArray()
 		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Open.Retval)
 		s.Async = event.Async
+	case model.FileCloseEventType:
+		s.FileEventSerializer = &FileEventSerializer{
+			FileSerializer: *newFileSerializer(&event.Close.File, event),
+		}
 	case model.FileMkdirEventType:
 		s.FileEventSerializer = &FileEventSerializer{
 			FileSerializer: *newFileSerializer(&event.Mkdir.File, event),
@@ -985,6 +1019,12 @@ func NewEventSerializer(event *Event) *EventSerializer {
 	case model.BindEventType:
 		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Bind.Retval)
 		s.BindEventSerializer = newBindEventSerializer(event)
+	case model.ConnectEventType:
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Connect.Retval)
+		s.ConnectEventSerializer = newConnectEventSerializer(event)
+	case model.AcceptEventType:
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Accept.Retval)
+		s.AcceptEventSerializer = newAcceptEventSerializer(event)
 	}
 
 	return s
@@ -10,6 +10,7 @@ package probe
 
 import (
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
 )
 
 var (
@@ -23,4 +24,10 @@ var (
 			return int(pc.Process.Pid)
 		}, nil),
 	}
+
+	// SequenceScopes fields identifying the scopes of the sequence rules
+	SequenceScopes = map[rules.Scope]eval.Field{
+		"process":   "process.pid",
+		"container": "process.container.id",
+	}
 )
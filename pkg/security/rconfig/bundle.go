@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package rconfig
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const (
+	policySource = "remote-config"
+	// fullRollout is the canary percentage of the bundles deployed on every host
+	fullRollout = 100
+)
+
+var (
+	// ErrNoPublicKey is returned when a policy bundle is received while no public key was configured to verify it
+	ErrNoPublicKey = errors.New("no public key configured to verify policy bundles")
+	// ErrInvalidSignature is returned when the signature of a policy bundle can't be verified by the configured keys
+	ErrInvalidSignature = errors.New("invalid policy bundle signature")
+	// ErrUnsignedPolicy is returned when a plain policy is received while public keys were configured to verify policies
+	ErrUnsignedPolicy = errors.New("unsigned policy rejected, public keys are configured")
+)
+
+// signedPolicyBundle is the envelope of a policy bundle delivered over remote config
+type signedPolicyBundle struct {
+	Signed    json.RawMessage `json:"signed"`
+	Signature string          `json:"signature"`
+}
+
+// PolicyBundle is a versioned set of policies delivered over remote config
+type PolicyBundle struct {
+	// Version is the version of the bundle, it is used as the version of the policies that don't define one
+	Version string `json:"version"`
+	// CanaryPercentage is the percentage of hosts that load the bundle, all the hosts load it if it isn't set
+	CanaryPercentage *uint `json:"canary_percentage,omitempty"`
+	// Policies holds the content of the policies of the bundle, indexed by policy name
+	Policies map[string]string `json:"policies"`
+}
+
+// configVersion holds the policies of a version of a remote config
+type configVersion struct {
+	version          uint64
+	canaryPercentage uint
+	policies         []*rules.Policy
+}
+
+// hasPolicy returns true if the provided policy belongs to this version
+func (cv *configVersion) hasPolicy(policy *rules.Policy) bool {
+	for _, p := range cv.policies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePublicKeys decodes the provided base64 encoded ed25519 public keys
+func ParsePublicKeys(encodedKeys []string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, encodedKey := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy public key: %w", err)
+		}
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid policy public key: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parseSignedBundle returns the envelope of a policy bundle, or nil if the config is a plain policy
+func parseSignedBundle(data []byte) *signedPolicyBundle {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil
+	}
+
+	var envelope signedPolicyBundle
+	if err := json.Unmarshal(data, &envelope); err != nil || len(envelope.Signed) == 0 {
+		return nil
+	}
+	return &envelope
+}
+
+// verify checks the signature of the bundle against the provided keys, and returns the bundle
+func (sb *signedPolicyBundle) verify(keys []ed25519.PublicKey) (*PolicyBundle, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoPublicKey
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(sb.Signature)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	verified := false
+	for _, key := range keys {
+		if ed25519.Verify(key, sb.Signed, signature) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	var bundle PolicyBundle
+	if err := json.Unmarshal(sb.Signed, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid policy bundle: %w", err)
+	}
+
+	if bundle.CanaryPercentage != nil && *bundle.CanaryPercentage > fullRollout {
+		return nil, fmt.Errorf("invalid policy bundle: canary percentage %d is greater than %d", *bundle.CanaryPercentage, fullRollout)
+	}
+
+	return &bundle, nil
+}
+
+func normalize(policy *rules.Policy) {
+	// remove the version
+	els := strings.SplitN(policy.Name, ".", 2)
+	if len(els) > 1 {
+		policy.Name = els[1]
+	}
+}
+
+// parseConfig loads the policies of a remote config. A remote config is either a signed policy bundle, or a single
+// policy. Single policies are only accepted when no public key is configured.
+func parseConfig(c client.ConfigCWSDD, keys []ed25519.PublicKey) (*configVersion, error) {
+	cv := &configVersion{
+		version:          c.Version,
+		canaryPercentage: fullRollout,
+	}
+
+	envelope := parseSignedBundle(c.Config)
+	if envelope == nil {
+		if len(keys) > 0 {
+			return nil, ErrUnsignedPolicy
+		}
+
+		policy, err := rules.LoadPolicy(c.ID, policySource, bytes.NewReader(c.Config))
+		if err != nil {
+			return nil, err
+		}
+		normalize(policy)
+		cv.policies = append(cv.policies, policy)
+		return cv, nil
+	}
+
+	bundle, err := envelope.verify(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle.CanaryPercentage != nil {
+		cv.canaryPercentage = *bundle.CanaryPercentage
+	}
+
+	names := make([]string, 0, len(bundle.Policies))
+	for name := range bundle.Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy, err := rules.LoadPolicy(name, policySource, strings.NewReader(bundle.Policies[name]))
+		if err != nil {
+			return nil, err
+		}
+		if len(policy.Version) == 0 {
+			policy.Version = bundle.Version
+		}
+		cv.policies = append(cv.policies, policy)
+	}
+
+	return cv, nil
+}
+
+// inCanary returns true if the host is part of the canary of the provided config. The hosts of a canary are the same
+// for every version of a config, so that a rollout only adds hosts to the previous canary.
+func inCanary(hostname string, configID string, percentage uint) bool {
+	if percentage >= fullRollout {
+		return true
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(hostname + "/" + configID))
+	return uint(h.Sum32()%fullRollout) < percentage
+}
@@ -9,38 +9,122 @@
 package rconfig
 
 import (
-	"bytes"
-	"strings"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"go.uber.org/atomic"
 
 	"github.com/DataDog/datadog-agent/pkg/config/remote"
 	"github.com/DataDog/datadog-agent/pkg/config/remote/data"
 	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client"
+	"github.com/DataDog/datadog-agent/pkg/security/metrics"
 	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/hashicorp/go-multierror"
 )
 
+// rcClient is the part of the remote config client used by the policy provider
+type rcClient interface {
+	CWSDDUpdates() <-chan []client.ConfigCWSDD
+	UpdateApplyStatus(product data.Product, id string, version uint64, err error)
+	ClearApplyStatus(product data.Product, id string)
+	Close()
+}
+
+// RCPolicyProviderOpts defines the options of the remote config policy provider
+type RCPolicyProviderOpts struct {
+	// PublicKeys are the base64 encoded ed25519 public keys trusted to sign policy bundles
+	PublicKeys []string
+	// RollbackPeriod is the period after the activation of a new version during which it is rolled back if its rules
+	// exceed the rate limiter
+	RollbackPeriod time.Duration
+	// RollbackMaxDroppedEvents is the number of events dropped by the rate limiter that triggers a rollback
+	RollbackMaxDroppedEvents int64
+}
+
+// configState holds the rollout state of a remote config
+type configState struct {
+	// received is the last version received for this config
+	received uint64
+	// applied is the version of the config currently loaded
+	applied *configVersion
+	// previous is the version restored if the applied version is rolled back
+	previous *configVersion
+	// rollbackDeadline is the end of the rollback period of the applied version
+	rollbackDeadline time.Time
+	dropped          int64
+	// rejected holds the versions that failed to be parsed or that were rolled back, they won't be applied again
+	rejected map[uint64]error
+	// err is the error reported for this config
+	err error
+}
+
+func (cs *configState) appliedVersion() uint64 {
+	if cs.applied == nil {
+		return 0
+	}
+	return cs.applied.version
+}
+
 // RCPolicyProvider defines a remote config policy provider
 type RCPolicyProvider struct {
 	sync.RWMutex
 
-	client               *remote.Client
+	client               rcClient
+	opts                 RCPolicyProviderOpts
+	publicKeys           []ed25519.PublicKey
+	hostname             string
 	onNewPoliciesReadyCb func()
-	lastConfigs          []client.ConfigCWSDD
+	states               map[string]*configState
+	now                  func() time.Time
+	// unsignedRejected counts the plain policies rejected because public keys are configured
+	unsignedRejected *atomic.Int64
 }
 
 var _ rules.PolicyProvider = (*RCPolicyProvider)(nil)
 
 // NewRCPolicyProvider returns a new Remote Config based policy provider
-func NewRCPolicyProvider(name string) (*RCPolicyProvider, error) {
+func NewRCPolicyProvider(name string, opts RCPolicyProviderOpts) (*RCPolicyProvider, error) {
 	c, err := remote.NewClient(name, []data.Product{data.ProductCWSDD})
 	if err != nil {
 		return nil, err
 	}
 
+	// the hostname selects the canaries of the policy bundles
+	hostname, err := util.GetHostname(context.TODO())
+	if err != nil {
+		log.Warnf("unable to get the hostname, policy bundle canaries will be ignored: %s", err)
+	}
+
+	r, err := newRCPolicyProvider(c, hostname, opts)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func newRCPolicyProvider(c rcClient, hostname string, opts RCPolicyProviderOpts) (*RCPolicyProvider, error) {
+	publicKeys, err := ParsePublicKeys(opts.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+
 	return &RCPolicyProvider{
-		client: c,
+		client:           c,
+		opts:             opts,
+		publicKeys:       publicKeys,
+		hostname:         hostname,
+		states:           make(map[string]*configState),
+		now:              time.Now,
+		unsignedRejected: atomic.NewInt64(0),
 	}, nil
 }
 
@@ -50,22 +134,164 @@ func (r *RCPolicyProvider) Start() {
 
 	go func() {
 		for configs := range r.client.CWSDDUpdates() {
-			r.Lock()
-			r.lastConfigs = configs
-			r.Unlock()
-
-			log.Debug("new policies from remote-config policy provider")
+			if r.update(configs) {
+				log.Debug("new policies from remote-config policy provider")
 
-			r.onNewPoliciesReadyCb()
+				r.onNewPoliciesReadyCb()
+			}
 		}
 	}()
 }
 
-func normalize(policy *rules.Policy) {
-	// remove the version
-	els := strings.SplitN(policy.Name, ".", 2)
-	if len(els) > 1 {
-		policy.Name = els[1]
+// update applies the received configs and returns true if the policies changed
+func (r *RCPolicyProvider) update(configs []client.ConfigCWSDD) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	var changed bool
+	received := make(map[string]bool)
+
+	for _, c := range configs {
+		received[c.ID] = true
+
+		state, exists := r.states[c.ID]
+		if !exists {
+			state = &configState{rejected: make(map[uint64]error)}
+			r.states[c.ID] = state
+		}
+
+		// the version was already handled, or was rejected
+		if state.received == c.Version || state.appliedVersion() == c.Version {
+			continue
+		}
+		state.received = c.Version
+
+		if err, rejected := state.rejected[c.Version]; rejected {
+			state.err = err
+			continue
+		}
+
+		cv, err := parseConfig(c, r.publicKeys)
+		if err != nil {
+			if errors.Is(err, ErrUnsignedPolicy) {
+				r.unsignedRejected.Inc()
+			}
+			log.Errorf("rejecting version %d of remote config %s: %s", c.Version, c.ID, err)
+			state.rejected[c.Version] = err
+			state.err = fmt.Errorf("version %d rejected: %w", c.Version, err)
+			continue
+		}
+
+		state.err = nil
+		if !inCanary(r.hostname, c.ID, cv.canaryPercentage) {
+			log.Infof("version %d of remote config %s isn't deployed on this host (canary %d%%)", c.Version, c.ID, cv.canaryPercentage)
+			continue
+		}
+
+		log.Infof("applying version %d of remote config %s", c.Version, c.ID)
+		state.previous = state.applied
+		state.applied = cv
+		state.rollbackDeadline = r.now().Add(r.opts.RollbackPeriod)
+		state.dropped = 0
+		changed = true
+	}
+
+	for id := range r.states {
+		if !received[id] {
+			delete(r.states, id)
+			r.client.ClearApplyStatus(data.ProductCWSDD, id)
+			changed = true
+		}
+	}
+
+	r.reportStatus()
+
+	return changed
+}
+
+// reportStatus reports the applied versions and the errors through the remote config client state
+func (r *RCPolicyProvider) reportStatus() {
+	for id, state := range r.states {
+		r.client.UpdateApplyStatus(data.ProductCWSDD, id, state.appliedVersion(), state.err)
+	}
+}
+
+// rollback restores the previous version of a config. The applied version won't be applied again.
+func (r *RCPolicyProvider) rollback(id string, state *configState, reason error) {
+	version := state.appliedVersion()
+	log.Errorf("rolling back version %d of remote config %s: %s", version, id, reason)
+
+	state.rejected[version] = reason
+	state.err = fmt.Errorf("version %d rolled back: %w", version, reason)
+	state.applied = state.previous
+	state.previous = nil
+	state.rollbackDeadline = time.Time{}
+	state.dropped = 0
+}
+
+// findPolicyState returns the config owning the provided policy
+func (r *RCPolicyProvider) findPolicyState(policy *rules.Policy) (string, *configState) {
+	if policy == nil || policy.Source != policySource {
+		return "", nil
+	}
+
+	for id, state := range r.states {
+		if state.applied != nil && state.applied.hasPolicy(policy) {
+			return id, state
+		}
+	}
+	return "", nil
+}
+
+// OnPoliciesLoaded rolls back the configs whose rules failed to load
+func (r *RCPolicyProvider) OnPoliciesLoaded(errs *multierror.Error) {
+	if errs == nil {
+		return
+	}
+
+	r.Lock()
+	var changed bool
+	for _, err := range errs.Errors {
+		var rErr *rules.ErrRuleLoad
+		if !errors.As(err, &rErr) || errors.Is(rErr.Err, rules.ErrEventTypeNotEnabled) || rErr.Definition == nil {
+			continue
+		}
+
+		if id, state := r.findPolicyState(rErr.Definition.Policy); state != nil {
+			r.rollback(id, state, rErr)
+			changed = true
+		}
+	}
+	if changed {
+		r.reportStatus()
+	}
+	r.Unlock()
+
+	if changed && r.onNewPoliciesReadyCb != nil {
+		r.onNewPoliciesReadyCb()
+	}
+}
+
+// OnEventDropped rolls back a config if its rules exceed the rate limiter during its rollback period
+func (r *RCPolicyProvider) OnEventDropped(rule *rules.Rule) {
+	if rule.Definition == nil || rule.Definition.Policy == nil || rule.Definition.Policy.Source != policySource {
+		return
+	}
+
+	r.Lock()
+	var changed bool
+	if id, state := r.findPolicyState(rule.Definition.Policy); state != nil && r.now().Before(state.rollbackDeadline) {
+		state.dropped++
+		if state.dropped > r.opts.RollbackMaxDroppedEvents {
+			r.rollback(id, state, fmt.Errorf("more than %d events of rule %s were dropped by the rate limiter", r.opts.RollbackMaxDroppedEvents, rule.Definition.ID))
+			r.reportStatus()
+			changed = true
+		}
+	}
+	r.Unlock()
+
+	if changed && r.onNewPoliciesReadyCb != nil {
+		r.onNewPoliciesReadyCb()
 	}
 }
 
@@ -77,15 +303,19 @@ func (r *RCPolicyProvider) LoadPolicies() ([]*rules.Policy, *multierror.Error) {
 	r.RLock()
 	defer r.RUnlock()
 
-	for _, c := range r.lastConfigs {
-		reader := bytes.NewReader(c.Config)
+	ids := make([]string, 0, len(r.states))
+	for id := range r.states {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
 
-		policy, err := rules.LoadPolicy(c.ID, "remote-config", reader)
-		if err != nil {
-			errs = multierror.Append(errs, err)
-		} else {
-			normalize(policy)
-			policies = append(policies, policy)
+	for _, id := range ids {
+		state := r.states[id]
+		if state.applied != nil {
+			policies = append(policies, state.applied.policies...)
+		}
+		if state.err != nil {
+			errs = multierror.Append(errs, &rules.ErrPolicyLoad{Name: id, Err: state.err})
 		}
 	}
 
@@ -97,6 +327,14 @@ func (r *RCPolicyProvider) SetOnNewPoliciesReadyCb(cb func()) {
 	r.onNewPoliciesReadyCb = cb
 }
 
+// SendStats sends the metrics of the remote config policy provider
+func (r *RCPolicyProvider) SendStats(statsdClient statsd.ClientInterface) error {
+	if rejected := r.unsignedRejected.Swap(0); rejected > 0 {
+		return statsdClient.Count(metrics.MetricRemoteConfigUnsignedPolicyRejected, rejected, []string{}, 1.0)
+	}
+	return nil
+}
+
 // Close stops the client
 func (r *RCPolicyProvider) Close() error {
 	r.client.Close()
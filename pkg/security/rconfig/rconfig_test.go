@@ -0,0 +1,258 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package rconfig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+type applyStatus struct {
+	version uint64
+	err     error
+}
+
+type testClient struct {
+	updates  chan []client.ConfigCWSDD
+	statuses map[string]applyStatus
+}
+
+func newTestClient() *testClient {
+	return &testClient{
+		updates:  make(chan []client.ConfigCWSDD),
+		statuses: make(map[string]applyStatus),
+	}
+}
+
+func (c *testClient) CWSDDUpdates() <-chan []client.ConfigCWSDD {
+	return c.updates
+}
+
+func (c *testClient) UpdateApplyStatus(product data.Product, id string, version uint64, err error) {
+	c.statuses[id] = applyStatus{version: version, err: err}
+}
+
+func (c *testClient) ClearApplyStatus(product data.Product, id string) {
+	delete(c.statuses, id)
+}
+
+func (c *testClient) Close() {}
+
+const testPolicy = `---
+version: 1.2.3
+rules:
+  - id: test_rule
+    expression: open.file.path == "/etc/passwd"
+`
+
+func newTestBundle(t *testing.T, key ed25519.PrivateKey, version string, canary *uint, ruleID string) []byte {
+	signed, err := json.Marshal(PolicyBundle{
+		Version:          version,
+		CanaryPercentage: canary,
+		Policies: map[string]string{
+			"bundle.policy": "rules:\n  - id: " + ruleID + "\n    expression: open.file.path == \"/etc/shadow\"\n",
+		},
+	})
+	require.NoError(t, err)
+
+	envelope, err := json.Marshal(signedPolicyBundle{
+		Signed:    signed,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, signed)),
+	})
+	require.NoError(t, err)
+	return envelope
+}
+
+func newTestProvider(t *testing.T) (*RCPolicyProvider, *testClient, ed25519.PrivateKey, *time.Time) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	c := newTestClient()
+	r, err := newRCPolicyProvider(c, "test-host", RCPolicyProviderOpts{
+		PublicKeys:               []string{base64.StdEncoding.EncodeToString(publicKey)},
+		RollbackPeriod:           10 * time.Minute,
+		RollbackMaxDroppedEvents: 2,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.SetOnNewPoliciesReadyCb(func() {})
+
+	return r, c, privateKey, &now
+}
+
+func loadedRuleIDs(t *testing.T, r *RCPolicyProvider) []string {
+	policies, _ := r.LoadPolicies()
+
+	var ids []string
+	for _, policy := range policies {
+		for _, rule := range policy.Rules {
+			ids = append(ids, rule.ID)
+		}
+	}
+	return ids
+}
+
+func TestRCPolicyProviderPlainPolicy(t *testing.T) {
+	c := newTestClient()
+	r, err := newRCPolicyProvider(c, "test-host", RCPolicyProviderOpts{})
+	require.NoError(t, err)
+
+	assert.True(t, r.update([]client.ConfigCWSDD{{ID: "1.default", Version: 1, Config: []byte(testPolicy)}}))
+
+	policies, errs := r.LoadPolicies()
+	assert.Nil(t, errs)
+	if assert.Len(t, policies, 1) {
+		assert.Equal(t, "default", policies[0].Name)
+		assert.Equal(t, policySource, policies[0].Source)
+	}
+	assert.Equal(t, applyStatus{version: 1}, c.statuses["1.default"])
+
+	// the same version isn't applied twice
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "1.default", Version: 1, Config: []byte(testPolicy)}}))
+
+	// removed configs are unloaded
+	assert.True(t, r.update(nil))
+	assert.Empty(t, loadedRuleIDs(t, r))
+	assert.Empty(t, c.statuses)
+}
+
+func TestRCPolicyProviderPlainPolicyRejected(t *testing.T) {
+	r, c, key, _ := newTestProvider(t)
+
+	// plain policies can't bypass the signature when public keys are configured
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "1.default", Version: 1, Config: []byte(testPolicy)}}))
+	assert.Empty(t, loadedRuleIDs(t, r))
+	assert.Equal(t, uint64(0), c.statuses["1.default"].version)
+	assert.ErrorIs(t, c.statuses["1.default"].err, ErrUnsignedPolicy)
+	assert.Equal(t, int64(1), r.unsignedRejected.Load())
+
+	// a plain policy doesn't replace a signed bundle
+	assert.True(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 1, Config: newTestBundle(t, key, "1.0.0", nil, "rule_v1")}}))
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: []byte(testPolicy)}}))
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+	assert.Equal(t, int64(2), r.unsignedRejected.Load())
+}
+
+func TestRCPolicyProviderSignedBundle(t *testing.T) {
+	r, c, key, _ := newTestProvider(t)
+
+	assert.True(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 1, Config: newTestBundle(t, key, "1.0.0", nil, "rule_v1")}}))
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+
+	policies, _ := r.LoadPolicies()
+	assert.Equal(t, "1.0.0", policies[0].Version)
+
+	// a bundle signed by an unknown key is rejected, the previous version is kept
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: newTestBundle(t, otherKey, "2.0.0", nil, "rule_v2")}}))
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+	assert.Equal(t, uint64(1), c.statuses["bundle"].version)
+	assert.ErrorIs(t, c.statuses["bundle"].err, ErrInvalidSignature)
+
+	_, errs := r.LoadPolicies()
+	assert.Error(t, errs.ErrorOrNil())
+}
+
+func TestRCPolicyProviderCanary(t *testing.T) {
+	r, c, key, _ := newTestProvider(t)
+
+	none, all := uint(0), uint(100)
+
+	assert.True(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 1, Config: newTestBundle(t, key, "1.0.0", &all, "rule_v1")}}))
+
+	// the host isn't part of an empty canary, the previous version is kept
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: newTestBundle(t, key, "2.0.0", &none, "rule_v2")}}))
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+	assert.Equal(t, applyStatus{version: 1}, c.statuses["bundle"])
+
+	// the hosts of a canary are stable and grow with the percentage
+	var previous bool
+	for percentage := uint(0); percentage <= 100; percentage++ {
+		selected := inCanary("test-host", "bundle", percentage)
+		assert.False(t, previous && !selected)
+		previous = selected
+	}
+	assert.True(t, previous)
+}
+
+func TestRCPolicyProviderRollbackOnLoadError(t *testing.T) {
+	r, c, key, _ := newTestProvider(t)
+
+	r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 1, Config: newTestBundle(t, key, "1.0.0", nil, "rule_v1")}})
+	r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: newTestBundle(t, key, "2.0.0", nil, "rule_v2")}})
+
+	policies, _ := r.LoadPolicies()
+	require.Len(t, policies, 1)
+
+	var errs *multierror.Error
+	errs = multierror.Append(errs, &rules.ErrRuleLoad{Definition: policies[0].Rules[0], Err: errors.New("syntax error")})
+	r.OnPoliciesLoaded(errs)
+
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+	assert.Equal(t, uint64(1), c.statuses["bundle"].version)
+	assert.Error(t, c.statuses["bundle"].err)
+
+	// the rolled back version isn't applied again
+	assert.False(t, r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: newTestBundle(t, key, "2.0.0", nil, "rule_v2")}}))
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+}
+
+func TestRCPolicyProviderRollbackOnRateLimiter(t *testing.T) {
+	r, c, key, now := newTestProvider(t)
+
+	r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 1, Config: newTestBundle(t, key, "1.0.0", nil, "rule_v1")}})
+
+	policies, _ := r.LoadPolicies()
+	rule := &rules.Rule{Definition: policies[0].Rules[0]}
+
+	// the drops are ignored once the rollback period is over
+	*now = now.Add(time.Hour)
+	for i := 0; i < 10; i++ {
+		r.OnEventDropped(rule)
+	}
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+
+	r.update([]client.ConfigCWSDD{{ID: "bundle", Version: 2, Config: newTestBundle(t, key, "2.0.0", nil, "rule_v2")}})
+	policies, _ = r.LoadPolicies()
+	rule = &rules.Rule{Definition: policies[0].Rules[0]}
+
+	r.OnEventDropped(rule)
+	r.OnEventDropped(rule)
+	assert.Equal(t, []string{"rule_v2"}, loadedRuleIDs(t, r))
+
+	r.OnEventDropped(rule)
+	assert.Equal(t, []string{"rule_v1"}, loadedRuleIDs(t, r))
+	assert.Equal(t, uint64(1), c.statuses["bundle"].version)
+	assert.Error(t, c.statuses["bundle"].err)
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	_, err := ParsePublicKeys([]string{"not base64"})
+	assert.Error(t, err)
+
+	_, err = ParsePublicKeys([]string{base64.StdEncoding.EncodeToString([]byte("short"))})
+	assert.Error(t, err)
+}
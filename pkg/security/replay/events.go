@@ -0,0 +1,397 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+)
+
+// RecordedEvent is a security event as serialized by the runtime security agent
+type RecordedEvent map[string]interface{}
+
+// GetType returns the type of the recorded event
+func (r RecordedEvent) GetType() eval.EventType {
+	evt, _ := r["evt"].(map[string]interface{})
+	name, _ := evt["name"].(string)
+	return name
+}
+
+// GetRuleID returns the ID of the rule that triggered the recorded event, if any
+func (r RecordedEvent) GetRuleID() eval.RuleID {
+	agent, _ := r["agent"].(map[string]interface{})
+	ruleID, _ := agent["rule_id"].(string)
+	return ruleID
+}
+
+// ReadEvents reads recorded events either from a JSON array or from a stream of JSON objects
+func ReadEvents(r io.Reader) ([]RecordedEvent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var events []RecordedEvent
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := decoder.Decode(&events); err != nil {
+			return nil, fmt.Errorf("failed to decode events: %w", err)
+		}
+		return events, nil
+	}
+
+	for {
+		var event RecordedEvent
+		if err := decoder.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", len(events), err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// sections of the serialized events that don't hold any event field
+var ignoredSections = map[string]bool{
+	"evt":   true,
+	"agent": true,
+	"date":  true,
+	"dd":    true,
+	"usr":   true,
+	"title": true,
+}
+
+// prefixRewrites maps the serialized sections that are shared by several event types to the fields of the event type.
+// The rewrites are applied in order, `%s` is replaced by the event type.
+var prefixRewrites = []struct {
+	from string
+	to   string
+}{
+	{from: "process.credentials.destination.", to: "%s."},
+	{from: "file.flags", to: "%s.flags"},
+	{from: "file.", to: "%s.file."},
+	{from: "module.", to: "%s."},
+}
+
+// processContextPrefixes lists the serialized sections holding a process context
+var processContextPrefixes = []string{"process.", "ptrace.tracee.", "signal.target."}
+
+// processRewrites maps the serialized process fields to the fields of a process context
+var processRewrites = []struct {
+	from string
+	to   string
+}{
+	{from: "executable.", to: "file."},
+	{from: "credentials.", to: ""},
+	{from: "tty", to: "tty_name"},
+	{from: "args", to: "argv"},
+}
+
+// outcomeRetvals maps the serialized outcome of a syscall to a return value
+var outcomeRetvals = map[string]int{
+	"Success": 0,
+	"Refused": -int(syscall.EACCES),
+	"Error":   -int(syscall.EINVAL),
+}
+
+func rewriteProcessField(field string) string {
+	for _, rewrite := range processRewrites {
+		if field == rewrite.from || strings.HasPrefix(field, rewrite.from) && strings.HasSuffix(rewrite.from, ".") {
+			return rewrite.to + strings.TrimPrefix(field, rewrite.from)
+		}
+	}
+	return field
+}
+
+// fieldFromPath returns the SECL field matching the given path of the serialized event
+func fieldFromPath(eventType eval.EventType, path string) eval.Field {
+	for _, rewrite := range prefixRewrites {
+		if path == rewrite.from || strings.HasPrefix(path, rewrite.from) && strings.HasSuffix(rewrite.from, ".") {
+			return fmt.Sprintf(rewrite.to, eventType) + strings.TrimPrefix(path, rewrite.from)
+		}
+	}
+
+	for _, prefix := range processContextPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return prefix + rewriteProcessField(strings.TrimPrefix(path, prefix))
+		}
+	}
+
+	return path
+}
+
+// flatten returns the leaves of a serialized event, indexed by their dotted path
+func flatten(prefix string, value interface{}, leaves map[string]interface{}) {
+	if object, ok := value.(map[string]interface{}); ok {
+		for key, child := range object {
+			flatten(prefix+key+".", child, leaves)
+		}
+		return
+	}
+	leaves[strings.TrimSuffix(prefix, ".")] = value
+}
+
+// resolveConstant resolves a SECL constant name, or a `|` separated list of names, to its value
+func resolveConstant(name string) (int, error) {
+	var value int
+	for _, part := range strings.Split(name, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		constant, found := model.SECLConstants[part]
+		if !found {
+			return 0, fmt.Errorf("unknown constant `%s`", part)
+		}
+
+		evaluator, ok := constant.(*eval.IntEvaluator)
+		if !ok {
+			return 0, fmt.Errorf("constant `%s` isn't an integer", part)
+		}
+		value |= evaluator.Value
+	}
+	return value, nil
+}
+
+func toInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case json.Number:
+		i, err := v.Int64()
+		if err != nil {
+			return 0, err
+		}
+		return int(i), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		// addresses are serialized as hexadecimal strings
+		if i, err := strconv.ParseInt(v, 0, 64); err == nil {
+			return int(i), nil
+		}
+		// dates are serialized using RFC3339
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return int(t.UnixNano()), nil
+		}
+		return resolveConstant(v)
+	case []interface{}:
+		var bitmask int
+		for _, item := range v {
+			i, err := toInt(item)
+			if err != nil {
+				return 0, err
+			}
+			bitmask |= i
+		}
+		return bitmask, nil
+	}
+	return 0, fmt.Errorf("unsupported value %v", value)
+}
+
+func toIPNet(value interface{}) (net.IPNet, error) {
+	str, ok := value.(string)
+	if !ok {
+		return net.IPNet{}, fmt.Errorf("unsupported value %v", value)
+	}
+
+	if _, ipnet, err := net.ParseCIDR(str); err == nil {
+		return *ipnet, nil
+	}
+
+	ip := net.ParseIP(str)
+	if ip == nil {
+		return net.IPNet{}, fmt.Errorf("invalid IP address `%s`", str)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// setFieldValue converts a serialized value to the type of the field and sets it
+func setFieldValue(event *model.Event, field eval.Field, value interface{}) error {
+	kind, err := event.GetFieldType(field)
+	if err != nil {
+		// the serialized events hold more information than the rules can use
+		return nil
+	}
+
+	switch kind {
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			return event.SetFieldValue(field, v)
+		case []interface{}:
+			for _, item := range v {
+				if err := setFieldValue(event, field, item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Int:
+		i, err := toInt(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for `%s`: %w", field, err)
+		}
+		return event.SetFieldValue(field, i)
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			return event.SetFieldValue(field, b)
+		}
+	case reflect.Struct:
+		ipnet, err := toIPNet(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for `%s`: %w", field, err)
+		}
+		return event.SetFieldValue(field, ipnet)
+	}
+
+	return fmt.Errorf("invalid value for `%s`: unsupported value %v", field, value)
+}
+
+// setLeaf sets a leaf of the serialized event, the arguments of the processes are also set as a single string
+func setLeaf(event *model.Event, field eval.Field, value interface{}) error {
+	if err := setFieldValue(event, field, value); err != nil {
+		return err
+	}
+
+	if argv, ok := value.([]interface{}); ok && strings.HasSuffix(field, ".argv") {
+		args := make([]string, 0, len(argv))
+		for _, arg := range argv {
+			args = append(args, fmt.Sprint(arg))
+		}
+		return setFieldValue(event, strings.TrimSuffix(field, "argv")+"args", strings.Join(args, " "))
+	}
+
+	return nil
+}
+
+func newProcessCacheEntry(ancestor interface{}) (*model.ProcessCacheEntry, []error) {
+	object, ok := ancestor.(map[string]interface{})
+	if !ok {
+		return nil, []error{fmt.Errorf("invalid ancestor %v", ancestor)}
+	}
+
+	var errs []error
+	event := &model.Event{ProcessContext: &model.ProcessContext{}}
+
+	leaves := make(map[string]interface{})
+	flatten("", object, leaves)
+	for _, path := range sortedKeys(leaves) {
+		field := "process." + rewriteProcessField(path)
+		if err := setLeaf(event, field, leaves[path]); err != nil {
+			errs = append(errs, fmt.Errorf("ancestor: %w", err))
+		}
+	}
+
+	return &model.ProcessCacheEntry{ProcessContext: *event.ProcessContext}, errs
+}
+
+func sortedKeys(leaves map[string]interface{}) []string {
+	keys := make([]string, 0, len(leaves))
+	for key := range leaves {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// NewEvent converts a recorded event to an event that can be evaluated by a rule set. The errors returned report
+// the values of the recorded event that couldn't be converted.
+func NewEvent(recorded RecordedEvent) (*model.Event, []error) {
+	eventType := recorded.GetType()
+	if eventType == "" {
+		return nil, []error{fmt.Errorf("event type not found")}
+	}
+
+	kind := model.ParseEvalEventType(eventType)
+	if kind == model.UnknownEventType {
+		return nil, []error{fmt.Errorf("unknown event type `%s`", eventType)}
+	}
+
+	var errs []error
+	event := &model.Event{
+		Type:           uint32(kind),
+		ProcessContext: &model.ProcessContext{},
+	}
+
+	// the exec events are serialized as the process context of the event
+	if kind == model.ExecEventType {
+		event.Exec.Process = &event.ProcessContext.Process
+	}
+
+	leaves := make(map[string]interface{})
+	for section, value := range recorded {
+		if !ignoredSections[section] {
+			flatten(section+".", value, leaves)
+		}
+	}
+
+	for _, path := range sortedKeys(leaves) {
+		value := leaves[path]
+
+		// only the ancestors of the process context of the event can be evaluated
+		if path == "process.ancestors" {
+			ancestors, _ := value.([]interface{})
+
+			var last *model.ProcessCacheEntry
+			for _, ancestor := range ancestors {
+				entry, entryErrs := newProcessCacheEntry(ancestor)
+				errs = append(errs, entryErrs...)
+				if entry == nil {
+					continue
+				}
+
+				if last == nil {
+					event.ProcessContext.Ancestor = entry
+				} else {
+					last.Ancestor = entry
+				}
+				last = entry
+			}
+			continue
+		}
+
+		if err := setLeaf(event, fieldFromPath(eventType, path), value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	evt, _ := recorded["evt"].(map[string]interface{})
+	if outcome, ok := evt["outcome"].(string); ok {
+		if retval, found := outcomeRetvals[outcome]; found {
+			if err := setFieldValue(event, eventType+".retval", json.Number(fmt.Sprint(retval))); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if async, ok := evt["async"].(bool); ok {
+		event.Async = async
+	}
+
+	return event, errs
+}
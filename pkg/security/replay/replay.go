@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package replay evaluates recorded security events against a set of policies, without a running system-probe
+package replay
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// DiscarderReport describes a discarder that would have been pushed for an event
+type DiscarderReport struct {
+	Field eval.Field  `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// EventReport describes how the rules behaved for a recorded event
+type EventReport struct {
+	Index          int               `json:"index"`
+	Type           eval.EventType    `json:"type"`
+	ExpectedRuleID eval.RuleID       `json:"expected_rule_id,omitempty"`
+	MatchedRules   []eval.RuleID     `json:"matched_rules,omitempty"`
+	Discarders     []DiscarderReport `json:"discarders,omitempty"`
+	Errors         []string          `json:"errors,omitempty"`
+}
+
+// Failed returns true if the event couldn't be evaluated or if it didn't match the rule that triggered it
+func (r *EventReport) Failed() bool {
+	if len(r.Errors) > 0 {
+		return true
+	}
+
+	if r.ExpectedRuleID == "" {
+		return false
+	}
+
+	for _, id := range r.MatchedRules {
+		if id == r.ExpectedRuleID {
+			return false
+		}
+	}
+	return true
+}
+
+// Report describes how the rules behaved for a set of recorded events
+type Report struct {
+	Errors    []string                           `json:"errors,omitempty"`
+	Approvers map[eval.EventType]rules.Approvers `json:"approvers,omitempty"`
+	Events    []*EventReport                     `json:"events"`
+}
+
+// Failed returns true if the policies couldn't be loaded or if an event failed
+func (r *Report) Failed() bool {
+	if len(r.Errors) > 0 {
+		return true
+	}
+
+	for _, event := range r.Events {
+		if event.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Replayer evaluates recorded events against a rule set
+type Replayer struct {
+	ruleSet *rules.RuleSet
+	current *EventReport
+}
+
+var _ rules.RuleSetListener = (*Replayer)(nil)
+
+// NewReplayer returns a new Replayer
+func NewReplayer(ruleSet *rules.RuleSet) *Replayer {
+	r := &Replayer{
+		ruleSet: ruleSet,
+	}
+	ruleSet.AddListener(r)
+
+	return r
+}
+
+// RuleMatch implements the RuleSetListener interface
+func (r *Replayer) RuleMatch(rule *rules.Rule, event eval.Event) {
+	if r.current != nil {
+		r.current.MatchedRules = append(r.current.MatchedRules, rule.ID)
+	}
+}
+
+// EventDiscarderFound implements the RuleSetListener interface
+func (r *Replayer) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field eval.Field, eventType eval.EventType) {
+	if r.current == nil {
+		return
+	}
+
+	value, err := event.GetFieldValue(field)
+	if err != nil {
+		r.current.Errors = append(r.current.Errors, fmt.Sprintf("failed to get the value of the discarder `%s`: %s", field, err))
+		return
+	}
+	r.current.Discarders = append(r.current.Discarders, DiscarderReport{Field: field, Value: value})
+}
+
+// Replay evaluates the recorded events. The load errors of the policies, if any, and the approvers computed with
+// the given capabilities are added to the report.
+func (r *Replayer) Replay(events []RecordedEvent, loadErrs *multierror.Error, capabilities map[eval.EventType]rules.FieldCapabilities) *Report {
+	report := &Report{}
+
+	if loadErrs != nil {
+		for _, err := range loadErrs.Errors {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+
+	if capabilities != nil {
+		approvers, err := r.ruleSet.GetApprovers(capabilities)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		} else if len(approvers) > 0 {
+			report.Approvers = approvers
+		}
+	}
+
+	for i, recorded := range events {
+		r.current = &EventReport{
+			Index:          i,
+			Type:           recorded.GetType(),
+			ExpectedRuleID: recorded.GetRuleID(),
+		}
+
+		event, errs := NewEvent(recorded)
+		for _, err := range errs {
+			r.current.Errors = append(r.current.Errors, err.Error())
+		}
+
+		if event != nil {
+			r.ruleSet.Evaluate(event)
+		}
+
+		sort.Strings(r.current.MatchedRules)
+		report.Events = append(report.Events, r.current)
+	}
+	r.current = nil
+
+	return report
+}
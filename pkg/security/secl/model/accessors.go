@@ -26,11 +26,13 @@ func (m *Model) GetIterator(field eval.Field) (eval.Iterator, error) {
 }
 func (m *Model) GetEventTypes() []eval.EventType {
 	return []eval.EventType{
+		eval.EventType("accept"),
 		eval.EventType("bind"),
 		eval.EventType("bpf"),
 		eval.EventType("capset"),
 		eval.EventType("chmod"),
 		eval.EventType("chown"),
+		eval.EventType("connect"),
 		eval.EventType("dns"),
 		eval.EventType("exec"),
 		eval.EventType("link"),
@@ -56,6 +58,46 @@ func (m *Model) GetEventTypes() []eval.EventType {
 }
 func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Evaluator, error) {
 	switch field {
+	case "accept.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Accept.AddrFamily)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "accept.addr.ip":
+		return &eval.CIDREvaluator{
+			EvalFnc: func(ctx *eval.Context) net.IPNet {
+				return (*Event)(ctx.Object).Accept.Addr.IPNet
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "accept.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Accept.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "accept.protocol":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Accept.Protocol)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "accept.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Accept.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
 	case "async":
 		return &eval.BoolEvaluator{
 			EvalFnc: func(ctx *eval.Context) bool {
@@ -478,6 +520,46 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Field:  field,
 			Weight: eval.FunctionWeight,
 		}, nil
+	case "connect.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Connect.AddrFamily)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "connect.addr.ip":
+		return &eval.CIDREvaluator{
+			EvalFnc: func(ctx *eval.Context) net.IPNet {
+				return (*Event)(ctx.Object).Connect.Addr.IPNet
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "connect.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Connect.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "connect.protocol":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Connect.Protocol)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+	case "connect.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+				return int((*Event)(ctx.Object).Connect.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
 	case "container.id":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
@@ -6305,6 +6387,11 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 }
 func (e *Event) GetFields() []eval.Field {
 	return []eval.Field{
+		"accept.addr.family",
+		"accept.addr.ip",
+		"accept.addr.port",
+		"accept.protocol",
+		"accept.retval",
 		"async",
 		"bind.addr.family",
 		"bind.addr.ip",
@@ -6357,6 +6444,11 @@ func (e *Event) GetFields() []eval.Field {
 		"chown.file.uid",
 		"chown.file.user",
 		"chown.retval",
+		"connect.addr.family",
+		"connect.addr.ip",
+		"connect.addr.port",
+		"connect.protocol",
+		"connect.retval",
 		"container.id",
 		"container.tags",
 		"dns.question.class",
@@ -6948,6 +7040,16 @@ func (e *Event) GetFields() []eval.Field {
 }
 func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 	switch field {
+	case "accept.addr.family":
+		return int(e.Accept.AddrFamily), nil
+	case "accept.addr.ip":
+		return e.Accept.Addr.IPNet, nil
+	case "accept.addr.port":
+		return int(e.Accept.Addr.Port), nil
+	case "accept.protocol":
+		return int(e.Accept.Protocol), nil
+	case "accept.retval":
+		return int(e.Accept.SyscallEvent.Retval), nil
 	case "async":
 		return e.Async, nil
 	case "bind.addr.family":
@@ -7056,6 +7158,16 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 		return e.Chown.File.FileFields.User, nil
 	case "chown.retval":
 		return int(e.Chown.SyscallEvent.Retval), nil
+	case "connect.addr.family":
+		return int(e.Connect.AddrFamily), nil
+	case "connect.addr.ip":
+		return e.Connect.Addr.IPNet, nil
+	case "connect.addr.port":
+		return int(e.Connect.Addr.Port), nil
+	case "connect.protocol":
+		return int(e.Connect.Protocol), nil
+	case "connect.retval":
+		return int(e.Connect.SyscallEvent.Retval), nil
 	case "container.id":
 		return e.ContainerContext.ID, nil
 	case "container.tags":
@@ -9615,6 +9727,16 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 }
 func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	switch field {
+	case "accept.addr.family":
+		return "accept", nil
+	case "accept.addr.ip":
+		return "accept", nil
+	case "accept.addr.port":
+		return "accept", nil
+	case "accept.protocol":
+		return "accept", nil
+	case "accept.retval":
+		return "accept", nil
 	case "async":
 		return "*", nil
 	case "bind.addr.family":
@@ -9719,6 +9841,16 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 		return "chown", nil
 	case "chown.retval":
 		return "chown", nil
+	case "connect.addr.family":
+		return "connect", nil
+	case "connect.addr.ip":
+		return "connect", nil
+	case "connect.addr.port":
+		return "connect", nil
+	case "connect.protocol":
+		return "connect", nil
+	case "connect.retval":
+		return "connect", nil
 	case "container.id":
 		return "*", nil
 	case "container.tags":
@@ -10898,6 +11030,16 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 }
 func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 	switch field {
+	case "accept.addr.family":
+		return reflect.Int, nil
+	case "accept.addr.ip":
+		return reflect.Struct, nil
+	case "accept.addr.port":
+		return reflect.Int, nil
+	case "accept.protocol":
+		return reflect.Int, nil
+	case "accept.retval":
+		return reflect.Int, nil
 	case "async":
 		return reflect.Bool, nil
 	case "bind.addr.family":
@@ -11002,6 +11144,16 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 		return reflect.String, nil
 	case "chown.retval":
 		return reflect.Int, nil
+	case "connect.addr.family":
+		return reflect.Int, nil
+	case "connect.addr.ip":
+		return reflect.Struct, nil
+	case "connect.addr.port":
+		return reflect.Int, nil
+	case "connect.protocol":
+		return reflect.Int, nil
+	case "connect.retval":
+		return reflect.Int, nil
 	case "container.id":
 		return reflect.String, nil
 	case "container.tags":
@@ -12181,6 +12333,41 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 }
 func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 	switch field {
+	case "accept.addr.family":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Accept.AddrFamily"}
+		}
+		e.Accept.AddrFamily = uint16(v)
+		return nil
+	case "accept.addr.ip":
+		v, ok := value.(net.IPNet)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Accept.Addr.IPNet"}
+		}
+		e.Accept.Addr.IPNet = v
+		return nil
+	case "accept.addr.port":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Accept.Addr.Port"}
+		}
+		e.Accept.Addr.Port = uint16(v)
+		return nil
+	case "accept.protocol":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Accept.Protocol"}
+		}
+		e.Accept.Protocol = uint16(v)
+		return nil
+	case "accept.retval":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Accept.SyscallEvent.Retval"}
+		}
+		e.Accept.SyscallEvent.Retval = int64(v)
+		return nil
 	case "async":
 		var ok bool
 		if e.Async, ok = value.(bool); !ok {
@@ -12542,6 +12729,41 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		}
 		e.Chown.SyscallEvent.Retval = int64(v)
 		return nil
+	case "connect.addr.family":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.AddrFamily"}
+		}
+		e.Connect.AddrFamily = uint16(v)
+		return nil
+	case "connect.addr.ip":
+		v, ok := value.(net.IPNet)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.IPNet"}
+		}
+		e.Connect.Addr.IPNet = v
+		return nil
+	case "connect.addr.port":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.Port"}
+		}
+		e.Connect.Addr.Port = uint16(v)
+		return nil
+	case "connect.protocol":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Protocol"}
+		}
+		e.Connect.Protocol = uint16(v)
+		return nil
+	case "connect.retval":
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.SyscallEvent.Retval"}
+		}
+		e.Connect.SyscallEvent.Retval = int64(v)
+		return nil
 	case "container.id":
 		str, ok := value.(string)
 		if !ok {
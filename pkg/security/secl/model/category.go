@@ -37,8 +37,8 @@ func GetEventTypeCategory(eventType eval.EventType) EventCategory {
 	switch eventType {
 	case "exec", "signal", "exit", "fork":
 		return ProcessCategory
-	case "bpf", "selinux", "mmap", "mprotect", "ptrace", "load_module", "unload_module", "bind":
-		// TODO(will): "bind" is in this category because answering "NetworkCategory" would insert a network section in the serializer.
+	case "bpf", "selinux", "mmap", "mprotect", "ptrace", "load_module", "unload_module", "bind", "connect", "accept":
+		// TODO(will): "bind", "connect" and "accept" are in this category because answering "NetworkCategory" would insert a network section in the serializer.
 		return KernelCategory
 	case "dns":
 		return NetworkCategory
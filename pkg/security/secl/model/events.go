@@ -85,6 +85,12 @@ const (
 	VethPairEventType
 	// BindEventType Bind event
 	BindEventType
+	// ConnectEventType Connect event
+	ConnectEventType
+	// AcceptEventType Accept event
+	AcceptEventType
+	// FileCloseEventType is sent when a file opened for writing is closed
+	FileCloseEventType
 	// MaxKernelEventType is used internally to get the maximum number of kernel events.
 	MaxKernelEventType
 
@@ -108,6 +114,10 @@ const (
 	CustomTruncatedParentsEventType
 	// CustomSelfTestEventType is the custom event used to report the results of a self test run
 	CustomSelfTestEventType
+	// CustomAnomalyDetectionEventType is the custom event used to report an activity missing from a security profile
+	CustomAnomalyDetectionEventType
+	// CustomFileIntegrityEventType is the custom event used to report the change of the content of a monitored file
+	CustomFileIntegrityEventType
 	// MaxAllEventType is used internally to get the maximum number of events.
 	MaxAllEventType
 )
@@ -186,6 +196,12 @@ func (t EventType) String() string {
 		return "veth_pair"
 	case BindEventType:
 		return "bind"
+	case ConnectEventType:
+		return "connect"
+	case AcceptEventType:
+		return "accept"
+	case FileCloseEventType:
+		return "close"
 
 	case CustomLostReadEventType:
 		return "lost_events_read"
@@ -201,6 +217,10 @@ func (t EventType) String() string {
 		return "truncated_parents"
 	case CustomSelfTestEventType:
 		return "self_test"
+	case CustomAnomalyDetectionEventType:
+		return "anomaly_detection"
+	case CustomFileIntegrityEventType:
+		return "file_integrity"
 	default:
 		return "unknown"
 	}
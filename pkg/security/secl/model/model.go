@@ -172,8 +172,10 @@ type Event struct {
 	UnloadModule UnloadModuleEvent `field:"unload_module" event:"unload_module"` // [7.35] [Kernel] A kernel module was deleted
 
 	// network events
-	DNS  DNSEvent  `field:"dns" event:"dns"`   // [7.36] [Network] A DNS request was sent
-	Bind BindEvent `field:"bind" event:"bind"` // [7.37] [Network] [Experimental] A bind was executed
+	DNS     DNSEvent     `field:"dns" event:"dns"`         // [7.36] [Network] A DNS request was sent
+	Bind    BindEvent    `field:"bind" event:"bind"`       // [7.37] [Network] [Experimental] A bind was executed
+	Connect ConnectEvent `field:"connect" event:"connect"` // [7.38] [Network] [Experimental] A connect was executed
+	Accept  AcceptEvent  `field:"accept" event:"accept"`   // [7.38] [Network] [Experimental] An incoming connection was accepted
 
 	// internal usage
 	Mount            MountEvent            `field:"-"`
@@ -181,6 +183,7 @@ type Event struct {
 	InvalidateDentry InvalidateDentryEvent `field:"-"`
 	ArgsEnvs         ArgsEnvsEvent         `field:"-"`
 	MountReleased    MountReleasedEvent    `field:"-"`
+	Close            CloseEvent            `field:"-"`
 	CgroupTracing    CgroupTracingEvent    `field:"-"`
 	NetDevice        NetDeviceEvent        `field:"-"`
 	VethPair         VethPairEvent         `field:"-"`
@@ -418,6 +421,12 @@ type MountReleasedEvent struct {
 	DiscarderRevision uint32
 }
 
+// CloseEvent defines the close event of a file opened for writing
+//msgp:ignore CloseEvent
+type CloseEvent struct {
+	File FileEvent
+}
+
 // LinkEvent represents a link event
 //msgp:ignore LinkEvent
 type LinkEvent struct {
@@ -822,6 +831,26 @@ type BindEvent struct {
 	Addr       IPPortContext `field:"addr"`                                                     // Bound address
 }
 
+// ConnectEvent represents a connect event
+//msgp:ignore ConnectEvent
+type ConnectEvent struct {
+	SyscallEvent
+
+	AddrFamily uint16        `field:"addr.family" constants:"Network Address Family constants"` // Address family
+	Addr       IPPortContext `field:"addr"`                                                     // Connection address
+	Protocol   uint16        `field:"protocol" constants:"L4 protocols"`                        // Transport protocol of the socket
+}
+
+// AcceptEvent represents an accept event
+//msgp:ignore AcceptEvent
+type AcceptEvent struct {
+	SyscallEvent
+
+	AddrFamily uint16        `field:"addr.family" constants:"Network Address Family constants"` // Address family
+	Addr       IPPortContext `field:"addr"`                                                     // Address of the remote peer
+	Protocol   uint16        `field:"protocol" constants:"L4 protocols"`                        // Transport protocol of the socket
+}
+
 // NetDevice represents a network device
 //msgp:ignore NetDevice
 type NetDevice struct {
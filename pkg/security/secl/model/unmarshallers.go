@@ -529,6 +529,11 @@ func (e *MountReleasedEvent) UnmarshalBinary(data []byte) (int, error) {
 	return 8, nil
 }
 
+// UnmarshalBinary unmarshalls a binary representation of itself
+func (e *CloseEvent) UnmarshalBinary(data []byte) (int, error) {
+	return UnmarshalBinary(data, &e.File)
+}
+
 // UnmarshalBinary unmarshalls a binary representation of itself
 func (e *BPFEvent) UnmarshalBinary(data []byte) (int, error) {
 	read, err := UnmarshalBinary(data, &e.SyscallEvent)
@@ -912,21 +917,75 @@ func (e *BindEvent) UnmarshalBinary(data []byte) (int, error) {
 		return 0, err
 	}
 
-	if len(data)-read < 20 {
+	n, err := unmarshalSocketAddr(data[read:], &e.AddrFamily, &e.Addr)
+	if err != nil {
+		return 0, err
+	}
+
+	return read + n, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ConnectEvent) UnmarshalBinary(data []byte) (int, error) {
+	read, err := UnmarshalBinary(data, &e.SyscallEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := unmarshalSocketAddr(data[read:], &e.AddrFamily, &e.Addr)
+	if err != nil {
+		return 0, err
+	}
+	read += n
+
+	if len(data)-read < 4 {
+		return 0, ErrNotEnoughData
+	}
+	e.Protocol = ByteOrder.Uint16(data[read : read+2])
+	// padding
+
+	return read + 4, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *AcceptEvent) UnmarshalBinary(data []byte) (int, error) {
+	read, err := UnmarshalBinary(data, &e.SyscallEvent)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := unmarshalSocketAddr(data[read:], &e.AddrFamily, &e.Addr)
+	if err != nil {
+		return 0, err
+	}
+	read += n
+
+	if len(data)-read < 4 {
+		return 0, ErrNotEnoughData
+	}
+	e.Protocol = ByteOrder.Uint16(data[read : read+2])
+	// padding
+
+	return read + 4, nil
+}
+
+// unmarshalSocketAddr unmarshals an address as sent by the kernel: a 16 bytes IP, the address family and the port
+func unmarshalSocketAddr(data []byte, family *uint16, addr *IPPortContext) (int, error) {
+	if len(data) < 20 {
 		return 0, ErrNotEnoughData
 	}
 
-	ipRaw := data[read : read+16]
-	e.AddrFamily = ByteOrder.Uint16(data[read+16 : read+18])
-	e.Addr.Port = binary.BigEndian.Uint16(data[read+18 : read+20])
+	ipRaw := data[0:16]
+	*family = ByteOrder.Uint16(data[16:18])
+	addr.Port = binary.BigEndian.Uint16(data[18:20])
 
 	// readjust IP size depending on the protocol
-	switch e.AddrFamily {
+	switch *family {
 	case 0x2: // unix.AF_INET
-		e.Addr.IPNet = *eval.IPNetFromIP(ipRaw[0:4])
+		addr.IPNet = *eval.IPNetFromIP(ipRaw[0:4])
 	case 0xa: // unix.AF_INET6
-		e.Addr.IPNet = *eval.IPNetFromIP(ipRaw)
+		addr.IPNet = *eval.IPNetFromIP(ipRaw)
 	}
 
-	return read + 20, nil
+	return 20, nil
 }
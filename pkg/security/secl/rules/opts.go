@@ -24,6 +24,7 @@ type Opts struct {
 	ReservedRuleIDs     []RuleID
 	EventTypeEnabled    map[eval.EventType]bool
 	StateScopes         map[Scope]VariableProviderFactory
+	SequenceScopes      map[Scope]eval.Field
 	Logger              Logger
 }
 
@@ -86,3 +87,9 @@ func (o *Opts) WithStateScopes(stateScopes map[Scope]VariableProviderFactory) *O
 	o.StateScopes = stateScopes
 	return o
 }
+
+// WithSequenceScopes set the fields identifying the scopes of the sequence rules
+func (o *Opts) WithSequenceScopes(sequenceScopes map[Scope]eval.Field) *Opts {
+	o.SequenceScopes = sequenceScopes
+	return o
+}
@@ -73,11 +73,16 @@ func parsePolicyDef(name string, source string, def *PolicyDef) (*Policy, *multi
 			continue
 		}
 
-		if ruleDef.Expression == "" && !ruleDef.Disabled {
+		if ruleDef.Expression == "" && ruleDef.Sequence == nil && !ruleDef.Disabled {
 			errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("no expression defined")})
 			continue
 		}
 
+		if ruleDef.Expression != "" && ruleDef.Sequence != nil {
+			errs = multierror.Append(errs, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("both an expression and a sequence are defined")})
+			continue
+		}
+
 		policy.AddRule(ruleDef)
 	}
 
@@ -631,3 +631,117 @@ func TestActionSetVariableInvalid(t *testing.T) {
 		}
 	})
 }
+
+func TestActionKill(t *testing.T) {
+	testPolicy := &PolicyDef{
+		Rules: []*RuleDefinition{{
+			ID:         "test_rule",
+			Expression: `open.filename == "/tmp/test"`,
+			Actions: []ActionDefinition{{
+				Kill: &KillDefinition{},
+			}},
+		}, {
+			ID:         "test_rule2",
+			Expression: `open.filename == "/tmp/test2"`,
+			Actions: []ActionDefinition{{
+				Kill: &KillDefinition{
+					Signal: "SIGUSR1",
+					Scope:  KillScopeContainer,
+				},
+			}},
+		}},
+	}
+
+	if err := loadPolicy(t, testPolicy); err != nil {
+		t.Fatal(err)
+	}
+
+	kill := testPolicy.Rules[0].Actions[0].Kill
+	if kill.GetSignal() != "SIGKILL" || kill.GetScope() != KillScopeProcess {
+		t.Errorf("unexpected kill defaults: %s %s", kill.GetSignal(), kill.GetScope())
+	}
+}
+
+func TestActionKillInvalid(t *testing.T) {
+	t.Run("invalid-scope", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Kill: &KillDefinition{
+						Scope: "host",
+					},
+				}},
+			}},
+		}
+
+		if err := loadPolicy(t, testPolicy); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+
+	t.Run("invalid-signal", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Kill: &KillDefinition{
+						Signal: "9",
+					},
+				}},
+			}},
+		}
+
+		if err := loadPolicy(t, testPolicy); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+
+	t.Run("unknown-signal", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Kill: &KillDefinition{
+						Signal: "SIGFOO",
+					},
+				}},
+			}},
+		}
+
+		if err := loadPolicy(t, testPolicy); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+
+	t.Run("both-set-and-kill", func(t *testing.T) {
+		testPolicy := &PolicyDef{
+			Rules: []*RuleDefinition{{
+				ID:         "test_rule",
+				Expression: `open.filename == "/tmp/test"`,
+				Actions: []ActionDefinition{{
+					Set: &SetDefinition{
+						Name:  "var1",
+						Value: true,
+					},
+					Kill: &KillDefinition{},
+				}},
+			}},
+		}
+
+		if err := loadPolicy(t, testPolicy); err == nil {
+			t.Error("expected policy to fail to load")
+		} else {
+			t.Log(err)
+		}
+	})
+}
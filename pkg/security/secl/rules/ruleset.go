@@ -10,6 +10,7 @@ import (
 	"reflect"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -68,14 +69,15 @@ var ruleIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_]*$`)
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID          RuleID             `yaml:"id"`
-	Version     string             `yaml:"version"`
-	Expression  string             `yaml:"expression"`
-	Description string             `yaml:"description"`
-	Tags        map[string]string  `yaml:"tags"`
-	Disabled    bool               `yaml:"disabled"`
-	Combine     CombinePolicy      `yaml:"combine"`
-	Actions     []ActionDefinition `yaml:"actions"`
+	ID          RuleID              `yaml:"id"`
+	Version     string              `yaml:"version"`
+	Expression  string              `yaml:"expression"`
+	Description string              `yaml:"description"`
+	Tags        map[string]string   `yaml:"tags"`
+	Disabled    bool                `yaml:"disabled"`
+	Combine     CombinePolicy       `yaml:"combine"`
+	Actions     []ActionDefinition  `yaml:"actions"`
+	Sequence    *SequenceDefinition `yaml:"sequence"`
 	Policy      *Policy
 }
 
@@ -99,6 +101,7 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 	switch rd2.Combine {
 	case OverridePolicy:
 		rd.Expression = rd2.Expression
+		rd.Sequence = rd2.Sequence
 	default:
 		if !rd2.Disabled {
 			return &ErrRuleLoad{Definition: rd2, Err: ErrInternalIDConflict}
@@ -110,13 +113,22 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 
 // ActionDefinition describes a rule action section
 type ActionDefinition struct {
-	Set *SetDefinition `yaml:"set"`
+	Set  *SetDefinition  `yaml:"set"`
+	Kill *KillDefinition `yaml:"kill"`
 }
 
 // Check returns an error if the action in invalid
 func (a *ActionDefinition) Check() error {
-	if a.Set == nil {
-		return errors.New("missing 'set' section in action")
+	if a.Set == nil && a.Kill == nil {
+		return errors.New("either 'set' or 'kill' section must be specified in action")
+	}
+
+	if a.Set != nil && a.Kill != nil {
+		return errors.New("only one of 'set' or 'kill' section can be specified in action")
+	}
+
+	if a.Kill != nil {
+		return a.Kill.check()
 	}
 
 	if a.Set.Name == "" {
@@ -142,10 +154,102 @@ type SetDefinition struct {
 	Scope  Scope       `yaml:"scope"`
 }
 
+// KillScope describes the set of processes targeted by a kill action
+type KillScope = string
+
+// Kill scopes
+const (
+	// KillScopeProcess targets the process that triggered the rule
+	KillScopeProcess KillScope = "process"
+	// KillScopeContainer targets all the processes of the container of the process that triggered the rule
+	KillScopeContainer KillScope = "container"
+)
+
+// DefaultKillSignal is the signal sent by a kill action when none is specified
+const DefaultKillSignal = "SIGKILL"
+
+// KillSignals maps the signals supported by the kill actions to their number. Kill actions are only executed on
+// Linux, so the Linux numbers are used on every platform.
+var KillSignals = map[string]int{
+	"SIGHUP":    1,
+	"SIGINT":    2,
+	"SIGQUIT":   3,
+	"SIGILL":    4,
+	"SIGTRAP":   5,
+	"SIGABRT":   6,
+	"SIGIOT":    6,
+	"SIGBUS":    7,
+	"SIGFPE":    8,
+	"SIGKILL":   9,
+	"SIGUSR1":   10,
+	"SIGSEGV":   11,
+	"SIGUSR2":   12,
+	"SIGPIPE":   13,
+	"SIGALRM":   14,
+	"SIGTERM":   15,
+	"SIGSTKFLT": 16,
+	"SIGCHLD":   17,
+	"SIGCONT":   18,
+	"SIGSTOP":   19,
+	"SIGTSTP":   20,
+	"SIGTTIN":   21,
+	"SIGTTOU":   22,
+	"SIGURG":    23,
+	"SIGXCPU":   24,
+	"SIGXFSZ":   25,
+	"SIGVTALRM": 26,
+	"SIGPROF":   27,
+	"SIGWINCH":  28,
+	"SIGIO":     29,
+	"SIGPOLL":   29,
+	"SIGPWR":    30,
+	"SIGSYS":    31,
+}
+
+// KillDefinition describes the 'kill' section of a rule action
+type KillDefinition struct {
+	Signal string    `yaml:"signal"`
+	Scope  KillScope `yaml:"scope"`
+}
+
+// GetSignal returns the signal to send, defaulting to SIGKILL
+func (k *KillDefinition) GetSignal() string {
+	if k.Signal == "" {
+		return DefaultKillSignal
+	}
+	return k.Signal
+}
+
+// GetScope returns the scope of the kill action, defaulting to the process scope
+func (k *KillDefinition) GetScope() KillScope {
+	if k.Scope == "" {
+		return KillScopeProcess
+	}
+	return k.Scope
+}
+
+func (k *KillDefinition) check() error {
+	if signal := k.GetSignal(); KillSignals[signal] == 0 {
+		return fmt.Errorf("invalid signal '%s'", signal)
+	}
+
+	switch k.GetScope() {
+	case KillScopeProcess, KillScopeContainer:
+	default:
+		return fmt.Errorf("invalid kill scope '%s'", k.Scope)
+	}
+
+	return nil
+}
+
 // Rule describes a rule of a ruleset
 type Rule struct {
 	*eval.Rule
 	Definition *RuleDefinition
+
+	// sequence is set for the steps of a sequence rule
+	sequence *sequence
+	step     int
 }
 
 // RuleSetListener describes the methods implemented by an object used to be
@@ -172,6 +276,7 @@ type RuleSet struct {
 	fields []string
 	logger Logger
 	pool   *eval.ContextPool
+	now    func() time.Time
 }
 
 // ListRuleIDs returns the list of RuleIDs from the ruleset
@@ -296,35 +401,76 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		tags = append(tags, k+":"+v)
 	}
 
-	rule := &Rule{
-		Rule: &eval.Rule{
-			ID:         ruleDef.ID,
-			Expression: ruleDef.Expression,
-			Tags:       tags,
-		},
-		Definition: ruleDef,
+	var rule *Rule
+	if ruleDef.Sequence != nil {
+		var err error
+		if rule, err = rs.addSequenceRule(ruleDef, tags); err != nil {
+			return nil, err
+		}
+	} else {
+		rule = &Rule{
+			Rule: &eval.Rule{
+				ID:         ruleDef.ID,
+				Expression: ruleDef.Expression,
+				Tags:       tags,
+			},
+			Definition: ruleDef,
+		}
+
+		if err := rs.generateRuleEvaluator(rule); err != nil {
+			return nil, err
+		}
+
+		if err := rs.addRuleToBuckets(rule); err != nil {
+			return nil, err
+		}
 	}
 
+	rs.rules[ruleDef.ID] = rule
+
+	// Generate evaluator for fields that are used in variables
+	for _, action := range rule.Definition.Actions {
+		if action.Set != nil && action.Set.Field != "" {
+			if _, found := rs.fieldEvaluators[action.Set.Field]; !found {
+				evaluator, err := rs.model.GetEvaluator(action.Set.Field, "")
+				if err != nil {
+					return nil, err
+				}
+				rs.fieldEvaluators[action.Set.Field] = evaluator
+			}
+		}
+	}
+
+	return rule.Rule, nil
+}
+
+// generateRuleEvaluator parses the rule and generates its evaluator
+func (rs *RuleSet) generateRuleEvaluator(rule *Rule) error {
 	if err := rule.Parse(); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: errors.Wrap(err, "syntax error")}
+		return &ErrRuleLoad{Definition: rule.Definition, Err: errors.Wrap(err, "syntax error")}
 	}
 
 	if err := rule.GenEvaluator(rs.model, &rs.opts.Opts); err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		return &ErrRuleLoad{Definition: rule.Definition, Err: err}
 	}
 
 	eventType, err := GetRuleEventType(rule.Rule)
 	if err != nil {
-		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		return &ErrRuleLoad{Definition: rule.Definition, Err: err}
 	}
 
 	// ignore event types not supported
 	if _, exists := rs.opts.EventTypeEnabled["*"]; !exists {
 		if _, exists := rs.opts.EventTypeEnabled[eventType]; !exists {
-			return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrEventTypeNotEnabled}
+			return &ErrRuleLoad{Definition: rule.Definition, Err: ErrEventTypeNotEnabled}
 		}
 	}
 
+	return nil
+}
+
+// addRuleToBuckets adds the rule to the buckets of its events
+func (rs *RuleSet) addRuleToBuckets(rule *Rule) error {
 	for _, event := range rule.GetEvaluator().EventTypes {
 		bucket, exists := rs.eventRuleBuckets[event]
 		if !exists {
@@ -333,29 +479,14 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		}
 
 		if err := bucket.AddRule(rule); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	// Merge the fields of the new rule with the existing list of fields of the ruleset
 	rs.AddFields(rule.GetEvaluator().GetFields())
 
-	rs.rules[ruleDef.ID] = rule
-
-	// Generate evaluator for fields that are used in variables
-	for _, action := range rule.Definition.Actions {
-		if action.Set != nil && action.Set.Field != "" {
-			if _, found := rs.fieldEvaluators[action.Set.Field]; !found {
-				evaluator, err := rs.model.GetEvaluator(action.Set.Field, "")
-				if err != nil {
-					return nil, err
-				}
-				rs.fieldEvaluators[action.Set.Field] = evaluator
-			}
-		}
-	}
-
-	return rule.Rule, nil
+	return nil
 }
 
 // NotifyRuleMatch notifies all the ruleset listeners that an event matched a rule
@@ -503,6 +634,8 @@ func (rs *RuleSet) runRuleActions(ctx *eval.Context, rule *Rule) error {
 					}
 				}
 			}
+		case action.Kill != nil:
+			// kill actions are executed by the rule set listeners, see RuleMatch
 		}
 	}
 
@@ -523,8 +656,14 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 	}
 	rs.logger.Tracef("Evaluating event of type `%s` against set of %d rules", eventType, len(bucket.rules))
 
+	var matchingSteps []*Rule
 	for _, rule := range bucket.rules {
 		if rule.GetEvaluator().Eval(ctx) {
+			if rule.sequence != nil {
+				matchingSteps = append(matchingSteps, rule)
+				continue
+			}
+
 			rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
 			rs.NotifyRuleMatch(rule, event)
@@ -536,7 +675,27 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 		}
 	}
 
-	if !result {
+	// the last steps are handled first so that an event can't match multiple steps of the same sequence
+	now := rs.now()
+	for i := len(matchingSteps) - 1; i >= 0; i-- {
+		rule := matchingSteps[i]
+		if !rule.sequence.advance(ctx, rule.step, now) {
+			rs.logger.Tracef("Step %d of sequence `%s` matches with event `%s`\n", rule.step, rule.sequence.rule.ID, event)
+			continue
+		}
+
+		rs.logger.Tracef("Sequence `%s` matches with event `%s`\n", rule.ID, event)
+
+		rs.NotifyRuleMatch(rule, event)
+		result = true
+
+		if err := rs.runRuleActions(ctx, rule); err != nil {
+			rs.logger.Errorf("Error while executing rule actions: %s", err)
+		}
+	}
+
+	// the events matching a step of a sequence can't be discarded
+	if !result && len(matchingSteps) == 0 {
 		rs.logger.Tracef("Looking for discarders for event of type `%s`", eventType)
 
 		for _, field := range bucket.fields {
@@ -776,5 +935,6 @@ func NewRuleSet(model eval.Model, eventCtor func() eval.Event, opts *Opts) *Rule
 		pool:             eval.NewContextPool(),
 		fieldEvaluators:  make(map[string]eval.Evaluator),
 		scopedVariables:  make(map[Scope]VariableProvider),
+		now:              time.Now,
 	}
 }
@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+const (
+	// DefaultSequenceTimeout is the default maximum duration between the first and the last step of a sequence
+	DefaultSequenceTimeout = time.Minute
+	// DefaultSequenceMaxStates is the default maximum number of sequences tracked at the same time for a rule
+	DefaultSequenceMaxStates = 1000
+)
+
+// SequenceStepDefinition describes a step of a sequence rule
+type SequenceStepDefinition struct {
+	Expression string     `yaml:"expression"`
+	Correlate  eval.Field `yaml:"correlate"`
+}
+
+// SequenceDefinition describes a rule matching an ordered list of events
+type SequenceDefinition struct {
+	Scope     Scope                     `yaml:"scope"`
+	Timeout   time.Duration             `yaml:"timeout"`
+	MaxStates int                       `yaml:"max_states"`
+	Steps     []*SequenceStepDefinition `yaml:"steps"`
+}
+
+// GetTimeout returns the maximum duration between the first and the last step of the sequence
+func (s *SequenceDefinition) GetTimeout() time.Duration {
+	if s.Timeout <= 0 {
+		return DefaultSequenceTimeout
+	}
+	return s.Timeout
+}
+
+// GetMaxStates returns the maximum number of sequences tracked at the same time
+func (s *SequenceDefinition) GetMaxStates() int {
+	if s.MaxStates <= 0 {
+		return DefaultSequenceMaxStates
+	}
+	return s.MaxStates
+}
+
+// Check returns an error if the sequence is invalid
+func (s *SequenceDefinition) Check() error {
+	if len(s.Steps) < 2 {
+		return errors.New("a sequence requires at least 2 steps")
+	}
+
+	if s.Timeout < 0 {
+		return errors.New("negative sequence timeout")
+	}
+
+	if s.MaxStates < 0 {
+		return errors.New("negative sequence max_states")
+	}
+
+	correlated := s.Steps[0].Correlate != ""
+	for i, step := range s.Steps {
+		if step.Expression == "" {
+			return fmt.Errorf("no expression defined for step %d", i)
+		}
+
+		if (step.Correlate != "") != correlated {
+			return errors.New("either all the steps or none of them define a correlation field")
+		}
+	}
+
+	return nil
+}
+
+// sequenceState tracks the progress of a sequence for a scope and a correlation value
+type sequenceState struct {
+	key   string
+	step  int
+	start time.Time
+}
+
+// sequence tracks the states of a sequence rule
+type sequence struct {
+	definition *SequenceDefinition
+	// rule is notified when the last step matches
+	rule      *Rule
+	scope     eval.Evaluator
+	correlate []eval.Evaluator
+
+	// states are ordered by start time, the oldest first
+	states  map[string]*list.Element
+	ordered *list.List
+}
+
+func newSequence(definition *SequenceDefinition, scope eval.Evaluator, correlate []eval.Evaluator) *sequence {
+	return &sequence{
+		definition: definition,
+		scope:      scope,
+		correlate:  correlate,
+		states:     make(map[string]*list.Element),
+		ordered:    list.New(),
+	}
+}
+
+func (s *sequence) stateKey(ctx *eval.Context, step int) string {
+	var scope, correlate interface{}
+	if s.scope != nil {
+		scope = s.scope.Eval(ctx)
+	}
+	if s.correlate[step] != nil {
+		correlate = s.correlate[step].Eval(ctx)
+	}
+	return fmt.Sprintf("%v/%v", scope, correlate)
+}
+
+func (s *sequence) remove(elem *list.Element) {
+	delete(s.states, elem.Value.(*sequenceState).key)
+	s.ordered.Remove(elem)
+}
+
+// expire drops the sequences started before the timeout
+func (s *sequence) expire(now time.Time) {
+	deadline := now.Add(-s.definition.GetTimeout())
+	for elem := s.ordered.Front(); elem != nil; elem = s.ordered.Front() {
+		if elem.Value.(*sequenceState).start.After(deadline) {
+			return
+		}
+		s.remove(elem)
+	}
+}
+
+// advance updates the state of the sequence with the given matching step and returns true when the sequence is complete
+func (s *sequence) advance(ctx *eval.Context, step int, now time.Time) bool {
+	s.expire(now)
+
+	key := s.stateKey(ctx, step)
+	elem, exists := s.states[key]
+
+	if step == 0 {
+		// a sequence in progress isn't restarted
+		if exists {
+			return false
+		}
+
+		if s.ordered.Len() >= s.definition.GetMaxStates() {
+			s.remove(s.ordered.Front())
+		}
+
+		s.states[key] = s.ordered.PushBack(&sequenceState{key: key, step: 1, start: now})
+		return false
+	}
+
+	if !exists {
+		return false
+	}
+
+	state := elem.Value.(*sequenceState)
+	if state.step != step {
+		return false
+	}
+
+	if step == len(s.definition.Steps)-1 {
+		s.remove(elem)
+		return true
+	}
+
+	state.step++
+	return false
+}
+
+func sequenceStepID(id RuleID, step int) RuleID {
+	return fmt.Sprintf("%s_step%d", id, step)
+}
+
+// addSequenceRule adds the steps of a sequence rule to the buckets of their events. The last step holds the ID of the
+// rule, the listeners are notified when it matches and all the previous steps matched in order.
+func (rs *RuleSet) addSequenceRule(ruleDef *RuleDefinition, tags []string) (*Rule, error) {
+	definition := ruleDef.Sequence
+	if err := definition.Check(); err != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+	}
+
+	var scope eval.Evaluator
+	if definition.Scope != "" {
+		field, found := rs.opts.SequenceScopes[definition.Scope]
+		if !found {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("invalid sequence scope '%s'", definition.Scope)}
+		}
+
+		evaluator, err := rs.model.GetEvaluator(field, "")
+		if err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		}
+		scope = evaluator
+	}
+
+	correlate := make([]eval.Evaluator, len(definition.Steps))
+	for i, step := range definition.Steps {
+		if step.Correlate == "" {
+			continue
+		}
+
+		evaluator, err := rs.model.GetEvaluator(step.Correlate, "")
+		if err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: err}
+		}
+		correlate[i] = evaluator
+	}
+
+	seq := newSequence(definition, scope, correlate)
+
+	var steps []*Rule
+	for i, step := range definition.Steps {
+		id := sequenceStepID(ruleDef.ID, i)
+		if i == len(definition.Steps)-1 {
+			id = ruleDef.ID
+		}
+
+		rule := &Rule{
+			Rule: &eval.Rule{
+				ID:         id,
+				Expression: step.Expression,
+				Tags:       tags,
+			},
+			Definition: ruleDef,
+			sequence:   seq,
+			step:       i,
+		}
+
+		if err := rs.generateRuleEvaluator(rule); err != nil {
+			return nil, err
+		}
+
+		// the correlation field has to be either a common field or a field of the event type of the step
+		if step.Correlate != "" {
+			eventType, _ := GetRuleEventType(rule.Rule)
+			correlateEventType, _ := rs.eventCtor().GetFieldEventType(step.Correlate)
+			if correlateEventType != "" && correlateEventType != "*" && correlateEventType != eventType {
+				return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("correlation field `%s` doesn't belong to the event type of step %d", step.Correlate, i)}
+			}
+		}
+
+		steps = append(steps, rule)
+	}
+
+	for _, rule := range steps {
+		if err := rs.addRuleToBuckets(rule); err != nil {
+			return nil, err
+		}
+	}
+
+	seq.rule = steps[len(steps)-1]
+	return seq.rule, nil
+}
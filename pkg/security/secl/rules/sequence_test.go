@@ -0,0 +1,249 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+type sequenceHandler struct {
+	matches []RuleID
+}
+
+func (h *sequenceHandler) RuleMatch(rule *Rule, event eval.Event) {
+	h.matches = append(h.matches, rule.ID)
+}
+
+func (h *sequenceHandler) EventDiscarderFound(rs *RuleSet, event eval.Event, field string, eventType eval.EventType) {
+}
+
+func newSequenceRuleSet(t *testing.T, sequence *SequenceDefinition) (*RuleSet, *sequenceHandler, *time.Time) {
+	var opts Opts
+	opts.
+		WithConstants(testConstants).
+		WithEventTypeEnabled(map[eval.EventType]bool{"*": true}).
+		WithSequenceScopes(map[Scope]eval.Field{"process": "process.name"})
+
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, &opts)
+
+	now := time.Now()
+	rs.now = func() time.Time { return now }
+
+	handler := &sequenceHandler{}
+	rs.AddListener(handler)
+
+	if err := rs.AddRules([]*RuleDefinition{{ID: "test_sequence", Sequence: sequence}}); err.ErrorOrNil() != nil {
+		t.Fatal(err)
+	}
+
+	return rs, handler, &now
+}
+
+func newSequenceEvent(kind string, filename string, process string) *testEvent {
+	event := &testEvent{
+		kind: kind,
+		process: testProcess{
+			name: process,
+		},
+	}
+
+	switch kind {
+	case "open":
+		event.open.filename = filename
+	case "mkdir":
+		event.mkdir.filename = filename
+	}
+
+	return event
+}
+
+func testSequenceDefinition() *SequenceDefinition {
+	return &SequenceDefinition{
+		Scope:   "process",
+		Timeout: time.Minute,
+		Steps: []*SequenceStepDefinition{
+			{Expression: `open.filename =~ "/tmp/*"`, Correlate: "open.filename"},
+			{Expression: `mkdir.filename =~ "/tmp/*"`, Correlate: "mkdir.filename"},
+		},
+	}
+}
+
+func TestSequenceRule(t *testing.T) {
+	t.Run("match", func(t *testing.T) {
+		rs, handler, _ := newSequenceRuleSet(t, testSequenceDefinition())
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		assert.Empty(t, handler.matches)
+
+		assert.True(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test", "a")))
+		assert.Equal(t, []RuleID{"test_sequence"}, handler.matches)
+
+		// the sequence is complete, it has to start over
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test", "a")))
+	})
+
+	t.Run("out-of-order", func(t *testing.T) {
+		rs, handler, _ := newSequenceRuleSet(t, testSequenceDefinition())
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test", "a")))
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		assert.Empty(t, handler.matches)
+	})
+
+	t.Run("correlation", func(t *testing.T) {
+		rs, handler, _ := newSequenceRuleSet(t, testSequenceDefinition())
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/other", "a")))
+		assert.Empty(t, handler.matches)
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		rs, handler, _ := newSequenceRuleSet(t, testSequenceDefinition())
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test", "b")))
+		assert.Empty(t, handler.matches)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		rs, handler, now := newSequenceRuleSet(t, testSequenceDefinition())
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		*now = now.Add(2 * time.Minute)
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test", "a")))
+		assert.Empty(t, handler.matches)
+	})
+
+	t.Run("max-states", func(t *testing.T) {
+		definition := testSequenceDefinition()
+		definition.MaxStates = 1
+
+		rs, handler, _ := newSequenceRuleSet(t, definition)
+
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test1", "a")))
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test2", "a")))
+
+		// the oldest sequence was evicted
+		assert.False(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test1", "a")))
+		assert.True(t, rs.Evaluate(newSequenceEvent("mkdir", "/tmp/test2", "a")))
+		assert.Equal(t, []RuleID{"test_sequence"}, handler.matches)
+	})
+
+	t.Run("same-event-type", func(t *testing.T) {
+		rs, handler, _ := newSequenceRuleSet(t, &SequenceDefinition{
+			Steps: []*SequenceStepDefinition{
+				{Expression: `open.filename =~ "/tmp/*"`},
+				{Expression: `open.filename == "/tmp/test"`},
+			},
+		})
+
+		// a single event can't match two steps
+		assert.False(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "a")))
+		assert.True(t, rs.Evaluate(newSequenceEvent("open", "/tmp/test", "b")))
+		assert.Equal(t, []RuleID{"test_sequence"}, handler.matches)
+	})
+}
+
+func TestSequenceRuleInvalid(t *testing.T) {
+	var opts Opts
+	opts.
+		WithConstants(testConstants).
+		WithEventTypeEnabled(map[eval.EventType]bool{"*": true}).
+		WithSequenceScopes(map[Scope]eval.Field{"process": "process.name"})
+
+	tests := []struct {
+		name     string
+		sequence *SequenceDefinition
+	}{
+		{
+			name: "single-step",
+			sequence: &SequenceDefinition{
+				Steps: []*SequenceStepDefinition{{Expression: `open.filename == "/tmp/test"`}},
+			},
+		},
+		{
+			name: "unknown-scope",
+			sequence: &SequenceDefinition{
+				Scope: "container",
+				Steps: []*SequenceStepDefinition{
+					{Expression: `open.filename == "/tmp/test"`},
+					{Expression: `mkdir.filename == "/tmp/test"`},
+				},
+			},
+		},
+		{
+			name: "partial-correlation",
+			sequence: &SequenceDefinition{
+				Steps: []*SequenceStepDefinition{
+					{Expression: `open.filename == "/tmp/test"`, Correlate: "open.filename"},
+					{Expression: `mkdir.filename == "/tmp/test"`},
+				},
+			},
+		},
+		{
+			name: "correlation-event-type",
+			sequence: &SequenceDefinition{
+				Steps: []*SequenceStepDefinition{
+					{Expression: `open.filename == "/tmp/test"`, Correlate: "open.filename"},
+					{Expression: `mkdir.filename == "/tmp/test"`, Correlate: "open.filename"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, &opts)
+			err := rs.AddRules([]*RuleDefinition{{ID: "test_sequence", Sequence: test.sequence}})
+			assert.Error(t, err.ErrorOrNil())
+		})
+	}
+}
+
+func TestSequenceRulePolicy(t *testing.T) {
+	policy, err := LoadPolicy("test.policy", "test", strings.NewReader(`---
+rules:
+  - id: test_sequence
+    sequence:
+      scope: process
+      timeout: 30s
+      max_states: 10
+      steps:
+        - expression: open.filename =~ "/tmp/*"
+          correlate: open.filename
+        - expression: mkdir.filename =~ "/tmp/*"
+          correlate: mkdir.filename
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sequence := policy.Rules[0].Sequence
+	if assert.NotNil(t, sequence) {
+		assert.Equal(t, Scope("process"), sequence.Scope)
+		assert.Equal(t, 30*time.Second, sequence.GetTimeout())
+		assert.Equal(t, 10, sequence.GetMaxStates())
+		assert.Len(t, sequence.Steps, 2)
+	}
+
+	_, err = LoadPolicy("test.policy", "test", strings.NewReader(`---
+rules:
+  - id: test_sequence
+    expression: open.filename == "/tmp/test"
+    sequence:
+      steps:
+        - expression: open.filename =~ "/tmp/*"
+        - expression: mkdir.filename =~ "/tmp/*"
+`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build functionaltests
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func TestConnectEvent(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_connect_af_inet",
+			Expression: `connect.addr.family == AF_INET && connect.addr.ip in 127.0.0.0/8 && connect.addr.port == 4243 && process.file.name == "syscall_tester"`,
+		},
+		{
+			ID:         "test_connect_af_inet6",
+			Expression: `connect.addr.family == AF_INET6 && connect.addr.ip == ::1 && connect.protocol == IP_PROTO_TCP && process.file.name == "syscall_tester"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	syscallTester, err := loadSyscallTester(t, test, "syscall_tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Run(t, "connect-af-inet", func(t *testing.T, kind wrapperType, cmdFunc func(cmd string, args []string, envs []string) *exec.Cmd) {
+		test.WaitSignal(t, func() error {
+			return runConnectAccept(syscallTester, cmdFunc, "AF_INET")
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "connect", event.GetType(), "wrong event type")
+			assert.Equal(t, uint16(unix.AF_INET), event.Connect.AddrFamily, "wrong address family")
+			assert.Equal(t, uint16(4243), event.Connect.Addr.Port, "wrong address port")
+			assert.Equal(t, "127.0.0.1/32", event.Connect.Addr.IPNet.String(), "wrong address")
+			assert.Equal(t, uint16(unix.IPPROTO_TCP), event.Connect.Protocol, "wrong protocol")
+			assert.Equal(t, int64(0), event.Connect.Retval, "wrong retval")
+
+			if !validateConnectSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+
+	test.Run(t, "connect-af-inet6", func(t *testing.T, kind wrapperType, cmdFunc func(cmd string, args []string, envs []string) *exec.Cmd) {
+		test.WaitSignal(t, func() error {
+			return runConnectAccept(syscallTester, cmdFunc, "AF_INET6")
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "connect", event.GetType(), "wrong event type")
+			assert.Equal(t, uint16(unix.AF_INET6), event.Connect.AddrFamily, "wrong address family")
+			assert.Equal(t, uint16(4243), event.Connect.Addr.Port, "wrong address port")
+			assert.Equal(t, "::1/128", event.Connect.Addr.IPNet.String(), "wrong address")
+			assert.Equal(t, int64(0), event.Connect.Retval, "wrong retval")
+
+			if !validateConnectSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+}
+
+func TestAcceptEvent(t *testing.T) {
+	ruleDefs := []*rules.RuleDefinition{
+		{
+			ID:         "test_accept_af_inet",
+			Expression: `accept.addr.family == AF_INET && accept.addr.ip in 127.0.0.0/8 && process.file.name == "syscall_tester"`,
+		},
+	}
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	syscallTester, err := loadSyscallTester(t, test, "syscall_tester")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Run(t, "accept-af-inet", func(t *testing.T, kind wrapperType, cmdFunc func(cmd string, args []string, envs []string) *exec.Cmd) {
+		test.WaitSignal(t, func() error {
+			return runConnectAccept(syscallTester, cmdFunc, "AF_INET")
+		}, func(event *sprobe.Event, r *rules.Rule) {
+			assert.Equal(t, "accept", event.GetType(), "wrong event type")
+			assert.Equal(t, uint16(unix.AF_INET), event.Accept.AddrFamily, "wrong address family")
+			assert.Equal(t, "127.0.0.1/32", event.Accept.Addr.IPNet.String(), "wrong address")
+			assert.Equal(t, uint16(unix.IPPROTO_TCP), event.Accept.Protocol, "wrong protocol")
+			assert.Greater(t, event.Accept.Retval, int64(0), "wrong retval")
+
+			if !validateAcceptSchema(t, event) {
+				t.Error(event.String())
+			}
+		})
+	})
+}
+
+func runConnectAccept(syscallTester string, cmdFunc func(cmd string, args []string, envs []string) *exec.Cmd, addrFamily string) error {
+	cmd := cmdFunc(syscallTester, []string{"connect-accept", addrFamily}, []string{})
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w", out, err)
+	}
+	return nil
+}
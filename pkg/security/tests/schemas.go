@@ -169,3 +169,13 @@ func validateDNSSchema(t *testing.T, event *sprobe.Event) bool {
 func validateBindSchema(t *testing.T, event *sprobe.Event) bool {
 	return validateSchema(t, event, "file:///schemas/bind.schema.json")
 }
+
+//nolint:deadcode,unused
+func validateConnectSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/connect.schema.json")
+}
+
+//nolint:deadcode,unused
+func validateAcceptSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/accept.schema.json")
+}
@@ -407,6 +407,77 @@ int test_bind(int argc, char** argv) {
     return EXIT_FAILURE;
 }
 
+int test_connect_accept(int argc, char** argv) {
+    if (argc != 2) {
+        fprintf(stderr, "Please speficy an addr_type: AF_INET or AF_INET6\n");
+        return EXIT_FAILURE;
+    }
+
+    struct sockaddr_storage addr;
+    socklen_t addr_len;
+    memset(&addr, 0, sizeof(addr));
+
+    char* addr_family = argv[1];
+    if (!strcmp(addr_family, "AF_INET")) {
+        struct sockaddr_in *addr_in = (struct sockaddr_in *)&addr;
+        addr_in->sin_family = AF_INET;
+        addr_in->sin_addr.s_addr = htonl(INADDR_LOOPBACK);
+        addr_in->sin_port = htons(4243);
+        addr_len = sizeof(struct sockaddr_in);
+    } else if (!strcmp(addr_family, "AF_INET6")) {
+        struct sockaddr_in6 *addr_in6 = (struct sockaddr_in6 *)&addr;
+        addr_in6->sin6_family = AF_INET6;
+        addr_in6->sin6_addr = in6addr_loopback;
+        addr_in6->sin6_port = htons(4243);
+        addr_len = sizeof(struct sockaddr_in6);
+    } else {
+        fprintf(stderr, "Specified %s addr_type is not a valid one, try: AF_INET or AF_INET6\n", addr_family);
+        return EXIT_FAILURE;
+    }
+
+    int server = socket(addr.ss_family, SOCK_STREAM, 0);
+    if (server < 0) {
+        perror("socket");
+        return EXIT_FAILURE;
+    }
+
+    int enable = 1;
+    setsockopt(server, SOL_SOCKET, SO_REUSEADDR, &enable, sizeof(enable));
+
+    if (bind(server, (struct sockaddr*)&addr, addr_len) < 0 || listen(server, 1) < 0) {
+        perror("bind/listen");
+        close(server);
+        return EXIT_FAILURE;
+    }
+
+    int client = socket(addr.ss_family, SOCK_STREAM, 0);
+    if (client < 0) {
+        perror("socket");
+        close(server);
+        return EXIT_FAILURE;
+    }
+
+    if (connect(client, (struct sockaddr*)&addr, addr_len) < 0) {
+        perror("connect");
+        close(client);
+        close(server);
+        return EXIT_FAILURE;
+    }
+
+    int conn = accept(server, NULL, NULL);
+    if (conn < 0) {
+        perror("accept");
+        close(client);
+        close(server);
+        return EXIT_FAILURE;
+    }
+
+    close(conn);
+    close(client);
+    close(server);
+    return EXIT_SUCCESS;
+}
+
 int test_forkexec(int argc, char **argv) {
     if (argc == 3) {
         char *subcmd = argv[1];
@@ -477,6 +548,8 @@ int main(int argc, char **argv) {
         return self_exec(argc - 1, argv + 1);
     } else if (strcmp(cmd, "bind") == 0) {
         return test_bind(argc - 1, argv + 1);
+    } else if (strcmp(cmd, "connect-accept") == 0) {
+        return test_connect_accept(argc - 1, argv + 1);
     } else if (strcmp(cmd, "fork") == 0) {
         return test_forkexec(argc - 1, argv + 1);
     } else {
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package devicemetadata shares the metadata of the network devices monitored by the SNMP check
+// with the other network devices components, such as NetFlow.
+package devicemetadata
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the duration after which the metadata of a device that isn't refreshed by the SNMP check is dropped
+const DefaultTTL = 1 * time.Hour
+
+// Device contains the metadata of a network device
+type Device struct {
+	ID   string
+	Name string
+	// Tags are the tags of the device metrics
+	Tags []string
+}
+
+// Interface contains the metadata of a network interface
+type Interface struct {
+	Index int32
+	Name  string
+	Alias string
+	// Speed is the speed of the interface in Mb/s (IF-MIB ifHighSpeed)
+	Speed uint64
+}
+
+type deviceKey struct {
+	namespace string
+	ipAddress string
+}
+
+type deviceEntry struct {
+	device     Device
+	interfaces map[int32]Interface
+	updatedAt  time.Time
+}
+
+// Cache stores the metadata of network devices indexed by namespace and IP address
+type Cache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	devices map[deviceKey]*deviceEntry
+	now     func() time.Time
+}
+
+// NewCache returns a new Cache
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		devices: make(map[deviceKey]*deviceEntry),
+		now:     time.Now,
+	}
+}
+
+// SetDevice replaces the metadata of a device and of its interfaces
+func (c *Cache) SetDevice(namespace string, ipAddress string, device Device, interfaces []Interface) {
+	entry := &deviceEntry{
+		device:     device,
+		interfaces: make(map[int32]Interface, len(interfaces)),
+		updatedAt:  c.now(),
+	}
+	for _, networkInterface := range interfaces {
+		entry.interfaces[networkInterface.Index] = networkInterface
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.devices[deviceKey{namespace: namespace, ipAddress: ipAddress}] = entry
+	c.expire()
+}
+
+// expire removes the devices that weren't updated during the TTL, the lock must be held
+func (c *Cache) expire() {
+	deadline := c.now().Add(-c.ttl)
+	for key, entry := range c.devices {
+		if entry.updatedAt.Before(deadline) {
+			delete(c.devices, key)
+		}
+	}
+}
+
+func (c *Cache) getEntry(namespace string, ipAddress string) *deviceEntry {
+	entry, ok := c.devices[deviceKey{namespace: namespace, ipAddress: ipAddress}]
+	if !ok || entry.updatedAt.Before(c.now().Add(-c.ttl)) {
+		return nil
+	}
+	return entry
+}
+
+// GetDevice returns the metadata of a device
+func (c *Cache) GetDevice(namespace string, ipAddress string) (Device, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry := c.getEntry(namespace, ipAddress)
+	if entry == nil {
+		return Device{}, false
+	}
+	return entry.device, true
+}
+
+// GetInterface returns the metadata of an interface of a device
+func (c *Cache) GetInterface(namespace string, ipAddress string, index int32) (Interface, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry := c.getEntry(namespace, ipAddress)
+	if entry == nil {
+		return Interface{}, false
+	}
+	networkInterface, ok := entry.interfaces[index]
+	return networkInterface, ok
+}
+
+var defaultCache = NewCache(DefaultTTL)
+
+// GetDefaultCache returns the cache shared by the components of the agent
+func GetDefaultCache() *Cache {
+	return defaultCache
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package devicemetadata
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	cache := NewCache(time.Hour)
+	cache.now = func() time.Time { return now }
+
+	cache.SetDevice("default", "1.2.3.4", Device{ID: "default:1.2.3.4", Name: "router"}, []Interface{
+		{Index: 1, Name: "eth0", Alias: "uplink", Speed: 1000},
+		{Index: 2, Name: "eth1"},
+	})
+
+	device, ok := cache.GetDevice("default", "1.2.3.4")
+	assert.True(t, ok)
+	assert.Equal(t, "router", device.Name)
+
+	networkInterface, ok := cache.GetInterface("default", "1.2.3.4", 1)
+	assert.True(t, ok)
+	assert.Equal(t, Interface{Index: 1, Name: "eth0", Alias: "uplink", Speed: 1000}, networkInterface)
+
+	_, ok = cache.GetInterface("default", "1.2.3.4", 3)
+	assert.False(t, ok)
+	_, ok = cache.GetDevice("other-ns", "1.2.3.4")
+	assert.False(t, ok)
+
+	// the interfaces are replaced on update
+	cache.SetDevice("default", "1.2.3.4", Device{Name: "router"}, []Interface{{Index: 2, Name: "eth1"}})
+	_, ok = cache.GetInterface("default", "1.2.3.4", 1)
+	assert.False(t, ok)
+
+	// the devices that aren't refreshed expire
+	now = now.Add(2 * time.Hour)
+	_, ok = cache.GetDevice("default", "1.2.3.4")
+	assert.False(t, ok)
+
+	cache.SetDevice("default", "5.6.7.8", Device{Name: "switch"}, nil)
+	assert.Len(t, cache.devices, 1)
+}
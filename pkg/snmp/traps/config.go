@@ -6,9 +6,11 @@
 package traps
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/common"
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -22,26 +24,29 @@ func IsEnabled() bool {
 }
 
 // UserV3 contains the definition of one SNMPv3 user with its username and its auth
-// parameters.
+// parameters. Several users can share a username with different engine IDs, the engine ID
+// is the hexadecimal authoritative engine ID of the devices sending traps for this user.
 type UserV3 struct {
 	Username     string `mapstructure:"user" yaml:"user"`
 	AuthKey      string `mapstructure:"authKey" yaml:"authKey"`
 	AuthProtocol string `mapstructure:"authProtocol" yaml:"authProtocol"`
 	PrivKey      string `mapstructure:"privKey" yaml:"privKey"`
 	PrivProtocol string `mapstructure:"privProtocol" yaml:"privProtocol"`
+	EngineID     string `mapstructure:"engineID" yaml:"engineID"`
 }
 
 // Config contains configuration for SNMP trap listeners.
 // YAML field tags provided for test marshalling purposes.
 type Config struct {
-	Enabled               bool     `mapstructure:"enabled" yaml:"enabled"`
-	Port                  uint16   `mapstructure:"port" yaml:"port"`
-	Users                 []UserV3 `mapstructure:"users" yaml:"users"`
-	CommunityStrings      []string `mapstructure:"community_strings" yaml:"community_strings"`
-	BindHost              string   `mapstructure:"bind_host" yaml:"bind_host"`
-	StopTimeout           int      `mapstructure:"stop_timeout" yaml:"stop_timeout"`
-	Namespace             string   `mapstructure:"namespace" yaml:"namespace"`
-	authoritativeEngineID string   `mapstructure:"-" yaml:"-"`
+	Enabled               bool       `mapstructure:"enabled" yaml:"enabled"`
+	Port                  uint16     `mapstructure:"port" yaml:"port"`
+	Users                 []UserV3   `mapstructure:"users" yaml:"users"`
+	CommunityStrings      []string   `mapstructure:"community_strings" yaml:"community_strings"`
+	BindHost              string     `mapstructure:"bind_host" yaml:"bind_host"`
+	StopTimeout           int        `mapstructure:"stop_timeout" yaml:"stop_timeout"`
+	Namespace             string     `mapstructure:"namespace" yaml:"namespace"`
+	Rules                 []TrapRule `mapstructure:"rules" yaml:"rules"`
+	authoritativeEngineID string     `mapstructure:"-" yaml:"-"`
 }
 
 // ReadConfig builds and returns configuration from Agent configuration.
@@ -56,9 +61,16 @@ func ReadConfig(agentHostname string) (*Config, error) {
 		return nil, errors.New("traps listener is disabled")
 	}
 
-	// gosnmp only supports one v3 user at the moment.
-	if len(c.Users) > 1 {
-		return nil, errors.New("only one user is currently supported in SNMP Traps Listener configuration")
+	for i := range c.Users {
+		user := &c.Users[i]
+		if user.EngineID == "" {
+			continue
+		}
+		engineID, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(user.EngineID), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid engine ID `%s` for user `%s`: %w", user.EngineID, user.Username, err)
+		}
+		user.EngineID = string(engineID)
 	}
 
 	// Set defaults.
@@ -94,6 +106,10 @@ func ReadConfig(agentHostname string) (*Config, error) {
 		return nil, fmt.Errorf("unable to load config: %w", err)
 	}
 
+	if err := validateRules(c.Rules); err != nil {
+		return nil, fmt.Errorf("invalid trap rules: %w", err)
+	}
+
 	return &c, nil
 }
 
@@ -102,17 +118,18 @@ func (c *Config) Addr() string {
 	return fmt.Sprintf("%s:%d", c.BindHost, c.Port)
 }
 
-// BuildSNMPParams returns a valid GoSNMP params structure from configuration.
+// BuildSNMPParams returns a valid GoSNMP params structure for the SNMPv1 and SNMPv2c traps.
 func (c *Config) BuildSNMPParams() (*gosnmp.GoSNMP, error) {
-	if len(c.Users) == 0 {
-		return &gosnmp.GoSNMP{
-			Port:      c.Port,
-			Transport: "udp",
-			Version:   gosnmp.Version2c, // No user configured, let's use Version2 which is enough and doesn't require setting up fake security data.
-			Logger:    gosnmp.NewLogger(&trapLogger{}),
-		}, nil
-	}
-	user := c.Users[0]
+	return &gosnmp.GoSNMP{
+		Port:      c.Port,
+		Transport: "udp",
+		Version:   gosnmp.Version2c, // Version2 also decodes Version1 packets and doesn't require setting up fake security data.
+		Logger:    gosnmp.NewLogger(&trapLogger{}),
+	}, nil
+}
+
+// BuildV3Params returns a valid GoSNMP params structure for the SNMPv3 traps of a user.
+func (c *Config) BuildV3Params(user UserV3) (*gosnmp.GoSNMP, error) {
 	authProtocol, err := gosnmplib.GetAuthProtocol(user.AuthProtocol)
 	if err != nil {
 		return nil, err
@@ -133,7 +150,7 @@ func (c *Config) BuildSNMPParams() (*gosnmp.GoSNMP, error) {
 	return &gosnmp.GoSNMP{
 		Port:          c.Port,
 		Transport:     "udp",
-		Version:       gosnmp.Version3, // Always using version3 for traps, only option that works with all SNMP versions simultaneously
+		Version:       gosnmp.Version3,
 		SecurityModel: gosnmp.UserSecurityModel,
 		MsgFlags:      msgFlags,
 		SecurityParameters: &gosnmp.UsmSecurityParameters{
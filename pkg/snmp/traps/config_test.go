@@ -56,7 +56,7 @@ func TestFullConfig(t *testing.T) {
 		},
 	}, config.Users)
 
-	params, err := config.BuildSNMPParams()
+	params, err := config.BuildV3Params(config.Users[0])
 	assert.NoError(t, err)
 	assert.Equal(t, uint16(1234), params.Port)
 	assert.Equal(t, gosnmp.Version3, params.Version)
@@ -150,3 +150,43 @@ func TestNamespaceSetBothGloballyAndLocally(t *testing.T) {
 
 	assert.Equal(t, "bar", config.Namespace)
 }
+
+func TestConfigRules(t *testing.T) {
+	Configure(t, Config{
+		Rules: []TrapRule{
+			{TrapOID: ".1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"ifAdminStatus": "up"}, Status: "error", Title: "Interface down"},
+		},
+	})
+	config, err := ReadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, []TrapRule{
+		{TrapOID: "1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"ifAdminStatus": "up"}, Status: "error", Title: "Interface down"},
+	}, config.Rules)
+
+	Configure(t, Config{
+		Rules: []TrapRule{{TrapOID: "1.3.6.1.6.3.1.1.5.3", Status: "unknown"}},
+	})
+	_, err = ReadConfig("")
+	assert.EqualError(t, err, "invalid trap rules: rule 0: invalid status `unknown`")
+}
+
+func TestConfigMultipleUsers(t *testing.T) {
+	Configure(t, Config{
+		Users: []UserV3{
+			{Username: "user", AuthKey: "password", AuthProtocol: "SHA", EngineID: "0x8000000001020304"},
+			{Username: "user", AuthKey: "other-password", AuthProtocol: "MD5"},
+		},
+	})
+	config, err := ReadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, []UserV3{
+		{Username: "user", AuthKey: "password", AuthProtocol: "SHA", EngineID: "\x80\x00\x00\x00\x01\x02\x03\x04"},
+		{Username: "user", AuthKey: "other-password", AuthProtocol: "MD5"},
+	}, config.Users)
+
+	Configure(t, Config{
+		Users: []UserV3{{Username: "user", EngineID: "not-hex"}},
+	})
+	_, err = ReadConfig("")
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/gosnmp/gosnmp"
 )
@@ -23,8 +24,10 @@ type Formatter interface {
 
 // JSONFormatter is a Formatter implementation that transforms Traps into JSON
 type JSONFormatter struct {
-	oidResolver OIDResolver
-	namespace   string
+	oidResolver    OIDResolver
+	namespace      string
+	rules          []TrapRule
+	deviceMetadata *devicemetadata.Cache
 }
 
 type trapVariable struct {
@@ -39,11 +42,18 @@ const (
 )
 
 // NewJSONFormatter creates a new JSONFormatter instance with an optional OIDResolver variable.
-func NewJSONFormatter(oidResolver OIDResolver, namespace string) (JSONFormatter, error) {
+// The traps are mapped to a status and a title by the rules, and are enriched with the tags of the devices
+// monitored by the SNMP check when deviceMetadata is set.
+func NewJSONFormatter(oidResolver OIDResolver, namespace string, rules []TrapRule, deviceMetadata *devicemetadata.Cache) (JSONFormatter, error) {
 	if oidResolver == nil {
 		return JSONFormatter{}, fmt.Errorf("NewJSONFormatter called with a nil OIDResolver")
 	}
-	return JSONFormatter{oidResolver, namespace}, nil
+	return JSONFormatter{
+		oidResolver:    oidResolver,
+		namespace:      namespace,
+		rules:          rules,
+		deviceMetadata: deviceMetadata,
+	}, nil
 }
 
 // FormatPacket converts a raw SNMP trap packet to a FormattedSnmpPacket containing the JSON data and the tags to attach
@@ -58,6 +68,8 @@ func NewJSONFormatter(oidResolver OIDResolver, namespace string) (JSONFormatter,
 //    "uptime": "12345",
 //    "genericTrap": "5", # v1 only
 //    "specificTrap": "0",  # v1 only
+//    "status": "error", # set by the matching rule
+//    "title": "...", # set by the matching rule
 //    "variables": [
 //      {
 //        "oid": "1.3.4.1....",
@@ -80,6 +92,7 @@ func (f JSONFormatter) FormatPacket(packet *SnmpPacket) ([]byte, error) {
 			return nil, err
 		}
 	}
+	applyRules(f.rules, formattedTrap)
 	formattedTrap["ddsource"] = ddsource
 	formattedTrap["ddtags"] = strings.Join(f.getTags(packet), ",")
 	formattedTrap["timestamp"] = packet.Timestamp
@@ -88,12 +101,32 @@ func (f JSONFormatter) FormatPacket(packet *SnmpPacket) ([]byte, error) {
 }
 
 // GetTags returns a list of tags associated to an SNMP trap packet.
+// The tags of the device collected by the SNMP check are added so that traps can be correlated with the device metrics.
 func (f JSONFormatter) getTags(packet *SnmpPacket) []string {
-	return []string{
+	tags := []string{
 		"snmp_version:" + formatVersion(packet.Content),
 		"device_namespace:" + f.namespace,
 		"snmp_device:" + packet.Addr.IP.String(),
 	}
+	if f.deviceMetadata == nil {
+		return tags
+	}
+	device, ok := f.deviceMetadata.GetDevice(f.namespace, packet.Addr.IP.String())
+	if !ok {
+		return tags
+	}
+
+	existingTags := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		existingTags[tag] = true
+	}
+	for _, tag := range device.Tags {
+		if !existingTags[tag] {
+			existingTags[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 func (f JSONFormatter) formatV1Trap(packet *gosnmp.SnmpPacket) map[string]interface{} {
@@ -0,0 +1,111 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traps
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	berInteger     = 0x02
+	berOctetString = 0x04
+	berSequence    = 0x30
+)
+
+// messageHeader contains the fields of an SNMP message needed to select the credentials used to decode it
+type messageHeader struct {
+	version gosnmp.SnmpVersion
+	// engineID and userName are only set for SNMPv3 messages using the User-based Security Model
+	engineID string
+	userName string
+}
+
+// parseMessageHeader parses the header of an SNMP message without decoding its PDU:
+// SNMPv1/v2c: SEQUENCE { version INTEGER, community OCTET STRING, pdu }
+// SNMPv3: SEQUENCE { version INTEGER, msgGlobalData SEQUENCE, msgSecurityParameters OCTET STRING, msgData }
+// where msgSecurityParameters contains SEQUENCE { msgAuthoritativeEngineID OCTET STRING,
+// msgAuthoritativeEngineBoots INTEGER, msgAuthoritativeEngineTime INTEGER, msgUserName OCTET STRING, ... }
+// See: https://tools.ietf.org/html/rfc3412#section-6 and https://tools.ietf.org/html/rfc3414#section-2.4
+func parseMessageHeader(packet []byte) (messageHeader, error) {
+	var header messageHeader
+
+	message, _, err := readBERField(packet, berSequence)
+	if err != nil {
+		return header, fmt.Errorf("invalid message: %w", err)
+	}
+	version, message, err := readBERField(message, berInteger)
+	if err != nil {
+		return header, fmt.Errorf("invalid message version: %w", err)
+	}
+	if len(version) != 1 {
+		return header, fmt.Errorf("invalid message version length: %d", len(version))
+	}
+	header.version = gosnmp.SnmpVersion(version[0])
+	if header.version != gosnmp.Version3 {
+		return header, nil
+	}
+
+	_, message, err = readBERField(message, berSequence)
+	if err != nil {
+		return header, fmt.Errorf("invalid message global data: %w", err)
+	}
+	securityParameters, _, err := readBERField(message, berOctetString)
+	if err != nil {
+		return header, fmt.Errorf("invalid message security parameters: %w", err)
+	}
+	usm, _, err := readBERField(securityParameters, berSequence)
+	if err != nil {
+		return header, fmt.Errorf("invalid user security model parameters: %w", err)
+	}
+	engineID, usm, err := readBERField(usm, berOctetString)
+	if err != nil {
+		return header, fmt.Errorf("invalid authoritative engine ID: %w", err)
+	}
+	for _, field := range []string{"authoritative engine boots", "authoritative engine time"} {
+		if _, usm, err = readBERField(usm, berInteger); err != nil {
+			return header, fmt.Errorf("invalid %s: %w", field, err)
+		}
+	}
+	userName, _, err := readBERField(usm, berOctetString)
+	if err != nil {
+		return header, fmt.Errorf("invalid user name: %w", err)
+	}
+	header.engineID = string(engineID)
+	header.userName = string(userName)
+	return header, nil
+}
+
+// readBERField reads a BER encoded field of the expected type, it returns the value of the field and the remaining data
+func readBERField(data []byte, expectedType byte) ([]byte, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errors.New("truncated field")
+	}
+	if data[0] != expectedType {
+		return nil, nil, fmt.Errorf("unexpected field type 0x%x, expected 0x%x", data[0], expectedType)
+	}
+
+	length := int(data[1])
+	cursor := 2
+	if length&0x80 != 0 {
+		// long form: the low bits are the number of bytes of the length
+		lengthSize := length & 0x7f
+		if lengthSize == 0 || lengthSize > 4 || len(data) < cursor+lengthSize {
+			return nil, nil, errors.New("invalid field length")
+		}
+		length = 0
+		for _, b := range data[cursor : cursor+lengthSize] {
+			length = length<<8 | int(b)
+		}
+		cursor += lengthSize
+	}
+	if length < 0 || len(data) < cursor+length {
+		return nil, nil, errors.New("truncated field")
+	}
+	return data[cursor : cursor+length], data[cursor+length:], nil
+}
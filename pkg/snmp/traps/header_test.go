@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traps
+
+import (
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMessageHeader(t *testing.T) {
+	v2Packet := []byte{
+		0x30, 0x0b, // message
+		0x02, 0x01, 0x01, // version
+		0x04, 0x06, 'p', 'u', 'b', 'l', 'i', 'c', // community
+	}
+	header, err := parseMessageHeader(v2Packet)
+	require.NoError(t, err)
+	assert.Equal(t, messageHeader{version: gosnmp.Version2c}, header)
+
+	v3Packet := []byte{
+		0x30, 0x81, 0x2b, // message, with a long form length
+		0x02, 0x01, 0x03, // version
+		0x30, 0x0d, 0x02, 0x01, 0x01, 0x02, 0x02, 0x05, 0xdc, 0x04, 0x01, 0x07, 0x02, 0x01, 0x03, // global data
+		0x04, 0x17, 0x30, 0x15, // security parameters
+		0x04, 0x05, 0x80, 0x00, 0x00, 0x00, 0x01, // engine ID
+		0x02, 0x01, 0x01, // engine boots
+		0x02, 0x01, 0x02, // engine time
+		0x04, 0x04, 'u', 's', 'e', 'r', // user name
+		0x04, 0x00, // authentication parameters
+	}
+	header, err = parseMessageHeader(v3Packet)
+	require.NoError(t, err)
+	assert.Equal(t, messageHeader{version: gosnmp.Version3, engineID: "\x80\x00\x00\x00\x01", userName: "user"}, header)
+
+	_, err = parseMessageHeader(v3Packet[:20])
+	assert.EqualError(t, err, "invalid message: truncated field")
+	_, err = parseMessageHeader([]byte{0x02, 0x01, 0x01})
+	assert.EqualError(t, err, "invalid message: unexpected field type 0x2, expected 0x30")
+}
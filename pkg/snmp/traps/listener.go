@@ -3,82 +3,274 @@ package traps
 import (
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/trace/log"
 	"github.com/gosnmp/gosnmp"
 )
 
+const (
+	// maxPacketSize is the maximum size of the UDP packets read by the listener
+	maxPacketSize = 65535
+
+	// RFC3411 section 5: a SnmpEngineID is an OCTET STRING of 5 to 32 bytes
+	minEngineIDLength = 5
+	maxEngineIDLength = 32
+
+	usmStatsUnknownEngineIDsOID = "1.3.6.1.6.3.15.1.1.4.0"
+
+	// unknownUserAuthErrors is the key under which the authentication failures of the users missing
+	// from the configuration are counted, so that the usernames sent by any host don't grow the stats
+	unknownUserAuthErrors = "unknown_user"
+)
+
+// userV3Params are the GoSNMP params used to decode the traps of a SNMPv3 user
+type userV3Params struct {
+	user   UserV3
+	params *gosnmp.GoSNMP
+}
+
 // TrapListener opens an UDP socket and put all received traps in a channel
 type TrapListener struct {
-	config        Config
-	packets       PacketsChannel
-	listener      *gosnmp.TrapListener
-	errorsChannel chan error
-	errorLogger   *log.ThrottledLogger
+	config      Config
+	packets     PacketsChannel
+	conn        *net.UDPConn
+	params      *gosnmp.GoSNMP
+	usersParams map[string][]userV3Params
+	// discoveryParams are used to decode the SNMPv3 engine ID discovery requests, sent without user
+	discoveryParams *gosnmp.GoSNMP
+	errorLogger     *log.ThrottledLogger
+	stopOnce        sync.Once
+	stopChan        chan struct{}
+	done            chan struct{}
+	// unknownEngineIDs is the number of discovery requests referencing an unknown engine ID (usmStatsUnknownEngineIDs)
+	unknownEngineIDs uint32
 }
 
 // NewTrapListener creates a simple TrapListener instance but does not start it
 func NewTrapListener(config Config, packets PacketsChannel) (*TrapListener, error) {
-	var err error
-	gosnmpListener := gosnmp.NewTrapListener()
-	gosnmpListener.Params, err = config.BuildSNMPParams()
+	params, err := config.BuildSNMPParams()
 	if err != nil {
 		return nil, err
 	}
-	errorsChan := make(chan error, 1)
-	trapListener := &TrapListener{
-		config:        config,
-		packets:       packets,
-		listener:      gosnmpListener,
-		errorsChannel: errorsChan,
-		errorLogger:   log.NewThrottled(5, 10*time.Second),
+	discoveryParams, err := config.BuildV3Params(UserV3{})
+	if err != nil {
+		return nil, err
+	}
+
+	usersParams := make(map[string][]userV3Params, len(config.Users))
+	for _, user := range config.Users {
+		userParams, err := config.BuildV3Params(user)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SNMPv3 user `%s`: %w", user.Username, err)
+		}
+		usersParams[user.Username] = append(usersParams[user.Username], userV3Params{user: user, params: userParams})
 	}
 
-	gosnmpListener.OnNewTrap = trapListener.receiveTrap
-	return trapListener, nil
+	return &TrapListener{
+		config:          config,
+		packets:         packets,
+		params:          params,
+		usersParams:     usersParams,
+		discoveryParams: discoveryParams,
+		errorLogger:     log.NewThrottled(5, 10*time.Second),
+		stopChan:        make(chan struct{}),
+		done:            make(chan struct{}),
+	}, nil
 }
 
 // Start the TrapListener instance. Need to be manually Stopped
 func (t *TrapListener) Start() error {
 	log.Infof("Start listening for traps on %s", t.config.Addr())
+	udpAddr, err := net.ResolveUDPAddr("udp", t.config.Addr())
+	if err != nil {
+		return fmt.Errorf("error happened when listening for SNMP Traps: %s", err)
+	}
+	t.conn, err = net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return fmt.Errorf("error happened when listening for SNMP Traps: %s", err)
+	}
 	go t.run()
-	return t.blockUntilReady()
+	return nil
 }
 
 func (t *TrapListener) run() {
-	err := t.listener.Listen(t.config.Addr()) // blocking call
-	if err != nil {
-		t.errorsChannel <- err
-	}
+	defer close(t.done)
 
-}
-
-func (t *TrapListener) blockUntilReady() error {
-	select {
-	// Wait for listener to be started and listening to traps.
-	// See: https://godoc.org/github.com/gosnmp/gosnmp#TrapListener.Listening
-	case <-t.listener.Listening():
-		return nil
-	// If the listener failed to start (eg because it couldn't bind to a socket),
-	// we'll get an error here.
-	case err := <-t.errorsChannel:
-		return fmt.Errorf("error happened when listening for SNMP Traps: %s", err)
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, remote, err := t.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-t.stopChan:
+				// err most likely comes from reading from a closed connection
+				return
+			default:
+			}
+			log.Debugf("Error reading SNMP Traps packet: %s", err)
+			continue
+		}
+		// the packet is copied as it's modified when decoded
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+		t.receivePacket(packet, remote)
 	}
 }
 
 // Stop the current TrapListener instance
 func (t *TrapListener) Stop() {
-	t.listener.Close()
+	if t.conn == nil {
+		return
+	}
+	t.stopOnce.Do(func() {
+		close(t.stopChan)
+		t.conn.Close()
+	})
+	<-t.done
 }
 
-func (t *TrapListener) receiveTrap(p *gosnmp.SnmpPacket, u *net.UDPAddr) {
-	if err := validatePacket(p, t.config); err != nil {
-		t.errorLogger.Warn("Invalid credentials from %s on listener %s, dropping traps", u.String(), t.config.Addr())
-		trapsPacketsAuthErrors.Add(1)
+func (t *TrapListener) receivePacket(packet []byte, u *net.UDPAddr) {
+	header, err := parseMessageHeader(packet)
+	if err != nil {
+		t.errorLogger.Warn("Invalid packet from %s on listener %s, dropping it: %s", u.String(), t.config.Addr(), err)
+		trapsPacketsInvalid.Add(1)
 		return
 	}
+
+	var p *gosnmp.SnmpPacket
+	if header.version == gosnmp.Version3 {
+		if len(header.engineID) < minEngineIDLength || len(header.engineID) > maxEngineIDLength {
+			// RFC3414 3.2.3b: the sender is discovering the engine ID of the listener, required to send informs
+			t.reportAuthoritativeEngineID(packet, u)
+			return
+		}
+		p, err = t.decodeV3Packet(packet, header)
+		if err != nil {
+			t.errorLogger.Warn("Invalid credentials for user `%s` from %s on listener %s, dropping traps: %s", header.userName, u.String(), t.config.Addr(), err)
+			trapsPacketsAuthErrors.Add(1)
+			trapsUserAuthErrors.Add(t.authErrorsKey(header.userName), 1)
+			return
+		}
+	} else {
+		p, err = t.params.UnmarshalTrap(packet, false)
+		if err != nil {
+			t.errorLogger.Warn("Invalid packet from %s on listener %s, dropping it: %s", u.String(), t.config.Addr(), err)
+			trapsPacketsInvalid.Add(1)
+			return
+		}
+		if err := validatePacket(p, t.config); err != nil {
+			t.errorLogger.Warn("Invalid credentials from %s on listener %s, dropping traps", u.String(), t.config.Addr())
+			trapsPacketsAuthErrors.Add(1)
+			return
+		}
+	}
+
 	log.Debugf("Packet received from %s on listener %s", u.String(), t.config.Addr())
 	trapsPackets.Add(1)
+	if p.PDUType == gosnmp.InformRequest {
+		t.acknowledgeInform(p, u)
+	}
 	t.packets <- &SnmpPacket{Content: p, Addr: u, Timestamp: time.Now().UnixMilli()}
 }
+
+// authErrorsKey returns the key under which the authentication failures of a user are counted
+func (t *TrapListener) authErrorsKey(userName string) string {
+	if _, ok := t.usersParams[userName]; ok {
+		return userName
+	}
+	return unknownUserAuthErrors
+}
+
+// decodeV3Packet decodes a SNMPv3 packet with the credentials of the users matching its username and engine ID
+func (t *TrapListener) decodeV3Packet(packet []byte, header messageHeader) (*gosnmp.SnmpPacket, error) {
+	candidates := t.usersParams[header.userName]
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("unknown user")
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		if candidate.user.EngineID != "" && candidate.user.EngineID != header.engineID {
+			continue
+		}
+		// each attempt works on a copy of the packet as it's modified when decoded
+		packetCopy := make([]byte, len(packet))
+		copy(packetCopy, packet)
+		p, err := candidate.params.UnmarshalTrap(packetCopy, false)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// the security level of the packet must match the one of the user, so that unauthenticated packets
+		// can't be sent on behalf of users with credentials
+		if p.MsgFlags&gosnmp.AuthPriv != candidate.params.MsgFlags {
+			lastErr = fmt.Errorf("unexpected security level")
+			continue
+		}
+		return p, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("unknown engine ID 0x%x", header.engineID)
+	}
+	return nil, lastErr
+}
+
+// acknowledgeInform sends the response to an INFORM request, it's the same packet with a response PDU type
+// See: https://tools.ietf.org/html/rfc3416#section-4.2.7
+func (t *TrapListener) acknowledgeInform(p *gosnmp.SnmpPacket, u *net.UDPAddr) {
+	response := *p
+	if p.SecurityParameters != nil {
+		response.SecurityParameters = p.SecurityParameters.Copy()
+	}
+	response.PDUType = gosnmp.GetResponse
+	response.MsgFlags &= gosnmp.AuthPriv
+	response.Error = gosnmp.NoError
+	response.ErrorIndex = 0
+	if err := t.send(&response, u); err != nil {
+		t.errorLogger.Warn("Unable to acknowledge inform from %s on listener %s: %s", u.String(), t.config.Addr(), err)
+		return
+	}
+	trapsInformsAcknowledged.Add(1)
+}
+
+// reportAuthoritativeEngineID answers to the engine ID discovery of the SNMPv3 senders with a report
+// containing the engine ID of the listener
+// See: https://tools.ietf.org/html/rfc3414#section-4
+func (t *TrapListener) reportAuthoritativeEngineID(packet []byte, u *net.UDPAddr) {
+	p, err := t.discoveryParams.UnmarshalTrap(packet, false)
+	if err != nil {
+		t.errorLogger.Warn("Invalid engine ID discovery request from %s on listener %s: %s", u.String(), t.config.Addr(), err)
+		trapsPacketsInvalid.Add(1)
+		return
+	}
+	if p.MsgFlags&gosnmp.Reportable == 0 {
+		return
+	}
+	securityParameters, ok := p.SecurityParameters.Copy().(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return
+	}
+	securityParameters.AuthoritativeEngineID = t.config.authoritativeEngineID
+	t.unknownEngineIDs++
+
+	report := *p
+	report.PDUType = gosnmp.Report
+	report.MsgFlags &= gosnmp.AuthPriv
+	report.SecurityParameters = securityParameters
+	report.Variables = []gosnmp.SnmpPDU{
+		{Name: usmStatsUnknownEngineIDsOID, Type: gosnmp.Counter32, Value: t.unknownEngineIDs},
+	}
+	if err := t.send(&report, u); err != nil {
+		t.errorLogger.Warn("Unable to report engine ID to %s on listener %s: %s", u.String(), t.config.Addr(), err)
+	}
+}
+
+func (t *TrapListener) send(p *gosnmp.SnmpPacket, u *net.UDPAddr) error {
+	out, err := p.MarshalMsg()
+	if err != nil {
+		return fmt.Errorf("error marshaling packet: %w", err)
+	}
+	_, err = t.conn.WriteToUDP(out, u)
+	return err
+}
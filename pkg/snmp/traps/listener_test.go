@@ -135,3 +135,146 @@ func assertNoPacketReceived(t *testing.T, listener *TrapListener) {
 		break
 	}
 }
+
+func TestServerV3MultipleUsers(t *testing.T) {
+	users := []UserV3{
+		{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "password", PrivProtocol: "aes"},
+		{Username: "other-user", AuthKey: "other-password", AuthProtocol: "md5", PrivKey: "other-password", PrivProtocol: "des"},
+		// users sharing a username are selected by the engine ID of the sender
+		{Username: "shared", AuthKey: "first-password", AuthProtocol: "sha", EngineID: "foobarbaz"},
+		{Username: "shared", AuthKey: "second-password", AuthProtocol: "sha"},
+	}
+	config := Config{Port: serverPort, Users: users}
+	Configure(t, config)
+
+	packetOutChan := make(PacketsChannel)
+	trapListener, err := startSNMPTrapListener(config, packetOutChan)
+	require.NoError(t, err)
+	defer trapListener.Stop()
+
+	for _, securityParams := range []*gosnmp.UsmSecurityParameters{
+		{UserName: "user", AuthoritativeEngineID: "foobarbaz", AuthenticationPassphrase: "password", AuthenticationProtocol: gosnmp.SHA, PrivacyPassphrase: "password", PrivacyProtocol: gosnmp.AES},
+		{UserName: "other-user", AuthoritativeEngineID: "foobarbaz", AuthenticationPassphrase: "other-password", AuthenticationProtocol: gosnmp.MD5, PrivacyPassphrase: "other-password", PrivacyProtocol: gosnmp.DES},
+	} {
+		sendTestV3Trap(t, config, securityParams)
+		packet := receivePacket(t, trapListener)
+		require.NotNil(t, packet)
+		assertVariables(t, packet)
+	}
+
+	sendTestV3TrapWithFlags(t, config, gosnmp.AuthNoPriv, &gosnmp.UsmSecurityParameters{
+		UserName: "shared", AuthoritativeEngineID: "foobarbaz", AuthenticationPassphrase: "first-password", AuthenticationProtocol: gosnmp.SHA,
+	})
+	require.NotNil(t, receivePacket(t, trapListener))
+	sendTestV3TrapWithFlags(t, config, gosnmp.AuthNoPriv, &gosnmp.UsmSecurityParameters{
+		UserName: "shared", AuthoritativeEngineID: "otherengine", AuthenticationPassphrase: "second-password", AuthenticationProtocol: gosnmp.SHA,
+	})
+	require.NotNil(t, receivePacket(t, trapListener))
+	sendTestV3TrapWithFlags(t, config, gosnmp.AuthNoPriv, &gosnmp.UsmSecurityParameters{
+		UserName: "shared", AuthoritativeEngineID: "otherengine", AuthenticationPassphrase: "first-password", AuthenticationProtocol: gosnmp.SHA,
+	})
+	assertNoPacketReceived(t, trapListener)
+}
+
+func TestServerV3AuthErrorsByUser(t *testing.T) {
+	userV3 := UserV3{Username: "auth-errors-user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "password", PrivProtocol: "aes"}
+	config := Config{Port: serverPort, Users: []UserV3{userV3}}
+	Configure(t, config)
+
+	packetOutChan := make(PacketsChannel)
+	trapListener, err := startSNMPTrapListener(config, packetOutChan)
+	require.NoError(t, err)
+	defer trapListener.Stop()
+
+	var unknownUserErrors float64
+	if userAuthErrors, ok := GetStatus()["userAuthErrors"].(map[string]interface{}); ok {
+		unknownUserErrors, _ = userAuthErrors[unknownUserAuthErrors].(float64)
+	}
+
+	// unauthenticated packets can't be sent on behalf of a user with credentials
+	sendTestV3TrapWithFlags(t, config, gosnmp.NoAuthNoPriv, &gosnmp.UsmSecurityParameters{
+		UserName: "auth-errors-user", AuthoritativeEngineID: "foobarbaz",
+	})
+	assertNoPacketReceived(t, trapListener)
+	// the users missing from the configuration share the same counter
+	for _, userName := range []string{"unknown-user-1", "unknown-user-2"} {
+		sendTestV3Trap(t, config, &gosnmp.UsmSecurityParameters{
+			UserName: userName, AuthoritativeEngineID: "foobarbaz", AuthenticationPassphrase: "password", AuthenticationProtocol: gosnmp.SHA, PrivacyPassphrase: "password", PrivacyProtocol: gosnmp.AES,
+		})
+		assertNoPacketReceived(t, trapListener)
+	}
+
+	userAuthErrors := GetStatus()["userAuthErrors"].(map[string]interface{})
+	assert.Equal(t, float64(1), userAuthErrors["auth-errors-user"])
+	assert.Equal(t, unknownUserErrors+2, userAuthErrors[unknownUserAuthErrors])
+	assert.NotContains(t, userAuthErrors, "unknown-user-1")
+	assert.NotContains(t, userAuthErrors, "unknown-user-2")
+}
+
+func TestServerV2Inform(t *testing.T) {
+	config := Config{Port: serverPort, CommunityStrings: []string{"public"}}
+	Configure(t, config)
+
+	packetOutChan := make(PacketsChannel, 1)
+	trapListener, err := startSNMPTrapListener(config, packetOutChan)
+	require.NoError(t, err)
+	defer trapListener.Stop()
+
+	params, err := config.BuildSNMPParams()
+	require.NoError(t, err)
+	params.Community = "public"
+	params.Timeout = 1 * time.Second
+	params.Retries = 1
+	require.NoError(t, params.Connect())
+	defer params.Conn.Close()
+
+	inform := NetSNMPExampleHeartbeatNotification
+	inform.IsInform = true
+	response, err := params.SendTrap(inform)
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.GetResponse, response.PDUType)
+
+	packet := receivePacket(t, trapListener)
+	require.NotNil(t, packet)
+	assert.Equal(t, gosnmp.InformRequest, packet.Content.PDUType)
+	assertVariables(t, packet)
+}
+
+func TestServerV3Inform(t *testing.T) {
+	userV3 := UserV3{Username: "user", AuthKey: "password", AuthProtocol: "sha", PrivKey: "password", PrivProtocol: "aes"}
+	config := Config{Port: serverPort, Users: []UserV3{userV3}}
+	Configure(t, config)
+	readConfig, err := ReadConfig("my-hostname")
+	require.NoError(t, err)
+
+	packetOutChan := make(PacketsChannel, 1)
+	trapListener, err := startSNMPTrapListener(*readConfig, packetOutChan)
+	require.NoError(t, err)
+	defer trapListener.Stop()
+
+	params, err := readConfig.BuildV3Params(userV3)
+	require.NoError(t, err)
+	// the sender discovers the engine ID of the listener
+	params.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		UserName:                 "user",
+		AuthenticationPassphrase: "password",
+		AuthenticationProtocol:   gosnmp.SHA,
+		PrivacyPassphrase:        "password",
+		PrivacyProtocol:          gosnmp.AES,
+	}
+	params.Timeout = 1 * time.Second
+	params.Retries = 1
+	require.NoError(t, params.Connect())
+	defer params.Conn.Close()
+
+	inform := NetSNMPExampleHeartbeatNotification
+	inform.IsInform = true
+	response, err := params.SendTrap(inform)
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.GetResponse, response.PDUType)
+
+	packet := receivePacket(t, trapListener)
+	require.NotNil(t, packet)
+	assert.Equal(t, gosnmp.InformRequest, packet.Content.PDUType)
+	assertVariables(t, packet)
+}
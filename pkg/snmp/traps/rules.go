@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traps
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validStatuses are the log statuses that can be set on the traps by the rules
+var validStatuses = map[string]bool{
+	"emergency": true,
+	"alert":     true,
+	"critical":  true,
+	"error":     true,
+	"warning":   true,
+	"notice":    true,
+	"info":      true,
+	"debug":     true,
+	"ok":        true,
+}
+
+// TrapRule maps the traps matching a trap OID and optionally some variable values to a status and a title.
+// The variables are identified by their OID or by their name when it's resolved, the enum values resolved
+// from the MIBs can be used to match them.
+type TrapRule struct {
+	TrapOID   string            `mapstructure:"trap_oid" yaml:"trap_oid"`
+	Variables map[string]string `mapstructure:"variables" yaml:"variables"`
+	Status    string            `mapstructure:"status" yaml:"status"`
+	Title     string            `mapstructure:"title" yaml:"title"`
+}
+
+// validateRules validates the rules and normalizes their OIDs
+func validateRules(rules []TrapRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.TrapOID == "" {
+			return fmt.Errorf("rule %d: `trap_oid` is required", i)
+		}
+		rule.TrapOID = NormalizeOID(rule.TrapOID)
+
+		if rule.Status != "" {
+			rule.Status = strings.ToLower(rule.Status)
+			if !validStatuses[rule.Status] {
+				return fmt.Errorf("rule %d: invalid status `%s`", i, rule.Status)
+			}
+		}
+		if rule.Status == "" && rule.Title == "" {
+			return fmt.Errorf("rule %d: at least one of `status` or `title` must be set", i)
+		}
+
+		variables := make(map[string]string, len(rule.Variables))
+		for variable, value := range rule.Variables {
+			variables[NormalizeOID(variable)] = value
+		}
+		rule.Variables = variables
+	}
+	return nil
+}
+
+// applyRules sets the status and the title of the first rule matching a formatted trap
+func applyRules(rules []TrapRule, formattedTrap map[string]interface{}) {
+	for _, rule := range rules {
+		if !rule.matches(formattedTrap) {
+			continue
+		}
+		if rule.Status != "" {
+			formattedTrap["status"] = rule.Status
+		}
+		if rule.Title != "" {
+			formattedTrap["title"] = rule.Title
+		}
+		return
+	}
+}
+
+func (r *TrapRule) matches(formattedTrap map[string]interface{}) bool {
+	if trapOID, _ := formattedTrap["snmpTrapOID"].(string); trapOID != r.TrapOID {
+		return false
+	}
+	variables, _ := formattedTrap["variables"].([]trapVariable)
+	for variable, expectedValue := range r.Variables {
+		if !variableMatches(formattedTrap, variables, variable, expectedValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// variableMatches returns true if a variable identified by its resolved name or by its OID has the expected value,
+// variables OIDs often contain an index so they are matched by prefix
+func variableMatches(formattedTrap map[string]interface{}, variables []trapVariable, variable string, expectedValue string) bool {
+	if value, ok := formattedTrap[variable]; ok && fmt.Sprint(value) == expectedValue {
+		return true
+	}
+	for _, trapVar := range variables {
+		if trapVar.OID != variable && !strings.HasPrefix(trapVar.OID, variable+".") {
+			continue
+		}
+		if fmt.Sprint(trapVar.Value) == expectedValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package traps
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicemetadata"
+)
+
+func formatTestPacket(t *testing.T, formatter JSONFormatter, packet *SnmpPacket) map[string]interface{} {
+	data, err := formatter.FormatPacket(packet)
+	require.NoError(t, err)
+	content := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(data, &content))
+	return content["trap"].(map[string]interface{})
+}
+
+func TestFormatterWithRules(t *testing.T) {
+	data := []struct {
+		description    string
+		rules          []TrapRule
+		expectedStatus interface{}
+		expectedTitle  interface{}
+	}{
+		{
+			description: "rule matching the trap OID",
+			rules: []TrapRule{
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Status: "error", Title: "Interface down"},
+			},
+			expectedStatus: "error",
+			expectedTitle:  "Interface down",
+		},
+		{
+			description: "rule matching a variable enum value by name",
+			rules: []TrapRule{
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"ifAdminStatus": "down"}, Status: "info"},
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"ifAdminStatus": "up"}, Status: "critical"},
+			},
+			expectedStatus: "critical",
+		},
+		{
+			description: "rule matching a variable raw value by OID prefix",
+			rules: []TrapRule{
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"1.3.6.1.2.1.2.2.1": "2", "1.3.6.1.2.1.2.2.1.7": "1"}, Title: "Interface 2 down"},
+			},
+			expectedTitle: "Interface 2 down",
+		},
+		{
+			description: "no matching rule",
+			rules: []TrapRule{
+				{TrapOID: "1.3.6.1.6.3.1.1.5.4", Status: "ok"},
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{"ifOperStatus": "up"}, Status: "ok"},
+			},
+		},
+	}
+
+	for _, d := range data {
+		t.Run(d.description, func(t *testing.T) {
+			formatter, err := NewJSONFormatter(resolverWithData, "porco_rosso", d.rules, nil)
+			require.NoError(t, err)
+			trapContent := formatTestPacket(t, formatter, createTestV1GenericPacket())
+
+			assert.Equal(t, d.expectedStatus, trapContent["status"])
+			assert.Equal(t, d.expectedTitle, trapContent["title"])
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	rules := []TrapRule{
+		{TrapOID: ".1.3.6.1.6.3.1.1.5.3", Variables: map[string]string{".1.3.6.1.2.1.2.2.1.7": "1", "ifOperStatus": "down"}, Status: "Error"},
+	}
+	require.NoError(t, validateRules(rules))
+	assert.Equal(t, TrapRule{
+		TrapOID:   "1.3.6.1.6.3.1.1.5.3",
+		Variables: map[string]string{"1.3.6.1.2.1.2.2.1.7": "1", "ifOperStatus": "down"},
+		Status:    "error",
+	}, rules[0])
+
+	assert.EqualError(t, validateRules([]TrapRule{{Status: "error"}}), "rule 0: `trap_oid` is required")
+	assert.EqualError(t, validateRules([]TrapRule{{TrapOID: "1.2.3", Status: "bad"}}), "rule 0: invalid status `bad`")
+	assert.EqualError(t, validateRules([]TrapRule{{TrapOID: "1.2.3"}}), "rule 0: at least one of `status` or `title` must be set")
+}
+
+func TestFormatterWithDeviceMetadata(t *testing.T) {
+	deviceMetadata := devicemetadata.NewCache(time.Hour)
+	deviceMetadata.SetDevice("porco_rosso", "127.0.0.1", devicemetadata.Device{
+		ID:   "porco_rosso:127.0.0.1",
+		Name: "my-router",
+		Tags: []string{"snmp_device:127.0.0.1", "device_vendor:cisco", "snmp_host:my-router"},
+	}, nil)
+
+	formatter, err := NewJSONFormatter(resolverWithData, "porco_rosso", nil, deviceMetadata)
+	require.NoError(t, err)
+	trapContent := formatTestPacket(t, formatter, createTestV1GenericPacket())
+	assert.Equal(t, "snmp_version:1,device_namespace:porco_rosso,snmp_device:127.0.0.1,device_vendor:cisco,snmp_host:my-router", trapContent["ddtags"])
+
+	// traps sent by devices that aren't monitored aren't enriched
+	formatter, err = NewJSONFormatter(resolverWithData, "other_namespace", nil, deviceMetadata)
+	require.NoError(t, err)
+	trapContent = formatTestPacket(t, formatter, createTestV1GenericPacket())
+	assert.Equal(t, "snmp_version:1,device_namespace:other_namespace,snmp_device:127.0.0.1", trapContent["ddtags"])
+}
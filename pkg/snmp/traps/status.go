@@ -13,14 +13,22 @@ import (
 )
 
 var (
-	trapsExpvars           = expvar.NewMap("snmp_traps")
-	trapsPackets           = expvar.Int{}
-	trapsPacketsAuthErrors = expvar.Int{}
+	trapsExpvars             = expvar.NewMap("snmp_traps")
+	trapsPackets             = expvar.Int{}
+	trapsPacketsAuthErrors   = expvar.Int{}
+	trapsPacketsInvalid      = expvar.Int{}
+	trapsInformsAcknowledged = expvar.Int{}
+	// trapsUserAuthErrors counts the SNMPv3 authentication failures by configured username,
+	// the failures of the other users are counted under unknownUserAuthErrors
+	trapsUserAuthErrors = expvar.Map{}
 )
 
 func init() {
 	trapsExpvars.Set("Packets", &trapsPackets)
 	trapsExpvars.Set("PacketsAuthErrors", &trapsPacketsAuthErrors)
+	trapsExpvars.Set("PacketsInvalid", &trapsPacketsInvalid)
+	trapsExpvars.Set("InformsAcknowledged", &trapsInformsAcknowledged)
+	trapsExpvars.Set("UserAuthErrors", &trapsUserAuthErrors)
 }
 
 func getDroppedPackets() int64 {
@@ -49,6 +57,11 @@ func GetStatus() map[string]interface{} {
 	metricsJSON := []byte(expvar.Get("snmp_traps").String())
 	metrics := make(map[string]interface{})
 	json.Unmarshal(metricsJSON, &metrics) //nolint:errcheck
+	// the authentication failures by user are reported separately from the other metrics
+	if userAuthErrors, ok := metrics["UserAuthErrors"].(map[string]interface{}); ok && len(userAuthErrors) > 0 {
+		status["userAuthErrors"] = userAuthErrors
+	}
+	delete(metrics, "UserAuthErrors")
 	if dropped := getDroppedPackets(); dropped > 0 {
 		metrics["PacketsDropped"] = dropped
 	}
@@ -182,9 +182,13 @@ func sendTestV2Trap(t *testing.T, trapConfig Config, community string) *gosnmp.G
 }
 
 func sendTestV3Trap(t *testing.T, trapConfig Config, securityParams *gosnmp.UsmSecurityParameters) *gosnmp.GoSNMP {
-	params, err := trapConfig.BuildSNMPParams()
+	return sendTestV3TrapWithFlags(t, trapConfig, gosnmp.AuthPriv, securityParams)
+}
+
+func sendTestV3TrapWithFlags(t *testing.T, trapConfig Config, msgFlags gosnmp.SnmpV3MsgFlags, securityParams *gosnmp.UsmSecurityParameters) *gosnmp.GoSNMP {
+	params, err := trapConfig.BuildV3Params(trapConfig.Users[0])
 	require.NoError(t, err)
-	params.MsgFlags = gosnmp.AuthPriv
+	params.MsgFlags = msgFlags
 	params.SecurityParameters = securityParams
 	params.Timeout = 1 * time.Second // Must be non-zero when sending traps.
 	params.Retries = 1               // Must be non-zero when sending traps.
@@ -23,6 +23,7 @@ import (
 	"github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/containers"
 	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/mount"
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/containerd/oci"
 )
@@ -48,6 +49,7 @@ type ContainerdItf interface {
 	ListImages() ([]containerd.Image, error)
 	Image(ctn containerd.Container) (containerd.Image, error)
 	ImageSize(ctn containerd.Container) (int64, error)
+	Mounts(ctn containerd.Container) ([]mount.Mount, error)
 	Spec(ctn containerd.Container) (*oci.Spec, error)
 	SpecWithContext(ctx context.Context, ctn containerd.Container) (*oci.Spec, error)
 	Metadata() (containerd.Version, error)
@@ -259,6 +261,20 @@ func (c *ContainerdUtil) ImageSize(ctn containerd.Container) (int64, error) {
 	return img.Size(ctxNamespace)
 }
 
+// Mounts interfaces with the containerd api to get the mounts of the container root filesystem snapshot
+func (c *ContainerdUtil) Mounts(ctn containerd.Container) ([]mount.Mount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+	ctxNamespace := namespaces.WithNamespace(ctx, c.namespace)
+
+	info, err := ctn.Info(ctxNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.cl.SnapshotService(info.Snapshotter).Mounts(ctxNamespace, info.SnapshotKey)
+}
+
 // Info interfaces with the containerd api to get Container info
 func (c *ContainerdUtil) Info(ctn containerd.Container) (containers.Container, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
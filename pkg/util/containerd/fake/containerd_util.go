@@ -14,6 +14,7 @@ import (
 	"github.com/containerd/containerd"
 	"github.com/containerd/containerd/api/types"
 	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/mount"
 	"github.com/containerd/containerd/oci"
 
 	"github.com/DataDog/datadog-agent/pkg/util/retry"
@@ -33,6 +34,7 @@ type MockedContainerdClient struct {
 	MockListImages            func() ([]containerd.Image, error)
 	MockImage                 func(ctn containerd.Container) (containerd.Image, error)
 	MockImageSize             func(ctn containerd.Container) (int64, error)
+	MockMounts                func(ctn containerd.Container) ([]mount.Mount, error)
 	MockTaskMetrics           func(ctn containerd.Container) (*types.Metric, error)
 	MockTaskPids              func(ctn containerd.Container) ([]containerd.ProcessInfo, error)
 	MockInfo                  func(ctn containerd.Container) (containers.Container, error)
@@ -74,6 +76,11 @@ func (client *MockedContainerdClient) ImageSize(ctn containerd.Container) (int64
 	return client.MockImageSize(ctn)
 }
 
+// Mounts is a mock method
+func (client *MockedContainerdClient) Mounts(ctn containerd.Container) ([]mount.Mount, error) {
+	return client.MockMounts(ctn)
+}
+
 // Labels is a mock method
 func (client *MockedContainerdClient) Labels(ctn containerd.Container) (map[string]string, error) {
 	return client.MockLabels(ctn)
@@ -36,6 +36,12 @@ func (m *MockCRIClient) GetContainerStatus(containerID string) (*pb.ContainerSta
 	return args.Get(0).(*pb.ContainerStatus), args.Error(1)
 }
 
+// ListContainers sends a ListContainersRequest to the server, and parses the returned response
+func (m *MockCRIClient) ListContainers() ([]*pb.Container, error) {
+	args := m.Called()
+	return args.Get(0).([]*pb.Container), args.Error(1)
+}
+
 // GetRuntime is a mock of GetRuntime
 func (m *MockCRIClient) GetRuntime() string {
 	return "fakeruntime"
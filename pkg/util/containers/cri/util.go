@@ -34,6 +34,7 @@ type CRIClient interface {
 	ListContainerStats() (map[string]*pb.ContainerStats, error)
 	GetContainerStats(containerID string) (*pb.ContainerStats, error)
 	GetContainerStatus(containerID string) (*pb.ContainerStatus, error)
+	ListContainers() ([]*pb.Container, error)
 	GetRuntime() string
 	GetRuntimeVersion() string
 }
@@ -152,6 +153,18 @@ func (c *CRIUtil) GetContainerStatus(containerID string) (*pb.ContainerStatus, e
 	return r.Status, nil
 }
 
+// ListContainers sends a ListContainersRequest to the server, and returns all the containers, including exited ones
+func (c *CRIUtil) ListContainers() ([]*pb.Container, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.queryTimeout)
+	defer cancel()
+	r, err := c.client.ListContainers(ctx, &pb.ListContainersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetContainers(), nil
+}
+
 // GetRuntime returns the CRI runtime
 func (c *CRIUtil) GetRuntime() string {
 	return c.runtime
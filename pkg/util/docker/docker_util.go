@@ -112,6 +112,17 @@ func (d *DockerUtil) Images(ctx context.Context, includeIntermediate bool) ([]ty
 	return images, nil
 }
 
+// ImageInspect returns the details of an image, including its storage driver data
+func (d *DockerUtil) ImageInspect(ctx context.Context, imageID string) (types.ImageInspect, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.queryTimeout)
+	defer cancel()
+	image, _, err := d.cli.ImageInspectWithRaw(ctx, imageID)
+	if err != nil {
+		return types.ImageInspect{}, fmt.Errorf("unable to inspect docker image %s: %s", imageID, err)
+	}
+	return image, nil
+}
+
 // CountVolumes returns the number of attached and dangling volumes.
 func (d *DockerUtil) CountVolumes(ctx context.Context) (int, int, error) {
 	attachedFilter, _ := buildDockerFilter("dangling", "false")
@@ -85,6 +85,7 @@ func (d *DockerUtil) dispatchEvents(sub *eventSubscriber) {
 	fltrs.Add("event", ContainerEventActionStart)
 	fltrs.Add("event", ContainerEventActionDie)
 	fltrs.Add("event", ContainerEventActionDied)
+	fltrs.Add("event", ContainerEventActionOOM)
 	fltrs.Add("event", ContainerEventActionRename)
 	fltrs.Add("event", ContainerEventActionHealthStatus)
 
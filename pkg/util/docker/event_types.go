@@ -23,6 +23,8 @@ const (
 	ContainerEventActionDie = "die"
 	// ContainerEventActionDied is the action of stopping a podman container
 	ContainerEventActionDied = "died"
+	// ContainerEventActionOOM is the action of a docker container being OOM killed, it precedes the die action
+	ContainerEventActionOOM = "oom"
 	// ContainerEventActionRename is the action of renaming a docker container
 	ContainerEventActionRename = "rename"
 	// ContainerEventActionHealthStatus is the action of changing a docker
@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package geoip reads the local MaxMind databases (MMDB) used to enrich IP addresses
+// with their location and autonomous system
+package geoip
+
+import (
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ASNRecord contains the fields used from the records of the MaxMind ASN databases
+type ASNRecord struct {
+	ASN   uint32 `maxminddb:"autonomous_system_number"`
+	ASOrg string `maxminddb:"autonomous_system_organization"`
+}
+
+// Database is a MMDB database which can be reloaded when its file changes
+type Database struct {
+	path string
+
+	mu      sync.RWMutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+// Open loads the database at path
+func Open(path string) (*Database, error) {
+	reader, modTime, size, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{path: path, reader: reader, modTime: modTime, size: size}, nil
+}
+
+func load(path string) (*maxminddb.Reader, time.Time, int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, 0, err
+	}
+	// the database is loaded in memory rather than mapped, so that a database updated in place can't alter the reader
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, 0, err
+	}
+	reader, err := maxminddb.FromBytes(content)
+	if err != nil {
+		return nil, time.Time{}, 0, err
+	}
+	return reader, info.ModTime(), info.Size(), nil
+}
+
+// Path returns the path of the file of the database
+func (db *Database) Path() string {
+	return db.path
+}
+
+// Lookup decodes the record of ip into result, result is left unchanged when ip isn't in the database
+func (db *Database) Lookup(ip net.IP, result interface{}) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.reader.Lookup(ip, result)
+}
+
+// LookupASN returns the autonomous system of ip from an ASN database
+func (db *Database) LookupASN(ip net.IP) (ASNRecord, error) {
+	var record ASNRecord
+	err := db.Lookup(ip, &record)
+	return record, err
+}
+
+// changed returns true if the file of the database was modified since it was loaded
+func (db *Database) changed() bool {
+	info, err := os.Stat(db.path)
+	if err != nil {
+		return false
+	}
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return !info.ModTime().Equal(db.modTime) || info.Size() != db.size
+}
+
+// Reload reloads the database if its file changed, the current database is kept if the file can't be loaded
+func (db *Database) Reload() (bool, error) {
+	if !db.changed() {
+		return false, nil
+	}
+	reader, modTime, size, err := load(db.path)
+	if err != nil {
+		return false, err
+	}
+
+	db.mu.Lock()
+	previous := db.reader
+	db.reader, db.modTime, db.size = reader, modTime, size
+	db.mu.Unlock()
+	previous.Close() //nolint:errcheck
+	return true, nil
+}
+
+// Close closes the database
+func (db *Database) Close() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.reader.Close() //nolint:errcheck
+}
+
+// ReloadPeriodically reloads the databases whose files changed at every interval until stopChan is closed,
+// nil databases are ignored
+func ReloadPeriodically(interval time.Duration, stopChan <-chan struct{}, databases ...*Database) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			for _, db := range databases {
+				if db == nil {
+					continue
+				}
+				reloaded, err := db.Reload()
+				if err != nil {
+					log.Warnf("Error reloading geo-IP database `%s`: %s", db.path, err)
+				} else if reloaded {
+					log.Infof("Geo-IP database `%s` reloaded", db.path)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/geoip/testutil"
+)
+
+func TestLookupASN(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	testutil.WriteMMDB(t, path, "1.2.0.0/16", testutil.ASNRecord(16276, "OVH SAS"))
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	record, err := db.LookupASN(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, ASNRecord{ASN: 16276, ASOrg: "OVH SAS"}, record)
+
+	record, err = db.LookupASN(net.ParseIP("5.6.7.8"))
+	require.NoError(t, err)
+	assert.Equal(t, ASNRecord{}, record)
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	testutil.WriteMMDB(t, path, "1.2.0.0/16", testutil.ASNRecord(16276, "OVH SAS"))
+
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// unchanged databases aren't reloaded
+	reloaded, err := db.Reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// an invalid database is ignored, the previous one is kept
+	require.NoError(t, os.WriteFile(path, []byte("invalid"), 0600))
+	_, err = db.Reload()
+	assert.Error(t, err)
+	record, err := db.LookupASN(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(16276), record.ASN)
+
+	testutil.WriteMMDB(t, path, "1.2.0.0/16", testutil.ASNRecord(3215, "Orange"))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+	reloaded, err = db.Reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	record, err = db.LookupASN(net.ParseIP("1.2.3.4"))
+	require.NoError(t, err)
+	assert.Equal(t, ASNRecord{ASN: 3215, ASOrg: "Orange"}, record)
+}
+
+func TestOpenInvalidDatabase(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package testutil writes minimal MaxMind databases (MMDB) for the tests of their readers
+package testutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// MMDBValue encodes values using the MaxMind DB data section format
+type MMDBValue []byte
+
+// MMDBString encodes a string
+func MMDBString(s string) MMDBValue {
+	if len(s) >= 29 {
+		return append(MMDBValue{0x40 | 29, byte(len(s) - 29)}, s...)
+	}
+	return append(MMDBValue{0x40 | byte(len(s))}, s...)
+}
+
+// MMDBUint16 encodes an uint16
+func MMDBUint16(v uint16) MMDBValue {
+	return MMDBValue{0xa0 | 2, byte(v >> 8), byte(v)}
+}
+
+// MMDBUint32 encodes an uint32
+func MMDBUint32(v uint32) MMDBValue {
+	value := MMDBValue{0xc0 | 4, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(value[1:], v)
+	return value
+}
+
+// MMDBMap encodes a map from its alternated keys and values
+func MMDBMap(keyValues ...MMDBValue) MMDBValue {
+	value := MMDBValue{0xe0 | byte(len(keyValues)/2)}
+	for _, kv := range keyValues {
+		value = append(value, kv...)
+	}
+	return value
+}
+
+// CityRecord returns the record of a MaxMind City database
+func CityRecord(country string, city string) MMDBValue {
+	return MMDBMap(
+		MMDBString("country"), MMDBMap(MMDBString("iso_code"), MMDBString(country)),
+		MMDBString("city"), MMDBMap(MMDBString("names"), MMDBMap(MMDBString("en"), MMDBString(city))),
+	)
+}
+
+// ASNRecord returns the record of a MaxMind ASN database
+func ASNRecord(asn uint32, org string) MMDBValue {
+	return MMDBMap(
+		MMDBString("autonomous_system_number"), MMDBUint32(asn),
+		MMDBString("autonomous_system_organization"), MMDBString(org),
+	)
+}
+
+// WriteMMDB writes an IPv4 MaxMind DB mapping the addresses of `network` to `record`
+func WriteMMDB(t *testing.T, path string, network string, record MMDBValue) {
+	_, ipNet, err := net.ParseCIDR(network)
+	require.NoError(t, err)
+	prefixLen, _ := ipNet.Mask.Size()
+	ip := ipNet.IP.To4()
+
+	// one node per bit of the prefix, the last node points to the record
+	nodeCount := uint32(prefixLen)
+	var tree bytes.Buffer
+	for i := 0; i < prefixLen; i++ {
+		next := uint32(i + 1)
+		if i == prefixLen-1 {
+			next = nodeCount + 16
+		}
+		records := [2]uint32{nodeCount, nodeCount}
+		records[(ip[i/8]>>(7-uint(i%8)))&1] = next
+		for _, r := range records {
+			tree.Write([]byte{byte(r >> 16), byte(r >> 8), byte(r)})
+		}
+	}
+
+	var db bytes.Buffer
+	db.Write(tree.Bytes())
+	db.Write(make([]byte, 16))
+	db.Write(record)
+	db.WriteString("\xAB\xCD\xEFMaxMind.com")
+	db.Write(MMDBMap(
+		MMDBString("node_count"), MMDBUint32(nodeCount),
+		MMDBString("record_size"), MMDBUint16(24),
+		MMDBString("ip_version"), MMDBUint16(4),
+		MMDBString("database_type"), MMDBString("test"),
+		MMDBString("binary_format_major_version"), MMDBUint16(2),
+	))
+	require.NoError(t, os.WriteFile(path, db.Bytes(), 0600))
+}
@@ -80,6 +80,10 @@ type APIClient struct {
 	// DDInformerFactory gives access to informers for all datadoghq/ custom types
 	DDInformerFactory dynamicinformer.DynamicSharedInformerFactory
 
+	// ComplianceInformerFactory gives access to informers for the Kubernetes objects checked by the compliance
+	// agent
+	ComplianceInformerFactory dynamicinformer.DynamicSharedInformerFactory
+
 	// initRetry used to setup the APIClient
 	initRetry retry.Retrier
 
@@ -304,6 +308,15 @@ func (c *APIClient) connect() error {
 		}
 	}
 
+	if config.Datadog.GetBool("compliance_config.enabled") {
+		// dynamic informer factory uses its own client with a larger timeout
+		c.ComplianceInformerFactory, err = getDDInformerFactory()
+		if err != nil {
+			log.Infof("Could not get compliance informer factory: %v", err)
+			return err
+		}
+	}
+
 	// informer factory uses its own clientset with a larger timeout
 	c.InformerFactory, err = getInformerFactory()
 	if err != nil {
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package kubelet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+// PodResourcesClient is a client for the kubelet PodResources gRPC API,
+// exposing the devices, CPUs and memory allocated to containers
+type PodResourcesClient struct {
+	conn   *grpc.ClientConn
+	client podresourcesv1.PodResourcesListerClient
+}
+
+// NewPodResourcesClient connects to the kubelet PodResources unix socket
+func NewPodResourcesClient(socketPath string, connectionTimeout time.Duration) (*PodResourcesClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, socketPath, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial kubelet pod resources socket %s: %v", socketPath, err)
+	}
+
+	return &PodResourcesClient{
+		conn:   conn,
+		client: podresourcesv1.NewPodResourcesListerClient(conn),
+	}, nil
+}
+
+// ListPodResources returns the resources allocated to the containers of the pods running on the node
+func (c *PodResourcesClient) ListPodResources(ctx context.Context) ([]*podresourcesv1.PodResources, error) {
+	resp, err := c.client.List(ctx, &podresourcesv1.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPodResources(), nil
+}
+
+// GetAllocatableResources returns the resources of the node that can be allocated to containers.
+// It requires Kubernetes 1.21+ (KubeletPodResourcesGetAllocatable feature gate).
+func (c *PodResourcesClient) GetAllocatableResources(ctx context.Context) (*podresourcesv1.AllocatableResourcesResponse, error) {
+	return c.client.GetAllocatableResources(ctx, &podresourcesv1.AllocatableResourcesRequest{})
+}
+
+// Close closes the connection to the kubelet
+func (c *PodResourcesClient) Close() error {
+	return c.conn.Close()
+}
@@ -26,13 +26,14 @@ type PodList struct {
 
 // PodMetadata contains fields for unmarshalling a pod's metadata
 type PodMetadata struct {
-	Name        string            `json:"name,omitempty"`
-	UID         string            `json:"uid,omitempty"`
-	Namespace   string            `json:"namespace,omitempty"`
-	ResVersion  string            `json:"resourceVersion,omitempty"`
-	Annotations map[string]string `json:"annotations,omitempty"`
-	Labels      map[string]string `json:"labels,omitempty"`
-	Owners      []PodOwner        `json:"ownerReferences,omitempty"`
+	Name              string            `json:"name,omitempty"`
+	UID               string            `json:"uid,omitempty"`
+	Namespace         string            `json:"namespace,omitempty"`
+	ResVersion        string            `json:"resourceVersion,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Owners            []PodOwner        `json:"ownerReferences,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp,omitempty"`
 }
 
 // PodOwner contains fields for unmarshalling a Pod.Metadata.Owners
@@ -160,6 +161,7 @@ type ContainerStateRunning struct {
 // ContainerStateTerminated is a terminated state of a container.
 type ContainerStateTerminated struct {
 	ExitCode   int32     `json:"exitCode"`
+	Reason     string    `json:"reason,omitempty"`
 	StartedAt  time.Time `json:"startedAt"`
 	FinishedAt time.Time `json:"finishedAt"`
 }
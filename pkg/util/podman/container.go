@@ -112,6 +112,11 @@ type ContainerState struct {
 	StartedTime time.Time `json:"startedTime,omitempty"`
 	// FinishedTime is the time the container finished executing
 	FinishedTime time.Time `json:"finishedTime,omitempty"`
+	// ExitCode is the exit code returned when the container stopped
+	ExitCode int32 `json:"exitCode,omitempty"`
+	// OOMKilled indicates that the container was killed as it ran out of
+	// memory
+	OOMKilled bool `json:"oomKilled,omitempty"`
 	// PID is the PID of a running container
 	PID int `json:"pid,omitempty"`
 	// NetworkStatus contains the configuration results for all networks
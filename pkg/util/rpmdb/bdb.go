@@ -0,0 +1,173 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rpmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The rpm database is a Berkeley DB hash database whose values are the headers of the installed packages.
+// Only the pages required to list the values of the database are parsed.
+const (
+	bdbHashMagic = 0x061561
+
+	bdbMetadataSize   = 72
+	bdbPageHeaderSize = 26
+
+	bdbPageTypeHashUnsorted = 2
+	bdbPageTypeOverflow     = 7
+	bdbPageTypeHash         = 13
+
+	bdbEntryTypeOffPage = 3
+)
+
+type bdbPageHeader struct {
+	nextPageNo     uint32
+	numEntries     uint16
+	freeAreaOffset uint16
+	pageType       uint8
+}
+
+type bdbReader struct {
+	content  []byte
+	order    binary.ByteOrder
+	pageSize uint32
+	lastPage uint32
+}
+
+func newBdbReader(content []byte) (*bdbReader, error) {
+	if len(content) < bdbMetadataSize {
+		return nil, errors.New("invalid database: file too short")
+	}
+
+	r := &bdbReader{content: content}
+	switch {
+	case binary.LittleEndian.Uint32(content[12:16]) == bdbHashMagic:
+		r.order = binary.LittleEndian
+	case binary.BigEndian.Uint32(content[12:16]) == bdbHashMagic:
+		r.order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid database: not a Berkeley DB hash database")
+	}
+
+	r.pageSize = r.order.Uint32(content[20:24])
+	r.lastPage = r.order.Uint32(content[32:36])
+	if r.pageSize < bdbMetadataSize || uint64(r.pageSize)*(uint64(r.lastPage)+1) > uint64(len(content)) {
+		return nil, fmt.Errorf("invalid database: page size %d and last page %d don't match the file size", r.pageSize, r.lastPage)
+	}
+
+	return r, nil
+}
+
+func (r *bdbReader) page(pageNo uint32) ([]byte, bdbPageHeader, error) {
+	if pageNo > r.lastPage {
+		return nil, bdbPageHeader{}, fmt.Errorf("invalid database: page %d out of range", pageNo)
+	}
+
+	page := r.content[pageNo*r.pageSize : (pageNo+1)*r.pageSize]
+	return page, bdbPageHeader{
+		nextPageNo:     r.order.Uint32(page[16:20]),
+		numEntries:     r.order.Uint16(page[20:22]),
+		freeAreaOffset: r.order.Uint16(page[22:24]),
+		pageType:       page[25],
+	}, nil
+}
+
+// overflowValue reads a value stored in a chain of overflow pages
+func (r *bdbReader) overflowValue(pageNo uint32, length uint32) ([]byte, error) {
+	value := make([]byte, 0, length)
+
+	for visited := uint32(0); pageNo != 0; visited++ {
+		if visited > r.lastPage {
+			return nil, errors.New("invalid database: loop in overflow pages")
+		}
+
+		page, header, err := r.page(pageNo)
+		if err != nil {
+			return nil, err
+		}
+		if header.pageType != bdbPageTypeOverflow {
+			return nil, fmt.Errorf("invalid database: unexpected page type %d for overflow page %d", header.pageType, pageNo)
+		}
+
+		// the free area offset holds the length of the data stored in an overflow page
+		end := bdbPageHeaderSize + uint32(header.freeAreaOffset)
+		if end > r.pageSize {
+			return nil, fmt.Errorf("invalid database: overflow page %d too long", pageNo)
+		}
+		value = append(value, page[bdbPageHeaderSize:end]...)
+		pageNo = header.nextPageNo
+	}
+
+	if uint32(len(value)) != length {
+		return nil, fmt.Errorf("invalid database: expected value of %d bytes, got %d", length, len(value))
+	}
+	return value, nil
+}
+
+// values returns the values of the database, the headers of the packages are too large to be stored in hash pages,
+// they are always stored in overflow pages
+func (r *bdbReader) values() ([][]byte, error) {
+	var values [][]byte
+
+	for pageNo := uint32(1); pageNo <= r.lastPage; pageNo++ {
+		page, header, err := r.page(pageNo)
+		if err != nil {
+			return nil, err
+		}
+		if header.pageType != bdbPageTypeHash && header.pageType != bdbPageTypeHashUnsorted {
+			continue
+		}
+
+		// hash pages hold pairs of key and value entries
+		for i := uint32(1); i < uint32(header.numEntries); i += 2 {
+			indexOffset := bdbPageHeaderSize + 2*i
+			if indexOffset+2 > r.pageSize {
+				return nil, fmt.Errorf("invalid database: too many entries in page %d", pageNo)
+			}
+
+			entryOffset := uint32(r.order.Uint16(page[indexOffset : indexOffset+2]))
+			if entryOffset+12 > r.pageSize || page[entryOffset] != bdbEntryTypeOffPage {
+				continue
+			}
+
+			valuePageNo := r.order.Uint32(page[entryOffset+4 : entryOffset+8])
+			length := r.order.Uint32(page[entryOffset+8 : entryOffset+12])
+			value, err := r.overflowValue(valuePageNo, length)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// ParseBerkeleyDB lists the packages of a Berkeley DB rpm database, the Packages file of rpm before 4.16
+func ParseBerkeleyDB(content []byte) ([]Package, error) {
+	db, err := newBdbReader(content)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := db.values()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make([]Package, 0, len(values))
+	for _, value := range values {
+		pkg, err := parseHeader(value)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package rpmdb lists the packages of a rpm database, stored either in the Berkeley DB or in the sqlite format,
+// without depending on the rpm binary or library.
+package rpmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+	rpmTagArch    = 1022
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+
+	rpmEntryInfoSize = 16
+	// rpmMaxHeaderSize is the maximum size of a package header, as defined by rpm
+	rpmMaxHeaderSize = 256 * 1024 * 1024
+)
+
+// Package is a package installed in a rpm database
+type Package struct {
+	Name    string
+	Version string
+	Release string
+	Epoch   int
+	Arch    string
+}
+
+// FullVersion returns the version of the package formatted as [epoch:]version[-release]
+func (p Package) FullVersion() string {
+	version := p.Version
+	if p.Release != "" {
+		version += "-" + p.Release
+	}
+	if p.Epoch > 0 {
+		version = strconv.Itoa(p.Epoch) + ":" + version
+	}
+	return version
+}
+
+// parseHeader parses the header of a package, as stored in the rpm database
+func parseHeader(header []byte) (Package, error) {
+	if len(header) < 8 {
+		return Package{}, errors.New("invalid rpm header: too short")
+	}
+
+	indexLength := binary.BigEndian.Uint32(header[0:4])
+	dataLength := binary.BigEndian.Uint32(header[4:8])
+	dataStart := 8 + uint64(indexLength)*rpmEntryInfoSize
+	if dataStart+uint64(dataLength) > uint64(len(header)) || dataStart+uint64(dataLength) > rpmMaxHeaderSize {
+		return Package{}, errors.New("invalid rpm header: invalid length")
+	}
+	data := header[dataStart : dataStart+uint64(dataLength)]
+
+	var pkg Package
+	for i := uint32(0); i < indexLength; i++ {
+		entry := header[8+i*rpmEntryInfoSize : 8+(i+1)*rpmEntryInfoSize]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		kind := binary.BigEndian.Uint32(entry[4:8])
+		offset := binary.BigEndian.Uint32(entry[8:12])
+		if offset >= uint32(len(data)) {
+			continue
+		}
+
+		switch {
+		case kind == rpmTypeString:
+			value := data[offset:]
+			if end := bytes.IndexByte(value, 0); end >= 0 {
+				value = value[:end]
+			}
+
+			switch tag {
+			case rpmTagName:
+				pkg.Name = string(value)
+			case rpmTagVersion:
+				pkg.Version = string(value)
+			case rpmTagRelease:
+				pkg.Release = string(value)
+			case rpmTagArch:
+				pkg.Arch = string(value)
+			}
+		case kind == rpmTypeInt32 && tag == rpmTagEpoch && offset+4 <= uint32(len(data)):
+			if epoch := int32(binary.BigEndian.Uint32(data[offset : offset+4])); epoch > 0 {
+				pkg.Epoch = int(epoch)
+			}
+		}
+	}
+
+	if pkg.Name == "" {
+		return Package{}, errors.New("invalid rpm header: missing package name")
+	}
+	return pkg, nil
+}
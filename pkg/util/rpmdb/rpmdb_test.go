@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rpmdb
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/rpmdb/testutil"
+)
+
+func TestParseBerkeleyDB(t *testing.T) {
+	// a long release makes the header span several overflow pages
+	longRelease := strings.Repeat("r", 1000)
+
+	db := testutil.BerkeleyDB(
+		testutil.Header("openssh-server", "7.4p1", "22.el7_9", "x86_64", 0),
+		testutil.Header("sudo", "1.8.23", "10.el7_9.2", "x86_64", 0),
+		testutil.Header("openssl", "1.0.2k", longRelease, "x86_64", 1),
+	)
+
+	packages, err := ParseBerkeleyDB(db)
+	require.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "openssh-server", Version: "7.4p1", Release: "22.el7_9", Arch: "x86_64"},
+		{Name: "sudo", Version: "1.8.23", Release: "10.el7_9.2", Arch: "x86_64"},
+		{Name: "openssl", Version: "1.0.2k", Release: longRelease, Epoch: 1, Arch: "x86_64"},
+	}, packages)
+
+	_, err = ParseBerkeleyDB(db[:100])
+	assert.Error(t, err)
+	_, err = ParseBerkeleyDB(testutil.Sqlite())
+	assert.Error(t, err)
+}
+
+func TestParseSqlite(t *testing.T) {
+	// rpm database created by sqlite with the schema of rpm, with small pages so that the Packages table spans
+	// interior and overflow pages
+	content, err := os.ReadFile("./testdata/rpmdb.sqlite")
+	require.NoError(t, err)
+
+	packages, err := ParseSqlite(content)
+	require.NoError(t, err)
+	assert.Len(t, packages, 45)
+	assert.Contains(t, packages, Package{Name: "openssh-server", Version: "8.7p1", Release: "8.el9", Arch: "x86_64"})
+	assert.Contains(t, packages, Package{Name: "openssl", Version: "3.0.1", Release: "41.el9_0", Epoch: 1, Arch: "x86_64"})
+	assert.Contains(t, packages, Package{Name: "gpg-pubkey", Version: "8483c65d", Release: "5ccc5b19"})
+	assert.Contains(t, packages, Package{Name: "kernel", Version: "5.14.0", Release: "70.13.1.el9_0" + strings.Repeat("r", 1500), Arch: "x86_64"})
+	assert.Contains(t, packages, Package{Name: "package-39", Version: "1.0", Release: "39", Arch: "noarch"})
+
+	packages, err = ParseSqlite(testutil.Sqlite(testutil.Header("sudo", "1.9.5p2", "7.el9", "x86_64", 0)))
+	require.NoError(t, err)
+	assert.Equal(t, []Package{{Name: "sudo", Version: "1.9.5p2", Release: "7.el9", Arch: "x86_64"}}, packages)
+
+	_, err = ParseSqlite([]byte("SQLite format 3\x00"))
+	assert.Error(t, err)
+	_, err = ParseSqlite(testutil.BerkeleyDB())
+	assert.Error(t, err)
+}
+
+func TestFullVersion(t *testing.T) {
+	assert.Equal(t, "1.8.23-10.el7_9.2", Package{Name: "sudo", Version: "1.8.23", Release: "10.el7_9.2"}.FullVersion())
+	assert.Equal(t, "1:1.0.2k-25.el7_9", Package{Name: "openssl", Version: "1.0.2k", Release: "25.el7_9", Epoch: 1}.FullVersion())
+	assert.Equal(t, "8483c65d", Package{Name: "gpg-pubkey", Version: "8483c65d"}.FullVersion())
+}
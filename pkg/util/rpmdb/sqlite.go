@@ -0,0 +1,331 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rpmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Since rpm 4.16, the rpm database can be a sqlite database whose Packages table holds the headers of the installed
+// packages, in the same format as in the Berkeley DB database. Only the table b-tree pages required to list the rows
+// of the sqlite_master and Packages tables are parsed. The write-ahead log isn't read, rpm checkpoints it into the
+// database when closing it.
+const (
+	sqliteMagic      = "SQLite format 3\x00"
+	sqliteHeaderSize = 100
+
+	sqlitePageTypeTableInterior = 0x05
+	sqlitePageTypeTableLeaf     = 0x0d
+
+	rpmSqlitePackagesTable = "Packages"
+)
+
+type sqliteReader struct {
+	content    []byte
+	pageSize   uint32
+	usableSize uint32
+	pageCount  uint32
+}
+
+func newSqliteReader(content []byte) (*sqliteReader, error) {
+	if len(content) < sqliteHeaderSize || string(content[:len(sqliteMagic)]) != sqliteMagic {
+		return nil, errors.New("invalid database: not a sqlite database")
+	}
+
+	// the page size is a power of two between 512 and 65536, 65536 being stored as 1
+	pageSize := uint32(binary.BigEndian.Uint16(content[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid database: invalid page size %d", pageSize)
+	}
+
+	r := &sqliteReader{
+		content:    content,
+		pageSize:   pageSize,
+		usableSize: pageSize - uint32(content[20]),
+		pageCount:  uint32(len(content)) / pageSize,
+	}
+	if r.usableSize < 480 {
+		return nil, fmt.Errorf("invalid database: invalid usable page size %d", r.usableSize)
+	}
+	return r, nil
+}
+
+func (r *sqliteReader) page(pageNo uint32) ([]byte, error) {
+	if pageNo == 0 || pageNo > r.pageCount {
+		return nil, fmt.Errorf("invalid database: page %d out of range", pageNo)
+	}
+	return r.content[(pageNo-1)*r.pageSize : pageNo*r.pageSize], nil
+}
+
+// sqliteVarint decodes a sqlite variable-length integer, it returns the number of bytes read, 0 if b is too short
+func sqliteVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// walkTable calls fn with the payload of each row of the table b-tree whose root is rootPageNo
+func (r *sqliteReader) walkTable(rootPageNo uint32, fn func(payload []byte) error) error {
+	visited := make(map[uint32]bool)
+	pending := []uint32{rootPageNo}
+
+	for len(pending) > 0 {
+		pageNo := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[pageNo] {
+			return errors.New("invalid database: loop in table pages")
+		}
+		visited[pageNo] = true
+
+		page, err := r.page(pageNo)
+		if err != nil {
+			return err
+		}
+
+		// the first page starts with the database header
+		headerOffset := uint32(0)
+		if pageNo == 1 {
+			headerOffset = sqliteHeaderSize
+		}
+		header := page[headerOffset:]
+		numCells := uint32(binary.BigEndian.Uint16(header[3:5]))
+
+		var cellPointers uint32
+		switch header[0] {
+		case sqlitePageTypeTableInterior:
+			pending = append(pending, binary.BigEndian.Uint32(header[8:12]))
+			cellPointers = headerOffset + 12
+		case sqlitePageTypeTableLeaf:
+			cellPointers = headerOffset + 8
+		default:
+			return fmt.Errorf("invalid database: unexpected page type %d for table page %d", header[0], pageNo)
+		}
+		if cellPointers+2*numCells > r.usableSize {
+			return fmt.Errorf("invalid database: too many cells in page %d", pageNo)
+		}
+
+		for i := uint32(0); i < numCells; i++ {
+			cellOffset := uint32(binary.BigEndian.Uint16(page[cellPointers+2*i:]))
+			if cellOffset >= r.usableSize {
+				return fmt.Errorf("invalid database: invalid cell offset in page %d", pageNo)
+			}
+			cell := page[cellOffset:r.usableSize]
+
+			if header[0] == sqlitePageTypeTableInterior {
+				if len(cell) < 4 {
+					return fmt.Errorf("invalid database: truncated cell in page %d", pageNo)
+				}
+				pending = append(pending, binary.BigEndian.Uint32(cell[0:4]))
+				continue
+			}
+
+			payload, err := r.cellPayload(cell)
+			if err != nil {
+				return err
+			}
+			if err := fn(payload); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cellPayload returns the payload of a table leaf cell, reading the overflow pages of the payloads too large to
+// be stored in the page
+func (r *sqliteReader) cellPayload(cell []byte) ([]byte, error) {
+	payloadSize, n := sqliteVarint(cell)
+	if n == 0 {
+		return nil, errors.New("invalid database: truncated cell")
+	}
+	cell = cell[n:]
+	// the row ID
+	if _, n = sqliteVarint(cell); n == 0 {
+		return nil, errors.New("invalid database: truncated cell")
+	}
+	cell = cell[n:]
+	if payloadSize > rpmMaxHeaderSize {
+		return nil, fmt.Errorf("invalid database: payload of %d bytes too large", payloadSize)
+	}
+
+	usable := uint64(r.usableSize)
+	localSize := payloadSize
+	if maxLocal := usable - 35; payloadSize > maxLocal {
+		minLocal := (usable-12)*32/255 - 23
+		localSize = minLocal + (payloadSize-minLocal)%(usable-4)
+		if localSize > maxLocal {
+			localSize = minLocal
+		}
+	}
+	if localSize > uint64(len(cell)) || (localSize < payloadSize && localSize+4 > uint64(len(cell))) {
+		return nil, errors.New("invalid database: truncated cell")
+	}
+
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, cell[:localSize]...)
+	if localSize == payloadSize {
+		return payload, nil
+	}
+
+	overflowPageNo := binary.BigEndian.Uint32(cell[localSize : localSize+4])
+	for visited := uint32(0); uint64(len(payload)) < payloadSize; visited++ {
+		if overflowPageNo == 0 || visited > r.pageCount {
+			return nil, errors.New("invalid database: invalid overflow pages")
+		}
+		page, err := r.page(overflowPageNo)
+		if err != nil {
+			return nil, err
+		}
+		chunk := page[4:r.usableSize]
+		if remaining := payloadSize - uint64(len(payload)); uint64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+		overflowPageNo = binary.BigEndian.Uint32(page[0:4])
+	}
+	return payload, nil
+}
+
+// sqliteRecord decodes the columns of a record, the integer columns are returned as int64, the text and blob
+// columns as []byte and the NULL columns as nil
+func sqliteRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := sqliteVarint(payload)
+	if n == 0 || headerSize < uint64(n) || headerSize > uint64(len(payload)) {
+		return nil, errors.New("invalid database: invalid record header")
+	}
+
+	var columns []interface{}
+	header := payload[n:headerSize]
+	body := payload[headerSize:]
+	for len(header) > 0 {
+		serialType, n := sqliteVarint(header)
+		if n == 0 {
+			return nil, errors.New("invalid database: invalid record header")
+		}
+		header = header[n:]
+
+		var size uint64
+		switch {
+		case serialType == 0, serialType == 8, serialType == 9:
+			size = 0
+		case serialType <= 4:
+			size = serialType
+		case serialType == 5:
+			size = 6
+		case serialType == 6, serialType == 7:
+			size = 8
+		case serialType >= 12:
+			size = (serialType - 12) / 2
+		default:
+			return nil, fmt.Errorf("invalid database: invalid serial type %d", serialType)
+		}
+		if size > uint64(len(body)) {
+			return nil, errors.New("invalid database: truncated record")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0, serialType == 7:
+			// NULL and floating point values aren't used
+			columns = append(columns, nil)
+		case serialType == 8, serialType == 9:
+			columns = append(columns, int64(serialType-8))
+		case serialType <= 6:
+			// big endian two's complement integer
+			v := int64(int8(value[0]))
+			for _, b := range value[1:] {
+				v = v<<8 | int64(b)
+			}
+			columns = append(columns, v)
+		default:
+			columns = append(columns, value)
+		}
+	}
+	return columns, nil
+}
+
+// tableRootPage returns the root page of a table, as listed in the sqlite_master table
+func (r *sqliteReader) tableRootPage(name string) (uint32, error) {
+	var rootPageNo uint32
+	err := r.walkTable(1, func(payload []byte) error {
+		// the columns of sqlite_master are type, name, tbl_name, rootpage and sql
+		columns, err := sqliteRecord(payload)
+		if err != nil {
+			return err
+		}
+		if len(columns) < 4 || rootPageNo != 0 {
+			return nil
+		}
+		kind, _ := columns[0].([]byte)
+		tableName, _ := columns[1].([]byte)
+		rootPage, _ := columns[3].(int64)
+		if string(kind) == "table" && string(tableName) == name && rootPage > 0 {
+			rootPageNo = uint32(rootPage)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if rootPageNo == 0 {
+		return 0, fmt.Errorf("invalid database: table %s not found", name)
+	}
+	return rootPageNo, nil
+}
+
+// ParseSqlite lists the packages of a sqlite rpm database, the rpmdb.sqlite file of rpm 4.16 and later
+func ParseSqlite(content []byte) ([]Package, error) {
+	db, err := newSqliteReader(content)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPageNo, err := db.tableRootPage(rpmSqlitePackagesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []Package
+	err = db.walkTable(rootPageNo, func(payload []byte) error {
+		// the columns of the Packages table are hnum, an alias of the row ID stored as NULL, and blob
+		columns, err := sqliteRecord(payload)
+		if err != nil {
+			return err
+		}
+		if len(columns) < 2 {
+			return errors.New("invalid database: unexpected Packages columns")
+		}
+		blob, ok := columns[1].([]byte)
+		if !ok {
+			return errors.New("invalid database: unexpected Packages columns")
+		}
+
+		pkg, err := parseHeader(blob)
+		if err != nil {
+			return err
+		}
+		packages = append(packages, pkg)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
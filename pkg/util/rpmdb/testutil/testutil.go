@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package testutil builds minimal rpm databases for the tests of their readers
+package testutil
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	bdbPageSize       = 512
+	bdbPageHeaderSize = 26
+	bdbHashMagic      = 0x061561
+
+	sqlitePageSize   = 4096
+	sqliteHeaderSize = 100
+)
+
+type headerEntry struct {
+	tag   uint32
+	kind  uint32
+	value []byte
+}
+
+// Header builds the header of a package as stored in the rpm database
+func Header(name, version, release, arch string, epoch int32) []byte {
+	str := func(s string) []byte { return append([]byte(s), 0) }
+
+	entries := []headerEntry{
+		{1000, 6, str(name)},
+		{1001, 6, str(version)},
+		{1002, 6, str(release)},
+		{1022, 6, str(arch)},
+	}
+	if epoch > 0 {
+		value := make([]byte, 4)
+		binary.BigEndian.PutUint32(value, uint32(epoch))
+		entries = append(entries, headerEntry{1003, 4, value})
+	}
+
+	var index, data bytes.Buffer
+	for _, entry := range entries {
+		_ = binary.Write(&index, binary.BigEndian, []uint32{entry.tag, entry.kind, uint32(data.Len()), 1})
+		data.Write(entry.value)
+	}
+
+	var header bytes.Buffer
+	_ = binary.Write(&header, binary.BigEndian, []uint32{uint32(len(entries)), uint32(data.Len())})
+	header.Write(index.Bytes())
+	header.Write(data.Bytes())
+	return header.Bytes()
+}
+
+// BerkeleyDB builds a little endian Berkeley DB hash database holding the provided headers, each header is split
+// across overflow pages
+func BerkeleyDB(headers ...[]byte) []byte {
+	order := binary.LittleEndian
+
+	var pages [][]byte
+	newPage := func(pageType uint8) []byte {
+		page := make([]byte, bdbPageSize)
+		order.PutUint32(page[8:12], uint32(len(pages)))
+		page[25] = pageType
+		pages = append(pages, page)
+		return page
+	}
+
+	meta := newPage(8)
+	order.PutUint32(meta[12:16], bdbHashMagic)
+	order.PutUint32(meta[20:24], bdbPageSize)
+
+	hashPage := newPage(13)
+	order.PutUint16(hashPage[20:22], uint16(2*len(headers)))
+
+	entryOffset := uint16(bdbPageSize)
+	for i, header := range headers {
+		// the key entry, the key is the package index
+		entryOffset -= 5
+		hashPage[entryOffset] = 1
+		order.PutUint32(hashPage[entryOffset+1:], uint32(i+1))
+		order.PutUint16(hashPage[bdbPageHeaderSize+4*i:], entryOffset)
+
+		// the value entry, pointing to the first overflow page
+		entryOffset -= 12
+		hashPage[entryOffset] = 3
+		order.PutUint32(hashPage[entryOffset+4:], uint32(len(pages)))
+		order.PutUint32(hashPage[entryOffset+8:], uint32(len(header)))
+		order.PutUint16(hashPage[bdbPageHeaderSize+4*i+2:], entryOffset)
+
+		for remaining := header; len(remaining) > 0; {
+			page := newPage(7)
+			n := copy(page[bdbPageHeaderSize:], remaining)
+			order.PutUint16(page[22:24], uint16(n))
+			remaining = remaining[n:]
+			if len(remaining) > 0 {
+				order.PutUint32(page[16:20], uint32(len(pages)))
+			}
+		}
+	}
+
+	order.PutUint32(meta[32:36], uint32(len(pages)-1))
+	return bytes.Join(pages, nil)
+}
+
+// Sqlite builds a sqlite database with the Packages table of rpm holding the provided headers, the table is stored
+// in a single leaf page so the headers must fit in a page
+func Sqlite(headers ...[]byte) []byte {
+	schema := sqliteLeafPage(sqliteHeaderSize, sqliteRecord(
+		"table", "Packages", "Packages", int64(2),
+		"CREATE TABLE Packages (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL)",
+	))
+	copy(schema, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(schema[16:18], sqlitePageSize)
+	schema[18], schema[19] = 1, 1
+	schema[21], schema[22], schema[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(schema[28:32], 2)
+	binary.BigEndian.PutUint32(schema[44:48], 4)
+	binary.BigEndian.PutUint32(schema[56:60], 1)
+
+	records := make([][]byte, 0, len(headers))
+	for _, header := range headers {
+		// hnum is an alias of the row ID, stored as NULL
+		records = append(records, sqliteRecord(nil, header))
+	}
+	return append(schema, sqliteLeafPage(0, records...)...)
+}
+
+// sqliteLeafPage builds a table leaf page holding the provided records, the page header starts at headerOffset
+func sqliteLeafPage(headerOffset int, records ...[]byte) []byte {
+	page := make([]byte, sqlitePageSize)
+	page[headerOffset] = 0x0d
+	binary.BigEndian.PutUint16(page[headerOffset+3:], uint16(len(records)))
+
+	cellOffset := sqlitePageSize
+	for i, record := range records {
+		cell := append(sqliteVarint(uint64(len(record))), sqliteVarint(uint64(i+1))...)
+		cell = append(cell, record...)
+		cellOffset -= len(cell)
+		copy(page[cellOffset:], cell)
+		binary.BigEndian.PutUint16(page[headerOffset+8+2*i:], uint16(cellOffset))
+	}
+	binary.BigEndian.PutUint16(page[headerOffset+5:], uint16(cellOffset))
+	return page
+}
+
+// sqliteRecord encodes the columns of a record, the columns can be nil, int64 values between 0 and 127, strings
+// stored as text or []byte stored as blobs
+func sqliteRecord(columns ...interface{}) []byte {
+	var serialTypes, body []byte
+	for _, column := range columns {
+		switch value := column.(type) {
+		case nil:
+			serialTypes = append(serialTypes, 0)
+		case int64:
+			serialTypes = append(serialTypes, 1)
+			body = append(body, byte(value))
+		case string:
+			serialTypes = append(serialTypes, sqliteVarint(uint64(len(value))*2+13)...)
+			body = append(body, value...)
+		case []byte:
+			serialTypes = append(serialTypes, sqliteVarint(uint64(len(value))*2+12)...)
+			body = append(body, value...)
+		}
+	}
+
+	// the size of the record header includes its own varint, which is a single byte for the records built here
+	record := append(sqliteVarint(uint64(len(serialTypes)+1)), serialTypes...)
+	return append(record, body...)
+}
+
+// sqliteVarint encodes a sqlite variable-length integer, only the values lower than 2^56 are supported
+func sqliteVarint(v uint64) []byte {
+	b := []byte{byte(v & 0x7f)}
+	for v >>= 7; v > 0; v >>= 7 {
+		b = append([]byte{byte(v&0x7f) | 0x80}, b...)
+	}
+	return b
+}
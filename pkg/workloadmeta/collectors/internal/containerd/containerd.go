@@ -65,8 +65,9 @@ var containerdTopics = []string{
 }
 
 type exitInfo struct {
-	exitCode *uint32
-	exitTS   time.Time
+	exitCode  *uint32
+	exitTS    time.Time
+	oomKilled bool
 }
 
 type collector struct {
@@ -332,8 +333,23 @@ func (c *collector) deleteExitInfo(id string) {
 }
 
 func (c *collector) cacheExitInfo(id string, exitCode *uint32, exitTS time.Time) {
-	c.contToExitInfo[id] = &exitInfo{
-		exitTS:   exitTS,
-		exitCode: exitCode,
+	info, found := c.contToExitInfo[id]
+	if !found {
+		info = &exitInfo{}
+		c.contToExitInfo[id] = info
 	}
+
+	info.exitTS = exitTS
+	info.exitCode = exitCode
+}
+
+// cacheOOMKilled records that the task of a container was OOM killed, task OOM events are received before task exit events
+func (c *collector) cacheOOMKilled(id string) {
+	info, found := c.contToExitInfo[id]
+	if !found {
+		info = &exitInfo{}
+		c.contToExitInfo[id] = info
+	}
+
+	info.oomKilled = true
 }
@@ -57,7 +57,16 @@ func (c *collector) buildCollectorEvent(
 		c.cacheExitInfo(containerID, &deleted.ExitStatus, deleted.ExitedAt)
 		return createSetEvent(container, containerdEvent.Namespace, c.containerdClient)
 
-	case TaskStartTopic, TaskOOMTopic, TaskPausedTopic, TaskResumedTopic:
+	case TaskOOMTopic:
+		c.cacheOOMKilled(containerID)
+		return createSetEvent(container, containerdEvent.Namespace, c.containerdClient)
+
+	case TaskStartTopic:
+		// A new task starts, the exit info of the previous one is not relevant anymore
+		c.deleteExitInfo(containerID)
+		return createSetEvent(container, containerdEvent.Namespace, c.containerdClient)
+
+	case TaskPausedTopic, TaskResumedTopic:
 		return createSetEvent(container, containerdEvent.Namespace, c.containerdClient)
 
 	default:
@@ -97,6 +106,7 @@ func createDeletionEvent(containerID string, exitInfo *exitInfo) workloadmeta.Co
 	if exitInfo != nil {
 		container.State.ExitCode = exitInfo.exitCode
 		container.State.FinishedAt = exitInfo.exitTS
+		container.State.OOMKilled = exitInfo.oomKilled
 	}
 
 	return workloadmeta.CollectorEvent{
@@ -314,3 +314,60 @@ func containerdClient(container containerd.Container) fake.MockedContainerdClien
 		},
 	}
 }
+
+func TestBuildCollectorEventOOMKilled(t *testing.T) {
+	containerID := "10"
+	namespace := "test_namespace"
+
+	container := mockedContainer{
+		mockID: func() string {
+			return containerID
+		},
+	}
+	client := containerdClient(&container)
+	c := &collector{containerdClient: &client, contToExitInfo: make(map[string]*exitInfo)}
+
+	newEnvelope := func(topic string, event proto.Message) *containerdevents.Envelope {
+		value, err := proto.Marshal(event)
+		assert.NoError(t, err)
+		return &containerdevents.Envelope{
+			Namespace: namespace,
+			Topic:     topic,
+			Event:     &types.Any{Value: value},
+		}
+	}
+
+	exitTime := time.Unix(1660000000, 0).UTC()
+	exitCode := uint32(137)
+
+	for _, envelope := range []*containerdevents.Envelope{
+		newEnvelope(TaskStartTopic, &events.TaskStart{ContainerID: containerID}),
+		newEnvelope(TaskOOMTopic, &events.TaskOOM{ContainerID: containerID}),
+		newEnvelope(TaskExitTopic, &events.TaskExit{ContainerID: containerID, ExitStatus: exitCode, ExitedAt: exitTime}),
+	} {
+		_, err := c.buildCollectorEvent(envelope, containerID, &container)
+		assert.NoError(t, err)
+	}
+
+	event, err := c.buildCollectorEvent(newEnvelope(containerDeletionTopic, &events.ContainerDelete{ID: containerID}), containerID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, workloadmeta.ContainerState{
+		ExitCode:   &exitCode,
+		FinishedAt: exitTime,
+		OOMKilled:  true,
+	}, event.Entity.(*workloadmeta.Container).State)
+
+	// A restarted task is not OOM killed anymore
+	for _, envelope := range []*containerdevents.Envelope{
+		newEnvelope(TaskOOMTopic, &events.TaskOOM{ContainerID: containerID}),
+		newEnvelope(TaskStartTopic, &events.TaskStart{ContainerID: containerID}),
+		newEnvelope(TaskExitTopic, &events.TaskExit{ContainerID: containerID, ExitStatus: 0, ExitedAt: exitTime}),
+	} {
+		_, err := c.buildCollectorEvent(envelope, containerID, &container)
+		assert.NoError(t, err)
+	}
+
+	event, err = c.buildCollectorEvent(newEnvelope(containerDeletionTopic, &events.ContainerDelete{ID: containerID}), containerID, nil)
+	assert.NoError(t, err)
+	assert.False(t, event.Entity.(*workloadmeta.Container).State.OOMKilled)
+}
@@ -43,11 +43,16 @@ type collector struct {
 	dockerUtil *docker.DockerUtil
 	eventCh    <-chan *docker.ContainerEvent
 	errCh      <-chan error
+
+	// oomKilled holds the containers that received an oom event, it's reported when they die
+	oomKilled map[string]struct{}
 }
 
 func init() {
 	workloadmeta.RegisterCollector(collectorID, func() workloadmeta.Collector {
-		return &collector{}
+		return &collector{
+			oomKilled: make(map[string]struct{}),
+		}
 	})
 }
 
@@ -159,6 +164,15 @@ func (c *collector) generateEventsFromContainerList(ctx context.Context, filter
 }
 
 func (c *collector) handleEvent(ctx context.Context, ev *docker.ContainerEvent) error {
+	switch ev.Action {
+	case docker.ContainerEventActionOOM:
+		c.oomKilled[ev.ContainerID] = struct{}{}
+		return nil
+	case docker.ContainerEventActionStart:
+		// A restarted container is not OOM killed anymore
+		delete(c.oomKilled, ev.ContainerID)
+	}
+
 	event, err := c.buildCollectorEvent(ctx, ev)
 	if err != nil {
 		return err
@@ -231,6 +245,7 @@ func (c *collector) buildCollectorEvent(ctx context.Context, ev *docker.Containe
 				StartedAt:  startedAt,
 				FinishedAt: finishedAt,
 				CreatedAt:  createdAt,
+				OOMKilled:  container.State.OOMKilled,
 			},
 			NetworkIPs: extractNetworkIPs(container.NetworkSettings.Networks),
 			Hostname:   container.Config.Hostname,
@@ -248,6 +263,10 @@ func (c *collector) buildCollectorEvent(ctx context.Context, ev *docker.Containe
 			}
 		}
 
+		// The die event doesn't tell whether the container was OOM killed, it's preceded by an oom event
+		_, oomKilled := c.oomKilled[ev.ContainerID]
+		delete(c.oomKilled, ev.ContainerID)
+
 		event.Type = workloadmeta.EventTypeUnset
 		event.Entity = &workloadmeta.Container{
 			EntityID: entityID,
@@ -255,6 +274,7 @@ func (c *collector) buildCollectorEvent(ctx context.Context, ev *docker.Containe
 				Running:    false,
 				FinishedAt: ev.Timestamp,
 				ExitCode:   exitCode,
+				OOMKilled:  oomKilled,
 			},
 		}
 
@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/pointer"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
@@ -135,6 +136,7 @@ func (c *collector) parsePods(pods []*kubelet.Pod) []workloadmeta.CollectorEvent
 				Labels:      podMeta.Labels,
 			},
 			Owners:                     owners,
+			CreationTimestamp:          podMeta.CreationTimestamp,
 			PersistentVolumeClaimNames: pod.GetPersistentVolumeClaimNames(),
 			Containers:                 podContainers,
 			Ready:                      kubelet.IsPodReady(pod),
@@ -222,6 +224,8 @@ func (c *collector) parsePodContainers(
 			containerState.CreatedAt = st.StartedAt
 			containerState.StartedAt = st.StartedAt
 			containerState.FinishedAt = st.FinishedAt
+			containerState.ExitCode = pointer.UInt32Ptr(int64(st.ExitCode))
+			containerState.OOMKilled = st.Reason == "OOMKilled"
 		}
 
 		podContainers = append(podContainers, podContainer)
@@ -298,6 +298,7 @@ type ContainerState struct {
 	StartedAt  time.Time
 	FinishedAt time.Time
 	ExitCode   *uint32
+	OOMKilled  bool
 }
 
 // String returns a string representation of ContainerState.
@@ -314,6 +315,9 @@ func (c ContainerState) String(verbose bool) string {
 		if c.ExitCode != nil {
 			_, _ = fmt.Fprintln(&sb, "Exit Code:", *c.ExitCode)
 		}
+		if c.OOMKilled {
+			_, _ = fmt.Fprintln(&sb, "OOM Killed:", c.OOMKilled)
+		}
 	}
 
 	return sb.String()
@@ -437,6 +441,7 @@ type KubernetesPod struct {
 	EntityID
 	EntityMeta
 	Owners                     []KubernetesPodOwner
+	CreationTimestamp          time.Time
 	PersistentVolumeClaimNames []string
 	Containers                 []OrchestratorContainer
 	Ready                      bool
@@ -498,6 +503,7 @@ func (p KubernetesPod) String(verbose bool) string {
 	_, _ = fmt.Fprintln(&sb, "IP:", p.IP)
 
 	if verbose {
+		_, _ = fmt.Fprintln(&sb, "Creation Timestamp:", p.CreationTimestamp)
 		_, _ = fmt.Fprintln(&sb, "Priority Class:", p.PriorityClass)
 		_, _ = fmt.Fprintln(&sb, "QOS Class:", p.QOSClass)
 		_, _ = fmt.Fprintln(&sb, "PVCs:", sliceToString(p.PersistentVolumeClaimNames))
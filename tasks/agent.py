@@ -54,6 +54,7 @@ AGENT_CORECHECKS = [
     "kubernetes_apiserver",
     "load",
     "memory",
+    "network_path",
     "ntp",
     "oom_kill",
     "systemd",